@@ -29,6 +29,10 @@ import (
 	s3init "server/internal/init/s3"
 
 	// Chat Module
+	caldavEntity "server/internal/modules/caldav"
+	caldavCtrl "server/internal/modules/caldav/controller"
+	caldavDB "server/internal/modules/caldav/repo/database"
+	caldavUC "server/internal/modules/caldav/usecase"
 	chatEntity "server/internal/modules/chat" // Для интерфейсов и DTO
 	chatCtrl "server/internal/modules/chat/controller"
 	chatDB "server/internal/modules/chat/repo/database"
@@ -80,6 +84,7 @@ import (
 	"server/pkg/lib/emailsender"
 	appMiddleware "server/pkg/middleware/jwt"
 	"server/pkg/middleware/logger"
+	"server/pkg/middleware/realip"
 )
 
 type App struct {
@@ -302,6 +307,7 @@ func (app *App) SetupRoutes() {
 	app.Router.Use(
 		middleware.Recoverer,
 		middleware.RequestID,
+		realip.New(app.Cfg.HttpServerConfig.TrustedProxies, app.Log), // До логгера и лимитеров, которые берут адрес из RemoteAddr
 		logger.New(app.Log),
 		cors.Handler(cors.Options{
 			AllowedOrigins:   app.Cfg.HttpServerConfig.AllowedOrigins,
@@ -397,16 +403,27 @@ func (app *App) SetupRoutes() {
 	s3BaseURLForTeamImages := fmt.Sprintf("https://%s/%s", strings.TrimSuffix(app.Cfg.S3Config.Endpoint, "/"), strings.TrimPrefix(app.Cfg.S3Config.BucketTeamImages, "/"))
 	teamS3Impl := teamS3Repo.NewTeamS3(app.Log, app.S3, app.Cfg.S3Config)
 	teamRepoImpl := teamRepo.NewRepo(teamDBImpl, teamCacheImpl, teamS3Impl, app.Log, app.Cfg.S3Config.BucketTeamImages, s3BaseURLForTeamImages)
-	teamUseCaseImpl := teamUC.NewTeamUseCase(teamRepoImpl, app.Log, *app.Cfg) // Тип *teamUC.TeamUseCase
+	var teamNotifier teamUC.Notifier // Без FCM уведомления команды отключены
+	if *app.PushNotificationSender != nil {
+		teamNotifier = teamUC.NewPushNotifier(*app.PushNotificationSender, profileUseCaseImpl, app.Log)
+	}
+	teamUseCaseImpl := teamUC.NewTeamUseCase(teamRepoImpl, teamNotifier, app.EmailSender, app.Log, *app.Cfg) // Тип *teamUC.TeamUseCase
 	teamCtrl := teamC.NewTeamController(teamUseCaseImpl, app.Log, app.Cfg)
+	if _, err := app.Cron.AddFunc("30 0 * * *", teamUseCaseImpl.PurgeExpiredTeams); err != nil { // Ежедневно очищаем корзину команд
+		app.Log.Error("failed to schedule team trash purge", "error", err)
+	}
 	app.Router.Route(apiVersion+"/teams", func(r chi.Router) {
 		r.Use(AuthUserMiddleware)
 		r.Post("/", teamCtrl.CreateTeam)
 		r.Get("/my", teamCtrl.GetMyTeams)
+		r.Get("/trash", teamCtrl.GetDeletedTeams)
 		r.Route("/{teamID}", func(r chi.Router) {
 			r.Get("/", teamCtrl.GetTeam)
 			r.Put("/", teamCtrl.UpdateTeam)
 			r.Delete("/", teamCtrl.DeleteTeam)
+			r.Post("/restore", teamCtrl.RestoreTeam)
+			r.Post("/archive", teamCtrl.ArchiveTeam)
+			r.Post("/unarchive", teamCtrl.UnarchiveTeam)
 			r.Get("/members", teamCtrl.GetTeamMembers)
 			r.Post("/members", teamCtrl.AddTeamMember)
 			r.Route("/members/{userID}", func(r chi.Router) {
@@ -415,10 +432,34 @@ func (app *App) SetupRoutes() {
 			})
 			r.Post("/leave", teamCtrl.LeaveTeam)
 			r.Post("/invites", teamCtrl.GenerateInviteToken)
+			r.Get("/invites", teamCtrl.GetTeamInvites)
+			r.Delete("/invites/{inviteID}", teamCtrl.RevokeTeamInvite)
+			r.Get("/join-requests", teamCtrl.GetTeamJoinRequests)
+			r.Post("/join-requests/{requestID}/approve", teamCtrl.ApproveJoinRequest)
+			r.Post("/join-requests/{requestID}/reject", teamCtrl.RejectJoinRequest)
+			r.Get("/statuses", teamCtrl.GetTaskWorkflow)
+			r.Put("/statuses", teamCtrl.UpdateTaskWorkflow)
+			r.Get("/permissions", teamCtrl.GetTeamPermissions)
+			r.Put("/permissions", teamCtrl.UpdateTeamPermissions)
+			r.Get("/stats", teamCtrl.GetTeamStats)
+			r.Get("/projects", teamCtrl.GetTeamProjects)
+			r.Post("/projects", teamCtrl.CreateTeamProject)
+			r.Route("/projects/{projectID}", func(r chi.Router) {
+				r.Get("/", teamCtrl.GetTeamProject)
+				r.Put("/", teamCtrl.UpdateTeamProject)
+				r.Delete("/", teamCtrl.DeleteTeamProject)
+			})
+			r.Route("/ownership-transfer", func(r chi.Router) {
+				r.Get("/", teamCtrl.GetOwnershipTransfer)
+				r.Post("/", teamCtrl.StartOwnershipTransfer)
+				r.Delete("/", teamCtrl.CancelOwnershipTransfer)
+				r.Post("/accept", teamCtrl.AcceptOwnershipTransfer)
+			})
 			// Маршруты для командных тегов будут ниже, после инициализации TagController
 		})
 	})
 	app.Router.With(AuthUserMiddleware).Post(apiVersion+"/teams/join", teamCtrl.JoinTeamByToken)
+	app.Router.With(AuthUserMiddleware).Post(apiVersion+"/teams/join-requests", teamCtrl.RequestToJoinTeam)
 
 	// --- Tag Module ---
 	tagDBImpl := tagDbRepo.NewTagDatabase(app.Storage.Db, app.Log)
@@ -447,18 +488,104 @@ func (app *App) SetupRoutes() {
 	taskCacheImpl := taskCacheRepo.NewTaskCache(app.Cache, app.Log, app.Cfg.CacheConfig)
 	taskRepoImpl := taskRepo.NewRepo(taskDBImpl, taskCacheImpl)
 	var teamServiceProviderForTask taskUC.TeamService = teamUseCaseImpl // Приведение типа
-	taskUseCaseImpl := taskUC.NewTaskUseCase(taskRepoImpl, tagUseCaseImpl, tagRepoImpl, teamServiceProviderForTask, app.Log, app.Cfg.CacheConfig.DefaultTaskCacheTtl)
-	taskCtrl := taskC.NewTaskController(taskUseCaseImpl, app.Log)
+	taskTxRunner := taskRepo.NewTxRunner(app.Storage.Db, taskCacheImpl, tagCacheImpl, app.Log)
+	var taskNotifier taskUC.Notifier // Без FCM уведомления о задачах отключены
+	if *app.PushNotificationSender != nil {
+		taskNotifier = taskUC.NewPushNotifier(*app.PushNotificationSender, profileUseCaseImpl, app.Log)
+	}
+	taskUseCaseImpl := taskUC.NewTaskUseCase(taskRepoImpl, tagUseCaseImpl, tagRepoImpl, teamServiceProviderForTask, taskTxRunner, taskNotifier, app.Log, app.Cfg.CacheConfig.DefaultTaskCacheTtl)
+	var appURL string
+	if len(app.Cfg.HttpServerConfig.AllowedOrigins) > 0 {
+		appURL = app.Cfg.HttpServerConfig.AllowedOrigins[0]
+	}
+	taskCtrl := taskC.NewTaskController(taskUseCaseImpl, app.Log, appURL)
 	app.Router.Route(apiVersion+"/tasks", func(r chi.Router) {
 		r.Use(AuthUserMiddleware)
 		r.Post("/", taskCtrl.CreateTask)
 		r.Get("/", taskCtrl.GetTasks)
+		r.Post("/bulk", taskCtrl.BulkUpdateTasks)
+		r.Get("/export", taskCtrl.ExportTasks)
+		r.Post("/import", taskCtrl.ImportTasks)
 		r.Get("/{taskID}", taskCtrl.GetTask)
 		r.Put("/{taskID}", taskCtrl.UpdateTask)
 		r.Patch("/{taskID}", taskCtrl.PatchTask)
 		r.Delete("/{taskID}", taskCtrl.DeleteTask)
 		r.Post("/{taskID}/restore", taskCtrl.RestoreTask)
 		r.Delete("/{taskID}/permanent", taskCtrl.DeleteTaskPermanently)
+		r.Post("/{taskID}/move", taskCtrl.MoveTask)
+		r.Post("/{taskID}/transfer", taskCtrl.TransferTask)
+		r.Get("/{taskID}/history", taskCtrl.GetTaskHistory)
+		r.Put("/{taskID}/watch", taskCtrl.WatchTask)
+		r.Delete("/{taskID}/watch", taskCtrl.UnwatchTask)
+		r.Get("/{taskID}/time-entries", taskCtrl.GetTimeEntries)
+		r.Post("/{taskID}/time-entries", taskCtrl.CreateTimeEntry)
+		r.Post("/{taskID}/time-entries/start", taskCtrl.StartTimer)
+		r.Post("/{taskID}/time-entries/stop", taskCtrl.StopTimer)
+		r.Delete("/{taskID}/time-entries/{entryID}", taskCtrl.DeleteTimeEntry)
+	})
+	app.Router.With(AuthUserMiddleware).Get(apiVersion+"/teams/{teamID}/activity", taskCtrl.GetTeamActivity)
+	app.Router.With(AuthUserMiddleware).Get(apiVersion+"/teams/{teamID}/time-report", taskCtrl.GetTeamTimeReport)
+	app.Router.With(AuthUserMiddleware).Get(apiVersion+"/time-entries/running", taskCtrl.GetRunningTimer)
+	app.Router.Route(apiVersion+"/teams/{teamID}/sprints", func(r chi.Router) {
+		r.Use(AuthUserMiddleware)
+		r.Get("/", taskCtrl.GetTeamSprints)
+		r.Post("/", taskCtrl.CreateSprint)
+		r.Get("/velocity", taskCtrl.GetSprintVelocity)
+		r.Get("/{sprintID}", taskCtrl.GetSprint)
+		r.Put("/{sprintID}", taskCtrl.UpdateSprint)
+		r.Delete("/{sprintID}", taskCtrl.DeleteSprint)
+		r.Post("/{sprintID}/start", taskCtrl.StartSprint)
+		r.Post("/{sprintID}/close", taskCtrl.CloseSprint)
+		r.Get("/{sprintID}/burndown", taskCtrl.GetSprintBurndown)
+	})
+	app.Router.Route(apiVersion+"/task-views", func(r chi.Router) {
+		r.Use(AuthUserMiddleware)
+		r.Get("/", taskCtrl.GetSavedViews)
+		r.Post("/", taskCtrl.CreateSavedView)
+		r.Put("/order", taskCtrl.ReorderSavedViews)
+		r.Put("/{viewID}", taskCtrl.UpdateSavedView)
+		r.Delete("/{viewID}", taskCtrl.DeleteSavedView)
+	})
+	app.Router.Route(apiVersion+"/templates", func(r chi.Router) {
+		r.Use(AuthUserMiddleware)
+		r.Get("/", taskCtrl.GetTaskTemplates)
+		r.Post("/", taskCtrl.CreateTaskTemplate)
+		r.Get("/{templateID}", taskCtrl.GetTaskTemplate)
+		r.Put("/{templateID}", taskCtrl.UpdateTaskTemplate)
+		r.Delete("/{templateID}", taskCtrl.DeleteTaskTemplate)
+		r.Post("/{templateID}/instantiate", taskCtrl.InstantiateTaskTemplate)
+	})
+	app.Router.Route(apiVersion+"/ical", func(r chi.Router) {
+		r.With(AuthUserMiddleware).Get("/feeds", taskCtrl.GetCalendarFeeds)
+		r.With(AuthUserMiddleware).Post("/feeds", taskCtrl.CreateCalendarFeed)
+		r.With(AuthUserMiddleware).Delete("/feeds/{feedID}", taskCtrl.DeleteCalendarFeed)
+		// Календарные клиенты не передают JWT: доступ по секретному токену в URL
+		r.Get("/{token}.ics", taskCtrl.GetCalendarFeed)
+	})
+
+	// --- CalDAV Module ---
+	caldavLog := app.Log.With(slog.String("module", "caldav"))
+	caldavDatabaseRepo := caldavDB.NewDBRepo(app.Storage.Db, caldavLog.With(slog.String("layer", "db_repo")))
+	var teamServiceForCalDAV caldavEntity.TeamService = teamUseCaseImpl
+	caldavUseCaseInstance := caldavUC.NewUseCase(
+		caldavLog.With(slog.String("layer", "usecase")),
+		caldavDatabaseRepo,
+		taskUseCaseImpl,
+		teamServiceForCalDAV,
+	)
+	caldavControllerInstance := caldavCtrl.NewController(caldavLog.With(slog.String("layer", "controller")), caldavUseCaseInstance, appURL)
+	app.Router.Route(apiVersion+"/app-passwords", func(r chi.Router) {
+		r.Use(AuthUserMiddleware)
+		r.Get("/", caldavControllerInstance.GetAppPasswords)
+		r.Post("/", caldavControllerInstance.CreateAppPassword)
+		r.Delete("/{appPasswordID}", caldavControllerInstance.DeleteAppPassword)
+	})
+	// CalDAV-клиенты аутентифицируются паролем приложения (Basic), а не JWT; путь без версии API
+	app.Router.HandleFunc("/.well-known/caldav", caldavControllerInstance.WellKnown)
+	app.Router.Route("/caldav", func(r chi.Router) {
+		r.Use(caldavControllerInstance.BasicAuth)
+		r.HandleFunc("/", caldavControllerInstance.ServeDAV)
+		r.HandleFunc("/*", caldavControllerInstance.ServeDAV)
 	})
 
 	// --- Chat Module ---
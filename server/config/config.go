@@ -40,6 +40,7 @@ type HttpServerConfig struct {
 	Timeout        time.Duration `yaml:"timeout" env-required:"true"`
 	IdleTimeout    time.Duration `yaml:"idle_timeout" env-required:"true"`
 	AllowedOrigins []string      `yaml:"allowed_origins"`
+	TrustedProxies []string      `yaml:"trusted_proxies"` // Адреса или подсети обратных прокси, которым можно верить в X-Forwarded-For
 	TLS            TLSConfig     `yaml:"tls"`
 }
 
@@ -0,0 +1,3 @@
+-- 003_add_task_events_down.sql
+
+DROP TABLE IF EXISTS TaskEvents;
@@ -0,0 +1,20 @@
+-- 003_add_task_events_up.sql
+
+-- Журнал изменений задач: кто, когда и какое поле изменил.
+-- Записи только добавляются, приложение их не обновляет и не удаляет.
+-- task_id намеренно без внешнего ключа, чтобы история переживала безвозвратное удаление задачи
+CREATE TABLE TaskEvents (
+                            event_id BIGSERIAL PRIMARY KEY,
+                            task_id INT NOT NULL,
+                            team_id INT REFERENCES Teams(team_id) ON DELETE CASCADE, -- NULL для личных задач
+                            actor_user_id INT REFERENCES Users(user_id) ON DELETE SET NULL,
+                            event_type VARCHAR(30) NOT NULL, -- 'created', 'updated', 'deleted', 'restored', 'deleted_permanently', 'tag_added', 'tag_removed'
+                            field_name VARCHAR(50), -- Для 'updated': имя изменённого поля
+                            old_value TEXT,
+                            new_value TEXT,
+                            created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_task_events_task_id ON TaskEvents(task_id, created_at);
+CREATE INDEX idx_task_events_team_id ON TaskEvents(team_id, created_at) WHERE team_id IS NOT NULL;
+CREATE INDEX idx_task_events_actor ON TaskEvents(actor_user_id, created_at);
@@ -0,0 +1,10 @@
+-- 004_add_team_task_statuses_down.sql
+
+DROP INDEX IF EXISTS idx_tasks_status_category;
+ALTER TABLE Tasks
+    DROP COLUMN IF EXISTS status_category;
+
+DROP TRIGGER IF EXISTS trigger_team_task_statuses_updated_at ON TeamTaskStatuses;
+
+DROP TABLE IF EXISTS TeamStatusTransitions;
+DROP TABLE IF EXISTS TeamTaskStatuses;
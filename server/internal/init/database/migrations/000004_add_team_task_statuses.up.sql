@@ -0,0 +1,43 @@
+-- 004_add_team_task_statuses_up.sql
+
+-- Набор статусов задач, определяемый командой (упорядоченный)
+-- Если у команды нет ни одной записи, используется набор по умолчанию (todo, in_progress, deferred, done)
+CREATE TABLE TeamTaskStatuses (
+                                  status_id SERIAL PRIMARY KEY,
+                                  team_id INT NOT NULL REFERENCES Teams(team_id) ON DELETE CASCADE,
+                                  status_key VARCHAR(50) NOT NULL, -- Значение, которое хранится в Tasks.status
+                                  name VARCHAR(50) NOT NULL,       -- Отображаемое название
+                                  category VARCHAR(10) NOT NULL,   -- 'open', 'active', 'done'
+                                  position INT NOT NULL DEFAULT 0,
+                                  color VARCHAR(7),
+                                  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+                                  updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+                                  CONSTRAINT chk_team_task_status_category CHECK (category IN ('open', 'active', 'done')),
+                                  CONSTRAINT unique_team_task_status_key UNIQUE (team_id, status_key)
+);
+
+-- Разрешенные переходы между статусами (необязательно)
+-- role = NULL означает правило для всех ролей. Если для роли нет ни одного правила, переходы не ограничены.
+CREATE TABLE TeamStatusTransitions (
+                                       transition_id SERIAL PRIMARY KEY,
+                                       team_id INT NOT NULL REFERENCES Teams(team_id) ON DELETE CASCADE,
+                                       from_status_key VARCHAR(50) NOT NULL,
+                                       to_status_key VARCHAR(50) NOT NULL,
+                                       role team_member_role
+);
+
+CREATE INDEX idx_team_status_transitions_team_id ON TeamStatusTransitions(team_id);
+
+CREATE TRIGGER trigger_team_task_statuses_updated_at BEFORE UPDATE ON TeamTaskStatuses FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Категория статуса хранится в задаче, чтобы фильтровать без JOIN-а
+ALTER TABLE Tasks
+    ADD COLUMN status_category VARCHAR(10) DEFAULT 'open' NOT NULL;
+
+UPDATE Tasks SET status_category = CASE status
+                                       WHEN 'done' THEN 'done'
+                                       WHEN 'in_progress' THEN 'active'
+                                       ELSE 'open'
+    END;
+
+CREATE INDEX idx_tasks_status_category ON Tasks(status_category);
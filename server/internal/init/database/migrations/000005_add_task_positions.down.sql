@@ -0,0 +1,6 @@
+-- 005_add_task_positions_down.sql
+
+DROP INDEX IF EXISTS idx_tasks_personal_status_position;
+DROP INDEX IF EXISTS idx_tasks_team_status_position;
+ALTER TABLE Tasks
+    DROP COLUMN IF EXISTS position;
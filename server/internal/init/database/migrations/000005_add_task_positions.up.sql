@@ -0,0 +1,22 @@
+-- 005_add_task_positions_up.sql
+
+-- Ручной порядок задач внутри колонки статуса (Kanban).
+-- Позиция дробная: при перемещении задача получает значение между соседями,
+-- при исчерпании точности колонка перенумеровывается.
+ALTER TABLE Tasks
+    ADD COLUMN position DOUBLE PRECISION DEFAULT 0 NOT NULL;
+
+-- Начальный порядок: по дате создания внутри колонки (команда + статус или личные задачи автора + статус)
+UPDATE Tasks t SET position = ordered.rn * 1024
+FROM (
+         SELECT task_id,
+                ROW_NUMBER() OVER (
+                    PARTITION BY team_id, CASE WHEN team_id IS NULL THEN created_by_user_id END, status
+                    ORDER BY created_at, task_id
+                    ) AS rn
+         FROM Tasks
+     ) ordered
+WHERE t.task_id = ordered.task_id;
+
+CREATE INDEX idx_tasks_team_status_position ON Tasks(team_id, status, position);
+CREATE INDEX idx_tasks_personal_status_position ON Tasks(created_by_user_id, status, position) WHERE team_id IS NULL;
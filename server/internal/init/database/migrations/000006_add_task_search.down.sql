@@ -0,0 +1,7 @@
+-- 006_add_task_search_down.sql
+
+DROP INDEX IF EXISTS idx_team_tags_name_search;
+DROP INDEX IF EXISTS idx_user_tags_name_search;
+DROP INDEX IF EXISTS idx_tasks_search_vector;
+ALTER TABLE Tasks
+    DROP COLUMN IF EXISTS search_vector;
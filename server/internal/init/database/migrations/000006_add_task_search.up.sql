@@ -0,0 +1,18 @@
+-- 006_add_task_search_up.sql
+
+-- Полнотекстовый поиск по задачам (русский и английский).
+-- Заголовок весит больше описания (A > B). Конфигурация 'russian' стеммит и латиницу (english_stem),
+-- 'english' дополнительно отбрасывает английские стоп-слова.
+ALTER TABLE Tasks
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(description, '')), 'B') ||
+        setweight(to_tsvector('english', COALESCE(description, '')), 'B')
+        ) STORED;
+
+CREATE INDEX idx_tasks_search_vector ON Tasks USING GIN (search_vector);
+
+-- Названия тегов ищутся без стемминга (короткие метки)
+CREATE INDEX idx_user_tags_name_search ON UserTags USING GIN (to_tsvector('simple', name));
+CREATE INDEX idx_team_tags_name_search ON TeamTags USING GIN (to_tsvector('simple', name));
@@ -0,0 +1,4 @@
+-- 007_add_saved_task_views_down.sql
+
+DROP TRIGGER IF EXISTS trigger_saved_task_views_updated_at ON SavedTaskViews;
+DROP TABLE IF EXISTS SavedTaskViews;
@@ -0,0 +1,20 @@
+-- 007_add_saved_task_views_up.sql
+
+-- Сохраненные представления (умные списки) задач.
+-- team_id = NULL - личное представление владельца; иначе - общее представление команды.
+-- filters хранит параметры GetTasks; даты задаются относительными токенами (today, +7d, ...).
+CREATE TABLE SavedTaskViews (
+                                view_id SERIAL PRIMARY KEY,
+                                owner_user_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+                                team_id INT REFERENCES Teams(team_id) ON DELETE CASCADE,
+                                name VARCHAR(100) NOT NULL,
+                                filters JSONB NOT NULL DEFAULT '{}'::jsonb,
+                                position INT NOT NULL DEFAULT 0,
+                                created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+                                updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_saved_task_views_owner ON SavedTaskViews(owner_user_id, position) WHERE team_id IS NULL;
+CREATE INDEX idx_saved_task_views_team ON SavedTaskViews(team_id, position) WHERE team_id IS NOT NULL;
+
+CREATE TRIGGER trigger_saved_task_views_updated_at BEFORE UPDATE ON SavedTaskViews FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,3 @@
+-- 008_add_calendar_feeds_down.sql
+
+DROP TABLE IF EXISTS CalendarFeeds;
@@ -0,0 +1,15 @@
+-- 008_add_calendar_feeds_up.sql
+
+-- Подписки на календарь задач (iCalendar). Ссылка содержит секретный токен, в БД хранится только его SHA-256.
+-- team_id = NULL - личная лента пользователя (его задачи и назначенные ему), иначе - лента задач команды.
+CREATE TABLE CalendarFeeds (
+                               feed_id SERIAL PRIMARY KEY,
+                               user_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+                               team_id INT REFERENCES Teams(team_id) ON DELETE CASCADE,
+                               token_hash CHAR(64) NOT NULL UNIQUE,
+                               created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+                               last_accessed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Одна лента на пользователя и область: повторная генерация заменяет токен
+CREATE UNIQUE INDEX idx_calendar_feeds_scope ON CalendarFeeds(user_id, COALESCE(team_id, 0));
@@ -0,0 +1,4 @@
+-- 009_add_caldav_down.sql
+
+DROP TABLE IF EXISTS CalDAVObjects;
+DROP TABLE IF EXISTS AppPasswords;
@@ -0,0 +1,23 @@
+-- 009_add_caldav_up.sql
+
+-- Пароли приложений для CalDAV-клиентов (Basic-аутентификация вместо основного пароля и JWT).
+-- Пароль показывается один раз при создании, в БД хранится только его SHA-256.
+CREATE TABLE AppPasswords (
+                              app_password_id SERIAL PRIMARY KEY,
+                              user_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+                              name VARCHAR(100) NOT NULL,
+                              password_hash CHAR(64) NOT NULL UNIQUE,
+                              created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+                              last_used_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_app_passwords_user ON AppPasswords(user_id);
+
+-- Имена и UID объектов, созданных CalDAV-клиентами. Клиент сам выбирает имя ресурса (обычно UUID.ics)
+-- и ожидает найти задачу по нему; задачи без записи здесь доступны как task-{id}.ics.
+CREATE TABLE CalDAVObjects (
+                               task_id INT PRIMARY KEY REFERENCES Tasks(task_id) ON DELETE CASCADE,
+                               href VARCHAR(255) NOT NULL UNIQUE,
+                               uid VARCHAR(255) NOT NULL,
+                               created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
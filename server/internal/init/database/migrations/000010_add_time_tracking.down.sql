@@ -0,0 +1,4 @@
+-- 010_add_time_tracking_down.sql
+
+DROP TABLE IF EXISTS TimeEntries;
+ALTER TABLE Tasks DROP COLUMN IF EXISTS estimate_minutes;
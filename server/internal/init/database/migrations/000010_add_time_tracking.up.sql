@@ -0,0 +1,23 @@
+-- 010_add_time_tracking_up.sql
+
+-- Оценка трудоемкости задачи в минутах (NULL - не задана)
+ALTER TABLE Tasks ADD COLUMN estimate_minutes INT CHECK (estimate_minutes > 0);
+
+-- Записи учтенного времени. Запущенный таймер - запись с ended_at = NULL;
+-- ручная запись сразу получает ended_at = started_at + длительность.
+CREATE TABLE TimeEntries (
+                             time_entry_id SERIAL PRIMARY KEY,
+                             task_id INT NOT NULL REFERENCES Tasks(task_id) ON DELETE CASCADE,
+                             user_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+                             started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                             ended_at TIMESTAMP WITH TIME ZONE,
+                             note TEXT,
+                             created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+                             CONSTRAINT chk_time_entry_range CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+CREATE INDEX idx_time_entries_task ON TimeEntries(task_id, started_at);
+CREATE INDEX idx_time_entries_user_started ON TimeEntries(user_id, started_at);
+
+-- У пользователя не больше одного запущенного таймера
+CREATE UNIQUE INDEX idx_time_entries_running ON TimeEntries(user_id) WHERE ended_at IS NULL;
@@ -0,0 +1,4 @@
+-- 011_add_task_assignees_watchers_down.sql
+
+DROP TABLE IF EXISTS TaskWatchers;
+DROP TABLE IF EXISTS TaskAssignees;
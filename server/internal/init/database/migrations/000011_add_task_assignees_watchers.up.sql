@@ -0,0 +1,25 @@
+-- 011_add_task_assignees_watchers_up.sql
+
+-- Исполнители задачи. Tasks.assigned_to_user_id остается основным исполнителем
+-- для обратной совместимости и всегда входит в этот список.
+CREATE TABLE TaskAssignees (
+                               task_id INT NOT NULL REFERENCES Tasks(task_id) ON DELETE CASCADE,
+                               user_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+                               assigned_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+                               PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX idx_task_assignees_user ON TaskAssignees(user_id);
+
+INSERT INTO TaskAssignees (task_id, user_id)
+SELECT task_id, assigned_to_user_id FROM Tasks WHERE assigned_to_user_id IS NOT NULL;
+
+-- Наблюдатели задачи: получают уведомления об изменениях, но не отвечают за задачу
+CREATE TABLE TaskWatchers (
+                              task_id INT NOT NULL REFERENCES Tasks(task_id) ON DELETE CASCADE,
+                              user_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+                              created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+                              PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX idx_task_watchers_user ON TaskWatchers(user_id);
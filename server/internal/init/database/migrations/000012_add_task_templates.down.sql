@@ -0,0 +1,4 @@
+-- 012_add_task_templates_down.sql
+
+DROP TRIGGER IF EXISTS trigger_task_templates_updated_at ON TaskTemplates;
+DROP TABLE IF EXISTS TaskTemplates;
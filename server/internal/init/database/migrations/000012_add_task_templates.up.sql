@@ -0,0 +1,18 @@
+-- 012_add_task_templates_up.sql
+
+-- Шаблоны задач. team_id = NULL - личный шаблон владельца; иначе - шаблон команды.
+-- content хранит поля задачи, чек-лист, смещение дедлайна и подзадачи; текст может содержать {{переменные}}.
+CREATE TABLE TaskTemplates (
+                               template_id SERIAL PRIMARY KEY,
+                               owner_user_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+                               team_id INT REFERENCES Teams(team_id) ON DELETE CASCADE,
+                               name VARCHAR(100) NOT NULL,
+                               content JSONB NOT NULL DEFAULT '{}'::jsonb,
+                               created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+                               updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_task_templates_owner ON TaskTemplates(owner_user_id) WHERE team_id IS NULL;
+CREATE INDEX idx_task_templates_team ON TaskTemplates(team_id) WHERE team_id IS NOT NULL;
+
+CREATE TRIGGER trigger_task_templates_updated_at BEFORE UPDATE ON TaskTemplates FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,3 @@
+-- 013_add_team_invites_down.sql
+
+DROP TABLE IF EXISTS TeamInvites;
@@ -0,0 +1,20 @@
+-- 013_add_team_invites_up.sql
+
+-- Приглашения в команду. Ссылка содержит секретный токен, в БД хранится только его SHA-256.
+-- max_uses = NULL - без ограничения числа вступлений; target_email - приглашение для конкретного адреса,
+-- по нему может вступить только пользователь с этим подтвержденным email.
+CREATE TABLE TeamInvites (
+                             invite_id SERIAL PRIMARY KEY,
+                             team_id INT NOT NULL REFERENCES Teams(team_id) ON DELETE CASCADE,
+                             token_hash CHAR(64) NOT NULL UNIQUE,
+                             created_by_user_id INT REFERENCES Users(user_id) ON DELETE SET NULL,
+                             role team_member_role NOT NULL DEFAULT 'member',
+                             max_uses INT CHECK (max_uses > 0),
+                             use_count INT NOT NULL DEFAULT 0,
+                             target_email VARCHAR(100),
+                             expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                             revoked_at TIMESTAMP WITH TIME ZONE,
+                             created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_team_invites_team ON TeamInvites(team_id) WHERE revoked_at IS NULL;
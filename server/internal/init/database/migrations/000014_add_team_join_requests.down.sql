@@ -0,0 +1,9 @@
+-- 014_add_team_join_requests_down.sql
+
+DROP TABLE IF EXISTS TeamJoinRequests;
+
+ALTER TABLE Teams
+    DROP COLUMN IF EXISTS invite_approval_required,
+    DROP COLUMN IF EXISTS join_email_domain,
+    DROP COLUMN IF EXISTS slug,
+    DROP COLUMN IF EXISTS join_policy;
@@ -0,0 +1,30 @@
+-- 014_add_team_join_requests_up.sql
+
+-- Политика вступления в команду:
+-- invite_only - только по приглашению; request - по заявке, которую одобряет администратор;
+-- open_within_domain - пользователи с подтвержденным email в домене join_email_domain вступают сразу.
+-- slug - публичный идентификатор команды для заявок на вступление.
+-- invite_approval_required - вступление по приглашению (кроме приглашений на email) тоже проходит через заявку.
+ALTER TABLE Teams
+    ADD COLUMN join_policy VARCHAR(20) NOT NULL DEFAULT 'invite_only'
+        CHECK (join_policy IN ('invite_only', 'request', 'open_within_domain')),
+    ADD COLUMN slug VARCHAR(50) UNIQUE,
+    ADD COLUMN join_email_domain VARCHAR(100),
+    ADD COLUMN invite_approval_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Заявки на вступление в команду. invite_id - заявка создана при вступлении по приглашению.
+CREATE TABLE TeamJoinRequests (
+                                  request_id SERIAL PRIMARY KEY,
+                                  team_id INT NOT NULL REFERENCES Teams(team_id) ON DELETE CASCADE,
+                                  user_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+                                  invite_id INT REFERENCES TeamInvites(invite_id) ON DELETE SET NULL,
+                                  role team_member_role NOT NULL DEFAULT 'member',
+                                  message VARCHAR(500),
+                                  status VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+                                  decided_by_user_id INT REFERENCES Users(user_id) ON DELETE SET NULL,
+                                  decided_at TIMESTAMP WITH TIME ZONE,
+                                  created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- У пользователя может быть только одна ожидающая заявка в команду
+CREATE UNIQUE INDEX idx_team_join_requests_pending ON TeamJoinRequests(team_id, user_id) WHERE status = 'pending';
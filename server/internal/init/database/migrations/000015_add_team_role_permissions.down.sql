@@ -0,0 +1,3 @@
+-- 015_add_team_role_permissions_down.sql
+
+DROP TABLE IF EXISTS TeamRolePermissions;
@@ -0,0 +1,11 @@
+-- 015_add_team_role_permissions_up.sql
+
+-- Переопределения прав ролей в команде. Права по умолчанию заданы в коде,
+-- здесь хранятся только отличия конкретной команды. Права владельца не настраиваются.
+CREATE TABLE TeamRolePermissions (
+                                     team_id INT NOT NULL REFERENCES Teams(team_id) ON DELETE CASCADE,
+                                     role team_member_role NOT NULL CHECK (role <> 'owner'),
+                                     permission VARCHAR(50) NOT NULL,
+                                     allowed BOOLEAN NOT NULL,
+                                     PRIMARY KEY (team_id, role, permission)
+);
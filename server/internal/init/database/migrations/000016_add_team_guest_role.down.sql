@@ -0,0 +1,12 @@
+-- 016_add_team_guest_role_down.sql
+
+-- PostgreSQL не умеет удалять значение из enum, поэтому 'guest' остается в типе,
+-- а гости удаляются из команд (повышать их до member небезопасно)
+DELETE FROM UserTeamMemberships WHERE role = 'guest';
+DELETE FROM TeamInvites WHERE role = 'guest';
+DELETE FROM TeamJoinRequests WHERE role = 'guest';
+DELETE FROM TeamStatusTransitions WHERE role = 'guest';
+
+ALTER TABLE Teams
+    DROP COLUMN IF EXISTS guest_chat_enabled,
+    DROP COLUMN IF EXISTS guest_tag_id;
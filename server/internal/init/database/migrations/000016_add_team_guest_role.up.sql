@@ -0,0 +1,11 @@
+-- 016_add_team_guest_role_up.sql
+
+-- Гость - участник только для чтения: видит задачи команды, но не создает и не меняет их
+ALTER TYPE team_member_role ADD VALUE IF NOT EXISTS 'guest';
+
+-- guest_tag_id - гости видят только задачи с этим тегом команды (NULL - все задачи).
+-- Внешнего ключа нет намеренно: после удаления тега гости не видят ни одной задачи, а не получают доступ ко всем.
+-- guest_chat_enabled - доступ гостей к чату команды.
+ALTER TABLE Teams
+    ADD COLUMN guest_tag_id INT,
+    ADD COLUMN guest_chat_enabled BOOLEAN NOT NULL DEFAULT FALSE;
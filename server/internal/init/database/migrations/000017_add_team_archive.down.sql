@@ -0,0 +1,5 @@
+-- 017_add_team_archive_down.sql
+
+ALTER TABLE Teams
+    DROP COLUMN IF EXISTS archived_at,
+    DROP COLUMN IF EXISTS is_archived;
@@ -0,0 +1,7 @@
+-- 017_add_team_archive_up.sql
+
+-- Архивная команда доступна только для чтения: задачи, теги и чат не меняются,
+-- а в списке команд пользователя она скрыта, пока не запрошены архивные.
+ALTER TABLE Teams
+    ADD COLUMN is_archived BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,5 @@
+-- 018_add_team_projects_down.sql
+
+DROP INDEX IF EXISTS idx_tasks_project;
+ALTER TABLE Tasks DROP COLUMN IF EXISTS project_id;
+DROP TABLE IF EXISTS TeamProjects;
@@ -0,0 +1,28 @@
+-- 018_add_team_projects_up.sql
+
+-- Проекты внутри команды. Задача команды может входить в один проект;
+-- при удалении проекта задачи остаются в команде без проекта.
+CREATE TABLE TeamProjects (
+                              project_id SERIAL PRIMARY KEY,
+                              team_id INT NOT NULL REFERENCES Teams(team_id) ON DELETE CASCADE,
+                              name VARCHAR(100) NOT NULL,
+                              description TEXT,
+                              color VARCHAR(7),
+                              status VARCHAR(20) NOT NULL DEFAULT 'planned'
+                                  CHECK (status IN ('planned', 'active', 'paused', 'completed', 'cancelled')),
+                              start_date DATE,
+                              target_date DATE,
+                              lead_user_id INT REFERENCES Users(user_id) ON DELETE SET NULL,
+                              created_by_user_id INT REFERENCES Users(user_id) ON DELETE SET NULL,
+                              created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+                              updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+                              CHECK (start_date IS NULL OR target_date IS NULL OR start_date <= target_date)
+);
+
+CREATE INDEX idx_team_projects_team ON TeamProjects(team_id);
+
+CREATE TRIGGER trigger_team_projects_updated_at BEFORE UPDATE ON TeamProjects FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE Tasks ADD COLUMN project_id INT REFERENCES TeamProjects(project_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tasks_project ON Tasks(project_id) WHERE project_id IS NOT NULL;
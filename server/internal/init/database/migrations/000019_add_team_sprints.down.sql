@@ -0,0 +1,7 @@
+-- 019_add_team_sprints_down.sql
+
+DROP INDEX IF EXISTS idx_task_events_sprint_old;
+DROP INDEX IF EXISTS idx_task_events_sprint_new;
+DROP INDEX IF EXISTS idx_tasks_sprint;
+ALTER TABLE Tasks DROP COLUMN IF EXISTS sprint_id;
+DROP TABLE IF EXISTS TeamSprints;
@@ -0,0 +1,33 @@
+-- 019_add_team_sprints_up.sql
+
+-- Спринты команды: ограниченные по времени итерации. В команде одновременно активен не более одного спринта.
+-- При закрытии спринта незавершенные задачи переносятся в следующий спринт или в бэклог (sprint_id = NULL);
+-- переносы пишутся в TaskEvents, по ним строится burndown.
+CREATE TABLE TeamSprints (
+                             sprint_id SERIAL PRIMARY KEY,
+                             team_id INT NOT NULL REFERENCES Teams(team_id) ON DELETE CASCADE,
+                             name VARCHAR(100) NOT NULL,
+                             goal TEXT,
+                             start_date DATE NOT NULL,
+                             end_date DATE NOT NULL,
+                             status VARCHAR(20) NOT NULL DEFAULT 'planned'
+                                 CHECK (status IN ('planned', 'active', 'closed')),
+                             closed_at TIMESTAMP WITH TIME ZONE,
+                             created_by_user_id INT REFERENCES Users(user_id) ON DELETE SET NULL,
+                             created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+                             updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+                             CHECK (start_date <= end_date)
+);
+
+CREATE INDEX idx_team_sprints_team ON TeamSprints(team_id, start_date);
+CREATE UNIQUE INDEX uq_team_sprints_active ON TeamSprints(team_id) WHERE status = 'active';
+
+CREATE TRIGGER trigger_team_sprints_updated_at BEFORE UPDATE ON TeamSprints FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE Tasks ADD COLUMN sprint_id INT REFERENCES TeamSprints(sprint_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tasks_sprint ON Tasks(sprint_id) WHERE sprint_id IS NOT NULL;
+
+-- Поиск задач, которые входили в спринт раньше (для burndown закрытых спринтов)
+CREATE INDEX idx_task_events_sprint_new ON TaskEvents(new_value) WHERE field_name = 'sprint_id';
+CREATE INDEX idx_task_events_sprint_old ON TaskEvents(old_value) WHERE field_name = 'sprint_id';
@@ -0,0 +1,3 @@
+-- 020_add_team_ownership_transfers_down.sql
+
+DROP TABLE IF EXISTS TeamOwnershipTransfers;
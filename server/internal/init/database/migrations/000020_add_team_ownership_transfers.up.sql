@@ -0,0 +1,14 @@
+-- 020_add_team_ownership_transfers_up.sql
+
+-- Ожидающая передача владения командой (раньше хранилась только в Redis и терялась при его очистке).
+-- У команды не больше одной заявки: новая заменяет прежнюю. Истекшая заявка не действует.
+CREATE TABLE TeamOwnershipTransfers (
+                                        team_id INT PRIMARY KEY REFERENCES Teams(team_id) ON DELETE CASCADE,
+                                        from_user_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+                                        to_user_id INT NOT NULL REFERENCES Users(user_id) ON DELETE CASCADE,
+                                        created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+                                        expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+                                        CHECK (from_user_id <> to_user_id)
+);
+
+CREATE INDEX idx_team_ownership_transfers_to_user ON TeamOwnershipTransfers(to_user_id);
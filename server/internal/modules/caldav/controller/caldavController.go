@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/httprate"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+
+	"server/internal/modules/caldav"
+	resp "server/pkg/lib/response"
+)
+
+func init() {
+	// chi отвечает 405 на методы, которых не знает, еще до поиска маршрута
+	chi.RegisterMethod("PROPFIND")
+	chi.RegisterMethod("PROPPATCH")
+	chi.RegisterMethod("REPORT")
+	chi.RegisterMethod("MKCALENDAR")
+	chi.RegisterMethod("MKCOL")
+	chi.RegisterMethod("MOVE")
+	chi.RegisterMethod("COPY")
+}
+
+// Неудачные проверки пароля приложения ограничиваются для пары "имя пользователя + IP" и для IP целиком.
+// Лимит не привязан к одному имени: иначе любой мог бы заблокировать синхронизацию выбранного пользователя,
+// отправляя неверные пароли для его логина. Адрес клиента за прокси подставляет middleware realip.
+const (
+	authFailuresPerLogin = 10
+	authFailuresPerIP    = 30
+	authFailureWindow    = 15 * time.Minute
+)
+
+type caldavController struct {
+	useCase      caldav.UseCase
+	log          *slog.Logger
+	validate     *validator.Validate
+	appURL       string                // Адрес фронтенда для ссылок на задачи
+	loginLimiter *httprate.RateLimiter // Неудачные попытки входа по имени пользователя с одного IP
+	ipLimiter    *httprate.RateLimiter // Неудачные попытки входа по IP
+}
+
+func NewController(log *slog.Logger, uc caldav.UseCase, appURL string) caldav.Controller {
+	return &caldavController{
+		useCase:      uc,
+		log:          log,
+		validate:     validator.New(),
+		appURL:       strings.TrimSuffix(appURL, "/"),
+		loginLimiter: httprate.NewRateLimiter(authFailuresPerLogin, authFailureWindow),
+		ipLimiter:    httprate.NewRateLimiter(authFailuresPerIP, authFailureWindow),
+	}
+}
+
+// GetAppPasswords
+// @Summary List app passwords
+// @Tags caldav
+// @Description Returns the user's app passwords for CalDAV clients. Passwords themselves are not returned.
+// @Produce json
+// @Success 200 {object} response.SuccessResponse{data=[]caldav.AppPasswordResponse} "App passwords retrieved successfully"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /app-passwords [get]
+// @Security ApiKeyAuth
+func (c *caldavController) GetAppPasswords(w http.ResponseWriter, r *http.Request) {
+	op := "caldavController.GetAppPasswords"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	passwords, err := c.useCase.GetAppPasswords(userID)
+	if err != nil {
+		log.Error("usecase GetAppPasswords failed", "error", err, "userID", userID)
+		resp.SendError(w, r, http.StatusInternalServerError, "Failed to retrieve app passwords")
+		return
+	}
+	resp.SendSuccess(w, r, http.StatusOK, passwords)
+}
+
+// CreateAppPassword
+// @Summary Create an app password
+// @Tags caldav
+// @Description Creates a password for a CalDAV client (username is the login or email). The password is shown only in this response.
+// @Accept json
+// @Produce json
+// @Param appPassword body caldav.CreateAppPasswordRequest true "App password name"
+// @Success 201 {object} response.SuccessResponse{data=caldav.AppPasswordResponse} "App password with the plaintext password"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload or validation error"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /app-passwords [post]
+// @Security ApiKeyAuth
+func (c *caldavController) CreateAppPassword(w http.ResponseWriter, r *http.Request) {
+	op := "caldavController.CreateAppPassword"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	var req caldav.CreateAppPasswordRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	password, err := c.useCase.CreateAppPassword(userID, req)
+	if err != nil {
+		log.Error("usecase CreateAppPassword failed", "error", err)
+		resp.SendError(w, r, http.StatusInternalServerError, "Failed to create app password")
+		return
+	}
+	resp.SendSuccess(w, r, http.StatusCreated, password)
+}
+
+// DeleteAppPassword
+// @Summary Revoke an app password
+// @Tags caldav
+// @Description Deletes the app password; clients using it can no longer sign in.
+// @Produce json
+// @Param appPasswordID path int true "App password ID"
+// @Success 204 "App password revoked"
+// @Failure 400 {object} response.ErrorResponse "Invalid app password ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 404 {object} response.ErrorResponse "App password not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /app-passwords/{appPasswordID} [delete]
+// @Security ApiKeyAuth
+func (c *caldavController) DeleteAppPassword(w http.ResponseWriter, r *http.Request) {
+	op := "caldavController.DeleteAppPassword"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	appPasswordID, err := strconv.ParseUint(chi.URLParam(r, "appPasswordID"), 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid app password ID")
+		return
+	}
+
+	if err := c.useCase.DeleteAppPassword(uint(appPasswordID), userID); err != nil {
+		switch {
+		case errors.Is(err, caldav.ErrAppPasswordNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		default:
+			log.Error("usecase DeleteAppPassword failed", "error", err, "appPasswordID", appPasswordID)
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to revoke app password")
+		}
+		return
+	}
+	resp.SendOK(w, r, http.StatusNoContent)
+}
+
+// BasicAuth аутентифицирует CalDAV-клиента по логину (или email) и паролю приложения.
+// OPTIONS пропускается без аутентификации: клиенты выясняют возможности сервера до входа.
+// После authFailuresPerLogin неудачных попыток для имени с одного адреса или authFailuresPerIP для адреса
+// проверка пароля не выполняется до конца окна authFailureWindow, клиент получает 429.
+func (c *caldavController) BasicAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op := "caldavController.BasicAuth"
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		username, password, ok := r.BasicAuth()
+		if !ok {
+			requireAuth(w)
+			return
+		}
+		ipKey, _ := httprate.KeyByIP(r)
+		loginKey := strings.ToLower(username) + "|" + ipKey
+		if c.authLimited(c.loginLimiter, loginKey) || c.authLimited(c.ipLimiter, ipKey) {
+			c.log.Warn("too many failed caldav authentication attempts", slog.String("op", op), slog.String("ip", ipKey))
+			w.Header().Set("Retry-After", strconv.Itoa(int(authFailureWindow.Seconds())))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		userID, err := c.useCase.Authenticate(username, password)
+		if err != nil {
+			if !errors.Is(err, caldav.ErrInvalidCredentials) {
+				c.log.Error("caldav authentication failed", slog.String("op", op), "error", err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			c.recordAuthFailure(c.loginLimiter, loginKey)
+			c.recordAuthFailure(c.ipLimiter, ipKey)
+			requireAuth(w)
+			return
+		}
+		ctx := context.WithValue(r.Context(), "userId", userID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authLimited сообщает, исчерпан ли лимит неудачных попыток для ключа.
+func (c *caldavController) authLimited(limiter *httprate.RateLimiter, key string) bool {
+	allowed, _, err := limiter.Status(key)
+	if err != nil {
+		c.log.Warn("failed to check caldav authentication limit", "error", err)
+		return false
+	}
+	return !allowed
+}
+
+// recordAuthFailure засчитывает ключу неудачную попытку входа.
+func (c *caldavController) recordAuthFailure(limiter *httprate.RateLimiter, key string) {
+	window := time.Now().UTC().Truncate(authFailureWindow)
+	if err := limiter.Counter().IncrementBy(key, window, 1); err != nil {
+		c.log.Warn("failed to record caldav authentication failure", "error", err)
+	}
+}
+
+func requireAuth(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Basic realm="ToDoApp CalDAV", charset="UTF-8"`)
+	w.WriteHeader(http.StatusUnauthorized)
+}
+
+// WellKnown направляет клиентов, настроенных только по адресу сервера, к корню CalDAV (RFC 6764).
+func (c *caldavController) WellKnown(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, davRoot+"/", http.StatusMovedPermanently)
+}
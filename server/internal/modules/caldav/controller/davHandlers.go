@@ -0,0 +1,526 @@
+package controller
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"server/internal/modules/caldav"
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+	"server/pkg/lib/ical"
+)
+
+const (
+	davRoot        = "/caldav"
+	principalPath  = davRoot + "/principal/"
+	calendarsPath  = davRoot + "/calendars/"
+	calendarProdID = "-//ToDoApp//Tasks//RU"
+	davAllow       = "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, PROPPATCH, REPORT"
+	maxDAVBodySize = 1 << 20 // 1MB - и для XML-запросов, и для VTODO
+	calendarCType  = "text/calendar; charset=utf-8"
+)
+
+type davResourceKind int
+
+const (
+	resourceRoot davResourceKind = iota
+	resourcePrincipal
+	resourceHome
+	resourceCalendar
+	resourceObject
+)
+
+// davPath - разобранный путь: /caldav/, /caldav/principal/, /caldav/calendars/{calendar}/{object}
+type davPath struct {
+	kind     davResourceKind
+	calendar string
+	object   string
+}
+
+func parseDAVPath(p string) (davPath, bool) {
+	rest, ok := strings.CutPrefix(p, davRoot)
+	if !ok {
+		return davPath{}, false
+	}
+	rest = strings.Trim(rest, "/")
+	if rest == "" {
+		return davPath{kind: resourceRoot}, true
+	}
+	parts := strings.Split(rest, "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "principal":
+		return davPath{kind: resourcePrincipal}, true
+	case parts[0] != "calendars":
+		return davPath{}, false
+	case len(parts) == 1:
+		return davPath{kind: resourceHome}, true
+	case len(parts) == 2:
+		return davPath{kind: resourceCalendar, calendar: parts[1]}, true
+	case len(parts) == 3:
+		return davPath{kind: resourceObject, calendar: parts[1], object: parts[2]}, true
+	}
+	return davPath{}, false
+}
+
+func calendarHref(name string) string {
+	return calendarsPath + url.PathEscape(name) + "/"
+}
+
+func objectHref(calendar, name string) string {
+	return calendarHref(calendar) + url.PathEscape(name)
+}
+
+// davDepth возвращает глубину PROPFIND: 0 или 1 (infinity обрабатывается как 1).
+func davDepth(r *http.Request) int {
+	if r.Header.Get("Depth") == "0" {
+		return 0
+	}
+	return 1
+}
+
+// ServeDAV обрабатывает запросы CalDAV (RFC 4791): календарь личных задач и календари команд с задачами в виде VTODO.
+// Изменения выполняются через сервис задач с теми же проверками прав, что и в REST API.
+func (c *caldavController) ServeDAV(w http.ResponseWriter, r *http.Request) {
+	op := "caldavController.ServeDAV"
+	log := c.log.With(slog.String("op", op), slog.String("method", r.Method), slog.String("path", r.URL.Path))
+
+	w.Header().Set("DAV", "1, 3, calendar-access")
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Allow", davAllow)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		requireAuth(w)
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	path, ok := parseDAVPath(r.URL.Path)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var err error
+	switch r.Method {
+	case "PROPFIND":
+		err = c.propfind(w, r, userID, path)
+	case "PROPPATCH":
+		err = c.proppatch(w, r, path)
+	case "REPORT":
+		err = c.report(w, r, userID, path)
+	case http.MethodGet, http.MethodHead:
+		err = c.get(w, r, userID, path)
+	case http.MethodPut:
+		err = c.put(w, r, userID, path)
+	case http.MethodDelete:
+		err = c.delete(w, r, userID, path)
+	default:
+		w.Header().Set("Allow", davAllow)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		c.writeError(w, log, err)
+	}
+}
+
+// writeError переводит ошибки модулей в статусы WebDAV.
+func (c *caldavController) writeError(w http.ResponseWriter, log *slog.Logger, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, caldav.ErrCalendarNotFound), errors.Is(err, caldav.ErrObjectNotFound), errors.Is(err, task.ErrTaskNotFound):
+		writeDAVError(w, http.StatusNotFound, "")
+	case errors.Is(err, caldav.ErrPreconditionFailed):
+		writeDAVError(w, http.StatusPreconditionFailed, "")
+	case errors.Is(err, caldav.ErrInvalidObject):
+		log.Warn("invalid calendar object", "error", err)
+		writeDAVError(w, http.StatusBadRequest, "c:valid-calendar-data")
+	case errors.Is(err, caldav.ErrUnsupportedComponent):
+		writeDAVError(w, http.StatusForbidden, "c:supported-calendar-component")
+	case errors.Is(err, caldav.ErrObjectNameConflict):
+		writeDAVError(w, http.StatusConflict, "c:no-uid-conflict")
+	case errors.Is(err, task.ErrTaskAccessDenied), errors.Is(err, caldav.ErrCalendarReadOnly):
+		writeDAVError(w, http.StatusForbidden, "d:need-privileges")
+	case errors.Is(err, task.ErrTaskInvalidStatusTransition), errors.Is(err, task.ErrTaskUnknownStatus),
+		errors.Is(err, task.ErrTaskAlreadyDeleted):
+		log.Warn("task change rejected", "error", err)
+		writeDAVError(w, http.StatusConflict, "")
+	case errors.Is(err, task.ErrTaskInvalidInput):
+		writeDAVError(w, http.StatusBadRequest, "")
+	case errors.As(err, &maxBytesErr):
+		writeDAVError(w, http.StatusRequestEntityTooLarge, "")
+	default:
+		log.Error("caldav request failed", "error", err)
+		writeDAVError(w, http.StatusInternalServerError, "")
+	}
+}
+
+// --- Свойства ресурсов ---
+
+func principalProps() []davProp {
+	return []davProp{
+		{propCurrentUserPrincipal, hrefXML(principalPath)},
+		{propPrincipalURL, hrefXML(principalPath)},
+		{propCalendarHomeSet, hrefXML(calendarsPath)},
+	}
+}
+
+func rootProps() []davProp {
+	return append([]davProp{{propResourceType, "<d:collection/>"}, {propDisplayName, "ToDoApp"}}, principalProps()...)
+}
+
+func principalResourceProps() []davProp {
+	return append([]davProp{{propResourceType, "<d:collection/><d:principal/>"}, {propDisplayName, "ToDoApp"}}, principalProps()...)
+}
+
+func homeProps() []davProp {
+	return append([]davProp{
+		{propResourceType, "<d:collection/>"},
+		{propDisplayName, "Calendars"},
+		{propOwner, hrefXML(principalPath)},
+	}, principalProps()...)
+}
+
+// calendarProps возвращает свойства календаря. В календаре архивной команды у пользователя есть только право чтения.
+func calendarProps(cal *caldav.Calendar) []davProp {
+	privileges := "<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>" +
+		"<d:privilege><d:write-content/></d:privilege><d:privilege><d:bind/></d:privilege><d:privilege><d:unbind/></d:privilege>"
+	if cal.ReadOnly {
+		privileges = "<d:privilege><d:read/></d:privilege>"
+	}
+	return []davProp{
+		{propResourceType, "<d:collection/><c:calendar/>"},
+		{propDisplayName, escapeXML(cal.DisplayName)},
+		{propGetCTag, escapeXML(cal.CTag)},
+		{propSupportedComponents, `<c:comp name="VTODO"/>`},
+		{propSupportedReports, "<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>" +
+			"<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>"},
+		{propCurrentUserPrivileges, privileges},
+		{propOwner, hrefXML(principalPath)},
+		{propCurrentUserPrincipal, hrefXML(principalPath)},
+	}
+}
+
+// objectProps возвращает свойства задачи; calendar-data формируется, только если его запросили.
+func (c *caldavController) objectProps(obj *caldav.CalendarObject, host string, withData bool) []davProp {
+	props := []davProp{
+		{propResourceType, ""},
+		{propGetETag, escapeXML(obj.ETag)},
+		{propGetContentType, calendarCType + "; component=vtodo"},
+	}
+	if withData {
+		props = append(props, davProp{propCalendarData, escapeXML(c.renderObjects([]*caldav.CalendarObject{obj}, host))})
+	}
+	return props
+}
+
+// --- Методы ---
+
+func (c *caldavController) propfind(w http.ResponseWriter, r *http.Request, userID uint, path davPath) error {
+	var body propfindBody
+	empty, err := decodeDAVBody(w, r, &body)
+	if err != nil {
+		writeDAVError(w, http.StatusBadRequest, "")
+		return nil
+	}
+	var requested []xml.Name // nil - allprop
+	if !empty && body.AllProp == nil && body.Prop != nil {
+		requested = body.Prop.list()
+	}
+	depth := davDepth(r)
+
+	var responses []davResponse
+	add := func(href string, props []davProp) {
+		responses = append(responses, davResponse{href: href, propstats: selectProps(props, requested)})
+	}
+	switch path.kind {
+	case resourceRoot:
+		add(davRoot+"/", rootProps())
+		if depth > 0 {
+			add(principalPath, principalResourceProps())
+			add(calendarsPath, homeProps())
+		}
+	case resourcePrincipal:
+		add(principalPath, principalResourceProps())
+	case resourceHome:
+		add(calendarsPath, homeProps())
+		if depth > 0 {
+			calendars, err := c.useCase.GetCalendars(userID)
+			if err != nil {
+				return err
+			}
+			for _, cal := range calendars {
+				add(calendarHref(cal.Name), calendarProps(cal))
+			}
+		}
+	case resourceCalendar:
+		cal, err := c.useCase.GetCalendar(userID, path.calendar)
+		if err != nil {
+			return err
+		}
+		add(calendarHref(cal.Name), calendarProps(cal))
+		if depth > 0 {
+			objects, err := c.useCase.GetObjects(userID, cal)
+			if err != nil {
+				return err
+			}
+			withData := hasProp(requested, propCalendarData)
+			for _, obj := range objects {
+				add(objectHref(cal.Name, obj.Name), c.objectProps(obj, r.Host, withData))
+			}
+		}
+	case resourceObject:
+		cal, err := c.useCase.GetCalendar(userID, path.calendar)
+		if err != nil {
+			return err
+		}
+		obj, err := c.useCase.GetObject(userID, cal, path.object)
+		if err != nil {
+			return err
+		}
+		add(objectHref(cal.Name, obj.Name), c.objectProps(obj, r.Host, hasProp(requested, propCalendarData)))
+	}
+	writeMultistatus(w, responses)
+	return nil
+}
+
+// proppatch отклоняет изменение свойств: названия календарей берутся из команд, а цвет и прочие
+// настройки клиента сервер не хранит. Клиенты ожидают ответ multistatus, а не 405.
+func (c *caldavController) proppatch(w http.ResponseWriter, r *http.Request, path davPath) error {
+	var body proppatchBody
+	if _, err := decodeDAVBody(w, r, &body); err != nil {
+		writeDAVError(w, http.StatusBadRequest, "")
+		return nil
+	}
+	denied := davPropStat{status: http.StatusForbidden}
+	for _, set := range body.Set {
+		for _, name := range set.Prop.list() {
+			denied.props = append(denied.props, davProp{name: name})
+		}
+	}
+	for _, remove := range body.Remove {
+		for _, name := range remove.Prop.list() {
+			denied.props = append(denied.props, davProp{name: name})
+		}
+	}
+	writeMultistatus(w, []davResponse{{href: r.URL.Path, propstats: []davPropStat{denied}}})
+	return nil
+}
+
+// report обрабатывает calendar-multiget и calendar-query по календарю.
+func (c *caldavController) report(w http.ResponseWriter, r *http.Request, userID uint, path davPath) error {
+	var body reportBody
+	if _, err := decodeDAVBody(w, r, &body); err != nil {
+		writeDAVError(w, http.StatusBadRequest, "")
+		return nil
+	}
+	if body.XMLName.Space != nsCalDAV || (body.XMLName.Local != "calendar-multiget" && body.XMLName.Local != "calendar-query") {
+		writeDAVError(w, http.StatusForbidden, "d:supported-report")
+		return nil
+	}
+	if path.kind != resourceCalendar {
+		writeDAVError(w, http.StatusForbidden, "d:supported-report")
+		return nil
+	}
+	cal, err := c.useCase.GetCalendar(userID, path.calendar)
+	if err != nil {
+		return err
+	}
+	requested := body.Prop.list()
+	withData := requested == nil || hasProp(requested, propCalendarData)
+
+	var responses []davResponse
+	if body.XMLName.Local == "calendar-multiget" {
+		for _, href := range body.Hrefs {
+			name := ""
+			if u, errParse := url.Parse(strings.TrimSpace(href)); errParse == nil {
+				if p, ok := parseDAVPath(u.Path); ok && p.kind == resourceObject && p.calendar == cal.Name {
+					name = p.object
+				}
+			}
+			obj, err := c.useCase.GetObject(userID, cal, name)
+			if errors.Is(err, caldav.ErrObjectNotFound) {
+				responses = append(responses, davResponse{href: strings.TrimSpace(href), status: http.StatusNotFound})
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			responses = append(responses, davResponse{
+				href:      objectHref(cal.Name, obj.Name),
+				propstats: selectProps(c.objectProps(obj, r.Host, withData), requested),
+			})
+		}
+	} else if body.matchesTodo() {
+		objects, err := c.useCase.GetObjects(userID, cal)
+		if err != nil {
+			return err
+		}
+		for _, obj := range objects {
+			responses = append(responses, davResponse{
+				href:      objectHref(cal.Name, obj.Name),
+				propstats: selectProps(c.objectProps(obj, r.Host, withData), requested),
+			})
+		}
+	}
+	writeMultistatus(w, responses)
+	return nil
+}
+
+// get отдает задачу как VCALENDAR с одной записью VTODO; для календаря - все его задачи.
+func (c *caldavController) get(w http.ResponseWriter, r *http.Request, userID uint, path davPath) error {
+	if path.kind != resourceCalendar && path.kind != resourceObject {
+		w.Header().Set("Allow", davAllow)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return nil
+	}
+	cal, err := c.useCase.GetCalendar(userID, path.calendar)
+	if err != nil {
+		return err
+	}
+
+	var objects []*caldav.CalendarObject
+	if path.kind == resourceObject {
+		obj, err := c.useCase.GetObject(userID, cal, path.object)
+		if err != nil {
+			return err
+		}
+		w.Header().Set("ETag", obj.ETag)
+		objects = append(objects, obj)
+	} else {
+		if objects, err = c.useCase.GetObjects(userID, cal); err != nil {
+			return err
+		}
+		w.Header().Set("ETag", `"`+cal.CTag+`"`)
+	}
+	w.Header().Set("Content-Type", calendarCType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, c.renderObjects(objects, r.Host))
+	return nil
+}
+
+// put создает или обновляет задачу. ETag в ответе не возвращается: сервер хранит не все поля VTODO,
+// и клиент должен перечитать задачу, чтобы увидеть ее фактическое состояние.
+func (c *caldavController) put(w http.ResponseWriter, r *http.Request, userID uint, path davPath) error {
+	if path.kind != resourceObject {
+		w.Header().Set("Allow", davAllow)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return nil
+	}
+	cal, err := c.useCase.GetCalendar(userID, path.calendar)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxDAVBodySize))
+	if err != nil {
+		return err
+	}
+
+	created, err := c.useCase.PutObject(userID, cal, caldav.PutObjectRequest{
+		Name:        path.object,
+		Data:        data,
+		IfMatch:     r.Header.Get("If-Match"),
+		IfNoneMatch: r.Header.Get("If-None-Match"),
+	})
+	if err != nil {
+		return err
+	}
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	} else {
+		w.WriteHeader(http.StatusNoContent)
+	}
+	return nil
+}
+
+// delete перемещает задачу в корзину. Календари удалять нельзя.
+func (c *caldavController) delete(w http.ResponseWriter, r *http.Request, userID uint, path davPath) error {
+	if path.kind != resourceObject {
+		writeDAVError(w, http.StatusForbidden, "")
+		return nil
+	}
+	cal, err := c.useCase.GetCalendar(userID, path.calendar)
+	if err != nil {
+		return err
+	}
+	if err := c.useCase.DeleteObject(userID, cal, path.object, r.Header.Get("If-Match")); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// --- VTODO ---
+
+// renderObjects формирует VCALENDAR с задачами.
+func (c *caldavController) renderObjects(objects []*caldav.CalendarObject, host string) string {
+	var b strings.Builder
+	cw := ical.NewWriter(&b)
+	cw.Begin(calendarProdID, "")
+	for _, obj := range objects {
+		cw.WriteItem(ical.ComponentTodo, c.toICalItem(obj, host))
+	}
+	_ = cw.End() // strings.Builder не возвращает ошибок записи
+	return b.String()
+}
+
+func (c *caldavController) toICalItem(obj *caldav.CalendarObject, host string) *ical.Item {
+	t := obj.Task
+	item := &ical.Item{
+		UID:          obj.UID,
+		Summary:      t.Title,
+		Status:       icalStatus(t.StatusCategory),
+		Priority:     icalPriority(t.Priority),
+		Due:          t.Deadline,
+		Completed:    t.CompletedAt,
+		Created:      t.CreatedAt,
+		LastModified: t.UpdatedAt,
+	}
+	if item.UID == "" {
+		item.UID = fmt.Sprintf("task-%d@%s", t.TaskID, host)
+	}
+	if t.Description != nil {
+		item.Description = *t.Description
+	}
+	for _, tg := range t.Tags {
+		item.Categories = append(item.Categories, tg.Name)
+	}
+	if c.appURL != "" {
+		item.URL = fmt.Sprintf("%s/task/%d", c.appURL, t.TaskID)
+	}
+	return item
+}
+
+// icalPriority переводит приоритет задачи (1-3) в шкалу iCalendar (1 - высший, 9 - низший).
+func icalPriority(priority int) int {
+	switch priority {
+	case 3:
+		return 1
+	case 2:
+		return 5
+	case 1:
+		return 9
+	}
+	return 0
+}
+
+// icalStatus переводит категорию статуса задачи в STATUS записи VTODO.
+func icalStatus(category string) string {
+	switch team.TaskStatusCategory(category) {
+	case team.StatusCategoryDone:
+		return ical.StatusCompleted
+	case team.StatusCategoryActive:
+		return ical.StatusInProcess
+	}
+	return ical.StatusNeedsAction
+}
@@ -0,0 +1,236 @@
+package controller
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Пространства имен WebDAV (RFC 4918), CalDAV (RFC 4791) и расширений Apple Calendar Server (getctag)
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+)
+
+var davPrefixes = map[string]string{nsDAV: "d", nsCalDAV: "c", nsCS: "cs"}
+
+var (
+	propResourceType          = xml.Name{Space: nsDAV, Local: "resourcetype"}
+	propDisplayName           = xml.Name{Space: nsDAV, Local: "displayname"}
+	propCurrentUserPrincipal  = xml.Name{Space: nsDAV, Local: "current-user-principal"}
+	propPrincipalURL          = xml.Name{Space: nsDAV, Local: "principal-URL"}
+	propOwner                 = xml.Name{Space: nsDAV, Local: "owner"}
+	propCurrentUserPrivileges = xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}
+	propSupportedReports      = xml.Name{Space: nsDAV, Local: "supported-report-set"}
+	propGetETag               = xml.Name{Space: nsDAV, Local: "getetag"}
+	propGetContentType        = xml.Name{Space: nsDAV, Local: "getcontenttype"}
+	propCalendarHomeSet       = xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}
+	propSupportedComponents   = xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}
+	propCalendarData          = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+	propGetCTag               = xml.Name{Space: nsCS, Local: "getctag"}
+)
+
+// --- Тела запросов ---
+
+type davPropNames struct {
+	Names []struct {
+		XMLName xml.Name
+	} `xml:",any"`
+}
+
+func (p *davPropNames) list() []xml.Name {
+	if p == nil {
+		return nil
+	}
+	names := make([]xml.Name, len(p.Names))
+	for i, n := range p.Names {
+		names[i] = n.XMLName
+	}
+	return names
+}
+
+type propfindBody struct {
+	XMLName xml.Name      `xml:"DAV: propfind"`
+	AllProp *struct{}     `xml:"DAV: allprop"`
+	Prop    *davPropNames `xml:"DAV: prop"`
+}
+
+type proppatchBody struct {
+	XMLName xml.Name `xml:"DAV: propertyupdate"`
+	Set     []struct {
+		Prop davPropNames `xml:"DAV: prop"`
+	} `xml:"DAV: set"`
+	Remove []struct {
+		Prop davPropNames `xml:"DAV: prop"`
+	} `xml:"DAV: remove"`
+}
+
+type davCompFilter struct {
+	Name    string          `xml:"name,attr"`
+	Filters []davCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// reportBody - calendar-multiget или calendar-query. Фильтры по времени и свойствам не применяются:
+// клиент получает все задачи календаря и отбирает нужные сам.
+type reportBody struct {
+	XMLName xml.Name
+	Prop    *davPropNames `xml:"DAV: prop"`
+	Hrefs   []string      `xml:"DAV: href"`
+	Filter  *struct {
+		Comp davCompFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	} `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+// matchesTodo проверяет, что фильтр calendar-query допускает записи VTODO.
+func (b *reportBody) matchesTodo() bool {
+	if b.Filter == nil || len(b.Filter.Comp.Filters) == 0 {
+		return true
+	}
+	for _, f := range b.Filter.Comp.Filters {
+		if strings.EqualFold(f.Name, "VTODO") {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeDAVBody разбирает XML-тело запроса. Пустое тело не считается ошибкой.
+func decodeDAVBody(w http.ResponseWriter, r *http.Request, v interface{}) (empty bool, err error) {
+	err = xml.NewDecoder(http.MaxBytesReader(w, r.Body, maxDAVBodySize)).Decode(v)
+	if err == io.EOF {
+		return true, nil
+	}
+	return false, err
+}
+
+// --- Ответ multistatus ---
+
+type davProp struct {
+	name  xml.Name
+	value string // Содержимое элемента в виде XML
+}
+
+type davPropStat struct {
+	status int
+	props  []davProp
+}
+
+type davResponse struct {
+	href      string
+	status    int // Статус ресурса целиком (например, 404 в calendar-multiget); тогда propstat не выводятся
+	propstats []davPropStat
+}
+
+// selectProps раскладывает свойства ресурса по запрошенным именам: найденные - в 200, остальные - в 404.
+// requested == nil означает allprop.
+func selectProps(available []davProp, requested []xml.Name) []davPropStat {
+	if requested == nil {
+		return []davPropStat{{status: http.StatusOK, props: available}}
+	}
+	found := davPropStat{status: http.StatusOK}
+	missing := davPropStat{status: http.StatusNotFound}
+	for _, name := range requested {
+		var prop *davProp
+		for i := range available {
+			if available[i].name == name {
+				prop = &available[i]
+				break
+			}
+		}
+		if prop != nil {
+			found.props = append(found.props, *prop)
+		} else {
+			missing.props = append(missing.props, davProp{name: name})
+		}
+	}
+	var propstats []davPropStat
+	if len(found.props) > 0 {
+		propstats = append(propstats, found)
+	}
+	if len(missing.props) > 0 {
+		propstats = append(propstats, missing)
+	}
+	return propstats
+}
+
+func hasProp(requested []xml.Name, name xml.Name) bool {
+	for _, n := range requested {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func hrefXML(href string) string {
+	return "<d:href>" + escapeXML(href) + "</d:href>"
+}
+
+// writeElement пишет элемент с известным префиксом или, для чужого пространства имен, с собственным xmlns.
+func writeElement(b *strings.Builder, name xml.Name, value string) {
+	tag := name.Local
+	attrs := ""
+	if prefix, ok := davPrefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		tag = "x:" + name.Local
+		attrs = ` xmlns:x="` + escapeXML(name.Space) + `"`
+	}
+	if value == "" {
+		fmt.Fprintf(b, "<%s%s/>", tag, attrs)
+		return
+	}
+	fmt.Fprintf(b, "<%s%s>%s</%s>", tag, attrs, value, tag)
+}
+
+func statusLine(status int) string {
+	return fmt.Sprintf("<d:status>HTTP/1.1 %d %s</d:status>", status, http.StatusText(status))
+}
+
+func writeMultistatus(w http.ResponseWriter, responses []davResponse) {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`)
+	for _, resp := range responses {
+		b.WriteString("<d:response>")
+		b.WriteString(hrefXML(resp.href))
+		if resp.status != 0 {
+			b.WriteString(statusLine(resp.status))
+		}
+		for _, ps := range resp.propstats {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, p := range ps.props {
+				writeElement(&b, p.name, p.value)
+			}
+			b.WriteString("</d:prop>")
+			b.WriteString(statusLine(ps.status))
+			b.WriteString("</d:propstat>")
+		}
+		b.WriteString("</d:response>")
+	}
+	b.WriteString("</d:multistatus>")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	_, _ = io.WriteString(w, b.String())
+}
+
+// writeDAVError отвечает статусом с телом DAV:error, содержащим нарушенное предусловие (например, "c:valid-calendar-data").
+func writeDAVError(w http.ResponseWriter, status int, condition string) {
+	if condition == "" {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, xml.Header+`<d:error xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><`+condition+`/></d:error>`)
+}
@@ -0,0 +1,141 @@
+// internal/modules/caldav/entity.go
+package caldav
+
+import (
+	"net/http"
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+	"time"
+)
+
+// --- GORM Модели ---
+
+// AppPassword - пароль приложения для CalDAV-клиентов. Хранится только SHA-256 пароля.
+type AppPassword struct {
+	AppPasswordID uint       `gorm:"primaryKey;column:app_password_id;autoIncrement"`
+	UserID        uint       `gorm:"column:user_id;not null"`
+	Name          string     `gorm:"type:varchar(100);column:name;not null"`
+	PasswordHash  string     `gorm:"type:char(64);column:password_hash;not null;uniqueIndex"`
+	CreatedAt     time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+	LastUsedAt    *time.Time `gorm:"column:last_used_at"`
+
+	// Только для чтения: логин и email владельца (при поиске пароля для аутентификации)
+	Login *string `gorm:"->;column:login"`
+	Email *string `gorm:"->;column:email"`
+}
+
+func (AppPassword) TableName() string {
+	return "apppasswords"
+}
+
+// CalDAVObject - имя ресурса и UID, под которыми CalDAV-клиент создал задачу
+type CalDAVObject struct {
+	TaskID    uint      `gorm:"primaryKey;column:task_id"`
+	Href      string    `gorm:"type:varchar(255);column:href;not null;uniqueIndex"`
+	UID       string    `gorm:"type:varchar(255);column:uid;not null"`
+	CreatedAt time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (CalDAVObject) TableName() string {
+	return "caldavobjects"
+}
+
+// --- DTO ---
+
+type AppPasswordResponse struct {
+	AppPasswordID uint       `json:"app_password_id"`
+	Name          string     `json:"name"`
+	Password      string     `json:"password,omitempty"` // Только в ответе на создание
+	CreatedAt     time.Time  `json:"created_at"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty"`
+}
+
+func ToAppPasswordResponse(p *AppPassword) *AppPasswordResponse {
+	if p == nil {
+		return nil
+	}
+	return &AppPasswordResponse{
+		AppPasswordID: p.AppPasswordID,
+		Name:          p.Name,
+		CreatedAt:     p.CreatedAt,
+		LastUsedAt:    p.LastUsedAt,
+	}
+}
+
+type CreateAppPasswordRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+// PersonalCalendar - имя календаря личных задач; календари команд называются team-{id}
+const PersonalCalendar = "personal"
+
+// Calendar - коллекция задач CalDAV: личные задачи пользователя или задачи одной команды
+type Calendar struct {
+	Name        string // Имя коллекции в пути
+	TeamID      *uint
+	DisplayName string
+	CTag        string // Меняется при любом изменении задач календаря
+	ReadOnly    bool   // Команда в архиве: задачи можно только читать
+}
+
+// CalendarObject - задача как ресурс календаря
+type CalendarObject struct {
+	Name string // Имя ресурса в календаре (например, task-15.ics)
+	UID  string // UID записи VTODO; пустой, если клиент задачу не создавал (UID строится из TaskID)
+	ETag string
+	Task *task.TaskResponse
+}
+
+// PutObjectRequest - запись VTODO, присланная клиентом
+type PutObjectRequest struct {
+	Name        string
+	Data        []byte
+	IfMatch     string
+	IfNoneMatch string
+}
+
+// --- Интерфейсы ---
+
+// TeamService - то, что модулю нужно от команд: список календарей, набор статусов и роль для правил переходов
+type TeamService interface {
+	GetMyTeams(userID uint, params team.GetMyTeamsRequest) ([]*team.TeamResponse, error)
+	GetTaskWorkflow(teamID uint) (*team.TaskWorkflow, error)
+	GetUserRoleInTeam(userID, teamID uint) (*team.TeamMemberRole, error)
+}
+
+type Controller interface {
+	GetAppPasswords(w http.ResponseWriter, r *http.Request)
+	CreateAppPassword(w http.ResponseWriter, r *http.Request)
+	DeleteAppPassword(w http.ResponseWriter, r *http.Request)
+	BasicAuth(next http.Handler) http.Handler
+	ServeDAV(w http.ResponseWriter, r *http.Request)
+	WellKnown(w http.ResponseWriter, r *http.Request)
+}
+
+type UseCase interface {
+	GetAppPasswords(userID uint) ([]*AppPasswordResponse, error)
+	CreateAppPassword(userID uint, req CreateAppPasswordRequest) (*AppPasswordResponse, error)
+	DeleteAppPassword(appPasswordID uint, userID uint) error
+	Authenticate(username, password string) (uint, error)
+
+	GetCalendars(userID uint) ([]*Calendar, error)
+	GetCalendar(userID uint, name string) (*Calendar, error)
+	GetObjects(userID uint, calendar *Calendar) ([]*CalendarObject, error)
+	GetObject(userID uint, calendar *Calendar, name string) (*CalendarObject, error)
+	PutObject(userID uint, calendar *Calendar, req PutObjectRequest) (created bool, err error)
+	DeleteObject(userID uint, calendar *Calendar, name string, ifMatch string) error
+}
+
+type Repo interface {
+	CreateAppPassword(p *AppPassword) (*AppPassword, error)
+	GetAppPasswords(userID uint) ([]*AppPassword, error)
+	GetAppPasswordByHash(passwordHash string) (*AppPassword, error)
+	DeleteAppPassword(appPasswordID uint, userID uint) error
+	TouchAppPassword(appPasswordID uint) error
+
+	// GetCalendarVersion возвращает число задач календаря и время последнего изменения (включая удаленные в корзину)
+	GetCalendarVersion(userID uint, teamID *uint) (int64, *time.Time, error)
+	GetObjectByHref(href string) (*CalDAVObject, error)
+	GetObjectsByTaskIDs(taskIDs []uint) (map[uint]*CalDAVObject, error)
+	CreateObject(obj *CalDAVObject) error
+}
@@ -0,0 +1,16 @@
+package caldav
+
+import "errors"
+
+var (
+	ErrCalDAVInternal       = errors.New("internal caldav error")
+	ErrInvalidCredentials   = errors.New("invalid username or app password")
+	ErrAppPasswordNotFound  = errors.New("app password not found")
+	ErrCalendarNotFound     = errors.New("calendar not found")
+	ErrObjectNotFound       = errors.New("calendar object not found")
+	ErrInvalidObject        = errors.New("invalid calendar object")
+	ErrPreconditionFailed   = errors.New("precondition failed")
+	ErrObjectNameConflict   = errors.New("calendar object name is already used by another task")
+	ErrUnsupportedComponent = errors.New("only VTODO components are supported")
+	ErrCalendarReadOnly     = errors.New("calendar of an archived team is read-only")
+)
@@ -0,0 +1,163 @@
+// internal/modules/caldav/repo/database/caldavDatabase.go
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"log/slog"
+	"server/internal/modules/caldav"
+	"strings"
+	"time"
+)
+
+type caldavDB struct {
+	db  *gorm.DB
+	log *slog.Logger
+}
+
+func NewDBRepo(db *gorm.DB, log *slog.Logger) caldav.Repo {
+	return &caldavDB{
+		db:  db,
+		log: log,
+	}
+}
+
+func (r *caldavDB) CreateAppPassword(p *caldav.AppPassword) (*caldav.AppPassword, error) {
+	op := "caldavDB.CreateAppPassword"
+	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(p.UserID)))
+
+	if err := r.db.Omit("Login", "Email").Create(p).Error; err != nil {
+		log.Error("failed to create app password", "error", err)
+		return nil, caldav.ErrCalDAVInternal
+	}
+	return p, nil
+}
+
+func (r *caldavDB) GetAppPasswords(userID uint) ([]*caldav.AppPassword, error) {
+	op := "caldavDB.GetAppPasswords"
+	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+	var passwords []*caldav.AppPassword
+
+	if err := r.db.Where("user_id = ?", userID).Order("app_password_id").Find(&passwords).Error; err != nil {
+		log.Error("failed to get app passwords", "error", err)
+		return nil, caldav.ErrCalDAVInternal
+	}
+	return passwords, nil
+}
+
+// GetAppPasswordByHash ищет пароль приложения вместе с логином и email владельца.
+func (r *caldavDB) GetAppPasswordByHash(passwordHash string) (*caldav.AppPassword, error) {
+	op := "caldavDB.GetAppPasswordByHash"
+	log := r.log.With(slog.String("op", op))
+	var p caldav.AppPassword
+
+	err := r.db.Model(&caldav.AppPassword{}).
+		Select("apppasswords.*, users.login, users.email").
+		Joins("JOIN users ON users.user_id = apppasswords.user_id").
+		Where("apppasswords.password_hash = ?", passwordHash).
+		First(&p).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, caldav.ErrInvalidCredentials
+		}
+		log.Error("failed to get app password", "error", err)
+		return nil, caldav.ErrCalDAVInternal
+	}
+	return &p, nil
+}
+
+func (r *caldavDB) DeleteAppPassword(appPasswordID uint, userID uint) error {
+	op := "caldavDB.DeleteAppPassword"
+	log := r.log.With(slog.String("op", op), slog.Uint64("appPasswordID", uint64(appPasswordID)))
+
+	result := r.db.Where("app_password_id = ? AND user_id = ?", appPasswordID, userID).Delete(&caldav.AppPassword{})
+	if result.Error != nil {
+		log.Error("failed to delete app password", "error", result.Error)
+		return caldav.ErrCalDAVInternal
+	}
+	if result.RowsAffected == 0 {
+		return caldav.ErrAppPasswordNotFound
+	}
+	return nil
+}
+
+func (r *caldavDB) TouchAppPassword(appPasswordID uint) error {
+	if err := r.db.Model(&caldav.AppPassword{}).Where("app_password_id = ?", appPasswordID).
+		Update("last_used_at", time.Now()).Error; err != nil {
+		return caldav.ErrCalDAVInternal
+	}
+	return nil
+}
+
+// GetCalendarVersion считает задачи календаря и их последнее изменение. Задачи в корзине не считаются,
+// но участвуют в максимуме updated_at: удаление задачи тоже меняет версию календаря.
+func (r *caldavDB) GetCalendarVersion(userID uint, teamID *uint) (int64, *time.Time, error) {
+	op := "caldavDB.GetCalendarVersion"
+	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	query := r.db.Table("tasks")
+	if teamID != nil {
+		query = query.Where("team_id = ?", *teamID)
+	} else {
+		query = query.Where("team_id IS NULL AND (created_by_user_id = ? OR EXISTS (SELECT 1 FROM taskassignees ta WHERE ta.task_id = tasks.task_id AND ta.user_id = ?))", userID, userID)
+	}
+	var version struct {
+		Count        int64
+		LastModified *time.Time
+	}
+	if err := query.Select("COUNT(*) FILTER (WHERE NOT is_deleted) AS count, MAX(updated_at) AS last_modified").
+		Scan(&version).Error; err != nil {
+		log.Error("failed to get calendar version", "error", err)
+		return 0, nil, caldav.ErrCalDAVInternal
+	}
+	return version.Count, version.LastModified, nil
+}
+
+func (r *caldavDB) GetObjectByHref(href string) (*caldav.CalDAVObject, error) {
+	op := "caldavDB.GetObjectByHref"
+	log := r.log.With(slog.String("op", op))
+	var obj caldav.CalDAVObject
+
+	if err := r.db.Where("href = ?", href).First(&obj).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, caldav.ErrObjectNotFound
+		}
+		log.Error("failed to get caldav object", "error", err, "href", href)
+		return nil, caldav.ErrCalDAVInternal
+	}
+	return &obj, nil
+}
+
+func (r *caldavDB) GetObjectsByTaskIDs(taskIDs []uint) (map[uint]*caldav.CalDAVObject, error) {
+	op := "caldavDB.GetObjectsByTaskIDs"
+	log := r.log.With(slog.String("op", op))
+	result := make(map[uint]*caldav.CalDAVObject, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return result, nil
+	}
+
+	var objects []*caldav.CalDAVObject
+	if err := r.db.Where("task_id IN ?", taskIDs).Find(&objects).Error; err != nil {
+		log.Error("failed to get caldav objects", "error", err)
+		return nil, caldav.ErrCalDAVInternal
+	}
+	for _, obj := range objects {
+		result[obj.TaskID] = obj
+	}
+	return result, nil
+}
+
+func (r *caldavDB) CreateObject(obj *caldav.CalDAVObject) error {
+	op := "caldavDB.CreateObject"
+	log := r.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(obj.TaskID)))
+
+	if err := r.db.Create(obj).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
+			log.Warn("caldav object name already used", "href", obj.Href)
+			return caldav.ErrObjectNameConflict
+		}
+		log.Error("failed to create caldav object", "error", err)
+		return caldav.ErrCalDAVInternal
+	}
+	return nil
+}
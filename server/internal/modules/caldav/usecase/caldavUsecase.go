@@ -0,0 +1,554 @@
+// internal/modules/caldav/usecase/caldavUsecase.go
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"server/internal/modules/caldav"
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+	"server/pkg/lib/ical"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	objectsPageSize = 500
+	// appPasswordTouchInterval - как часто обновлять время последнего использования пароля:
+	// клиенты синхронизируются десятками запросов подряд
+	appPasswordTouchInterval = 5 * time.Minute
+	maxTitleLength           = 255
+	maxDescriptionLength     = 65535
+)
+
+type caldavUseCase struct {
+	repo        caldav.Repo
+	taskService task.UseCase
+	teamService caldav.TeamService
+	log         *slog.Logger
+}
+
+func NewUseCase(
+	log *slog.Logger,
+	repo caldav.Repo,
+	taskService task.UseCase,
+	teamService caldav.TeamService,
+) caldav.UseCase {
+	return &caldavUseCase{
+		log:         log,
+		repo:        repo,
+		taskService: taskService,
+		teamService: teamService,
+	}
+}
+
+// --- Пароли приложений ---
+
+// normalizeAppPassword убирает разделители групп: пароль можно вводить как с дефисами, так и без них.
+func normalizeAppPassword(password string) string {
+	password = strings.ToLower(password)
+	return strings.NewReplacer("-", "", " ", "").Replace(password)
+}
+
+func hashAppPassword(password string) string {
+	sum := sha256.Sum256([]byte(normalizeAppPassword(password)))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateAppPassword возвращает 32 символа base32 группами по 4 (например, abcd-efgh-...): его удобно набирать на телефоне.
+func generateAppPassword() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	raw := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))
+	groups := make([]string, 0, len(raw)/4)
+	for i := 0; i < len(raw); i += 4 {
+		groups = append(groups, raw[i:i+4])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+func (uc *caldavUseCase) GetAppPasswords(userID uint) ([]*caldav.AppPasswordResponse, error) {
+	passwords, err := uc.repo.GetAppPasswords(userID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*caldav.AppPasswordResponse, len(passwords))
+	for i, p := range passwords {
+		responses[i] = caldav.ToAppPasswordResponse(p)
+	}
+	return responses, nil
+}
+
+// CreateAppPassword создает пароль приложения. Пароль возвращается только здесь: в БД хранится его хэш.
+func (uc *caldavUseCase) CreateAppPassword(userID uint, req caldav.CreateAppPasswordRequest) (*caldav.AppPasswordResponse, error) {
+	op := "caldavUseCase.CreateAppPassword"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	password, err := generateAppPassword()
+	if err != nil {
+		log.Error("failed to generate app password", "error", err)
+		return nil, caldav.ErrCalDAVInternal
+	}
+	saved, err := uc.repo.CreateAppPassword(&caldav.AppPassword{
+		UserID:       userID,
+		Name:         strings.TrimSpace(req.Name),
+		PasswordHash: hashAppPassword(password),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("app password created", slog.Uint64("appPasswordID", uint64(saved.AppPasswordID)))
+	response := caldav.ToAppPasswordResponse(saved)
+	response.Password = password
+	return response, nil
+}
+
+func (uc *caldavUseCase) DeleteAppPassword(appPasswordID uint, userID uint) error {
+	return uc.repo.DeleteAppPassword(appPasswordID, userID)
+}
+
+// Authenticate проверяет пару логин (или email) и пароль приложения и возвращает ID пользователя.
+func (uc *caldavUseCase) Authenticate(username, password string) (uint, error) {
+	op := "caldavUseCase.Authenticate"
+	log := uc.log.With(slog.String("op", op))
+
+	if username == "" || password == "" {
+		return 0, caldav.ErrInvalidCredentials
+	}
+	p, err := uc.repo.GetAppPasswordByHash(hashAppPassword(password))
+	if err != nil {
+		return 0, err
+	}
+	loginMatches := p.Login != nil && strings.EqualFold(*p.Login, username)
+	emailMatches := p.Email != nil && strings.EqualFold(*p.Email, username)
+	if !loginMatches && !emailMatches {
+		log.Warn("app password used with another username", slog.Uint64("appPasswordID", uint64(p.AppPasswordID)))
+		return 0, caldav.ErrInvalidCredentials
+	}
+
+	if p.LastUsedAt == nil || time.Since(*p.LastUsedAt) > appPasswordTouchInterval {
+		if errTouch := uc.repo.TouchAppPassword(p.AppPasswordID); errTouch != nil {
+			log.Warn("failed to update app password usage time", "error", errTouch)
+		}
+	}
+	return p.UserID, nil
+}
+
+// --- Календари ---
+
+func teamCalendarName(teamID uint) string {
+	return fmt.Sprintf("team-%d", teamID)
+}
+
+// calendarCTag строится из числа задач и их последнего изменения
+func (uc *caldavUseCase) calendarCTag(userID uint, teamID *uint) (string, error) {
+	count, lastModified, err := uc.repo.GetCalendarVersion(userID, teamID)
+	if err != nil {
+		return "", err
+	}
+	var micros int64
+	if lastModified != nil {
+		micros = lastModified.UnixMicro()
+	}
+	return fmt.Sprintf("%d-%d", micros, count), nil
+}
+
+// GetCalendars возвращает календарь личных задач и календари всех команд пользователя.
+// Календари архивных команд остаются в списке, но доступны только для чтения.
+func (uc *caldavUseCase) GetCalendars(userID uint) ([]*caldav.Calendar, error) {
+	op := "caldavUseCase.GetCalendars"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	teams, err := uc.teamService.GetMyTeams(userID, team.GetMyTeamsRequest{IncludeArchived: true})
+	if err != nil {
+		log.Error("failed to get user teams", "error", err)
+		return nil, caldav.ErrCalDAVInternal
+	}
+
+	calendars := make([]*caldav.Calendar, 0, len(teams)+1)
+	personal := &caldav.Calendar{Name: caldav.PersonalCalendar, DisplayName: "Personal tasks"}
+	if personal.CTag, err = uc.calendarCTag(userID, nil); err != nil {
+		return nil, err
+	}
+	calendars = append(calendars, personal)
+	for _, t := range teams {
+		teamID := t.TeamID
+		cal := &caldav.Calendar{Name: teamCalendarName(teamID), TeamID: &teamID, DisplayName: t.Name, ReadOnly: t.IsArchived}
+		if cal.CTag, err = uc.calendarCTag(userID, &teamID); err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, cal)
+	}
+	return calendars, nil
+}
+
+// GetCalendar возвращает календарь по имени коллекции. Календарь команды доступен только ее участникам.
+func (uc *caldavUseCase) GetCalendar(userID uint, name string) (*caldav.Calendar, error) {
+	op := "caldavUseCase.GetCalendar"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)), slog.String("calendar", name))
+
+	if name == caldav.PersonalCalendar {
+		cal := &caldav.Calendar{Name: name, DisplayName: "Personal tasks"}
+		var err error
+		if cal.CTag, err = uc.calendarCTag(userID, nil); err != nil {
+			return nil, err
+		}
+		return cal, nil
+	}
+
+	rawID, ok := strings.CutPrefix(name, "team-")
+	if !ok {
+		return nil, caldav.ErrCalendarNotFound
+	}
+	teamID64, err := strconv.ParseUint(rawID, 10, 32)
+	if err != nil || teamCalendarName(uint(teamID64)) != name {
+		return nil, caldav.ErrCalendarNotFound
+	}
+	teamID := uint(teamID64)
+
+	teams, err := uc.teamService.GetMyTeams(userID, team.GetMyTeamsRequest{IncludeArchived: true})
+	if err != nil {
+		log.Error("failed to get user teams", "error", err)
+		return nil, caldav.ErrCalDAVInternal
+	}
+	for _, t := range teams {
+		if t.TeamID != teamID {
+			continue
+		}
+		cal := &caldav.Calendar{Name: name, TeamID: &teamID, DisplayName: t.Name, ReadOnly: t.IsArchived}
+		if cal.CTag, err = uc.calendarCTag(userID, &teamID); err != nil {
+			return nil, err
+		}
+		return cal, nil
+	}
+	return nil, caldav.ErrCalendarNotFound
+}
+
+// --- Объекты календаря ---
+
+// objectETag - версия задачи. Время обрезается до микросекунд: с такой точностью его хранит PostgreSQL.
+func objectETag(t *task.TaskResponse) string {
+	return fmt.Sprintf(`"%d"`, t.UpdatedAt.UnixMicro())
+}
+
+func defaultObjectName(taskID uint) string {
+	return fmt.Sprintf("task-%d.ics", taskID)
+}
+
+func toCalendarObject(t *task.TaskResponse, obj *caldav.CalDAVObject) *caldav.CalendarObject {
+	result := &caldav.CalendarObject{Name: defaultObjectName(t.TaskID), ETag: objectETag(t), Task: t}
+	if obj != nil {
+		result.Name = obj.Href
+		result.UID = obj.UID
+	}
+	return result
+}
+
+func inCalendar(t *task.TaskResponse, calendar *caldav.Calendar) bool {
+	if calendar.TeamID == nil {
+		return t.TeamID == nil
+	}
+	return t.TeamID != nil && *t.TeamID == *calendar.TeamID
+}
+
+// matchesETag проверяет заголовок If-Match / If-None-Match: "*" или список ETag через запятую.
+func matchesETag(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// GetObjects возвращает все задачи календаря (кроме удаленных в корзину).
+func (uc *caldavUseCase) GetObjects(userID uint, calendar *caldav.Calendar) ([]*caldav.CalendarObject, error) {
+	req := task.GetTasksRequest{}
+	if calendar.TeamID != nil {
+		req.TeamID = calendar.TeamID
+	} else {
+		viewType := task.ViewTypeUserPersonal
+		req.ViewType = &viewType
+	}
+	sortBy, sortOrder, limit := task.FieldCreatedAt, task.SortDirectionAsc, objectsPageSize
+	req.SortBy, req.SortOrder, req.Limit = &sortBy, &sortOrder, &limit
+
+	var objects []*caldav.CalendarObject
+	for {
+		tasks, meta, err := uc.taskService.GetTasks(userID, req)
+		if err != nil {
+			return nil, err
+		}
+		taskIDs := make([]uint, len(tasks))
+		for i, t := range tasks {
+			taskIDs[i] = t.TaskID
+		}
+		stored, err := uc.repo.GetObjectsByTaskIDs(taskIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tasks {
+			objects = append(objects, toCalendarObject(t, stored[t.TaskID]))
+		}
+		if meta == nil || meta.NextCursor == nil {
+			return objects, nil
+		}
+		req.Cursor = meta.NextCursor
+	}
+}
+
+// GetObject находит задачу календаря по имени ресурса: сначала среди имен, выбранных клиентами, затем по шаблону task-{id}.ics.
+func (uc *caldavUseCase) GetObject(userID uint, calendar *caldav.Calendar, name string) (*caldav.CalendarObject, error) {
+	var taskID uint
+	obj, err := uc.repo.GetObjectByHref(name)
+	switch {
+	case err == nil:
+		taskID = obj.TaskID
+	case errors.Is(err, caldav.ErrObjectNotFound):
+		rawID, ok := strings.CutPrefix(strings.TrimSuffix(name, ".ics"), "task-")
+		id, errParse := strconv.ParseUint(rawID, 10, 32)
+		if !ok || errParse != nil || defaultObjectName(uint(id)) != name {
+			return nil, caldav.ErrObjectNotFound
+		}
+		taskID = uint(id)
+		stored, err := uc.repo.GetObjectsByTaskIDs([]uint{taskID})
+		if err != nil {
+			return nil, err
+		}
+		obj = stored[taskID]
+	default:
+		return nil, err
+	}
+
+	t, err := uc.taskService.GetTask(taskID, userID)
+	if err != nil {
+		if errors.Is(err, task.ErrTaskNotFound) || errors.Is(err, task.ErrTaskAccessDenied) {
+			return nil, caldav.ErrObjectNotFound
+		}
+		return nil, err
+	}
+	if t.IsDeleted || !inCalendar(t, calendar) {
+		return nil, caldav.ErrObjectNotFound
+	}
+	return toCalendarObject(t, obj), nil
+}
+
+// PutObject создает или обновляет задачу по записи VTODO. Возвращает true, если задача создана.
+func (uc *caldavUseCase) PutObject(userID uint, calendar *caldav.Calendar, req caldav.PutObjectRequest) (bool, error) {
+	op := "caldavUseCase.PutObject"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)),
+		slog.String("calendar", calendar.Name), slog.String("name", req.Name))
+
+	if calendar.ReadOnly {
+		log.Warn("attempt to change read-only calendar")
+		return false, caldav.ErrCalendarReadOnly
+	}
+	item, err := ical.ParseTodo(req.Data)
+	if err != nil {
+		log.Warn("failed to parse calendar object", "error", err)
+		if errors.Is(err, ical.ErrNoTodo) {
+			return false, caldav.ErrUnsupportedComponent
+		}
+		return false, caldav.ErrInvalidObject
+	}
+	title := truncateRunes(strings.TrimSpace(item.Summary), maxTitleLength)
+	if title == "" || item.UID == "" {
+		log.Warn("calendar object has no SUMMARY or UID")
+		return false, caldav.ErrInvalidObject
+	}
+	description := truncateRunes(item.Description, maxDescriptionLength)
+
+	existing, err := uc.GetObject(userID, calendar, req.Name)
+	if err != nil && !errors.Is(err, caldav.ErrObjectNotFound) {
+		return false, err
+	}
+	if existing != nil {
+		if req.IfNoneMatch != "" && matchesETag(req.IfNoneMatch, existing.ETag) {
+			return false, caldav.ErrPreconditionFailed
+		}
+		if req.IfMatch != "" && !matchesETag(req.IfMatch, existing.ETag) {
+			return false, caldav.ErrPreconditionFailed
+		}
+	} else {
+		if req.IfMatch != "" {
+			return false, caldav.ErrPreconditionFailed
+		}
+		// Имя занято задачей другого календаря (или удаленной в корзину)
+		if _, err := uc.repo.GetObjectByHref(req.Name); err == nil {
+			return false, caldav.ErrObjectNameConflict
+		} else if !errors.Is(err, caldav.ErrObjectNotFound) {
+			return false, err
+		}
+	}
+
+	workflow, err := uc.getTaskWorkflow(calendar.TeamID)
+	if err != nil {
+		log.Error("failed to get task workflow", "error", err)
+		return false, caldav.ErrCalDAVInternal
+	}
+	targetCategory := todoStatusCategory(item)
+	priority := taskPriority(item.Priority)
+
+	if existing == nil {
+		createReq := task.CreateTaskRequest{
+			Title:    title,
+			Deadline: item.Due,
+			TeamID:   calendar.TeamID,
+		}
+		if description != "" {
+			createReq.Description = &description
+		}
+		if priority > 0 {
+			createReq.Priority = &priority
+		}
+		if targetCategory != team.StatusCategoryOpen {
+			if status := uc.pickStatus(userID, calendar.TeamID, workflow, "", targetCategory); status != "" {
+				createReq.Status = &status
+			}
+		}
+		created, err := uc.taskService.CreateTask(userID, createReq)
+		if err != nil {
+			return false, err
+		}
+		if err := uc.repo.CreateObject(&caldav.CalDAVObject{TaskID: created.TaskID, Href: req.Name, UID: item.UID}); err != nil {
+			// Задача уже создана: без записи она останется доступна как task-{id}.ics
+			log.Warn("failed to save caldav object name", "error", err, "taskID", created.TaskID)
+		}
+		log.Info("task created via caldav", slog.Uint64("taskID", uint64(created.TaskID)))
+		return true, nil
+	}
+
+	// В запрос попадают только изменившиеся поля: правка деталей требует больших прав, чем смена статуса,
+	// и участник, которому можно лишь менять статус, должен иметь возможность отметить задачу выполненной
+	t := existing.Task
+	patch := task.PatchTaskRequest{}
+	if title != t.Title {
+		patch.Title = &title
+	}
+	if t.Description == nil && description != "" || t.Description != nil && *t.Description != description {
+		patch.Description = &description
+	}
+	switch {
+	case item.Due != nil && (t.Deadline == nil || !t.Deadline.Truncate(time.Second).Equal(*item.Due)): // В DUE нет долей секунды
+		patch.Deadline = item.Due
+	case item.Due == nil && t.Deadline != nil:
+		clearDeadline := true
+		patch.ClearDeadline = &clearDeadline
+	}
+	if priority > 0 && priority != t.Priority {
+		patch.Priority = &priority
+	}
+	// Категорию клиент видит только через STATUS, поэтому статус меняется, лишь если сменилась категория:
+	// отложенная задача (open) остается отложенной после правки названия
+	if team.TaskStatusCategory(t.StatusCategory) != targetCategory {
+		if status := uc.pickStatus(userID, calendar.TeamID, workflow, t.Status, targetCategory); status != "" {
+			patch.Status = &status
+		}
+	}
+	if patch == (task.PatchTaskRequest{}) {
+		log.Debug("calendar object has no changes")
+		return false, nil
+	}
+	if _, err := uc.taskService.PatchTask(t.TaskID, userID, patch); err != nil {
+		return false, err
+	}
+	log.Info("task updated via caldav", slog.Uint64("taskID", uint64(t.TaskID)))
+	return false, nil
+}
+
+// DeleteObject перемещает задачу в корзину.
+func (uc *caldavUseCase) DeleteObject(userID uint, calendar *caldav.Calendar, name string, ifMatch string) error {
+	if calendar.ReadOnly {
+		return caldav.ErrCalendarReadOnly
+	}
+	obj, err := uc.GetObject(userID, calendar, name)
+	if err != nil {
+		return err
+	}
+	if ifMatch != "" && !matchesETag(ifMatch, obj.ETag) {
+		return caldav.ErrPreconditionFailed
+	}
+	return uc.taskService.DeleteTask(obj.Task.TaskID, userID)
+}
+
+func (uc *caldavUseCase) getTaskWorkflow(teamID *uint) (*team.TaskWorkflow, error) {
+	if teamID == nil {
+		return team.DefaultTaskWorkflow(), nil
+	}
+	return uc.teamService.GetTaskWorkflow(*teamID)
+}
+
+// pickStatus выбирает статус нужной категории: первый, в который разрешен переход из текущего для роли пользователя.
+// Если разрешенного нет, возвращается первый статус категории - переход отклонит сервис задач.
+// Для новых задач открытой категории возвращается пустая строка: сервис задач подставит начальный статус.
+func (uc *caldavUseCase) pickStatus(userID uint, teamID *uint, workflow *team.TaskWorkflow, current string, category team.TaskStatusCategory) string {
+	if current == "" && category == team.StatusCategoryOpen {
+		return ""
+	}
+	var role *team.TeamMemberRole
+	if teamID != nil {
+		if r, err := uc.teamService.GetUserRoleInTeam(userID, *teamID); err == nil {
+			role = r
+		}
+	}
+	fallback := ""
+	for _, s := range workflow.Statuses {
+		if s.Category != category {
+			continue
+		}
+		if current == "" || workflow.CanTransition(current, s.Key, role) {
+			return s.Key
+		}
+		if fallback == "" {
+			fallback = s.Key
+		}
+	}
+	return fallback
+}
+
+// todoStatusCategory переводит STATUS (и COMPLETED) записи VTODO в категорию статуса задачи.
+func todoStatusCategory(item *ical.Item) team.TaskStatusCategory {
+	switch item.Status {
+	case ical.StatusCompleted, ical.StatusCancelled:
+		return team.StatusCategoryDone
+	case ical.StatusInProcess:
+		return team.StatusCategoryActive
+	case ical.StatusNeedsAction:
+		return team.StatusCategoryOpen
+	}
+	if item.Completed != nil {
+		return team.StatusCategoryDone
+	}
+	return team.StatusCategoryOpen
+}
+
+// taskPriority переводит PRIORITY (1 - высший, 9 - низший, 0 - не задан) в приоритет задачи 1-3. 0 - не менять.
+func taskPriority(priority int) int {
+	switch {
+	case priority >= 1 && priority <= 4:
+		return 3
+	case priority == 5:
+		return 2
+	case priority >= 6:
+		return 1
+	}
+	return 0
+}
+
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
@@ -85,15 +85,15 @@ func (wc *wsChatController) ServeWs(w http.ResponseWriter, r *http.Request) {
 	teamID := uint(teamIDUint64)
 	log = log.With(slog.Uint64("teamID", uint64(teamID)))
 
-	isMember, err := wc.teamService.IsUserMember(userID, teamID)
+	hasAccess, err := wc.teamService.CanUserAccessTeamChat(userID, teamID)
 	if err != nil {
-		log.Error("Failed to check team membership for WS", "error", err)
+		log.Error("Failed to check team chat access for WS", "error", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
-	if !isMember {
-		log.Warn("User not member of team for WS", "userID", userID, "teamID", teamID)
-		http.Error(w, "Forbidden: Not a team member", http.StatusForbidden)
+	if !hasAccess {
+		log.Warn("User has no access to team chat for WS", "userID", userID, "teamID", teamID)
+		http.Error(w, "Forbidden: No access to team chat", http.StatusForbidden)
 		return
 	}
 
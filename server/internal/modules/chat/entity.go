@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"server/internal/modules/team"
 	"server/internal/modules/user/profile"
 	"time"
 )
@@ -202,7 +203,10 @@ type HTTPGetHistoryResponse struct {
 
 // --- Интерфейсы ---
 type TeamChecker interface {
-	IsUserMember(userID uint, teamID uint) (bool, error)
+	CanUserAccessTeamChat(userID uint, teamID uint) (bool, error)                    // Участник команды; гость - только если команда открыла ему чат
+	HasTeamPermission(userID, teamID uint, permission team.Permission) (bool, error) // chat.moderate - удаление чужих сообщений
+	CheckTeamWritable(teamID uint) error                                             // team.ErrTeamArchived - чат архивной команды только для чтения
+	InvalidateTeamStats(teamID uint)                                                 // Активность чата входит в статистику команды
 }
 type UserInfoProvider interface {
 	GetUser(userID uint) (*profile.UserProfileResponse, error)
@@ -13,4 +13,5 @@ var (
 	ErrWebSocketUpgradeFailed  = errors.New("failed to upgrade to websocket protocol")
 	ErrInternalChatService     = errors.New("internal chat service error")
 	ErrClientMessageIDConflict = errors.New("message with this client_message_id already processed")
+	ErrChatTeamArchived        = errors.New("team is archived, chat is read-only")
 )
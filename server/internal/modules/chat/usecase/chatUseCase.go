@@ -3,9 +3,11 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"server/internal/modules/chat"
+	"server/internal/modules/team"
 	"server/internal/modules/user/profile"
 	"time"
 )
@@ -35,14 +37,17 @@ func (uc *chatUseCase) HandleNewMessage(ctx context.Context, userID, teamID uint
 	op := "chatUseCase.HandleNewMessage"
 	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(teamID)))
 
-	if isMember, err := uc.teamService.IsUserMember(userID, teamID); err != nil || !isMember {
+	if hasAccess, err := uc.teamService.CanUserAccessTeamChat(userID, teamID); err != nil || !hasAccess {
 		if err != nil {
-			log.Error("Failed to check team membership", "error", err)
+			log.Error("Failed to check team chat access", "error", err)
 			return nil, chat.ErrInternalChatService
 		}
-		log.Warn("User not member of team")
+		log.Warn("User has no access to team chat")
 		return nil, chat.ErrUserNotInTeamChat
 	}
+	if err := uc.checkChatWritable(teamID, log); err != nil {
+		return nil, err
+	}
 
 	chatMsgModel := &chat.ChatMessage{
 		TeamID:           teamID,
@@ -56,6 +61,7 @@ func (uc *chatUseCase) HandleNewMessage(ctx context.Context, userID, teamID uint
 	if err != nil {
 		return nil, err
 	}
+	uc.teamService.InvalidateTeamStats(teamID)
 
 	senderProfile, _ := uc.userProfileService.GetUser(userID)
 
@@ -97,6 +103,9 @@ func (uc *chatUseCase) HandleEditMessage(ctx context.Context, userID, teamID uin
 	if existingMsg.IsDeleted {
 		return nil, chat.ErrMessageNotFound
 	}
+	if err := uc.checkChatWritable(teamID, log); err != nil {
+		return nil, err
+	}
 
 	editedAt := time.Now().UTC()
 	updatedMsg, err := uc.chatRepo.UpdateMessageText(ctx, payload.MessageID, payload.NewText, editedAt)
@@ -113,25 +122,37 @@ func (uc *chatUseCase) HandleEditMessage(ctx context.Context, userID, teamID uin
 }
 
 func (uc *chatUseCase) HandleDeleteMessage(ctx context.Context, userID, teamID, messageID uint) (*chat.MessageDeletedPayload, error) {
-	//op := "chatUseCase.HandleDeleteMessage"
-	//log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)), slog.Uint64("messageID", uint64(messageID)))
+	op := "chatUseCase.HandleDeleteMessage"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)), slog.Uint64("messageID", uint64(messageID)))
 
 	existingMsg, err := uc.chatRepo.GetMessageByID(ctx, messageID)
 	if err != nil {
 		return nil, err
 	}
 
-	if existingMsg.SenderUserID != userID {
-		// TODO: Add role check for admins/owners to delete others' messages
-		return nil, chat.ErrCannotDeleteMessage
-	}
 	if existingMsg.TeamID != teamID {
 		return nil, chat.ErrChatAccessDenied
 	}
+	if err := uc.checkChatWritable(teamID, log); err != nil {
+		return nil, err
+	}
+	if existingMsg.SenderUserID != userID {
+		// Чужие сообщения удаляют участники с правом chat.moderate
+		canModerate, err := uc.teamService.HasTeamPermission(userID, teamID, team.PermChatModerate)
+		if err != nil {
+			log.Error("Failed to check chat moderation permission", "error", err)
+			return nil, chat.ErrInternalChatService
+		}
+		if !canModerate {
+			return nil, chat.ErrCannotDeleteMessage
+		}
+		log.Info("Message deleted by moderator", slog.Uint64("senderUserID", uint64(existingMsg.SenderUserID)))
+	}
 
 	if err = uc.chatRepo.MarkMessageAsDeleted(ctx, messageID); err != nil {
 		return nil, err
 	}
+	uc.teamService.InvalidateTeamStats(teamID)
 
 	return &chat.MessageDeletedPayload{
 		MessageID: fmt.Sprintf("%d", messageID),
@@ -173,7 +194,7 @@ func (uc *chatUseCase) GetMessagesForHistory(ctx context.Context, userID uint, p
 	//op := "chatUseCase.GetMessagesForHistory"
 	//log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(params.TeamID)))
 
-	if isMember, err := uc.teamService.IsUserMember(userID, params.TeamID); err != nil || !isMember {
+	if hasAccess, err := uc.teamService.CanUserAccessTeamChat(userID, params.TeamID); err != nil || !hasAccess {
 		if err != nil {
 			return nil, chat.ErrInternalChatService
 		}
@@ -257,3 +278,16 @@ func (uc *chatUseCase) GetMessagesForHistory(ctx context.Context, userID uint, p
 
 	return &chat.HTTPGetHistoryResponse{Messages: responseMessages, HasMore: hasMore}, nil
 }
+
+// checkChatWritable - в архивной команде сообщения нельзя отправлять, изменять и удалять.
+func (uc *chatUseCase) checkChatWritable(teamID uint, log *slog.Logger) error {
+	if err := uc.teamService.CheckTeamWritable(teamID); err != nil {
+		if errors.Is(err, team.ErrTeamArchived) {
+			log.Warn("Team is archived, chat is read-only")
+			return chat.ErrChatTeamArchived
+		}
+		log.Error("Failed to check team archive state", "error", err)
+		return chat.ErrInternalChatService
+	}
+	return nil
+}
@@ -15,9 +15,7 @@ import (
 // В реальном приложении это будет интерфейс, реализуемый TeamUseCase из модуля team.
 type TeamServiceForTag interface {
 	IsUserMember(userID, teamID uint) (bool, error)
-	GetUserRoleInTeam(userID, teamID uint) (*team.TeamMemberRole, error) // Используем team.TeamMemberRole из пакета tag (или общий enum)
-	// Можно добавить более гранулярные права, если нужно:
-	// CanUserManageTeamTags(userID, teamID uint) (bool, error)
+	HasTeamPermission(userID, teamID uint, permission team.Permission) (bool, error) // Управление тегами - право tag.manage (в архивной команде - team.ErrTeamArchived)
 }
 
 // MockTeamServiceForTag - заглушка для TeamServiceForTag
@@ -30,13 +28,12 @@ func (m *MockTeamServiceForTag) IsUserMember(userID, teamID uint) (bool, error)
 	}
 	return true, nil
 }
-func (m *MockTeamServiceForTag) GetUserRoleInTeam(userID, teamID uint) (*team.TeamMemberRole, error) {
-	slog.Warn("MockTeamServiceForTag: GetUserRoleInTeam called, returning 'editor' by default", "userID", userID, "teamID", teamID)
+func (m *MockTeamServiceForTag) HasTeamPermission(userID, teamID uint, permission team.Permission) (bool, error) {
+	slog.Warn("MockTeamServiceForTag: HasTeamPermission called, returning true by default", "userID", userID, "teamID", teamID, "permission", permission)
 	if teamID == 0 {
-		return nil, errors.New("mock: invalid teamID")
+		return false, errors.New("mock: invalid teamID")
 	}
-	role := team.RoleEditor // По умолчанию даем права на управление тегами
-	return &role, nil
+	return true, nil // По умолчанию даем права на управление тегами
 }
 
 // TagUseCase реализует интерфейс tag.UseCase.
@@ -216,18 +213,16 @@ func (uc *TagUseCase) CreateTeamTag(teamID uint, userID uint, req tag.CreateTeam
 	}
 
 	// Проверка прав пользователя на создание тега в команде
-	role, err := uc.teamService.GetUserRoleInTeam(userID, teamID)
+	canManage, err := uc.teamService.HasTeamPermission(userID, teamID, team.PermTagManage)
 	if err != nil {
-		log.Error("failed to get user role in team", "error", err)
+		if errors.Is(err, team.ErrTeamArchived) {
+			return nil, err // Теги архивной команды не меняются
+		}
+		log.Error("failed to check tag permission in team", "error", err)
 		return nil, tag.ErrTagInternal
 	}
-	if role == nil {
-		log.Warn("user not member of team, cannot create team tag")
-		return nil, team.ErrTeamAccessDenied
-	}
-
-	if !(*role == team.RoleOwner || *role == team.RoleAdmin || *role == team.RoleEditor) {
-		log.Warn("user does not have permission to create team tags", "role", *role)
+	if !canManage {
+		log.Warn("user does not have permission to create team tags")
 		return nil, team.ErrTeamAccessDenied
 	}
 
@@ -291,17 +286,16 @@ func (uc *TagUseCase) UpdateTeamTag(tagID uint, teamID uint, userID uint, req ta
 	log := uc.log.With(slog.String("op", op), slog.Uint64("tagID", uint64(tagID)), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
 
 	// Проверка прав пользователя на редактирование тега в команде
-	role, err := uc.teamService.GetUserRoleInTeam(userID, teamID)
+	canManage, err := uc.teamService.HasTeamPermission(userID, teamID, team.PermTagManage)
 	if err != nil {
-		log.Error("failed to get user role", "error", err)
+		if errors.Is(err, team.ErrTeamArchived) {
+			return nil, err // Теги архивной команды не меняются
+		}
+		log.Error("failed to check tag permission", "error", err)
 		return nil, tag.ErrTagInternal
 	}
-	if role == nil {
-		log.Warn("user not member, cannot update")
-		return nil, team.ErrTeamAccessDenied
-	}
-	if !(*role == team.RoleOwner || *role == team.RoleAdmin || *role == team.RoleEditor) {
-		log.Warn("user lacks permission to update team tags", "role", *role)
+	if !canManage {
+		log.Warn("user lacks permission to update team tags")
 		return nil, team.ErrTeamAccessDenied
 	}
 
@@ -361,17 +355,16 @@ func (uc *TagUseCase) DeleteTeamTag(tagID uint, teamID uint, userID uint) error
 	op := "TagUseCase.DeleteTeamTag"
 	log := uc.log.With(slog.String("op", op), slog.Uint64("tagID", uint64(tagID)), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
 
-	role, err := uc.teamService.GetUserRoleInTeam(userID, teamID)
+	canManage, err := uc.teamService.HasTeamPermission(userID, teamID, team.PermTagManage)
 	if err != nil {
-		log.Error("failed to get user role", "error", err)
+		if errors.Is(err, team.ErrTeamArchived) {
+			return err // Теги архивной команды не меняются
+		}
+		log.Error("failed to check tag permission", "error", err)
 		return tag.ErrTagInternal
 	}
-	if role == nil {
-		log.Warn("user not member, cannot delete")
-		return team.ErrTeamAccessDenied
-	}
-	if !(*role == team.RoleOwner || *role == team.RoleAdmin || *role == team.RoleEditor) {
-		log.Warn("user lacks permission to delete team tags", "role", *role)
+	if !canManage {
+		log.Warn("user lacks permission to delete team tags")
 		return team.ErrTeamAccessDenied
 	}
 
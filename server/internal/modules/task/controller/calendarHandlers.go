@@ -0,0 +1,276 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+	"server/pkg/lib/ical"
+	resp "server/pkg/lib/response"
+)
+
+const calendarProdID = "-//ToDoApp//Tasks//RU"
+
+// requestBaseURL возвращает схему и хост, по которым клиент обратился к API (с учетом обратного прокси).
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// icalPriority переводит приоритет задачи (1-3) в шкалу iCalendar (1 - высший, 9 - низший).
+func icalPriority(priority int) int {
+	switch priority {
+	case 3:
+		return 1
+	case 2:
+		return 5
+	case 1:
+		return 9
+	}
+	return 0
+}
+
+// icalStatus переводит категорию статуса задачи в STATUS записи VTODO.
+func icalStatus(category string) string {
+	switch team.TaskStatusCategory(category) {
+	case team.StatusCategoryDone:
+		return ical.StatusCompleted
+	case team.StatusCategoryActive:
+		return ical.StatusInProcess
+	}
+	return ical.StatusNeedsAction
+}
+
+// toICalItem формирует запись календаря. Статус и приоритет дублируются в описании:
+// у VEVENT нет подходящих свойств, а клиенты показывают описание всегда.
+func (c *TaskController) toICalItem(item *task.CalendarFeedItem, host string) *ical.Item {
+	t := item.Task
+	details := "Status: " + item.StatusName
+	if t.Priority > 0 {
+		details += " · Priority: " + map[int]string{1: "low", 2: "medium", 3: "high"}[t.Priority]
+	}
+	if t.Description != nil && *t.Description != "" {
+		details += "\n\n" + *t.Description
+	}
+	icalItem := &ical.Item{
+		UID:          fmt.Sprintf("task-%d@%s", t.TaskID, host),
+		Summary:      t.Title,
+		Description:  details,
+		Status:       icalStatus(t.StatusCategory),
+		Priority:     icalPriority(t.Priority),
+		Due:          t.Deadline,
+		Completed:    t.CompletedAt,
+		Categories:   item.Tags,
+		Created:      t.CreatedAt,
+		LastModified: t.UpdatedAt,
+	}
+	if c.appURL != "" {
+		icalItem.URL = fmt.Sprintf("%s/task/%d", c.appURL, t.TaskID)
+	}
+	return icalItem
+}
+
+// GetCalendarFeeds
+// @Summary List calendar feeds
+// @Tags calendar
+// @Description Returns the user's calendar subscriptions (personal feed first, then team feeds). Subscription URLs are not returned: tokens are stored hashed, a lost URL is replaced by regenerating the feed.
+// @Produce json
+// @Success 200 {object} response.SuccessResponse{data=[]task.CalendarFeedResponse} "Calendar feeds retrieved successfully"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /ical/feeds [get]
+// @Security ApiKeyAuth
+func (c *TaskController) GetCalendarFeeds(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.GetCalendarFeeds"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	feeds, err := c.useCase.GetCalendarFeeds(userID)
+	if err != nil {
+		log.Error("usecase GetCalendarFeeds failed", "error", err, "userID", userID)
+		resp.SendError(w, r, http.StatusInternalServerError, "Failed to retrieve calendar feeds")
+		return
+	}
+	resp.SendSuccess(w, r, http.StatusOK, feeds)
+}
+
+// CreateCalendarFeed
+// @Summary Create or regenerate a calendar feed
+// @Tags calendar
+// @Description Issues a secret subscription URL (/ical/{token}.ics) for the user's deadlines, or for a team's deadlines with team_id. If the feed already exists its token is replaced and the old URL stops working. Add ?type=event to the URL for calendars that do not show VTODO entries.
+// @Accept json
+// @Produce json
+// @Param feed body task.CreateCalendarFeedRequest false "Feed scope"
+// @Success 201 {object} response.SuccessResponse{data=task.CalendarFeedResponse} "Feed with token and subscription URL"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not a member of the team"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /ical/feeds [post]
+// @Security ApiKeyAuth
+func (c *TaskController) CreateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.CreateCalendarFeed"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	var req task.CreateCalendarFeedRequest
+	if r.ContentLength != 0 {
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Warn("failed to decode request body", "error", err)
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+	}
+
+	feed, err := c.useCase.CreateCalendarFeed(userID, req)
+	if err != nil {
+		log.Error("usecase CreateCalendarFeed failed", "error", err)
+		switch {
+		case errors.Is(err, task.ErrTaskAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to create calendar feed")
+		}
+		return
+	}
+	// /v1/ical/feeds -> /v1/ical/{token}.ics
+	feed.URL = requestBaseURL(r) + path.Dir(strings.TrimSuffix(r.URL.Path, "/")) + "/" + feed.Token + ".ics"
+	resp.SendSuccess(w, r, http.StatusCreated, feed)
+}
+
+// DeleteCalendarFeed
+// @Summary Revoke a calendar feed
+// @Tags calendar
+// @Description Deletes the feed; its subscription URL stops working immediately.
+// @Produce json
+// @Param feedID path int true "Feed ID"
+// @Success 204 "Feed revoked"
+// @Failure 400 {object} response.ErrorResponse "Invalid feed ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 404 {object} response.ErrorResponse "Feed not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /ical/feeds/{feedID} [delete]
+// @Security ApiKeyAuth
+func (c *TaskController) DeleteCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.DeleteCalendarFeed"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	feedID, err := strconv.ParseUint(chi.URLParam(r, "feedID"), 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid feed ID")
+		return
+	}
+
+	if err := c.useCase.DeleteCalendarFeed(uint(feedID), userID); err != nil {
+		log.Error("usecase DeleteCalendarFeed failed", "error", err, "feedID", feedID)
+		switch {
+		case errors.Is(err, task.ErrCalendarFeedNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to revoke calendar feed")
+		}
+		return
+	}
+	resp.SendOK(w, r, http.StatusNoContent)
+}
+
+// GetCalendarFeed
+// @Summary Calendar feed (iCalendar)
+// @Tags calendar
+// @Description Public subscription URL for calendar apps, authorized by the secret token. Returns tasks with a deadline (up to 90 days back) as VTODO entries, or as VEVENT entries with type=event. Supports conditional requests via ETag / If-None-Match and Last-Modified / If-Modified-Since.
+// @Produce text/calendar
+// @Param token path string true "Feed token"
+// @Param type query string false "Entry type" Enums(todo, event)
+// @Success 200 {string} string "iCalendar data"
+// @Success 304 "Not modified"
+// @Failure 400 {object} response.ErrorResponse "Invalid entry type"
+// @Failure 404 {object} response.ErrorResponse "Unknown or revoked token"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /ical/{token}.ics [get]
+func (c *TaskController) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.GetCalendarFeed"
+	log := c.log.With(slog.String("op", op))
+
+	query := task.CalendarFeedQuery{
+		Token:       chi.URLParam(r, "token"),
+		EntryType:   task.CalendarEntryTodo,
+		IfNoneMatch: r.Header.Get("If-None-Match"),
+	}
+	component := ical.ComponentTodo
+	switch entryType := task.CalendarFeedEntryType(r.URL.Query().Get("type")); entryType {
+	case "", task.CalendarEntryTodo:
+	case task.CalendarEntryEvent:
+		query.EntryType = entryType
+		component = ical.ComponentEvent
+	default:
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid type")
+		return
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		if t, err := http.ParseTime(ims); err == nil {
+			query.IfModifiedSince = &t
+		}
+	}
+
+	content, err := c.useCase.GetCalendarFeed(query)
+	if err != nil {
+		switch {
+		case errors.Is(err, task.ErrCalendarFeedNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		default:
+			log.Error("usecase GetCalendarFeed failed", "error", err)
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to build calendar feed")
+		}
+		return
+	}
+
+	w.Header().Set("ETag", content.ETag)
+	w.Header().Set("Last-Modified", content.LastModified.Format(http.TimeFormat))
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	if content.NotModified {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="tasks.ics"`)
+	w.WriteHeader(http.StatusOK)
+	cw := ical.NewWriter(w)
+	cw.Begin(calendarProdID, content.Name)
+	for _, item := range content.Items {
+		cw.WriteItem(component, c.toICalItem(item, r.Host))
+	}
+	if err := cw.End(); err != nil {
+		log.Warn("failed to write calendar feed", "error", err)
+		return
+	}
+	log.Debug("calendar feed sent", slog.Int("count", len(content.Items)), slog.Time("lastModified", content.LastModified))
+}
@@ -0,0 +1,321 @@
+package controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"log/slog"
+
+	"server/internal/modules/task"
+	resp "server/pkg/lib/response"
+)
+
+// maxImportFileSize - ограничение на размер загружаемого файла импорта
+const maxImportFileSize = 10 * 1024 * 1024
+
+// exportFlushEvery - через сколько задач буфер экспорта отправляется клиенту
+const exportFlushEvery = 100
+
+// formatExportTime приводит необязательное время к RFC3339 для CSV.
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// taskExportCSVRecord раскладывает задачу по колонкам task.TaskExportColumns.
+func taskExportCSVRecord(item *task.TaskExportItem) []string {
+	description, assignee, teamID := "", "", ""
+	if item.Description != nil {
+		description = *item.Description
+	}
+	if item.Assignee != nil {
+		assignee = *item.Assignee
+	}
+	if item.TeamID != nil {
+		teamID = strconv.FormatUint(uint64(*item.TeamID), 10)
+	}
+	return []string{
+		strconv.FormatUint(uint64(item.TaskID), 10),
+		item.Title,
+		description,
+		item.Status,
+		item.StatusCategory,
+		strconv.Itoa(item.Priority),
+		formatExportTime(item.Deadline),
+		assignee,
+		strings.Join(item.Assignees, ";"),
+		item.CreatedBy,
+		teamID,
+		strings.Join(item.Tags, ";"),
+		formatExportTime(item.CreatedAt),
+		formatExportTime(item.UpdatedAt),
+		formatExportTime(item.CompletedAt),
+	}
+}
+
+// taskExportWriter пишет задачи в ответ по мере получения. Заголовки отправляются перед первой задачей,
+// поэтому ошибка usecase до начала выгрузки еще может быть отдана обычным JSON-ответом.
+type taskExportWriter struct {
+	w       http.ResponseWriter
+	format  task.TaskExportFormat
+	csv     *csv.Writer
+	started bool
+	count   int
+}
+
+func (e *taskExportWriter) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	filename := "tasks-" + time.Now().Format("20060102") + "." + string(e.format)
+	if e.format == task.ExportFormatCSV {
+		e.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		e.w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
+	e.w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	e.w.WriteHeader(http.StatusOK)
+
+	if e.format == task.ExportFormatCSV {
+		e.csv = csv.NewWriter(e.w)
+		return e.csv.Write(task.TaskExportColumns)
+	}
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *taskExportWriter) write(item *task.TaskExportItem) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	if e.format == task.ExportFormatCSV {
+		if err := e.csv.Write(taskExportCSVRecord(item)); err != nil {
+			return err
+		}
+	} else {
+		if e.count > 0 {
+			if _, err := io.WriteString(e.w, ","); err != nil {
+				return err
+			}
+		}
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if _, err := e.w.Write(data); err != nil {
+			return err
+		}
+	}
+	e.count++
+	// Отдаем данные клиенту порциями, не накапливая всю выгрузку в буфере
+	if e.count%exportFlushEvery == 0 {
+		e.flush()
+	}
+	return nil
+}
+
+func (e *taskExportWriter) finish() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	if e.format == task.ExportFormatJSON {
+		if _, err := io.WriteString(e.w, "]"); err != nil {
+			return err
+		}
+	}
+	e.flush()
+	if e.csv != nil {
+		return e.csv.Error()
+	}
+	return nil
+}
+
+func (e *taskExportWriter) flush() {
+	if e.csv != nil {
+		e.csv.Flush()
+	}
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// ExportTasks
+// @Summary Export tasks
+// @Tags tasks
+// @Description Streams the user's personal tasks, or all tasks of a team with team_id, as a CSV or JSON file download. Tags are exported by name and users by login. The JSON file can be imported back with format=json.
+// @Produce text/csv
+// @Produce json
+// @Param format query string true "File format" Enums(csv, json)
+// @Param team_id query int false "Export tasks of this team instead of personal tasks"
+// @Success 200 {array} task.TaskExportItem "Exported tasks (JSON) or CSV with a header row"
+// @Failure 400 {object} response.ErrorResponse "Invalid query parameters"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not a member of the team"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /tasks/export [get]
+// @Security ApiKeyAuth
+func (c *TaskController) ExportTasks(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.ExportTasks"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	req := task.TaskExportRequest{Format: task.TaskExportFormat(r.URL.Query().Get("format"))}
+	if teamIDStr := r.URL.Query().Get("team_id"); teamIDStr != "" {
+		id, err := strconv.ParseUint(teamIDStr, 10, 32)
+		if err != nil {
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid team_id")
+			return
+		}
+		teamID := uint(id)
+		req.TeamID = &teamID
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	out := &taskExportWriter{w: w, format: req.Format}
+	err := c.useCase.ExportTasks(userID, req, out.write)
+	if err == nil {
+		err = out.finish()
+	}
+	if err != nil {
+		if out.started {
+			// Заголовки уже отправлены - остается только оборвать выгрузку
+			log.Error("task export interrupted", "error", err, "exported", out.count)
+			return
+		}
+		log.Error("usecase ExportTasks failed", "error", err)
+		switch {
+		case errors.Is(err, task.ErrTaskAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to export tasks")
+		}
+		return
+	}
+	log.Info("tasks exported successfully", slog.Int("count", out.count))
+}
+
+// ImportTasks
+// @Summary Import tasks from a file
+// @Tags tasks
+// @Description Imports tasks into the personal scope or into a team (team_id). Supported formats: json (our export), csv (header row; mapping binds task fields to column names), todoist and trello (their JSON exports). Every record is validated separately and reported in rows; records with errors are not created. Missing tags are created, assignees are matched by login or email. With dry_run=true nothing is changed and the response is a preview.
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "Import file (max 10MB)"
+// @Param format formData string true "File format" Enums(json, csv, todoist, trello)
+// @Param team_id formData int false "Import into this team"
+// @Param dry_run formData bool false "Validate only, do not create anything"
+// @Param mapping formData string false "CSV only: JSON object {\"title\": \"Name\", \"deadline\": \"Due\", ...}"
+// @Success 200 {object} response.SuccessResponse{data=task.TaskImportResult} "Import preview or result"
+// @Failure 400 {object} response.ErrorResponse "Invalid form or unreadable file"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "No permission to create tasks in the team"
+// @Failure 413 {object} response.ErrorResponse "File too large"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /tasks/import [post]
+// @Security ApiKeyAuth
+func (c *TaskController) ImportTasks(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.ImportTasks"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	// Важно установить MaxBytesReader до ParseMultipartForm
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize+1024*1024)
+	if err := r.ParseMultipartForm(maxImportFileSize); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			log.Warn("import request body too large", "error", err)
+			resp.SendError(w, r, http.StatusRequestEntityTooLarge, "Import file is too large")
+			return
+		}
+		log.Warn("failed to parse multipart form for import", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid multipart form")
+		return
+	}
+
+	req := task.TaskImportRequest{Format: task.TaskImportFormat(r.FormValue("format"))}
+	if teamIDStr := r.FormValue("team_id"); teamIDStr != "" {
+		id, err := strconv.ParseUint(teamIDStr, 10, 32)
+		if err != nil {
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid team_id")
+			return
+		}
+		teamID := uint(id)
+		req.TeamID = &teamID
+	}
+	if dryRunStr := r.FormValue("dry_run"); dryRunStr != "" {
+		dryRun, err := strconv.ParseBool(dryRunStr)
+		if err != nil {
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid dry_run")
+			return
+		}
+		req.DryRun = dryRun
+	}
+	if mapping := r.FormValue("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &req.Mapping); err != nil {
+			log.Warn("failed to unmarshal import mapping", "error", err)
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid mapping format")
+			return
+		}
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		log.Warn("import file is missing", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Import file is required")
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		log.Error("failed to read import file", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Failed to read import file")
+		return
+	}
+	log = log.With(slog.String("filename", header.Filename), slog.Int64("size", header.Size))
+
+	result, err := c.useCase.ImportTasks(userID, req, data)
+	if err != nil {
+		log.Error("usecase ImportTasks failed", "error", err)
+		switch {
+		case errors.Is(err, task.ErrTaskImportInvalid):
+			resp.SendError(w, r, http.StatusBadRequest, err.Error())
+		case errors.Is(err, task.ErrTaskAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to import tasks")
+		}
+		return
+	}
+	log.Info("tasks import processed", slog.Int("created", result.Created), slog.Int("failed", result.Failed))
+	resp.SendSuccess(w, r, http.StatusOK, result)
+}
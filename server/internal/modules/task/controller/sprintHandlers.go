@@ -0,0 +1,471 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	"server/internal/modules/task"
+	resp "server/pkg/lib/response"
+)
+
+// sendSprintError сопоставляет ошибки usecase спринтов с HTTP-статусами.
+func sendSprintError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, task.ErrSprintNotFound):
+		resp.SendError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, task.ErrTaskAccessDenied):
+		resp.SendError(w, r, http.StatusForbidden, err.Error())
+	case errors.Is(err, task.ErrSprintAlreadyActive):
+		resp.SendError(w, r, http.StatusConflict, err.Error())
+	case errors.Is(err, task.ErrSprintInvalid):
+		resp.SendError(w, r, http.StatusUnprocessableEntity, err.Error())
+	default:
+		resp.SendError(w, r, http.StatusInternalServerError, fallback)
+	}
+}
+
+// parseSprintRoute извлекает ID команды и (если есть в маршруте) ID спринта из URL.
+func parseSprintRoute(r *http.Request, withSprint bool) (teamID uint, sprintID uint, ok bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "teamID"), 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	if !withSprint {
+		return uint(id), 0, true
+	}
+	sid, err := strconv.ParseUint(chi.URLParam(r, "sprintID"), 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	return uint(id), uint(sid), true
+}
+
+// GetTeamSprints
+// @Summary List team sprints
+// @Tags sprints
+// @Description Returns all sprints of the team, newest first. Available to team members except guests.
+// @Produce json
+// @Param teamID path int true "Team ID"
+// @Success 200 {object} response.SuccessResponse{data=[]task.SprintResponse} "Sprints retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid Team ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not a member of the team or a guest"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /teams/{teamID}/sprints [get]
+// @Security ApiKeyAuth
+func (c *TaskController) GetTeamSprints(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.GetTeamSprints"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	teamID, _, ok := parseSprintRoute(r, false)
+	if !ok {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(teamID)))
+
+	sprints, err := c.useCase.GetTeamSprints(teamID, userID)
+	if err != nil {
+		log.Error("usecase GetTeamSprints failed", "error", err)
+		sendSprintError(w, r, err, "Failed to retrieve sprints")
+		return
+	}
+
+	resp.SendSuccess(w, r, http.StatusOK, sprints)
+}
+
+// CreateSprint
+// @Summary Plan a sprint
+// @Tags sprints
+// @Description Creates a planned sprint with dates and an optional goal. Requires the sprint.manage team permission.
+// @Accept json
+// @Produce json
+// @Param teamID path int true "Team ID"
+// @Param sprint body task.CreateSprintRequest true "Sprint name, goal and dates"
+// @Success 201 {object} task.SprintResponse "Sprint created successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload, validation error, or invalid Team ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not allowed to manage sprints or team is archived"
+// @Failure 422 {object} response.ErrorResponse "End date is before start date"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /teams/{teamID}/sprints [post]
+// @Security ApiKeyAuth
+func (c *TaskController) CreateSprint(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.CreateSprint"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	teamID, _, ok := parseSprintRoute(r, false)
+	if !ok {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(teamID)))
+
+	var req task.CreateSprintRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body for CreateSprint", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed for CreateSprintRequest", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	sprint, err := c.useCase.CreateSprint(teamID, userID, req)
+	if err != nil {
+		log.Error("usecase CreateSprint failed", "error", err)
+		sendSprintError(w, r, err, "Failed to create sprint")
+		return
+	}
+
+	log.Info("sprint created", slog.Uint64("sprintID", uint64(sprint.SprintID)))
+	resp.SendSuccess(w, r, http.StatusCreated, sprint)
+}
+
+// GetSprint
+// @Summary Get a sprint
+// @Tags sprints
+// @Produce json
+// @Param teamID path int true "Team ID"
+// @Param sprintID path int true "Sprint ID"
+// @Success 200 {object} task.SprintResponse "Sprint retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid Team ID or Sprint ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not a member of the team or a guest"
+// @Failure 404 {object} response.ErrorResponse "Sprint not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /teams/{teamID}/sprints/{sprintID} [get]
+// @Security ApiKeyAuth
+func (c *TaskController) GetSprint(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.GetSprint"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	teamID, sprintID, ok := parseSprintRoute(r, true)
+	if !ok {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID or Sprint ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("sprintID", uint64(sprintID)))
+
+	sprint, err := c.useCase.GetSprint(teamID, sprintID, userID)
+	if err != nil {
+		log.Error("usecase GetSprint failed", "error", err)
+		sendSprintError(w, r, err, "Failed to retrieve sprint")
+		return
+	}
+
+	resp.SendSuccess(w, r, http.StatusOK, sprint)
+}
+
+// UpdateSprint
+// @Summary Update a sprint
+// @Tags sprints
+// @Description Changes name, goal or dates of a planned or active sprint. A closed sprint cannot be changed. Requires the sprint.manage team permission.
+// @Accept json
+// @Produce json
+// @Param teamID path int true "Team ID"
+// @Param sprintID path int true "Sprint ID"
+// @Param sprint body task.UpdateSprintRequest true "Fields to change"
+// @Success 200 {object} task.SprintResponse "Sprint updated successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload, validation error, or invalid IDs"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not allowed to manage sprints or team is archived"
+// @Failure 404 {object} response.ErrorResponse "Sprint not found"
+// @Failure 422 {object} response.ErrorResponse "Sprint is closed or dates are invalid"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /teams/{teamID}/sprints/{sprintID} [put]
+// @Security ApiKeyAuth
+func (c *TaskController) UpdateSprint(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.UpdateSprint"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	teamID, sprintID, ok := parseSprintRoute(r, true)
+	if !ok {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID or Sprint ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("sprintID", uint64(sprintID)))
+
+	var req task.UpdateSprintRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body for UpdateSprint", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed for UpdateSprintRequest", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	sprint, err := c.useCase.UpdateSprint(teamID, sprintID, userID, req)
+	if err != nil {
+		log.Error("usecase UpdateSprint failed", "error", err)
+		sendSprintError(w, r, err, "Failed to update sprint")
+		return
+	}
+
+	log.Info("sprint updated")
+	resp.SendSuccess(w, r, http.StatusOK, sprint)
+}
+
+// DeleteSprint
+// @Summary Delete a sprint
+// @Tags sprints
+// @Description Deletes the sprint; its tasks return to the team backlog. Requires the sprint.manage team permission.
+// @Param teamID path int true "Team ID"
+// @Param sprintID path int true "Sprint ID"
+// @Success 204 "Sprint deleted successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid Team ID or Sprint ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not allowed to manage sprints or team is archived"
+// @Failure 404 {object} response.ErrorResponse "Sprint not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /teams/{teamID}/sprints/{sprintID} [delete]
+// @Security ApiKeyAuth
+func (c *TaskController) DeleteSprint(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.DeleteSprint"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	teamID, sprintID, ok := parseSprintRoute(r, true)
+	if !ok {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID or Sprint ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("sprintID", uint64(sprintID)))
+
+	if err := c.useCase.DeleteSprint(teamID, sprintID, userID); err != nil {
+		log.Error("usecase DeleteSprint failed", "error", err)
+		sendSprintError(w, r, err, "Failed to delete sprint")
+		return
+	}
+
+	log.Info("sprint deleted")
+	resp.SendOK(w, r, http.StatusNoContent)
+}
+
+// StartSprint
+// @Summary Start a sprint
+// @Tags sprints
+// @Description Moves a planned sprint to active. A team can have only one active sprint. Requires the sprint.manage team permission.
+// @Produce json
+// @Param teamID path int true "Team ID"
+// @Param sprintID path int true "Sprint ID"
+// @Success 200 {object} task.SprintResponse "Sprint started successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid Team ID or Sprint ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not allowed to manage sprints or team is archived"
+// @Failure 404 {object} response.ErrorResponse "Sprint not found"
+// @Failure 409 {object} response.ErrorResponse "Team already has an active sprint"
+// @Failure 422 {object} response.ErrorResponse "Sprint is not planned"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /teams/{teamID}/sprints/{sprintID}/start [post]
+// @Security ApiKeyAuth
+func (c *TaskController) StartSprint(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.StartSprint"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	teamID, sprintID, ok := parseSprintRoute(r, true)
+	if !ok {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID or Sprint ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("sprintID", uint64(sprintID)))
+
+	sprint, err := c.useCase.StartSprint(teamID, sprintID, userID)
+	if err != nil {
+		log.Error("usecase StartSprint failed", "error", err)
+		sendSprintError(w, r, err, "Failed to start sprint")
+		return
+	}
+
+	log.Info("sprint started")
+	resp.SendSuccess(w, r, http.StatusOK, sprint)
+}
+
+// CloseSprint
+// @Summary Close a sprint
+// @Tags sprints
+// @Description Closes the active sprint and carries unfinished tasks over: to carry_over_to_sprint_id if given (0 - to the backlog), otherwise to the next planned sprint, or to the backlog if there is none. Requires the sprint.manage team permission.
+// @Accept json
+// @Produce json
+// @Param teamID path int true "Team ID"
+// @Param sprintID path int true "Sprint ID"
+// @Param request body task.CloseSprintRequest false "Carry-over target"
+// @Success 200 {object} task.CloseSprintResponse "Sprint closed successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload or invalid IDs"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not allowed to manage sprints or team is archived"
+// @Failure 404 {object} response.ErrorResponse "Sprint not found"
+// @Failure 422 {object} response.ErrorResponse "Sprint is not active or carry-over sprint is invalid"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /teams/{teamID}/sprints/{sprintID}/close [post]
+// @Security ApiKeyAuth
+func (c *TaskController) CloseSprint(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.CloseSprint"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	teamID, sprintID, ok := parseSprintRoute(r, true)
+	if !ok {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID or Sprint ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("sprintID", uint64(sprintID)))
+
+	// Тело необязательно: без него задачи переносятся в следующий запланированный спринт
+	var req task.CloseSprintRequest
+	if r.ContentLength != 0 {
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Warn("failed to decode request body for CloseSprint", "error", err)
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+	}
+
+	result, err := c.useCase.CloseSprint(teamID, sprintID, userID, req)
+	if err != nil {
+		log.Error("usecase CloseSprint failed", "error", err)
+		sendSprintError(w, r, err, "Failed to close sprint")
+		return
+	}
+
+	log.Info("sprint closed", slog.Int("carriedOver", len(result.CarriedOverTaskIDs)))
+	resp.SendSuccess(w, r, http.StatusOK, result)
+}
+
+// GetSprintBurndown
+// @Summary Sprint burndown
+// @Tags sprints
+// @Description Returns remaining work per sprint day, reconstructed from the task history. Work is counted in estimate minutes if any sprint task has an estimate, otherwise in tasks.
+// @Produce json
+// @Param teamID path int true "Team ID"
+// @Param sprintID path int true "Sprint ID"
+// @Success 200 {object} task.SprintBurndownResponse "Burndown retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid Team ID or Sprint ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not a member of the team or a guest"
+// @Failure 404 {object} response.ErrorResponse "Sprint not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /teams/{teamID}/sprints/{sprintID}/burndown [get]
+// @Security ApiKeyAuth
+func (c *TaskController) GetSprintBurndown(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.GetSprintBurndown"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	teamID, sprintID, ok := parseSprintRoute(r, true)
+	if !ok {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID or Sprint ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("sprintID", uint64(sprintID)))
+
+	burndown, err := c.useCase.GetSprintBurndown(teamID, sprintID, userID)
+	if err != nil {
+		log.Error("usecase GetSprintBurndown failed", "error", err)
+		sendSprintError(w, r, err, "Failed to build sprint burndown")
+		return
+	}
+
+	resp.SendSuccess(w, r, http.StatusOK, burndown)
+}
+
+// GetSprintVelocity
+// @Summary Team velocity
+// @Tags sprints
+// @Description Returns work completed in the last closed sprints (newest first) and the average.
+// @Produce json
+// @Param teamID path int true "Team ID"
+// @Param limit query int false "Number of closed sprints (1-20, default 5)"
+// @Success 200 {object} task.SprintVelocityResponse "Velocity retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid Team ID or limit"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not a member of the team or a guest"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /teams/{teamID}/sprints/velocity [get]
+// @Security ApiKeyAuth
+func (c *TaskController) GetSprintVelocity(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.GetSprintVelocity"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	teamID, _, ok := parseSprintRoute(r, false)
+	if !ok {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(teamID)))
+
+	var req task.GetSprintVelocityRequest
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		req.Limit = &limit
+	}
+	if err := c.validate.Struct(req); err != nil {
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	velocity, err := c.useCase.GetSprintVelocity(teamID, userID, req)
+	if err != nil {
+		log.Error("usecase GetSprintVelocity failed", "error", err)
+		sendSprintError(w, r, err, "Failed to retrieve team velocity")
+		return
+	}
+
+	resp.SendSuccess(w, r, http.StatusOK, velocity)
+}
@@ -21,14 +21,16 @@ type TaskController struct {
 	useCase  task.UseCase
 	log      *slog.Logger
 	validate *validator.Validate
+	appURL   string // Адрес фронтенда для ссылок на задачи (лента календаря)
 }
 
 // NewTaskController создает новый экземпляр TaskController.
-func NewTaskController(useCase task.UseCase, log *slog.Logger) *TaskController {
+func NewTaskController(useCase task.UseCase, log *slog.Logger, appURL string) *TaskController {
 	return &TaskController{
 		useCase:  useCase,
 		log:      log,
 		validate: validator.New(),
+		appURL:   strings.TrimSuffix(appURL, "/"),
 	}
 }
 
@@ -78,7 +80,7 @@ func (c *TaskController) CreateTask(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, task.ErrTaskAccessDenied):
 			resp.SendError(w, r, http.StatusForbidden, err.Error())
-		case errors.Is(err, task.ErrTaskAssigneeNotInTeam), errors.Is(err, task.ErrTaskInvalidInput):
+		case errors.Is(err, task.ErrTaskAssigneeNotInTeam), errors.Is(err, task.ErrTaskAssigneeIsGuest), errors.Is(err, task.ErrTaskProjectInvalid), errors.Is(err, task.ErrTaskSprintInvalid), errors.Is(err, task.ErrTaskInvalidInput), errors.Is(err, task.ErrTaskUnknownStatus):
 			resp.SendError(w, r, http.StatusUnprocessableEntity, err.Error())
 		default:
 			resp.SendError(w, r, http.StatusInternalServerError, "Failed to create task")
@@ -150,18 +152,32 @@ func (c *TaskController) GetTask(w http.ResponseWriter, r *http.Request) {
 // @Description Retrieves a list of tasks based on filters and sorting. Returns personal tasks or tasks of a specified team.
 // @Produce json
 // @Param team_id query int false "Filter by Team ID (for team tasks)"
-// @Param status query string false "Filter by status (todo, in_progress, deferred, done)" enums(todo,in_progress,deferred,done)
+// @Param project_id query int false "Filter by team project ID"
+// @Param sprint_id query int false "Filter by team sprint ID"
+// @Param status query string false "Filter by status key (team-defined; default set: todo, in_progress, deferred, done)"
+// @Param status_category query string false "Filter by status category (open, active, done)" enums(open,active,done)
 // @Param priority query int false "Filter by priority (1=low, 2=medium, 3=high)" enums(1,2,3)
 // @Param assigned_to_user_id query int false "Filter by assigned user ID"
 // @Param deadline_from query string false "Filter by deadline: start date (YYYY-MM-DD or RFC3339)" format(date-time)
 // @Param deadline_to query string false "Filter by deadline: end date (YYYY-MM-DD or RFC3339)" format(date-time)
-// @Param search query string false "Search by title or description"
-// @Param sort_by query string false "Sort by field (created_at, updated_at, deadline, priority, status, title)" enums(created_at,updated_at,deadline,priority,status,title)
+// @Param overdue query bool false "Only tasks with a past deadline that are not done"
+// @Param search query string false "Full-text search (Russian and English) over title, description and tag names; websearch syntax. Results are ranked and carry highlighted snippets"
+// @Param sort_by query string false "Sort by field (created_at, updated_at, deadline, priority, status, title, position, relevance). Defaults to relevance when searching" enums(created_at,updated_at,deadline,priority,status,title,position,relevance)
 // @Param sort_order query string false "Sort order (ASC, DESC)" enums(ASC,DESC)
-// @Success 200 {object} response.SuccessResponse{data=[]task.TaskResponse} "Tasks retrieved successfully"
-// @Failure 400 {object} response.ErrorResponse "Invalid query parameters"
+// @Param user_tag_ids query string false "Filter by user tag IDs (comma-separated or repeated)"
+// @Param team_tag_ids query string false "Filter by team tag IDs (comma-separated or repeated)"
+// @Param untagged query bool false "Include tasks without tags (alone: only untagged tasks)"
+// @Param tag_match query string false "How multiple tags combine: any (default) or all" enums(any,all)
+// @Param include_facets query bool false "Include per-tag task counts for the whole result set in meta.facets"
+// @Param limit query int false "Page size (1-500, default 100)"
+// @Param cursor query string false "Cursor from meta.next_cursor of the previous page"
+// @Param include_total query bool false "Include total count of matching tasks in meta.total"
+// @Param view_id query int false "Run a saved view: its filters and sorting replace the filter parameters of the query; pagination parameters still apply"
+// @Success 200 {object} response.SuccessResponse{data=[]task.TaskResponse,meta=task.TaskListMeta} "Tasks retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid query parameters or cursor"
 // @Failure 401 {object} response.ErrorResponse "Unauthorized"
 // @Failure 403 {object} response.ErrorResponse "Access denied to team tasks"
+// @Failure 404 {object} response.ErrorResponse "Saved view not found"
 // @Failure 500 {object} response.ErrorResponse "Internal server error"
 // @Router /tasks [get]
 // @Security ApiKeyAuth
@@ -210,9 +226,30 @@ func (c *TaskController) GetTasks(w http.ResponseWriter, r *http.Request) {
 			log.Warn("invalid team_id query param", "value", teamIDStr, "error", err)
 		}
 	}
+	if projectIDStr := r.URL.Query().Get("project_id"); projectIDStr != "" {
+		id, err := strconv.ParseUint(projectIDStr, 10, 32)
+		if err == nil {
+			uid := uint(id)
+			reqParams.ProjectID = &uid
+		} else {
+			log.Warn("invalid project_id query param", "value", projectIDStr, "error", err)
+		}
+	}
+	if sprintIDStr := r.URL.Query().Get("sprint_id"); sprintIDStr != "" {
+		id, err := strconv.ParseUint(sprintIDStr, 10, 32)
+		if err == nil {
+			uid := uint(id)
+			reqParams.SprintID = &uid
+		} else {
+			log.Warn("invalid sprint_id query param", "value", sprintIDStr, "error", err)
+		}
+	}
 	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
 		reqParams.Status = &statusStr
 	}
+	if categoryStr := r.URL.Query().Get("status_category"); categoryStr != "" {
+		reqParams.StatusCategory = &categoryStr
+	}
 	if priorityStr := r.URL.Query().Get("priority"); priorityStr != "" {
 		p, err := strconv.Atoi(priorityStr)
 		if err == nil {
@@ -249,6 +286,14 @@ func (c *TaskController) GetTasks(w http.ResponseWriter, r *http.Request) {
 	}
 	reqParams.DeadlineFrom = parseTimeParam("deadline_from")
 	reqParams.DeadlineTo = parseTimeParam("deadline_to")
+	if overdueStr := r.URL.Query().Get("overdue"); overdueStr != "" {
+		overdue, err := strconv.ParseBool(overdueStr)
+		if err == nil {
+			reqParams.Overdue = &overdue
+		} else {
+			log.Warn("invalid overdue query param", "value", overdueStr, "error", err)
+		}
+	}
 
 	if searchStr := r.URL.Query().Get("search"); searchStr != "" {
 		reqParams.Search = &searchStr
@@ -263,25 +308,91 @@ func (c *TaskController) GetTasks(w http.ResponseWriter, r *http.Request) {
 		reqParams.SortOrder = &order
 	}
 
+	userTagIDs, err := parseUintListParam(r, "user_tag_ids")
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid user_tag_ids")
+		return
+	}
+	reqParams.UserTagIDs = userTagIDs
+	teamTagIDs, err := parseUintListParam(r, "team_tag_ids")
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid team_tag_ids")
+		return
+	}
+	reqParams.TeamTagIDs = teamTagIDs
+	if untaggedStr := r.URL.Query().Get("untagged"); untaggedStr != "" {
+		untagged, err := strconv.ParseBool(untaggedStr)
+		if err == nil {
+			reqParams.Untagged = &untagged
+		} else {
+			log.Warn("invalid untagged query param", "value", untaggedStr, "error", err)
+		}
+	}
+	if tagMatchStr := r.URL.Query().Get("tag_match"); tagMatchStr != "" {
+		tagMatch := task.TagMatchMode(strings.ToLower(tagMatchStr))
+		reqParams.TagMatch = &tagMatch
+	}
+	if includeFacetsStr := r.URL.Query().Get("include_facets"); includeFacetsStr != "" {
+		includeFacets, err := strconv.ParseBool(includeFacetsStr)
+		if err == nil {
+			reqParams.IncludeFacets = &includeFacets
+		} else {
+			log.Warn("invalid include_facets query param", "value", includeFacetsStr, "error", err)
+		}
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		reqParams.Limit = &limit
+	}
+	if cursorStr := r.URL.Query().Get("cursor"); cursorStr != "" {
+		reqParams.Cursor = &cursorStr
+	}
+	if includeTotalStr := r.URL.Query().Get("include_total"); includeTotalStr != "" {
+		includeTotal, err := strconv.ParseBool(includeTotalStr)
+		if err == nil {
+			reqParams.IncludeTotal = &includeTotal
+		} else {
+			log.Warn("invalid include_total query param", "value", includeTotalStr, "error", err)
+		}
+	}
+	if viewIDStr := r.URL.Query().Get("view_id"); viewIDStr != "" {
+		id, err := strconv.ParseUint(viewIDStr, 10, 32)
+		if err != nil {
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid view_id")
+			return
+		}
+		viewID := uint(id)
+		reqParams.ViewID = &viewID
+	}
+
 	if err := c.validate.Struct(reqParams); err != nil {
 		log.Warn("validation failed for GetTasksRequest", "error", err)
 		resp.SendValidationError(w, r, err)
 		return
 	}
 
-	tasksList, err := c.useCase.GetTasks(userID, reqParams)
+	tasksList, meta, err := c.useCase.GetTasks(userID, reqParams)
 	if err != nil {
 		log.Error("usecase GetTasks failed", "error", err)
-		if errors.Is(err, task.ErrTaskAccessDenied) {
+		switch {
+		case errors.Is(err, task.ErrTaskAccessDenied):
 			resp.SendError(w, r, http.StatusForbidden, err.Error())
-		} else {
+		case errors.Is(err, task.ErrSavedViewNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, task.ErrTaskInvalidCursor), errors.Is(err, task.ErrTaskInvalidInput), errors.Is(err, task.ErrSavedViewInvalid):
+			resp.SendError(w, r, http.StatusBadRequest, err.Error())
+		default:
 			resp.SendError(w, r, http.StatusInternalServerError, "Failed to retrieve tasks")
 		}
 		return
 	}
 
 	log.Info("tasks retrieved successfully", slog.Int("count", len(tasksList)))
-	resp.SendSuccess(w, r, http.StatusOK, tasksList)
+	resp.SendSuccessWithMeta(w, r, http.StatusOK, tasksList, meta)
 }
 
 // UpdateTask (PUT)
@@ -338,7 +449,8 @@ func (c *TaskController) UpdateTask(w http.ResponseWriter, r *http.Request) {
 			resp.SendError(w, r, http.StatusNotFound, task.ErrTaskNotFound.Error())
 		case errors.Is(err, task.ErrTaskAccessDenied):
 			resp.SendError(w, r, http.StatusForbidden, err.Error())
-		case errors.Is(err, task.ErrTaskAssigneeNotInTeam), errors.Is(err, task.ErrTaskAlreadyCompleted), errors.Is(err, task.ErrTaskAlreadyDeleted), errors.Is(err, task.ErrTaskInvalidInput):
+		case errors.Is(err, task.ErrTaskAssigneeNotInTeam), errors.Is(err, task.ErrTaskAssigneeIsGuest), errors.Is(err, task.ErrTaskProjectInvalid), errors.Is(err, task.ErrTaskSprintInvalid), errors.Is(err, task.ErrTaskAlreadyCompleted), errors.Is(err, task.ErrTaskAlreadyDeleted), errors.Is(err, task.ErrTaskInvalidInput),
+			errors.Is(err, task.ErrTaskUnknownStatus), errors.Is(err, task.ErrTaskInvalidStatusTransition):
 			resp.SendError(w, r, http.StatusUnprocessableEntity, err.Error())
 		default:
 			resp.SendError(w, r, http.StatusInternalServerError, "Failed to update task")
@@ -410,7 +522,8 @@ func (c *TaskController) PatchTask(w http.ResponseWriter, r *http.Request) {
 			resp.SendError(w, r, http.StatusNotFound, task.ErrTaskNotFound.Error())
 		case errors.Is(err, task.ErrTaskAccessDenied):
 			resp.SendError(w, r, http.StatusForbidden, err.Error())
-		case errors.Is(err, task.ErrTaskAssigneeNotInTeam), errors.Is(err, task.ErrTaskAlreadyCompleted), errors.Is(err, task.ErrTaskAlreadyDeleted), errors.Is(err, task.ErrTaskInvalidInput):
+		case errors.Is(err, task.ErrTaskAssigneeNotInTeam), errors.Is(err, task.ErrTaskAssigneeIsGuest), errors.Is(err, task.ErrTaskProjectInvalid), errors.Is(err, task.ErrTaskSprintInvalid), errors.Is(err, task.ErrTaskAlreadyCompleted), errors.Is(err, task.ErrTaskAlreadyDeleted), errors.Is(err, task.ErrTaskInvalidInput),
+			errors.Is(err, task.ErrTaskUnknownStatus), errors.Is(err, task.ErrTaskInvalidStatusTransition):
 			resp.SendError(w, r, http.StatusUnprocessableEntity, err.Error())
 		default:
 			resp.SendError(w, r, http.StatusInternalServerError, "Failed to patch task")
@@ -421,6 +534,25 @@ func (c *TaskController) PatchTask(w http.ResponseWriter, r *http.Request) {
 	resp.SendSuccess(w, r, http.StatusOK, taskResponse)
 }
 
+// parseUintListParam разбирает список ID из query: повторяющийся параметр и/или значения через запятую.
+func parseUintListParam(r *http.Request, name string) ([]uint, error) {
+	var ids []uint
+	for _, raw := range r.URL.Query()[name] {
+		for _, part := range strings.Split(raw, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			id, err := strconv.ParseUint(part, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids, nil
+}
+
 // isEmptyPatchRequest проверяет, все ли поля в PatchTaskRequest равны nil.
 func isEmptyPatchRequest(req task.PatchTaskRequest) bool {
 	return req.Title == nil &&
@@ -431,9 +563,130 @@ func isEmptyPatchRequest(req task.PatchTaskRequest) bool {
 		req.Priority == nil &&
 		req.AssignedToUserID == nil &&
 		req.ClearAssignedTo == nil &&
+		req.AssigneeIDs == nil &&
+		req.EstimateMinutes == nil &&
+		req.ClearEstimate == nil &&
 		req.IsDeleted == nil // <<< ДОБАВЛЕНО
 }
 
+// MoveTask
+// @Summary Move a task on the board
+// @Tags tasks
+// @Description Sets a new status (column) and manual position of a task in one call. The task is placed after after_task_id or before before_task_id; without neighbours it goes to the end of the column.
+// @Accept json
+// @Produce json
+// @Param taskID path int true "Task ID"
+// @Param move body task.MoveTaskRequest true "Target status and neighbour task"
+// @Success 200 {object} task.TaskResponse "Task moved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload, validation error, or invalid Task ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Access denied"
+// @Failure 404 {object} response.ErrorResponse "Task not found"
+// @Failure 422 {object} response.ErrorResponse "Unknown status, forbidden transition or neighbour task from another column"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /tasks/{taskID}/move [post]
+// @Security ApiKeyAuth
+func (c *TaskController) MoveTask(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.MoveTask"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	taskIDStr := chi.URLParam(r, "taskID")
+	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Task ID")
+		return
+	}
+	log = log.With(slog.Uint64("taskID", taskID))
+
+	var req task.MoveTaskRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	taskResponse, err := c.useCase.MoveTask(uint(taskID), userID, req)
+	if err != nil {
+		log.Error("usecase MoveTask failed", "error", err)
+		switch {
+		case errors.Is(err, task.ErrTaskNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, task.ErrTaskAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		case errors.Is(err, task.ErrTaskInvalidInput), errors.Is(err, task.ErrTaskUnknownStatus), errors.Is(err, task.ErrTaskInvalidStatusTransition):
+			resp.SendError(w, r, http.StatusUnprocessableEntity, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to move task")
+		}
+		return
+	}
+	log.Info("task moved successfully")
+	resp.SendSuccess(w, r, http.StatusOK, taskResponse)
+}
+
+// BulkUpdateTasks
+// @Summary Apply one operation to many tasks
+// @Tags tasks
+// @Description Applies set_status, set_priority, assign, add_tags, remove_tags, trash, restore or delete_permanently to every listed task. Permissions are checked per task and results are reported per task. With atomic=true all changes run in one transaction and the first failure rolls everything back (rolled_back=true).
+// @Accept json
+// @Produce json
+// @Param bulk body task.BulkTaskRequest true "Task IDs, operation and its parameter"
+// @Success 200 {object} task.BulkTaskResponse "Per-task results"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload, validation error, or missing operation parameter"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /tasks/bulk [post]
+// @Security ApiKeyAuth
+func (c *TaskController) BulkUpdateTasks(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.BulkUpdateTasks"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	var req task.BulkTaskRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	bulkResponse, err := c.useCase.BulkUpdateTasks(userID, req)
+	if err != nil {
+		log.Error("usecase BulkUpdateTasks failed", "error", err)
+		switch {
+		case errors.Is(err, task.ErrTaskInvalidInput):
+			resp.SendError(w, r, http.StatusBadRequest, "Missing parameter for operation "+string(req.Operation))
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to apply bulk operation")
+		}
+		return
+	}
+	log.Info("bulk operation applied", slog.Int("succeeded", bulkResponse.Succeeded), slog.Int("failed", bulkResponse.Failed))
+	resp.SendSuccess(w, r, http.StatusOK, bulkResponse)
+}
+
 // DeleteTask
 // @Summary Delete a task
 // @Tags tasks
@@ -588,3 +841,153 @@ func (c *TaskController) DeleteTaskPermanently(w http.ResponseWriter, r *http.Re
 	log.Info("task permanently deleted successfully")
 	resp.SendOK(w, r, http.StatusNoContent)
 }
+
+// GetTaskHistory
+// @Summary Get task change history
+// @Tags tasks
+// @Description Returns the audit trail of a task: who changed which field and when. Available for tasks in the trash as well.
+// @Produce json
+// @Param taskID path int true "Task ID"
+// @Success 200 {object} response.SuccessResponse{data=[]task.TaskEventResponse} "Task history retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid Task ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Access denied"
+// @Failure 404 {object} response.ErrorResponse "Task not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /tasks/{taskID}/history [get]
+// @Security ApiKeyAuth
+func (c *TaskController) GetTaskHistory(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.GetTaskHistory"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	taskIDStr := chi.URLParam(r, "taskID")
+	taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Task ID")
+		return
+	}
+	log = log.With(slog.Uint64("taskID", taskID))
+
+	history, err := c.useCase.GetTaskHistory(uint(taskID), userID)
+	if err != nil {
+		log.Error("usecase GetTaskHistory failed", "error", err)
+		switch {
+		case errors.Is(err, task.ErrTaskNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, task.ErrTaskAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to retrieve task history")
+		}
+		return
+	}
+	log.Info("task history retrieved successfully", slog.Int("count", len(history)))
+	resp.SendSuccess(w, r, http.StatusOK, history)
+}
+
+// GetTeamActivity
+// @Summary Get team activity feed
+// @Tags tasks
+// @Description Returns task changes made within a team, newest first. Available to team owners and admins.
+// @Produce json
+// @Param teamID path int true "Team ID"
+// @Param member_id query int false "Filter by the user who made the change"
+// @Param from query string false "Start of the period (YYYY-MM-DD or RFC3339)" format(date-time)
+// @Param to query string false "End of the period (YYYY-MM-DD or RFC3339)" format(date-time)
+// @Param limit query int false "Maximum number of events (1-500, default 100)"
+// @Success 200 {object} response.SuccessResponse{data=[]task.TaskEventResponse} "Team activity retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid Team ID or query parameters"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Access denied"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /teams/{teamID}/activity [get]
+// @Security ApiKeyAuth
+func (c *TaskController) GetTeamActivity(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.GetTeamActivity"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	teamIDStr := chi.URLParam(r, "teamID")
+	teamID, err := strconv.ParseUint(teamIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID")
+		return
+	}
+	log = log.With(slog.Uint64("teamID", teamID))
+
+	var req task.GetTeamActivityRequest
+	query := r.URL.Query()
+	if memberIDStr := query.Get("member_id"); memberIDStr != "" {
+		id, err := strconv.ParseUint(memberIDStr, 10, 32)
+		if err != nil {
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid member_id")
+			return
+		}
+		uid := uint(id)
+		req.ActorUserID = &uid
+	}
+	parseTimeParam := func(paramName string, endOfDay bool) (*time.Time, bool) {
+		valStr := query.Get(paramName)
+		if valStr == "" {
+			return nil, true
+		}
+		if t, err := time.Parse(time.RFC3339, valStr); err == nil {
+			return &t, true
+		}
+		t, err := time.Parse("2006-01-02", valStr)
+		if err != nil {
+			log.Warn("invalid date format for query param", "param", paramName, "value", valStr)
+			return nil, false
+		}
+		if endOfDay {
+			t = t.Add(24*time.Hour - time.Second)
+		}
+		return &t, true
+	}
+	var okFrom, okTo bool
+	req.From, okFrom = parseTimeParam("from", false)
+	req.To, okTo = parseTimeParam("to", true)
+	if !okFrom || !okTo {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid date format (expected YYYY-MM-DD or RFC3339)")
+		return
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		req.Limit = &limit
+	}
+
+	if err := c.validate.Struct(req); err != nil {
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	activity, err := c.useCase.GetTeamActivity(uint(teamID), userID, req)
+	if err != nil {
+		log.Error("usecase GetTeamActivity failed", "error", err)
+		if errors.Is(err, task.ErrTaskAccessDenied) {
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		} else {
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to retrieve team activity")
+		}
+		return
+	}
+	log.Info("team activity retrieved successfully", slog.Int("count", len(activity)))
+	resp.SendSuccess(w, r, http.StatusOK, activity)
+}
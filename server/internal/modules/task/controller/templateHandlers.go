@@ -0,0 +1,327 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	"server/internal/modules/task"
+	resp "server/pkg/lib/response"
+)
+
+// sendTaskTemplateError сопоставляет ошибки usecase шаблонов с HTTP-статусами.
+func sendTaskTemplateError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, task.ErrTaskTemplateNotFound):
+		resp.SendError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, task.ErrTaskAccessDenied):
+		resp.SendError(w, r, http.StatusForbidden, err.Error())
+	case errors.Is(err, task.ErrTaskTemplateInvalid), errors.Is(err, task.ErrTaskAssigneeNotInTeam), errors.Is(err, task.ErrTaskAssigneeIsGuest),
+		errors.Is(err, task.ErrTaskInvalidInput), errors.Is(err, task.ErrTaskUnknownStatus):
+		resp.SendError(w, r, http.StatusUnprocessableEntity, err.Error())
+	default:
+		resp.SendError(w, r, http.StatusInternalServerError, fallback)
+	}
+}
+
+// parseTemplateID извлекает ID шаблона из URL.
+func parseTemplateID(r *http.Request) (uint, error) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "templateID"), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
+
+// GetTaskTemplates
+// @Summary List task templates
+// @Tags task-templates
+// @Description Returns the user's personal templates followed by templates of all their teams. With team_id only that team's templates are returned.
+// @Produce json
+// @Param team_id query int false "Only templates of this team"
+// @Success 200 {object} response.SuccessResponse{data=[]task.TaskTemplateResponse} "Task templates retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid team_id"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not a member of the team"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /templates [get]
+// @Security ApiKeyAuth
+func (c *TaskController) GetTaskTemplates(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.GetTaskTemplates"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	var teamID *uint
+	if teamIDStr := r.URL.Query().Get("team_id"); teamIDStr != "" {
+		id, err := strconv.ParseUint(teamIDStr, 10, 32)
+		if err != nil {
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid team_id")
+			return
+		}
+		uid := uint(id)
+		teamID = &uid
+	}
+
+	templates, err := c.useCase.GetTaskTemplates(userID, teamID)
+	if err != nil {
+		log.Error("usecase GetTaskTemplates failed", "error", err)
+		sendTaskTemplateError(w, r, err, "Failed to retrieve task templates")
+		return
+	}
+
+	resp.SendSuccess(w, r, http.StatusOK, templates)
+}
+
+// GetTaskTemplate
+// @Summary Get a task template
+// @Tags task-templates
+// @Description Returns the template with the list of variables that must be provided on instantiation.
+// @Produce json
+// @Param templateID path int true "Template ID"
+// @Success 200 {object} task.TaskTemplateResponse "Task template retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid Template ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 404 {object} response.ErrorResponse "Task template not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /templates/{templateID} [get]
+// @Security ApiKeyAuth
+func (c *TaskController) GetTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.GetTaskTemplate"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	templateID, err := parseTemplateID(r)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Template ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("templateID", uint64(templateID)))
+
+	tmpl, err := c.useCase.GetTaskTemplate(templateID, userID)
+	if err != nil {
+		log.Error("usecase GetTaskTemplate failed", "error", err)
+		sendTaskTemplateError(w, r, err, "Failed to retrieve task template")
+		return
+	}
+
+	resp.SendSuccess(w, r, http.StatusOK, tmpl)
+}
+
+// CreateTaskTemplate
+// @Summary Create a task template
+// @Tags task-templates
+// @Description Saves a reusable task with checklist, relative deadline and subtasks. Texts may contain {{variables}}. With team_id the template belongs to the team; only editors and above may create team templates.
+// @Accept json
+// @Produce json
+// @Param template body task.CreateTaskTemplateRequest true "Template name and content"
+// @Success 201 {object} task.TaskTemplateResponse "Task template created successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload or validation error"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not allowed to create templates in the team"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /templates [post]
+// @Security ApiKeyAuth
+func (c *TaskController) CreateTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.CreateTaskTemplate"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	var req task.CreateTaskTemplateRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body for CreateTaskTemplate", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed for CreateTaskTemplateRequest", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	tmpl, err := c.useCase.CreateTaskTemplate(userID, req)
+	if err != nil {
+		log.Error("usecase CreateTaskTemplate failed", "error", err)
+		sendTaskTemplateError(w, r, err, "Failed to create task template")
+		return
+	}
+
+	log.Info("task template created", slog.Uint64("templateID", uint64(tmpl.TemplateID)))
+	resp.SendSuccess(w, r, http.StatusCreated, tmpl)
+}
+
+// UpdateTaskTemplate
+// @Summary Update a task template
+// @Tags task-templates
+// @Description Renames a template and/or replaces its content. Personal templates can be changed by their owner, team templates by team editors and above.
+// @Accept json
+// @Produce json
+// @Param templateID path int true "Template ID"
+// @Param template body task.UpdateTaskTemplateRequest true "New name and/or content"
+// @Success 200 {object} task.TaskTemplateResponse "Task template updated successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload, validation error, or invalid Template ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Access denied"
+// @Failure 404 {object} response.ErrorResponse "Task template not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /templates/{templateID} [put]
+// @Security ApiKeyAuth
+func (c *TaskController) UpdateTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.UpdateTaskTemplate"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	templateID, err := parseTemplateID(r)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Template ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("templateID", uint64(templateID)))
+
+	var req task.UpdateTaskTemplateRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body for UpdateTaskTemplate", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed for UpdateTaskTemplateRequest", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+	if req.Name == nil && req.Content == nil {
+		resp.SendError(w, r, http.StatusBadRequest, "No fields to update")
+		return
+	}
+
+	tmpl, err := c.useCase.UpdateTaskTemplate(templateID, userID, req)
+	if err != nil {
+		log.Error("usecase UpdateTaskTemplate failed", "error", err)
+		sendTaskTemplateError(w, r, err, "Failed to update task template")
+		return
+	}
+
+	log.Info("task template updated")
+	resp.SendSuccess(w, r, http.StatusOK, tmpl)
+}
+
+// DeleteTaskTemplate
+// @Summary Delete a task template
+// @Tags task-templates
+// @Description Deletes the template. Tasks created from it are not affected.
+// @Produce json
+// @Param templateID path int true "Template ID"
+// @Success 204 "Task template deleted successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid Template ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Access denied"
+// @Failure 404 {object} response.ErrorResponse "Task template not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /templates/{templateID} [delete]
+// @Security ApiKeyAuth
+func (c *TaskController) DeleteTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.DeleteTaskTemplate"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	templateID, err := parseTemplateID(r)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Template ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("templateID", uint64(templateID)))
+
+	if err := c.useCase.DeleteTaskTemplate(templateID, userID); err != nil {
+		log.Error("usecase DeleteTaskTemplate failed", "error", err)
+		sendTaskTemplateError(w, r, err, "Failed to delete task template")
+		return
+	}
+
+	log.Info("task template deleted")
+	resp.SendOK(w, r, http.StatusNoContent)
+}
+
+// InstantiateTaskTemplate
+// @Summary Create tasks from a template
+// @Tags task-templates
+// @Description Fills in {{variables}} and creates the task and its subtasks in one transaction: either all tasks are created or none. Checklist items are appended to the description as a Markdown checklist. Deadlines are counted in days from start_date (today by default) and fall at the end of that day. Team templates create team tasks and require permission to create tasks in the team.
+// @Accept json
+// @Produce json
+// @Param templateID path int true "Template ID"
+// @Param instantiate body task.InstantiateTaskTemplateRequest false "Variable values, start date and assignee"
+// @Success 201 {object} task.InstantiateTaskTemplateResponse "Tasks created successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload, validation error, or invalid Template ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not allowed to create tasks in the team"
+// @Failure 404 {object} response.ErrorResponse "Task template not found"
+// @Failure 422 {object} response.ErrorResponse "Missing variables, rendered text too long, or invalid assignee"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /templates/{templateID}/instantiate [post]
+// @Security ApiKeyAuth
+func (c *TaskController) InstantiateTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.InstantiateTaskTemplate"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	templateID, err := parseTemplateID(r)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Template ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("templateID", uint64(templateID)))
+
+	var req task.InstantiateTaskTemplateRequest
+	if r.ContentLength != 0 {
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Warn("failed to decode request body for InstantiateTaskTemplate", "error", err)
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+		if err := c.validate.Struct(req); err != nil {
+			log.Warn("validation failed for InstantiateTaskTemplateRequest", "error", err)
+			resp.SendValidationError(w, r, err)
+			return
+		}
+	}
+
+	result, err := c.useCase.InstantiateTaskTemplate(templateID, userID, req)
+	if err != nil {
+		log.Error("usecase InstantiateTaskTemplate failed", "error", err)
+		sendTaskTemplateError(w, r, err, "Failed to create tasks from template")
+		return
+	}
+
+	log.Info("task template instantiated", slog.Uint64("taskID", uint64(result.Task.TaskID)))
+	resp.SendSuccess(w, r, http.StatusCreated, result)
+}
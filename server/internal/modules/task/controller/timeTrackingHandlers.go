@@ -0,0 +1,446 @@
+package controller
+
+import (
+	"encoding/csv"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"server/internal/modules/task"
+	resp "server/pkg/lib/response"
+)
+
+// sendTimeEntryError отвечает статусом, соответствующим ошибке учета времени.
+func sendTimeEntryError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, task.ErrTaskNotFound), errors.Is(err, task.ErrTimeEntryNotFound):
+		resp.SendError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, task.ErrTaskAccessDenied):
+		resp.SendError(w, r, http.StatusForbidden, err.Error())
+	case errors.Is(err, task.ErrTimerAlreadyRunning):
+		resp.SendError(w, r, http.StatusConflict, err.Error())
+	case errors.Is(err, task.ErrTaskInvalidInput):
+		resp.SendError(w, r, http.StatusUnprocessableEntity, err.Error())
+	default:
+		resp.SendError(w, r, http.StatusInternalServerError, fallback)
+	}
+}
+
+// parseTaskIDParam разбирает taskID из пути; при ошибке отвечает 400.
+func parseTaskIDParam(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	taskID, err := strconv.ParseUint(chi.URLParam(r, "taskID"), 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Task ID")
+		return 0, false
+	}
+	return uint(taskID), true
+}
+
+// GetTimeEntries
+// @Summary List time entries of a task
+// @Tags time-tracking
+// @Description Returns time logged on the task by all users, newest first. A running timer has no ended_at.
+// @Produce json
+// @Param taskID path int true "Task ID"
+// @Success 200 {object} response.SuccessResponse{data=[]task.TimeEntryResponse} "Time entries retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid Task ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Access denied"
+// @Failure 404 {object} response.ErrorResponse "Task not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /tasks/{taskID}/time-entries [get]
+// @Security ApiKeyAuth
+func (c *TaskController) GetTimeEntries(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.GetTimeEntries"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	taskID, ok := parseTaskIDParam(w, r)
+	if !ok {
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("taskID", uint64(taskID)))
+
+	entries, err := c.useCase.GetTimeEntries(taskID, userID)
+	if err != nil {
+		log.Error("usecase GetTimeEntries failed", "error", err)
+		sendTimeEntryError(w, r, err, "Failed to retrieve time entries")
+		return
+	}
+	resp.SendSuccess(w, r, http.StatusOK, entries)
+}
+
+// CreateTimeEntry
+// @Summary Log time manually
+// @Tags time-tracking
+// @Description Adds a finished time entry of the given length. Without started_at the entry ends now.
+// @Accept json
+// @Produce json
+// @Param taskID path int true "Task ID"
+// @Param entry body task.CreateTimeEntryRequest true "Duration, start and note"
+// @Success 201 {object} response.SuccessResponse{data=task.TimeEntryResponse} "Time entry created"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload, validation error, or invalid Task ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Access denied"
+// @Failure 404 {object} response.ErrorResponse "Task not found"
+// @Failure 422 {object} response.ErrorResponse "Entry ends in the future"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /tasks/{taskID}/time-entries [post]
+// @Security ApiKeyAuth
+func (c *TaskController) CreateTimeEntry(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.CreateTimeEntry"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	taskID, ok := parseTaskIDParam(w, r)
+	if !ok {
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("taskID", uint64(taskID)))
+
+	var req task.CreateTimeEntryRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	entry, err := c.useCase.CreateTimeEntry(taskID, userID, req)
+	if err != nil {
+		log.Error("usecase CreateTimeEntry failed", "error", err)
+		sendTimeEntryError(w, r, err, "Failed to create time entry")
+		return
+	}
+	resp.SendSuccess(w, r, http.StatusCreated, entry)
+}
+
+// StartTimer
+// @Summary Start a timer
+// @Tags time-tracking
+// @Description Starts the user's timer on the task. A user can have only one running timer.
+// @Accept json
+// @Produce json
+// @Param taskID path int true "Task ID"
+// @Param timer body task.StartTimerRequest false "Optional note"
+// @Success 201 {object} response.SuccessResponse{data=task.TimeEntryResponse} "Timer started"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload or invalid Task ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Access denied"
+// @Failure 404 {object} response.ErrorResponse "Task not found"
+// @Failure 409 {object} response.ErrorResponse "Another timer is already running"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /tasks/{taskID}/time-entries/start [post]
+// @Security ApiKeyAuth
+func (c *TaskController) StartTimer(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.StartTimer"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	taskID, ok := parseTaskIDParam(w, r)
+	if !ok {
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("taskID", uint64(taskID)))
+
+	var req task.StartTimerRequest
+	if r.ContentLength != 0 {
+		if err := render.DecodeJSON(r.Body, &req); err != nil {
+			log.Warn("failed to decode request body", "error", err)
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+	}
+	if err := c.validate.Struct(req); err != nil {
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	entry, err := c.useCase.StartTimer(taskID, userID, req)
+	if err != nil {
+		log.Error("usecase StartTimer failed", "error", err)
+		sendTimeEntryError(w, r, err, "Failed to start timer")
+		return
+	}
+	resp.SendSuccess(w, r, http.StatusCreated, entry)
+}
+
+// StopTimer
+// @Summary Stop the timer
+// @Tags time-tracking
+// @Description Stops the user's running timer on the task.
+// @Produce json
+// @Param taskID path int true "Task ID"
+// @Success 200 {object} response.SuccessResponse{data=task.TimeEntryResponse} "Timer stopped"
+// @Failure 400 {object} response.ErrorResponse "Invalid Task ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 404 {object} response.ErrorResponse "No running timer on this task"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /tasks/{taskID}/time-entries/stop [post]
+// @Security ApiKeyAuth
+func (c *TaskController) StopTimer(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.StopTimer"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	taskID, ok := parseTaskIDParam(w, r)
+	if !ok {
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("taskID", uint64(taskID)))
+
+	entry, err := c.useCase.StopTimer(taskID, userID)
+	if err != nil {
+		log.Error("usecase StopTimer failed", "error", err)
+		sendTimeEntryError(w, r, err, "Failed to stop timer")
+		return
+	}
+	resp.SendSuccess(w, r, http.StatusOK, entry)
+}
+
+// DeleteTimeEntry
+// @Summary Delete a time entry
+// @Tags time-tracking
+// @Description Deletes the user's own time entry (a running timer is discarded).
+// @Produce json
+// @Param taskID path int true "Task ID"
+// @Param entryID path int true "Time entry ID"
+// @Success 204 "Time entry deleted"
+// @Failure 400 {object} response.ErrorResponse "Invalid Task ID or entry ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 404 {object} response.ErrorResponse "Time entry not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /tasks/{taskID}/time-entries/{entryID} [delete]
+// @Security ApiKeyAuth
+func (c *TaskController) DeleteTimeEntry(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.DeleteTimeEntry"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	taskID, ok := parseTaskIDParam(w, r)
+	if !ok {
+		return
+	}
+	entryID, err := strconv.ParseUint(chi.URLParam(r, "entryID"), 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid time entry ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("taskID", uint64(taskID)), slog.Uint64("entryID", entryID))
+
+	if err := c.useCase.DeleteTimeEntry(taskID, uint(entryID), userID); err != nil {
+		log.Error("usecase DeleteTimeEntry failed", "error", err)
+		sendTimeEntryError(w, r, err, "Failed to delete time entry")
+		return
+	}
+	resp.SendOK(w, r, http.StatusNoContent)
+}
+
+// GetRunningTimer
+// @Summary Get the running timer
+// @Tags time-tracking
+// @Description Returns the user's running timer, or null if no timer is running.
+// @Produce json
+// @Success 200 {object} response.SuccessResponse{data=task.TimeEntryResponse} "Running timer or null"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /time-entries/running [get]
+// @Security ApiKeyAuth
+func (c *TaskController) GetRunningTimer(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.GetRunningTimer"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	entry, err := c.useCase.GetRunningTimer(userID)
+	if err != nil {
+		log.Error("usecase GetRunningTimer failed", "error", err, "userID", userID)
+		resp.SendError(w, r, http.StatusInternalServerError, "Failed to retrieve running timer")
+		return
+	}
+	resp.SendSuccess(w, r, http.StatusOK, entry)
+}
+
+// parseReportDate разбирает границу периода отчета (RFC3339 или YYYY-MM-DD).
+// Дата в конце периода включается целиком: граница переносится на начало следующего дня.
+func parseReportDate(value string, endOfPeriod bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	if endOfPeriod {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// timeReportCSVRecord раскладывает строку отчета по колонкам CSV.
+func timeReportCSVRecord(row *task.TimeReportRow) []string {
+	optUint := func(v *uint) string {
+		if v == nil {
+			return ""
+		}
+		return strconv.FormatUint(uint64(*v), 10)
+	}
+	optStr := func(v *string) string {
+		if v == nil {
+			return ""
+		}
+		return *v
+	}
+	return []string{
+		optUint(row.UserID), optStr(row.Login),
+		optUint(row.TagID), optStr(row.TagName),
+		optUint(row.TaskID), optStr(row.Title),
+		optStr(row.Date),
+		strconv.FormatInt(row.Minutes, 10),
+		strconv.FormatInt(row.Entries, 10),
+	}
+}
+
+// timeReportColumns - колонки CSV отчета; пустые измерения остаются пустыми
+var timeReportColumns = []string{"user_id", "login", "tag_id", "tag_name", "task_id", "title", "date", "minutes", "entries"}
+
+// GetTeamTimeReport
+// @Summary Get team time report
+// @Tags time-tracking
+// @Description Aggregates finished time entries of team tasks started within the period. Available to team owners and admins.
+// @Produce json
+// @Produce text/csv
+// @Param teamID path int true "Team ID"
+// @Param from query string false "Start of the period (YYYY-MM-DD or RFC3339)" format(date-time)
+// @Param to query string false "End of the period, inclusive for YYYY-MM-DD (YYYY-MM-DD or RFC3339)" format(date-time)
+// @Param group_by query string false "Grouping: member (default), tag, task or date" Enums(member, tag, task, date)
+// @Param member_id query int false "Only entries of this member"
+// @Param tag_id query int false "Only tasks with this team tag"
+// @Param format query string false "Response format: json (default) or csv" Enums(json, csv)
+// @Success 200 {object} response.SuccessResponse{data=task.TimeReportResponse} "Time report"
+// @Failure 400 {object} response.ErrorResponse "Invalid Team ID or query parameters"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Access denied"
+// @Failure 422 {object} response.ErrorResponse "Empty period"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /teams/{teamID}/time-report [get]
+// @Security ApiKeyAuth
+func (c *TaskController) GetTeamTimeReport(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.GetTeamTimeReport"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	teamID, err := strconv.ParseUint(chi.URLParam(r, "teamID"), 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", teamID))
+
+	query := r.URL.Query()
+	var req task.GetTimeReportRequest
+	parseID := func(name string) (*uint, bool) {
+		value := query.Get(name)
+		if value == "" {
+			return nil, true
+		}
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid "+name)
+			return nil, false
+		}
+		uid := uint(id)
+		return &uid, true
+	}
+	if req.MemberID, ok = parseID("member_id"); !ok {
+		return
+	}
+	if req.TagID, ok = parseID("tag_id"); !ok {
+		return
+	}
+	var errFrom, errTo error
+	req.From, errFrom = parseReportDate(query.Get("from"), false)
+	req.To, errTo = parseReportDate(query.Get("to"), true)
+	if errFrom != nil || errTo != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid date format (expected YYYY-MM-DD or RFC3339)")
+		return
+	}
+	if groupBy := query.Get("group_by"); groupBy != "" {
+		g := task.TimeReportGroup(groupBy)
+		req.GroupBy = &g
+	}
+	format := task.TimeReportFormat(query.Get("format"))
+	if format != "" && format != task.TimeReportFormatJSON && format != task.TimeReportFormatCSV {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid format (expected json or csv)")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	report, err := c.useCase.GetTeamTimeReport(uint(teamID), userID, req)
+	if err != nil {
+		log.Error("usecase GetTeamTimeReport failed", "error", err)
+		sendTimeEntryError(w, r, err, "Failed to build time report")
+		return
+	}
+
+	if format != task.TimeReportFormatCSV {
+		resp.SendSuccess(w, r, http.StatusOK, report)
+		return
+	}
+	filename := "time-report-team-" + strconv.FormatUint(teamID, 10) + "-" + string(report.GroupBy) + ".csv"
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	cw := csv.NewWriter(w)
+	_ = cw.Write(timeReportColumns)
+	for _, row := range report.Rows {
+		_ = cw.Write(timeReportCSVRecord(row))
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		log.Warn("failed to write time report CSV", "error", err)
+	}
+}
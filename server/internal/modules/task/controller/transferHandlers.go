@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"server/internal/modules/tag"
+	"server/internal/modules/task"
+	resp "server/pkg/lib/response"
+)
+
+// TransferTask
+// @Summary Move or copy a task to another space
+// @Tags tasks
+// @Description Moves a task between the personal space and teams (mode=move) or creates a copy there (mode=copy). Without team_id the target is the current user's personal space. Moving requires the right to delete the task in its current space; copying only requires read access. The user must be able to create tasks in the target team. Tags are matched by name; missing ones are created with create_missing_tags=true, otherwise dropped. Without assignee_ids the current assignees are kept and must be members of the target team.
+// @Accept json
+// @Produce json
+// @Param taskID path int true "Task ID"
+// @Param transfer body task.TransferTaskRequest true "Mode, target team and tag handling"
+// @Success 200 {object} task.TransferTaskResponse "Task in the target space with created and dropped tags"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload, validation error, or invalid Task ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Access denied in the source or target space"
+// @Failure 404 {object} response.ErrorResponse "Task not found"
+// @Failure 422 {object} response.ErrorResponse "Task is already in the target space, assignee is not a member of the target team, or tag conflict"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /tasks/{taskID}/transfer [post]
+// @Security ApiKeyAuth
+func (c *TaskController) TransferTask(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.TransferTask"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	taskID, ok := parseTaskIDParam(w, r)
+	if !ok {
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("taskID", uint64(taskID)))
+
+	var req task.TransferTaskRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	result, err := c.useCase.TransferTask(taskID, userID, req)
+	if err != nil {
+		log.Error("usecase TransferTask failed", "error", err)
+		switch {
+		case errors.Is(err, task.ErrTaskNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, task.ErrTaskAccessDenied), errors.Is(err, tag.ErrTagAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		case errors.Is(err, task.ErrTaskInvalidInput), errors.Is(err, task.ErrTaskAssigneeNotInTeam), errors.Is(err, task.ErrTaskAssigneeIsGuest),
+			errors.Is(err, task.ErrTaskUnknownStatus), errors.Is(err, tag.ErrTeamTagNameConflict),
+			errors.Is(err, tag.ErrUserTagNameConflict), errors.Is(err, tag.ErrTagNotFound):
+			resp.SendError(w, r, http.StatusUnprocessableEntity, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to transfer task")
+		}
+		return
+	}
+	log.Info("task transferred successfully", slog.String("mode", string(req.Mode)))
+	resp.SendSuccess(w, r, http.StatusOK, result)
+}
@@ -0,0 +1,278 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	"server/internal/modules/task"
+	resp "server/pkg/lib/response"
+)
+
+// sendSavedViewError сопоставляет ошибки usecase сохраненных представлений с HTTP-статусами.
+func sendSavedViewError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, task.ErrSavedViewNotFound):
+		resp.SendError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, task.ErrTaskAccessDenied):
+		resp.SendError(w, r, http.StatusForbidden, err.Error())
+	case errors.Is(err, task.ErrSavedViewInvalid):
+		resp.SendError(w, r, http.StatusUnprocessableEntity, err.Error())
+	default:
+		resp.SendError(w, r, http.StatusInternalServerError, fallback)
+	}
+}
+
+// parseViewID извлекает ID представления из URL.
+func parseViewID(r *http.Request) (uint, error) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "viewID"), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
+
+// GetSavedViews
+// @Summary List saved task views
+// @Tags task-views
+// @Description Returns the views for the sidebar in their manual order: the user's personal views followed by shared views of all their teams. With team_id only that team's shared views are returned.
+// @Produce json
+// @Param team_id query int false "Only shared views of this team"
+// @Success 200 {object} response.SuccessResponse{data=[]task.SavedViewResponse} "Saved views retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid team_id"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not a member of the team"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /task-views [get]
+// @Security ApiKeyAuth
+func (c *TaskController) GetSavedViews(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.GetSavedViews"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	var teamID *uint
+	if teamIDStr := r.URL.Query().Get("team_id"); teamIDStr != "" {
+		id, err := strconv.ParseUint(teamIDStr, 10, 32)
+		if err != nil {
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid team_id")
+			return
+		}
+		uid := uint(id)
+		teamID = &uid
+	}
+
+	views, err := c.useCase.GetSavedViews(userID, teamID)
+	if err != nil {
+		log.Error("usecase GetSavedViews failed", "error", err)
+		sendSavedViewError(w, r, err, "Failed to retrieve saved views")
+		return
+	}
+
+	resp.SendSuccess(w, r, http.StatusOK, views)
+}
+
+// CreateSavedView
+// @Summary Create a saved task view
+// @Tags task-views
+// @Description Saves a named set of task filters. With team_id the view is shared with the team; only editors and above may create shared views. Deadline bounds accept relative tokens: today, tomorrow, yesterday, +Nd/-Nd, +Nw, +Nm, or a date.
+// @Accept json
+// @Produce json
+// @Param view body task.CreateSavedViewRequest true "View name and filters"
+// @Success 201 {object} task.SavedViewResponse "Saved view created successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload or validation error"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Not allowed to create shared views in the team"
+// @Failure 422 {object} response.ErrorResponse "Invalid filters (e.g., unknown date token)"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /task-views [post]
+// @Security ApiKeyAuth
+func (c *TaskController) CreateSavedView(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.CreateSavedView"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	var req task.CreateSavedViewRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body for CreateSavedView", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed for CreateSavedViewRequest", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	view, err := c.useCase.CreateSavedView(userID, req)
+	if err != nil {
+		log.Error("usecase CreateSavedView failed", "error", err)
+		sendSavedViewError(w, r, err, "Failed to create saved view")
+		return
+	}
+
+	log.Info("saved view created", slog.Uint64("viewID", uint64(view.ViewID)))
+	resp.SendSuccess(w, r, http.StatusCreated, view)
+}
+
+// UpdateSavedView
+// @Summary Update a saved task view
+// @Tags task-views
+// @Description Renames a view and/or replaces its filters. Personal views can be changed by their owner, shared views by team editors and above.
+// @Accept json
+// @Produce json
+// @Param viewID path int true "View ID"
+// @Param view body task.UpdateSavedViewRequest true "New name and/or filters"
+// @Success 200 {object} task.SavedViewResponse "Saved view updated successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload, validation error, or invalid View ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Access denied"
+// @Failure 404 {object} response.ErrorResponse "Saved view not found"
+// @Failure 422 {object} response.ErrorResponse "Invalid filters"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /task-views/{viewID} [put]
+// @Security ApiKeyAuth
+func (c *TaskController) UpdateSavedView(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.UpdateSavedView"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	viewID, err := parseViewID(r)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid View ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("viewID", uint64(viewID)))
+
+	var req task.UpdateSavedViewRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body for UpdateSavedView", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed for UpdateSavedViewRequest", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+	if req.Name == nil && req.Filters == nil {
+		resp.SendError(w, r, http.StatusBadRequest, "No fields to update")
+		return
+	}
+
+	view, err := c.useCase.UpdateSavedView(viewID, userID, req)
+	if err != nil {
+		log.Error("usecase UpdateSavedView failed", "error", err)
+		sendSavedViewError(w, r, err, "Failed to update saved view")
+		return
+	}
+
+	log.Info("saved view updated")
+	resp.SendSuccess(w, r, http.StatusOK, view)
+}
+
+// DeleteSavedView
+// @Summary Delete a saved task view
+// @Tags task-views
+// @Produce json
+// @Param viewID path int true "View ID"
+// @Success 204 "Saved view deleted successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid View ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Access denied"
+// @Failure 404 {object} response.ErrorResponse "Saved view not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /task-views/{viewID} [delete]
+// @Security ApiKeyAuth
+func (c *TaskController) DeleteSavedView(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.DeleteSavedView"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	viewID, err := parseViewID(r)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid View ID")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("viewID", uint64(viewID)))
+
+	if err := c.useCase.DeleteSavedView(viewID, userID); err != nil {
+		log.Error("usecase DeleteSavedView failed", "error", err)
+		sendSavedViewError(w, r, err, "Failed to delete saved view")
+		return
+	}
+
+	log.Info("saved view deleted")
+	resp.SendOK(w, r, http.StatusNoContent)
+}
+
+// ReorderSavedViews
+// @Summary Reorder saved task views
+// @Tags task-views
+// @Description Sets the sidebar order. view_ids must list all personal views (without team_id) or all shared views of the team (with team_id) in the new order. Reordering shared views requires editor role or above.
+// @Accept json
+// @Produce json
+// @Param order body task.ReorderSavedViewsRequest true "Views in the new order"
+// @Success 200 {object} response.SuccessResponse{data=[]task.SavedViewResponse} "Saved views reordered successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request payload or validation error"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Access denied"
+// @Failure 422 {object} response.ErrorResponse "view_ids do not match the existing views"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /task-views/order [put]
+// @Security ApiKeyAuth
+func (c *TaskController) ReorderSavedViews(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.ReorderSavedViews"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	var req task.ReorderSavedViewsRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body for ReorderSavedViews", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed for ReorderSavedViewsRequest", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	views, err := c.useCase.ReorderSavedViews(userID, req)
+	if err != nil {
+		log.Error("usecase ReorderSavedViews failed", "error", err)
+		sendSavedViewError(w, r, err, "Failed to reorder saved views")
+		return
+	}
+
+	resp.SendSuccess(w, r, http.StatusOK, views)
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"server/internal/modules/task"
+	resp "server/pkg/lib/response"
+)
+
+// WatchTask
+// @Summary Watch a task
+// @Tags tasks
+// @Description Subscribes the current user to push notifications about task changes without making them responsible for it. Repeated calls are ignored.
+// @Param taskID path int true "Task ID"
+// @Success 204 "Watching the task"
+// @Failure 400 {object} response.ErrorResponse "Invalid Task ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Access denied"
+// @Failure 404 {object} response.ErrorResponse "Task not found"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /tasks/{taskID}/watch [put]
+// @Security ApiKeyAuth
+func (c *TaskController) WatchTask(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.WatchTask"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	taskID, ok := parseTaskIDParam(w, r)
+	if !ok {
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("taskID", uint64(taskID)))
+
+	if err := c.useCase.WatchTask(taskID, userID); err != nil {
+		log.Error("usecase WatchTask failed", "error", err)
+		switch {
+		case errors.Is(err, task.ErrTaskNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, task.ErrTaskAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to watch task")
+		}
+		return
+	}
+	resp.SendOK(w, r, http.StatusNoContent)
+}
+
+// UnwatchTask
+// @Summary Stop watching a task
+// @Tags tasks
+// @Description Unsubscribes the current user from task notifications. Succeeds even if the user was not watching the task.
+// @Param taskID path int true "Task ID"
+// @Success 204 "Not watching the task"
+// @Failure 400 {object} response.ErrorResponse "Invalid Task ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 500 {object} response.ErrorResponse "Internal server error"
+// @Router /tasks/{taskID}/watch [delete]
+// @Security ApiKeyAuth
+func (c *TaskController) UnwatchTask(w http.ResponseWriter, r *http.Request) {
+	op := "TaskController.UnwatchTask"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	taskID, ok := parseTaskIDParam(w, r)
+	if !ok {
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)), slog.Uint64("taskID", uint64(taskID)))
+
+	if err := c.useCase.UnwatchTask(taskID, userID); err != nil {
+		log.Error("usecase UnwatchTask failed", "error", err)
+		resp.SendError(w, r, http.StatusInternalServerError, "Failed to unwatch task")
+		return
+	}
+	resp.SendOK(w, r, http.StatusNoContent)
+}
@@ -4,6 +4,7 @@ package task
 import (
 	"net/http" // Для Controller
 	"server/internal/modules/tag"
+	"strconv"
 	"time"
 )
 
@@ -14,22 +15,88 @@ type Task struct {
 	Description      *string    `gorm:"type:text;column:description"`
 	Deadline         *time.Time `gorm:"column:deadline"`
 	Status           string     `gorm:"type:varchar(50);default:'todo';not null;column:status"`
+	StatusCategory   string     `gorm:"type:varchar(10);default:'open';not null;column:status_category"`
 	Priority         int        `gorm:"default:1;not null;column:priority"`
+	Position         float64    `gorm:"default:0;not null;column:position"` // Ручной порядок внутри колонки статуса
+	EstimateMinutes  *int       `gorm:"column:estimate_minutes"`            // Оценка трудоемкости
 	CreatedByUserID  uint       `gorm:"column:created_by_user_id;not null"`
 	AssignedToUserID *uint      `gorm:"column:assigned_to_user_id"`
 	TeamID           *uint      `gorm:"column:team_id"`
+	ProjectID        *uint      `gorm:"column:project_id"` // Проект команды; только для задач команды
+	SprintID         *uint      `gorm:"column:sprint_id"`  // Спринт команды; только для задач команды
 	CreatedAt        time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
 	UpdatedAt        time.Time  `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP"`
 	CompletedAt      *time.Time `gorm:"column:completed_at"`
 	IsDeleted        bool       `gorm:"default:false;not null;column:is_deleted"`
 	DeletedAt        *time.Time `gorm:"column:deleted_at"`
 	DeletedByUserID  *uint      `gorm:"column:deleted_by_user_id"`
+
+	// Результаты полнотекстового поиска: вычисляются только в выборке с search, в таблицу не записываются
+	SearchRank           *float64 `gorm:"->;column:search_rank"`
+	TitleHighlight       *string  `gorm:"->;column:title_highlight"`
+	DescriptionHighlight *string  `gorm:"->;column:description_highlight"`
 }
 
 func (Task) TableName() string {
 	return "tasks"
 }
 
+// TaskAssignee - GORM модель для таблицы 'taskassignees' (исполнители задачи)
+type TaskAssignee struct {
+	TaskID     uint      `gorm:"primaryKey;column:task_id"`
+	UserID     uint      `gorm:"primaryKey;column:user_id"`
+	AssignedAt time.Time `gorm:"column:assigned_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (TaskAssignee) TableName() string {
+	return "taskassignees"
+}
+
+// TaskWatcher - GORM модель для таблицы 'taskwatchers' (наблюдатели задачи)
+type TaskWatcher struct {
+	TaskID    uint      `gorm:"primaryKey;column:task_id"`
+	UserID    uint      `gorm:"primaryKey;column:user_id"`
+	CreatedAt time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (TaskWatcher) TableName() string {
+	return "taskwatchers"
+}
+
+// TaskEventType - тип записи в истории изменений задачи
+type TaskEventType string
+
+const (
+	EventTaskCreated            TaskEventType = "created"
+	EventTaskUpdated            TaskEventType = "updated"
+	EventTaskDeleted            TaskEventType = "deleted"
+	EventTaskRestored           TaskEventType = "restored"
+	EventTaskDeletedPermanently TaskEventType = "deleted_permanently"
+	EventTaskTagAdded           TaskEventType = "tag_added"
+	EventTaskTagRemoved         TaskEventType = "tag_removed"
+	EventTaskAssigneeAdded      TaskEventType = "assignee_added"
+	EventTaskAssigneeRemoved    TaskEventType = "assignee_removed"
+	EventTaskMoved              TaskEventType = "moved"  // Перенос между личным пространством и командами
+	EventTaskCopied             TaskEventType = "copied" // Задача создана копированием (в new_value - ID исходной задачи)
+)
+
+// TaskEvent - GORM модель для таблицы 'taskevents' (журнал изменений задач, только добавление)
+type TaskEvent struct {
+	EventID     uint64        `gorm:"primaryKey;column:event_id;autoIncrement"`
+	TaskID      uint          `gorm:"column:task_id;not null"`
+	TeamID      *uint         `gorm:"column:team_id"`
+	ActorUserID *uint         `gorm:"column:actor_user_id"`
+	EventType   TaskEventType `gorm:"type:varchar(30);not null;column:event_type"`
+	FieldName   *string       `gorm:"type:varchar(50);column:field_name"`
+	OldValue    *string       `gorm:"type:text;column:old_value"`
+	NewValue    *string       `gorm:"type:text;column:new_value"`
+	CreatedAt   time.Time     `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (TaskEvent) TableName() string {
+	return "taskevents"
+}
+
 // TaskResponse - DTO для ответа API (получение задачи/задач)
 type TaskResponse struct {
 	TaskID           uint               `json:"task_id"`
@@ -37,10 +104,18 @@ type TaskResponse struct {
 	Description      *string            `json:"description,omitempty"`
 	Deadline         *time.Time         `json:"deadline,omitempty"`
 	Status           string             `json:"status"`
+	StatusCategory   string             `json:"status_category"`
 	Priority         int                `json:"priority"`
+	Position         float64            `json:"position"`
+	EstimateMinutes  *int               `json:"estimate_minutes,omitempty"`
+	LoggedMinutes    int64              `json:"logged_minutes"` // Учтенное время, включая запущенные таймеры
 	CreatedByUserID  uint               `json:"created_by_user_id"`
-	AssignedToUserID *uint              `json:"assigned_to_user_id,omitempty"`
+	AssignedToUserID *uint              `json:"assigned_to_user_id,omitempty"` // Основной исполнитель, всегда первый в assignee_ids
+	AssigneeIDs      []uint             `json:"assignee_ids"`
+	WatcherIDs       []uint             `json:"watcher_ids"`
 	TeamID           *uint              `json:"team_id,omitempty"`
+	ProjectID        *uint              `json:"project_id,omitempty"`
+	SprintID         *uint              `json:"sprint_id,omitempty"`
 	Tags             []*tag.TagResponse `json:"tags,omitempty"`
 	CreatedAt        time.Time          `json:"created_at"`
 	UpdatedAt        time.Time          `json:"updated_at"`
@@ -48,6 +123,14 @@ type TaskResponse struct {
 	IsDeleted        bool               `json:"is_deleted"`
 	DeletedAt        *time.Time         `json:"deleted_at,omitempty"`         // <<< ДОБАВЛЕНО
 	DeletedByUserID  *uint              `json:"deleted_by_user_id,omitempty"` // <<< ДОБАВЛЕНО
+	Search           *TaskSearchHit     `json:"search,omitempty"`
+}
+
+// TaskSearchHit - релевантность и фрагменты с подсветкой совпадений (только в выдаче с search)
+type TaskSearchHit struct {
+	Rank                 float64 `json:"rank"`
+	TitleHighlight       *string `json:"title_highlight,omitempty"`
+	DescriptionHighlight *string `json:"description_highlight,omitempty"`
 }
 
 // --- Конвертеры ---
@@ -55,16 +138,21 @@ func ToTaskResponse(task *Task) *TaskResponse {
 	if task == nil {
 		return nil
 	}
-	return &TaskResponse{
+	resp := &TaskResponse{
 		TaskID:           task.TaskID,
 		Title:            task.Title,
 		Description:      task.Description,
 		Deadline:         task.Deadline,
 		Status:           task.Status,
+		StatusCategory:   task.StatusCategory,
 		Priority:         task.Priority,
+		Position:         task.Position,
+		EstimateMinutes:  task.EstimateMinutes,
 		CreatedByUserID:  task.CreatedByUserID,
 		AssignedToUserID: task.AssignedToUserID,
 		TeamID:           task.TeamID,
+		ProjectID:        task.ProjectID,
+		SprintID:         task.SprintID,
 		CreatedAt:        task.CreatedAt,
 		UpdatedAt:        task.UpdatedAt,
 		CompletedAt:      task.CompletedAt,
@@ -72,6 +160,14 @@ func ToTaskResponse(task *Task) *TaskResponse {
 		DeletedAt:        task.DeletedAt,       // <<< ДОБАВЛЕНО
 		DeletedByUserID:  task.DeletedByUserID, // <<< ДОБАВЛЕНО
 	}
+	if task.SearchRank != nil {
+		resp.Search = &TaskSearchHit{
+			Rank:                 *task.SearchRank,
+			TitleHighlight:       task.TitleHighlight,
+			DescriptionHighlight: task.DescriptionHighlight,
+		}
+	}
+	return resp
 }
 
 func ToTaskResponseList(tasks []*Task) []*TaskResponse {
@@ -85,6 +181,37 @@ func ToTaskResponseList(tasks []*Task) []*TaskResponse {
 	return responses
 }
 
+// TaskEventResponse - DTO для записи истории изменений задачи
+type TaskEventResponse struct {
+	EventID     uint64        `json:"event_id"`
+	TaskID      uint          `json:"task_id"`
+	TeamID      *uint         `json:"team_id,omitempty"`
+	ActorUserID *uint         `json:"actor_user_id,omitempty"`
+	EventType   TaskEventType `json:"event_type"`
+	FieldName   *string       `json:"field_name,omitempty"`
+	OldValue    *string       `json:"old_value,omitempty"`
+	NewValue    *string       `json:"new_value,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+func ToTaskEventResponseList(events []*TaskEvent) []*TaskEventResponse {
+	responses := make([]*TaskEventResponse, len(events))
+	for i, e := range events {
+		responses[i] = &TaskEventResponse{
+			EventID:     e.EventID,
+			TaskID:      e.TaskID,
+			TeamID:      e.TeamID,
+			ActorUserID: e.ActorUserID,
+			EventType:   e.EventType,
+			FieldName:   e.FieldName,
+			OldValue:    e.OldValue,
+			NewValue:    e.NewValue,
+			CreatedAt:   e.CreatedAt,
+		}
+	}
+	return responses
+}
+
 // --- Параметры для фильтрации и сортировки ---
 type SortDirection string
 
@@ -102,8 +229,95 @@ const (
 	FieldPriority  TaskSortableField = "priority"
 	FieldStatus    TaskSortableField = "status"
 	FieldTitle     TaskSortableField = "title"
+	FieldPosition  TaskSortableField = "position"
+	FieldDeletedAt TaskSortableField = "deleted_at" // Только для корзины, не передается клиентом
+	FieldRelevance TaskSortableField = "relevance"  // Только вместе с search
+)
+
+// IsNullable сообщает, может ли поле сортировки быть NULL (такие значения идут в конце при ASC).
+func (f TaskSortableField) IsNullable() bool {
+	return f == FieldDeadline || f == FieldDeletedAt
+}
+
+// SortValue возвращает значение поля сортировки задачи в строковом виде для курсора (nil для NULL).
+func (t *Task) SortValue(field TaskSortableField) *string {
+	formatTime := func(v time.Time) *string {
+		s := v.UTC().Format(time.RFC3339Nano)
+		return &s
+	}
+	var s string
+	switch field {
+	case FieldCreatedAt:
+		return formatTime(t.CreatedAt)
+	case FieldUpdatedAt:
+		return formatTime(t.UpdatedAt)
+	case FieldDeadline:
+		if t.Deadline == nil {
+			return nil
+		}
+		return formatTime(*t.Deadline)
+	case FieldDeletedAt:
+		if t.DeletedAt == nil {
+			return nil
+		}
+		return formatTime(*t.DeletedAt)
+	case FieldPriority:
+		s = strconv.Itoa(t.Priority)
+	case FieldPosition:
+		s = strconv.FormatFloat(t.Position, 'g', -1, 64)
+	case FieldStatus:
+		s = t.Status
+	case FieldTitle:
+		s = t.Title
+	case FieldRelevance:
+		if t.SearchRank != nil {
+			s = strconv.FormatFloat(*t.SearchRank, 'g', -1, 64)
+		} else {
+			s = "0"
+		}
+	}
+	return &s
+}
+
+// TaskCursor - позиция в выдаче для keyset-пагинации: значение поля сортировки и task_id последней задачи страницы.
+// Сортировка входит в курсор, чтобы курсор нельзя было применить к выдаче с другим порядком.
+type TaskCursor struct {
+	SortBy    TaskSortableField `json:"s"`
+	SortOrder SortDirection     `json:"o"`
+	Value     *string           `json:"v,omitempty"`
+	TaskID    uint              `json:"id"`
+}
+
+// TaskListMeta - метаданные списка задач
+type TaskListMeta struct {
+	NextCursor *string     `json:"next_cursor"`
+	Total      *int64      `json:"total,omitempty"`
+	Facets     *TaskFacets `json:"facets,omitempty"`
+}
+
+// TagMatchMode - как сочетаются несколько тегов в фильтре: любой из них или все сразу
+type TagMatchMode string
+
+const (
+	TagMatchAny TagMatchMode = "any"
+	TagMatchAll TagMatchMode = "all"
 )
 
+// TaskTagFacet - количество задач выборки с данным тегом
+type TaskTagFacet struct {
+	TagID uint    `json:"tag_id"`
+	Type  string  `json:"type"` // "user" или "team"
+	Name  string  `json:"name"`
+	Color *string `json:"color,omitempty"`
+	Count int64   `json:"count"`
+}
+
+// TaskFacets - распределение задач выборки по тегам (по всей выборке, без учета пагинации)
+type TaskFacets struct {
+	Tags     []*TaskTagFacet `json:"tags"`
+	Untagged int64           `json:"untagged"`
+}
+
 type GetTasksViewType string
 
 const (
@@ -116,39 +330,135 @@ type GetTasksParams struct {
 	UserID           uint
 	ViewType         GetTasksViewType
 	TeamID           *uint
+	ProjectID        *uint
+	SprintID         *uint
 	Status           *string
+	StatusCategory   *string
 	Priority         *int
 	AssignedToUserID *uint
 	DeadlineFrom     *time.Time
 	DeadlineTo       *time.Time
+	Overdue          bool // Дедлайн прошел, а задача не завершена
 	SearchQuery      *string
 	SortBy           TaskSortableField
 	SortOrder        SortDirection
 	IsDeleted        *bool // <<< ДОБАВЛЕНО
+	UserTagIDs       []uint
+	TeamTagIDs       []uint
+	Untagged         bool
+	TagMatch         TagMatchMode
+	ScopeTeamTagID   *uint // Гость видит только задачи с этим тегом команды (задается сервером, не клиентом)
+	Cursor           *TaskCursor
+	Limit            int // 0 - без ограничения
+}
+
+// EffectiveSort возвращает фактический порядок выдачи: корзина всегда сортируется по дате удаления.
+func (p GetTasksParams) EffectiveSort() (TaskSortableField, SortDirection) {
+	if p.IsDeleted != nil && *p.IsDeleted {
+		return FieldDeletedAt, SortDirectionDesc
+	}
+	hasSearch := p.SearchQuery != nil && *p.SearchQuery != ""
+	if p.SortBy == "" || (p.SortBy == FieldRelevance && !hasSearch) {
+		if hasSearch {
+			return FieldRelevance, SortDirectionDesc
+		}
+		return FieldUpdatedAt, SortDirectionDesc
+	}
+	if p.SortOrder == SortDirectionDesc {
+		return p.SortBy, SortDirectionDesc
+	}
+	return p.SortBy, SortDirectionAsc
 }
 
 type GetTasksRequest struct {
 	ViewType         *GetTasksViewType  `form:"view_type" validate:"omitempty,oneof=global personal"`
 	TeamID           *uint              `form:"team_id"`
-	Status           *string            `form:"status" validate:"omitempty,oneof=todo in_progress deferred done"`
+	ProjectID        *uint              `form:"project_id"`
+	SprintID         *uint              `form:"sprint_id"`
+	Status           *string            `form:"status" validate:"omitempty,min=1,max=50"`
+	StatusCategory   *string            `form:"status_category" validate:"omitempty,oneof=open active done"`
 	Priority         *int               `form:"priority" validate:"omitempty,min=1,max=3"`
 	AssignedToUserID *uint              `form:"assigned_to_user_id"`
 	DeadlineFrom     *time.Time         `form:"deadline_from"`
 	DeadlineTo       *time.Time         `form:"deadline_to"`
+	Overdue          *bool              `form:"overdue"`
 	Search           *string            `form:"search" validate:"omitempty,min=1"`
-	SortBy           *TaskSortableField `form:"sort_by" validate:"omitempty,oneof=created_at updated_at deadline priority status title"`
+	SortBy           *TaskSortableField `form:"sort_by" validate:"omitempty,oneof=created_at updated_at deadline priority status title position relevance"`
 	SortOrder        *SortDirection     `form:"sort_order" validate:"omitempty,oneof=ASC DESC"`
 	IsDeleted        *bool              `form:"is_deleted"` // <<< ДОБАВЛЕНО
+	UserTagIDs       []uint             `form:"user_tag_ids" validate:"omitempty,max=50"`
+	TeamTagIDs       []uint             `form:"team_tag_ids" validate:"omitempty,max=50"`
+	Untagged         *bool              `form:"untagged"`
+	TagMatch         *TagMatchMode      `form:"tag_match" validate:"omitempty,oneof=any all"`
+	IncludeFacets    *bool              `form:"include_facets"`
+	Limit            *int               `form:"limit" validate:"omitempty,min=1,max=500"`
+	Cursor           *string            `form:"cursor" validate:"omitempty,max=1024"`
+	IncludeTotal     *bool              `form:"include_total"`
+	ViewID           *uint              `form:"view_id"` // Фильтры берутся из сохраненного представления
+}
+
+// GetTeamActivityParams - параметры выборки ленты активности команды для репозитория
+type GetTeamActivityParams struct {
+	TeamID      uint
+	ActorUserID *uint
+	From        *time.Time
+	To          *time.Time
+	Limit       int
+}
+
+// GetTeamActivityRequest - DTO для query-параметров ленты активности команды
+type GetTeamActivityRequest struct {
+	ActorUserID *uint      `form:"member_id"`
+	From        *time.Time `form:"from"`
+	To          *time.Time `form:"to"`
+	Limit       *int       `form:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+// TaskPositionScope - колонка, внутри которой задается ручной порядок задач:
+// статус командной задачи в команде или статус личных задач автора.
+type TaskPositionScope struct {
+	TeamID      *uint
+	OwnerUserID uint
+	Status      string
+}
+
+// PositionStep - шаг между соседними позициями при добавлении в конец колонки и перенумерации.
+const PositionStep = 1024.0
+
+// ScopeOfTask возвращает колонку, к которой относится задача.
+func ScopeOfTask(t *Task) TaskPositionScope {
+	if t.TeamID != nil {
+		return TaskPositionScope{TeamID: t.TeamID, Status: t.Status}
+	}
+	return TaskPositionScope{OwnerUserID: t.CreatedByUserID, Status: t.Status}
+}
+
+// Equal сравнивает колонки по значению (TeamID - указатель).
+func (s TaskPositionScope) Equal(other TaskPositionScope) bool {
+	if (s.TeamID == nil) != (other.TeamID == nil) {
+		return false
+	}
+	if s.TeamID != nil && *s.TeamID != *other.TeamID {
+		return false
+	}
+	return s.OwnerUserID == other.OwnerUserID && s.Status == other.Status
 }
 
+// Исполнители задачи: assignee_ids задает полный список, assigned_to_user_id - основного исполнителя.
+// Если передан только assigned_to_user_id (старые клиенты), он заменяет прежнего основного исполнителя,
+// остальные исполнители сохраняются; снятие основного исполнителя делает основным следующего по списку.
 type CreateTaskRequest struct {
 	Title            string     `json:"title" validate:"required,min=1,max=255"`
 	Description      *string    `json:"description,omitempty" validate:"omitempty,max=65535"`
 	Deadline         *time.Time `json:"deadline,omitempty"`
-	Status           *string    `json:"status,omitempty" validate:"omitempty,oneof=todo in_progress deferred done"`
+	Status           *string    `json:"status,omitempty" validate:"omitempty,min=1,max=50"`
 	Priority         *int       `json:"priority,omitempty" validate:"omitempty,min=1,max=3"`
 	AssignedToUserID *uint      `json:"assigned_to_user_id,omitempty"`
+	AssigneeIDs      []uint     `json:"assignee_ids,omitempty" validate:"omitempty,max=20,unique"`
 	TeamID           *uint      `json:"team_id,omitempty"`
+	ProjectID        *uint      `json:"project_id,omitempty"` // Только для задачи команды; проект должен принадлежать этой команде
+	SprintID         *uint      `json:"sprint_id,omitempty"`  // Только для задачи команды; спринт этой команды, еще не закрытый
+	EstimateMinutes  *int       `json:"estimate_minutes,omitempty" validate:"omitempty,min=1,max=100000"`
 	UserTagIDs       []uint     `json:"user_tag_ids,omitempty"`
 	TeamTagIDs       []uint     `json:"team_tag_ids,omitempty"`
 }
@@ -157,9 +467,13 @@ type UpdateTaskRequest struct {
 	Title            string     `json:"title" validate:"required,min=1,max=255"`
 	Description      *string    `json:"description" validate:"omitempty,max=65535"`
 	Deadline         *time.Time `json:"deadline"`
-	Status           string     `json:"status" validate:"required,oneof=todo in_progress deferred done"`
+	Status           string     `json:"status" validate:"required,min=1,max=50"`
 	Priority         int        `json:"priority" validate:"required,min=1,max=3"`
 	AssignedToUserID *uint      `json:"assigned_to_user_id"`
+	AssigneeIDs      *[]uint    `json:"assignee_ids,omitempty" validate:"omitempty,max=20,unique"`
+	EstimateMinutes  *int       `json:"estimate_minutes" validate:"omitempty,min=1,max=100000"`
+	ProjectID        *uint      `json:"project_id,omitempty"` // nil - без изменений, 0 - убрать задачу из проекта
+	SprintID         *uint      `json:"sprint_id,omitempty"`  // nil - без изменений, 0 - вернуть задачу в бэклог
 	UserTagIDs       *[]uint    `json:"user_tag_ids,omitempty"`
 	TeamTagIDs       *[]uint    `json:"team_tag_ids,omitempty"`
 }
@@ -169,15 +483,763 @@ type PatchTaskRequest struct {
 	Description      *string    `json:"description,omitempty" validate:"omitempty,max=65535"`
 	Deadline         *time.Time `json:"deadline,omitempty"`
 	ClearDeadline    *bool      `json:"clear_deadline,omitempty"`
-	Status           *string    `json:"status,omitempty" validate:"omitempty,oneof=todo in_progress deferred done"`
+	Status           *string    `json:"status,omitempty" validate:"omitempty,min=1,max=50"`
 	Priority         *int       `json:"priority,omitempty" validate:"omitempty,min=1,max=3"`
 	AssignedToUserID *uint      `json:"assigned_to_user_id,omitempty"`
 	ClearAssignedTo  *bool      `json:"clear_assigned_to,omitempty"`
+	AssigneeIDs      *[]uint    `json:"assignee_ids,omitempty" validate:"omitempty,max=20,unique"`
+	EstimateMinutes  *int       `json:"estimate_minutes,omitempty" validate:"omitempty,min=1,max=100000"`
+	ClearEstimate    *bool      `json:"clear_estimate,omitempty"`
+	ProjectID        *uint      `json:"project_id,omitempty"`
+	ClearProject     *bool      `json:"clear_project,omitempty"`
+	SprintID         *uint      `json:"sprint_id,omitempty"`
+	ClearSprint      *bool      `json:"clear_sprint,omitempty"`
 	UserTagIDs       *[]uint    `json:"user_tag_ids,omitempty"`
 	TeamTagIDs       *[]uint    `json:"team_tag_ids,omitempty"`
 	IsDeleted        *bool      `json:"is_deleted,omitempty"` // <<< ДОБАВЛЕНО
 }
 
+// MoveTaskRequest - DTO для перемещения задачи на доске: новый статус (колонка) и место в ней.
+// Если указаны оба соседа, используется after_task_id. Без соседей задача ставится в конец колонки.
+type MoveTaskRequest struct {
+	Status       *string `json:"status,omitempty" validate:"omitempty,min=1,max=50"`
+	AfterTaskID  *uint   `json:"after_task_id,omitempty"`
+	BeforeTaskID *uint   `json:"before_task_id,omitempty"`
+}
+
+// TaskTransferMode - перенос задачи в другое пространство или создание ее копии там
+type TaskTransferMode string
+
+const (
+	TaskTransferMove TaskTransferMode = "move"
+	TaskTransferCopy TaskTransferMode = "copy"
+)
+
+// TransferTaskRequest - DTO для переноса или копирования задачи между личным пространством и командами.
+// TeamID = nil - личное пространство текущего пользователя. Теги сопоставляются по имени;
+// без create_missing_tags теги, которых нет в целевом пространстве, не переносятся.
+// AssigneeIDs заменяет исполнителей; без него исполнители сохраняются, если могут быть назначены в целевом пространстве.
+type TransferTaskRequest struct {
+	Mode              TaskTransferMode `json:"mode" validate:"required,oneof=move copy"`
+	TeamID            *uint            `json:"team_id,omitempty"`
+	AssigneeIDs       *[]uint          `json:"assignee_ids,omitempty" validate:"omitempty,max=20,unique"`
+	CreateMissingTags bool             `json:"create_missing_tags,omitempty"`
+}
+
+// TransferTaskResponse - задача в целевом пространстве и результат сопоставления тегов по имени.
+type TransferTaskResponse struct {
+	Task        *TaskResponse `json:"task"`
+	CreatedTags []string      `json:"created_tags"`
+	DroppedTags []string      `json:"dropped_tags"`
+}
+
+// BulkTaskOperation - операция, применяемая ко всем задачам массового запроса
+type BulkTaskOperation string
+
+const (
+	BulkSetStatus         BulkTaskOperation = "set_status"
+	BulkSetPriority       BulkTaskOperation = "set_priority"
+	BulkAssign            BulkTaskOperation = "assign"
+	BulkAddTags           BulkTaskOperation = "add_tags"
+	BulkRemoveTags        BulkTaskOperation = "remove_tags"
+	BulkTrash             BulkTaskOperation = "trash"
+	BulkRestore           BulkTaskOperation = "restore"
+	BulkDeletePermanently BulkTaskOperation = "delete_permanently"
+)
+
+// BulkTaskRequest - DTO для массовой операции над задачами.
+// Параметр операции зависит от operation: status, priority, assigned_to_user_id (nil - снять исполнителя)
+// или user_tag_ids / team_tag_ids. С atomic = true все изменения выполняются в одной транзакции:
+// первая ошибка откатывает весь запрос.
+type BulkTaskRequest struct {
+	TaskIDs          []uint            `json:"task_ids" validate:"required,min=1,max=500,unique"`
+	Operation        BulkTaskOperation `json:"operation" validate:"required,oneof=set_status set_priority assign add_tags remove_tags trash restore delete_permanently"`
+	Status           *string           `json:"status,omitempty" validate:"omitempty,min=1,max=50"`
+	Priority         *int              `json:"priority,omitempty" validate:"omitempty,min=1,max=3"`
+	AssignedToUserID *uint             `json:"assigned_to_user_id,omitempty"`
+	UserTagIDs       []uint            `json:"user_tag_ids,omitempty" validate:"omitempty,max=50"`
+	TeamTagIDs       []uint            `json:"team_tag_ids,omitempty" validate:"omitempty,max=50"`
+	Atomic           bool              `json:"atomic,omitempty"`
+}
+
+// BulkTaskResult - результат операции для одной задачи
+type BulkTaskResult struct {
+	TaskID  uint   `json:"task_id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkTaskResponse - DTO ответа массовой операции.
+// RolledBack = true означает, что в режиме atomic изменения не сохранены ни для одной задачи.
+type BulkTaskResponse struct {
+	Operation  BulkTaskOperation `json:"operation"`
+	Atomic     bool              `json:"atomic"`
+	RolledBack bool              `json:"rolled_back"`
+	Succeeded  int               `json:"succeeded"`
+	Failed     int               `json:"failed"`
+	Results    []*BulkTaskResult `json:"results"`
+}
+
+// --- Экспорт и импорт ---
+
+type TaskExportFormat string
+
+const (
+	ExportFormatCSV  TaskExportFormat = "csv"
+	ExportFormatJSON TaskExportFormat = "json"
+)
+
+type TaskImportFormat string
+
+const (
+	ImportFormatJSON    TaskImportFormat = "json" // Собственный формат (массив TaskExportItem)
+	ImportFormatCSV     TaskImportFormat = "csv"
+	ImportFormatTodoist TaskImportFormat = "todoist"
+	ImportFormatTrello  TaskImportFormat = "trello"
+)
+
+// TaskExportColumns - колонки CSV экспорта; по умолчанию они же ожидаются при импорте CSV.
+var TaskExportColumns = []string{
+	"task_id", "title", "description", "status", "status_category", "priority", "deadline",
+	"assignee", "assignees", "created_by", "team_id", "tags", "created_at", "updated_at", "completed_at",
+}
+
+// TaskExportRequest - DTO для query-параметров экспорта: без team_id выгружаются личные задачи.
+type TaskExportRequest struct {
+	Format TaskExportFormat `form:"format" validate:"required,oneof=csv json"`
+	TeamID *uint            `form:"team_id"`
+}
+
+// TaskExportItem - задача в формате экспорта: теги и пользователи заменены на имена и логины.
+// Этот же формат принимает импорт с format=json (assignee и assignees - логины или email).
+// Assignee - основной исполнитель, Assignees - все исполнители задачи, основной первым.
+type TaskExportItem struct {
+	TaskID         uint       `json:"task_id,omitempty"`
+	Title          string     `json:"title"`
+	Description    *string    `json:"description,omitempty"`
+	Status         string     `json:"status,omitempty"`
+	StatusCategory string     `json:"status_category,omitempty"`
+	Priority       int        `json:"priority,omitempty"`
+	Deadline       *time.Time `json:"deadline,omitempty"`
+	Assignee       *string    `json:"assignee,omitempty"`
+	Assignees      []string   `json:"assignees,omitempty"`
+	CreatedBy      string     `json:"created_by,omitempty"`
+	TeamID         *uint      `json:"team_id,omitempty"`
+	Tags           []string   `json:"tags,omitempty"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+}
+
+// TaskImportRequest - параметры импорта (поля multipart-формы рядом с файлом).
+// Mapping для CSV сопоставляет поле задачи (title, description, status, priority, deadline, assignee, assignees, tags)
+// с заголовком колонки файла.
+type TaskImportRequest struct {
+	Format  TaskImportFormat `validate:"required,oneof=json csv todoist trello"`
+	TeamID  *uint
+	DryRun  bool
+	Mapping map[string]string `validate:"omitempty,max=20,dive,keys,oneof=title description status priority deadline assignee assignees tags,endkeys,min=1,max=100"`
+}
+
+// TaskImportRowResult - результат по одной строке (записи) импортируемого файла
+type TaskImportRowResult struct {
+	Row       int      `json:"row"` // Номер записи в файле, с 1
+	Title     string   `json:"title"`
+	Status    string   `json:"status,omitempty"`
+	Assignee  *string  `json:"assignee,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	TaskID    *uint    `json:"task_id,omitempty"`
+	Skipped   bool     `json:"skipped,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// TaskImportResult - итог импорта; при dry_run ничего не создается, Created = 0,
+// а TagsCreated содержит теги, которые будут созданы.
+type TaskImportResult struct {
+	DryRun      bool                   `json:"dry_run"`
+	Total       int                    `json:"total"`
+	Valid       int                    `json:"valid"`
+	Created     int                    `json:"created"`
+	Failed      int                    `json:"failed"`
+	Skipped     int                    `json:"skipped"`
+	TagsCreated []string               `json:"tags_created"`
+	Rows        []*TaskImportRowResult `json:"rows"`
+}
+
+// TaskUserRef - логин и email пользователя для экспорта и сопоставления исполнителей при импорте
+type TaskUserRef struct {
+	UserID uint   `gorm:"column:user_id"`
+	Login  string `gorm:"column:login"`
+	Email  string `gorm:"column:email"`
+}
+
+// SavedView - GORM модель для таблицы 'savedtaskviews' (сохраненные фильтры / умные списки).
+// TeamID = nil - личное представление владельца, иначе - общее представление команды.
+type SavedView struct {
+	ViewID      uint             `gorm:"primaryKey;column:view_id;autoIncrement"`
+	OwnerUserID uint             `gorm:"column:owner_user_id;not null"`
+	TeamID      *uint            `gorm:"column:team_id"`
+	Name        string           `gorm:"type:varchar(100);not null;column:name"`
+	Filters     SavedViewFilters `gorm:"type:jsonb;serializer:json;not null;column:filters"`
+	Position    int              `gorm:"default:0;not null;column:position"`
+	CreatedAt   time.Time        `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time        `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (SavedView) TableName() string {
+	return "savedtaskviews"
+}
+
+// SavedViewFilters - сохраненные параметры списка задач.
+// Границы дедлайна задаются токенами и вычисляются в момент выполнения:
+// today, tomorrow, yesterday, смещение вида +7d / -2w / +1m или конкретная дата (2006-01-02, RFC3339).
+// AssignedToMe подставляет текущего пользователя, поэтому общее представление работает для каждого участника.
+type SavedViewFilters struct {
+	ViewType         *GetTasksViewType  `json:"view_type,omitempty" validate:"omitempty,oneof=global personal"`
+	TeamID           *uint              `json:"team_id,omitempty"` // Для общих представлений всегда равен команде представления
+	ProjectID        *uint              `json:"project_id,omitempty"`
+	SprintID         *uint              `json:"sprint_id,omitempty"`
+	Status           *string            `json:"status,omitempty" validate:"omitempty,min=1,max=50"`
+	StatusCategory   *string            `json:"status_category,omitempty" validate:"omitempty,oneof=open active done"`
+	Priority         *int               `json:"priority,omitempty" validate:"omitempty,min=1,max=3"`
+	AssignedToUserID *uint              `json:"assigned_to_user_id,omitempty"`
+	AssignedToMe     bool               `json:"assigned_to_me,omitempty"`
+	DeadlineFrom     *string            `json:"deadline_from,omitempty" validate:"omitempty,max=40"`
+	DeadlineTo       *string            `json:"deadline_to,omitempty" validate:"omitempty,max=40"`
+	Overdue          bool               `json:"overdue,omitempty"`
+	Search           *string            `json:"search,omitempty" validate:"omitempty,min=1,max=255"`
+	UserTagIDs       []uint             `json:"user_tag_ids,omitempty" validate:"omitempty,max=50"`
+	TeamTagIDs       []uint             `json:"team_tag_ids,omitempty" validate:"omitempty,max=50"`
+	Untagged         bool               `json:"untagged,omitempty"`
+	TagMatch         *TagMatchMode      `json:"tag_match,omitempty" validate:"omitempty,oneof=any all"`
+	SortBy           *TaskSortableField `json:"sort_by,omitempty" validate:"omitempty,oneof=created_at updated_at deadline priority status title position relevance"`
+	SortOrder        *SortDirection     `json:"sort_order,omitempty" validate:"omitempty,oneof=ASC DESC"`
+}
+
+// SavedViewResponse - DTO сохраненного представления
+type SavedViewResponse struct {
+	ViewID      uint             `json:"view_id"`
+	OwnerUserID uint             `json:"owner_user_id"`
+	TeamID      *uint            `json:"team_id,omitempty"`
+	Name        string           `json:"name"`
+	Filters     SavedViewFilters `json:"filters"`
+	Position    int              `json:"position"`
+	IsShared    bool             `json:"is_shared"`
+	CanEdit     bool             `json:"can_edit"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+func ToSavedViewResponse(view *SavedView, canEdit bool) *SavedViewResponse {
+	return &SavedViewResponse{
+		ViewID:      view.ViewID,
+		OwnerUserID: view.OwnerUserID,
+		TeamID:      view.TeamID,
+		Name:        view.Name,
+		Filters:     view.Filters,
+		Position:    view.Position,
+		IsShared:    view.TeamID != nil,
+		CanEdit:     canEdit,
+		CreatedAt:   view.CreatedAt,
+		UpdatedAt:   view.UpdatedAt,
+	}
+}
+
+// CreateSavedViewRequest - DTO для создания представления; с team_id представление становится общим для команды.
+type CreateSavedViewRequest struct {
+	Name    string           `json:"name" validate:"required,min=1,max=100"`
+	TeamID  *uint            `json:"team_id,omitempty"`
+	Filters SavedViewFilters `json:"filters"`
+}
+
+// UpdateSavedViewRequest - DTO для изменения представления; фильтры заменяются целиком.
+type UpdateSavedViewRequest struct {
+	Name    *string           `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Filters *SavedViewFilters `json:"filters,omitempty"`
+}
+
+// ReorderSavedViewsRequest - DTO для порядка представлений в боковой панели.
+// Без team_id упорядочиваются личные представления, с team_id - общие представления команды.
+type ReorderSavedViewsRequest struct {
+	TeamID  *uint  `json:"team_id,omitempty"`
+	ViewIDs []uint `json:"view_ids" validate:"required,min=1,max=200"`
+}
+
+// --- Подписка на календарь (iCalendar) ---
+
+// CalendarFeed - GORM модель для таблицы 'calendarfeeds'.
+// TeamID = nil - личная лента пользователя, иначе - лента задач команды.
+type CalendarFeed struct {
+	FeedID         uint       `gorm:"primaryKey;column:feed_id;autoIncrement"`
+	UserID         uint       `gorm:"not null;column:user_id"`
+	TeamID         *uint      `gorm:"column:team_id"`
+	TokenHash      string     `gorm:"type:char(64);not null;uniqueIndex;column:token_hash"`
+	CreatedAt      time.Time  `gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+	LastAccessedAt *time.Time `gorm:"column:last_accessed_at"`
+	TeamName       *string    `gorm:"->;column:team_name"` // Только для чтения, из JOIN с teams
+}
+
+func (CalendarFeed) TableName() string {
+	return "calendarfeeds"
+}
+
+// CalendarFeedEntryType - тип записей ленты: VTODO (задачи) или VEVENT (события, для календарей без поддержки задач)
+type CalendarFeedEntryType string
+
+const (
+	CalendarEntryTodo  CalendarFeedEntryType = "todo"
+	CalendarEntryEvent CalendarFeedEntryType = "event"
+)
+
+// CalendarFeedResponse - DTO ленты. Token и URL подписки возвращаются только при создании или перегенерации.
+type CalendarFeedResponse struct {
+	FeedID         uint       `json:"feed_id"`
+	TeamID         *uint      `json:"team_id,omitempty"`
+	TeamName       *string    `json:"team_name,omitempty"`
+	Token          string     `json:"token,omitempty"`
+	URL            string     `json:"url,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+}
+
+func ToCalendarFeedResponse(feed *CalendarFeed) *CalendarFeedResponse {
+	return &CalendarFeedResponse{
+		FeedID:         feed.FeedID,
+		TeamID:         feed.TeamID,
+		TeamName:       feed.TeamName,
+		CreatedAt:      feed.CreatedAt,
+		LastAccessedAt: feed.LastAccessedAt,
+	}
+}
+
+// CreateCalendarFeedRequest - DTO для создания ленты; для уже существующей ленты области токен перегенерируется.
+type CreateCalendarFeedRequest struct {
+	TeamID *uint `json:"team_id,omitempty"`
+}
+
+// CalendarFeedQuery - запрос ленты по токену с условными заголовками клиента.
+// EntryType не влияет на выборку, но входит в версию ленты.
+type CalendarFeedQuery struct {
+	Token           string
+	EntryType       CalendarFeedEntryType
+	IfNoneMatch     string
+	IfModifiedSince *time.Time
+}
+
+// CalendarFeedItem - задача ленты с названием статуса и тегами
+type CalendarFeedItem struct {
+	Task       *Task
+	StatusName string
+	Tags       []string
+}
+
+// TasksVersion - состояние выборки задач, от которого зависит содержимое ленты календаря.
+// Теги, статусы и команды меняются без изменения задач, поэтому учитываются отдельно.
+type TasksVersion struct {
+	Count            int64
+	LastModified     *time.Time // Наибольший updated_at задач выборки
+	TagsChecksum     int64      // Контрольная сумма привязок тегов к задачам выборки
+	TagsModified     *time.Time // Последнее изменение привязанных тегов (переименование)
+	StatusCount      int64      // Число статусов команд выборки
+	StatusesModified *time.Time // Последнее изменение статусов команд выборки
+	TeamsModified    *time.Time // Последнее изменение команд выборки
+}
+
+// Latest возвращает время последнего изменения выборки, тегов, статусов и команд (nil - изменений нет).
+func (v *TasksVersion) Latest() *time.Time {
+	var latest *time.Time
+	for _, t := range []*time.Time{v.LastModified, v.TagsModified, v.StatusesModified, v.TeamsModified} {
+		if t != nil && (latest == nil || t.After(*latest)) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+// CalendarFeedContent - содержимое ленты. При NotModified задачи не загружаются.
+type CalendarFeedContent struct {
+	Name         string
+	ETag         string
+	LastModified time.Time
+	NotModified  bool
+	Items        []*CalendarFeedItem
+}
+
+// --- Учет времени ---
+
+// TimeEntry - GORM модель для таблицы 'timeentries'. EndedAt = nil - запущенный таймер.
+type TimeEntry struct {
+	TimeEntryID uint       `gorm:"primaryKey;column:time_entry_id;autoIncrement"`
+	TaskID      uint       `gorm:"column:task_id;not null"`
+	UserID      uint       `gorm:"column:user_id;not null"`
+	StartedAt   time.Time  `gorm:"column:started_at;not null"`
+	EndedAt     *time.Time `gorm:"column:ended_at"`
+	Note        *string    `gorm:"type:text;column:note"`
+	CreatedAt   time.Time  `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (TimeEntry) TableName() string {
+	return "timeentries"
+}
+
+// TimeEntryResponse - DTO записи учета времени. Для запущенного таймера длительность считается на момент ответа.
+type TimeEntryResponse struct {
+	TimeEntryID     uint       `json:"time_entry_id"`
+	TaskID          uint       `json:"task_id"`
+	UserID          uint       `json:"user_id"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DurationMinutes int64      `json:"duration_minutes"`
+	Note            *string    `json:"note,omitempty"`
+	IsRunning       bool       `json:"is_running"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+func ToTimeEntryResponse(entry *TimeEntry) *TimeEntryResponse {
+	end := time.Now()
+	if entry.EndedAt != nil {
+		end = *entry.EndedAt
+	}
+	return &TimeEntryResponse{
+		TimeEntryID:     entry.TimeEntryID,
+		TaskID:          entry.TaskID,
+		UserID:          entry.UserID,
+		StartedAt:       entry.StartedAt,
+		EndedAt:         entry.EndedAt,
+		DurationMinutes: int64(end.Sub(entry.StartedAt) / time.Minute),
+		Note:            entry.Note,
+		IsRunning:       entry.EndedAt == nil,
+		CreatedAt:       entry.CreatedAt,
+	}
+}
+
+func ToTimeEntryResponseList(entries []*TimeEntry) []*TimeEntryResponse {
+	responses := make([]*TimeEntryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = ToTimeEntryResponse(entry)
+	}
+	return responses
+}
+
+// CreateTimeEntryRequest - DTO для ручной записи времени. Без started_at запись заканчивается в момент запроса.
+type CreateTimeEntryRequest struct {
+	Minutes   int        `json:"minutes" validate:"required,min=1,max=1440"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	Note      *string    `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
+
+// StartTimerRequest - DTO для запуска таймера (тело необязательно)
+type StartTimerRequest struct {
+	Note *string `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
+
+// TimeReportGroup - измерение, по которому группируется отчет по времени
+type TimeReportGroup string
+
+const (
+	TimeReportByMember TimeReportGroup = "member"
+	TimeReportByTag    TimeReportGroup = "tag"
+	TimeReportByTask   TimeReportGroup = "task"
+	TimeReportByDate   TimeReportGroup = "date"
+)
+
+// TimeReportFormat - формат ответа отчета
+type TimeReportFormat string
+
+const (
+	TimeReportFormatJSON TimeReportFormat = "json"
+	TimeReportFormatCSV  TimeReportFormat = "csv"
+)
+
+// GetTimeReportRequest - DTO для query-параметров отчета по времени команды
+type GetTimeReportRequest struct {
+	From     *time.Time       `form:"from"`
+	To       *time.Time       `form:"to"`
+	GroupBy  *TimeReportGroup `form:"group_by" validate:"omitempty,oneof=member tag task date"`
+	MemberID *uint            `form:"member_id"`
+	TagID    *uint            `form:"tag_id"` // Командный тег
+}
+
+// TimeReportParams - параметры отчета для репозитория. Учитываются только завершенные записи,
+// начатые в интервале [From, To).
+type TimeReportParams struct {
+	TeamID   uint
+	From     *time.Time
+	To       *time.Time
+	GroupBy  TimeReportGroup
+	MemberID *uint
+	TagID    *uint
+}
+
+// TimeReportRow - строка отчета; заполнены поля измерения группировки.
+// При группировке по тегу задачи без тегов попадают в строку с TagID = nil,
+// а задача с несколькими тегами учитывается в каждом из них.
+type TimeReportRow struct {
+	UserID  *uint   `json:"user_id,omitempty" gorm:"column:user_id"`
+	Login   *string `json:"login,omitempty" gorm:"column:login"`
+	TagID   *uint   `json:"tag_id,omitempty" gorm:"column:tag_id"`
+	TagName *string `json:"tag_name,omitempty" gorm:"column:tag_name"`
+	TaskID  *uint   `json:"task_id,omitempty" gorm:"column:task_id"`
+	Title   *string `json:"title,omitempty" gorm:"column:title"`
+	Date    *string `json:"date,omitempty" gorm:"column:day"` // YYYY-MM-DD
+	Minutes int64   `json:"minutes" gorm:"column:minutes"`
+	Entries int64   `json:"entries" gorm:"column:entries"`
+}
+
+// TimeReportResponse - DTO отчета по времени. TotalMinutes считается без разбивки по тегам.
+type TimeReportResponse struct {
+	TeamID       uint             `json:"team_id"`
+	From         *time.Time       `json:"from,omitempty"`
+	To           *time.Time       `json:"to,omitempty"`
+	GroupBy      TimeReportGroup  `json:"group_by"`
+	TotalMinutes int64            `json:"total_minutes"`
+	Rows         []*TimeReportRow `json:"rows"`
+}
+
+// --- Шаблоны задач ---
+
+// TaskTemplate - GORM модель для таблицы 'tasktemplates'.
+// TeamID = nil - личный шаблон владельца, иначе - шаблон команды.
+type TaskTemplate struct {
+	TemplateID  uint                `gorm:"primaryKey;column:template_id;autoIncrement"`
+	OwnerUserID uint                `gorm:"column:owner_user_id;not null"`
+	TeamID      *uint               `gorm:"column:team_id"`
+	Name        string              `gorm:"type:varchar(100);not null;column:name"`
+	Content     TaskTemplateContent `gorm:"type:jsonb;serializer:json;not null;column:content"`
+	CreatedAt   time.Time           `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time           `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (TaskTemplate) TableName() string {
+	return "tasktemplates"
+}
+
+// TaskTemplateContent - содержимое шаблона. Название, описание и пункты чек-листа могут содержать
+// переменные вида {{name}}, значения которых передаются при создании задач.
+// Дедлайн задается числом дней от даты начала: задача получает дедлайн в конце этого дня.
+type TaskTemplateContent struct {
+	Title              string                 `json:"title" validate:"required,min=1,max=255"`
+	Description        *string                `json:"description,omitempty" validate:"omitempty,max=65535"`
+	Priority           *int                   `json:"priority,omitempty" validate:"omitempty,min=1,max=3"`
+	UserTagIDs         []uint                 `json:"user_tag_ids,omitempty" validate:"omitempty,max=50"`
+	TeamTagIDs         []uint                 `json:"team_tag_ids,omitempty" validate:"omitempty,max=50"`
+	Checklist          []string               `json:"checklist,omitempty" validate:"omitempty,max=100,dive,min=1,max=500"`
+	DeadlineOffsetDays *int                   `json:"deadline_offset_days,omitempty" validate:"omitempty,min=0,max=365"`
+	Subtasks           []*TaskTemplateSubtask `json:"subtasks,omitempty" validate:"omitempty,max=50,dive"`
+}
+
+// TaskTemplateSubtask - подзадача шаблона. Создается отдельной задачей с тегами основной задачи.
+type TaskTemplateSubtask struct {
+	Title              string   `json:"title" validate:"required,min=1,max=255"`
+	Description        *string  `json:"description,omitempty" validate:"omitempty,max=65535"`
+	Priority           *int     `json:"priority,omitempty" validate:"omitempty,min=1,max=3"`
+	Checklist          []string `json:"checklist,omitempty" validate:"omitempty,max=100,dive,min=1,max=500"`
+	DeadlineOffsetDays *int     `json:"deadline_offset_days,omitempty" validate:"omitempty,min=0,max=365"`
+}
+
+// TaskTemplateResponse - DTO шаблона; Variables - переменные, которые нужно заполнить при создании задач.
+type TaskTemplateResponse struct {
+	TemplateID  uint                `json:"template_id"`
+	OwnerUserID uint                `json:"owner_user_id"`
+	TeamID      *uint               `json:"team_id,omitempty"`
+	Name        string              `json:"name"`
+	Content     TaskTemplateContent `json:"content"`
+	Variables   []string            `json:"variables"`
+	IsShared    bool                `json:"is_shared"`
+	CanEdit     bool                `json:"can_edit"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+func ToTaskTemplateResponse(tmpl *TaskTemplate, variables []string, canEdit bool) *TaskTemplateResponse {
+	return &TaskTemplateResponse{
+		TemplateID:  tmpl.TemplateID,
+		OwnerUserID: tmpl.OwnerUserID,
+		TeamID:      tmpl.TeamID,
+		Name:        tmpl.Name,
+		Content:     tmpl.Content,
+		Variables:   variables,
+		IsShared:    tmpl.TeamID != nil,
+		CanEdit:     canEdit,
+		CreatedAt:   tmpl.CreatedAt,
+		UpdatedAt:   tmpl.UpdatedAt,
+	}
+}
+
+// CreateTaskTemplateRequest - DTO для создания шаблона; с team_id шаблон становится шаблоном команды.
+type CreateTaskTemplateRequest struct {
+	Name    string              `json:"name" validate:"required,min=1,max=100"`
+	TeamID  *uint               `json:"team_id,omitempty"`
+	Content TaskTemplateContent `json:"content"`
+}
+
+// UpdateTaskTemplateRequest - DTO для изменения шаблона; содержимое заменяется целиком.
+type UpdateTaskTemplateRequest struct {
+	Name    *string              `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Content *TaskTemplateContent `json:"content,omitempty"`
+}
+
+// InstantiateTaskTemplateRequest - DTO для создания задач по шаблону.
+// StartDate - точка отсчета дедлайнов (по умолчанию - сегодня).
+type InstantiateTaskTemplateRequest struct {
+	Variables        map[string]string `json:"variables,omitempty" validate:"omitempty,max=50,dive,keys,min=1,max=50,endkeys,max=255"`
+	StartDate        *time.Time        `json:"start_date,omitempty"`
+	AssignedToUserID *uint             `json:"assigned_to_user_id,omitempty"`
+}
+
+// InstantiateTaskTemplateResponse - созданная задача и ее подзадачи в порядке шаблона.
+type InstantiateTaskTemplateResponse struct {
+	Task     *TaskResponse   `json:"task"`
+	Subtasks []*TaskResponse `json:"subtasks"`
+}
+
+// --- Спринты ---
+
+// SprintStatus - этап спринта: planned -> active -> closed
+type SprintStatus string
+
+const (
+	SprintStatusPlanned SprintStatus = "planned"
+	SprintStatusActive  SprintStatus = "active"
+	SprintStatusClosed  SprintStatus = "closed"
+)
+
+// Sprint - GORM модель для таблицы 'teamsprints'. В команде активен не более одного спринта.
+type Sprint struct {
+	SprintID        uint         `gorm:"primaryKey;column:sprint_id;autoIncrement"`
+	TeamID          uint         `gorm:"column:team_id;not null"`
+	Name            string       `gorm:"type:varchar(100);not null;column:name"`
+	Goal            *string      `gorm:"type:text;column:goal"`
+	StartDate       time.Time    `gorm:"type:date;not null;column:start_date"`
+	EndDate         time.Time    `gorm:"type:date;not null;column:end_date"`
+	Status          SprintStatus `gorm:"type:varchar(20);not null;default:'planned';column:status"`
+	ClosedAt        *time.Time   `gorm:"column:closed_at"`
+	CreatedByUserID *uint        `gorm:"column:created_by_user_id"`
+	CreatedAt       time.Time    `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time    `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (Sprint) TableName() string {
+	return "teamsprints"
+}
+
+// SprintResponse - DTO спринта. Даты - в формате YYYY-MM-DD.
+type SprintResponse struct {
+	SprintID        uint         `json:"sprint_id"`
+	TeamID          uint         `json:"team_id"`
+	Name            string       `json:"name"`
+	Goal            *string      `json:"goal,omitempty"`
+	StartDate       string       `json:"start_date"`
+	EndDate         string       `json:"end_date"`
+	Status          SprintStatus `json:"status"`
+	ClosedAt        *time.Time   `json:"closed_at,omitempty"`
+	CreatedByUserID *uint        `json:"created_by_user_id,omitempty"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+func ToSprintResponse(s *Sprint) *SprintResponse {
+	return &SprintResponse{
+		SprintID:        s.SprintID,
+		TeamID:          s.TeamID,
+		Name:            s.Name,
+		Goal:            s.Goal,
+		StartDate:       s.StartDate.Format("2006-01-02"),
+		EndDate:         s.EndDate.Format("2006-01-02"),
+		Status:          s.Status,
+		ClosedAt:        s.ClosedAt,
+		CreatedByUserID: s.CreatedByUserID,
+		CreatedAt:       s.CreatedAt,
+		UpdatedAt:       s.UpdatedAt,
+	}
+}
+
+// CreateSprintRequest - DTO планирования спринта
+type CreateSprintRequest struct {
+	Name      string  `json:"name" validate:"required,min=1,max=100"`
+	Goal      *string `json:"goal,omitempty" validate:"omitempty,max=65535"`
+	StartDate string  `json:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate   string  `json:"end_date" validate:"required,datetime=2006-01-02"`
+}
+
+// UpdateSprintRequest - DTO изменения спринта; закрытый спринт не меняется.
+type UpdateSprintRequest struct {
+	Name      *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Goal      *string `json:"goal,omitempty" validate:"omitempty,max=65535"`
+	StartDate *string `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	EndDate   *string `json:"end_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// CloseSprintRequest - DTO закрытия спринта. Незавершенные задачи переносятся в carry_over_to_sprint_id;
+// без него - в ближайший запланированный спринт команды, а если такого нет (или передан 0) - в бэклог.
+type CloseSprintRequest struct {
+	CarryOverToSprintID *uint `json:"carry_over_to_sprint_id,omitempty"`
+}
+
+// CloseSprintResponse - закрытый спринт и перенесенные из него задачи
+type CloseSprintResponse struct {
+	Sprint                *SprintResponse `json:"sprint"`
+	CarriedOverToSprintID *uint           `json:"carried_over_to_sprint_id,omitempty"` // nil - задачи в бэклоге
+	CarriedOverTaskIDs    []uint          `json:"carried_over_task_ids"`
+}
+
+// BurndownUnit - в чем считается остаток работы: в задачах или в минутах оценки
+type BurndownUnit string
+
+const (
+	BurndownUnitTasks   BurndownUnit = "tasks"
+	BurndownUnitMinutes BurndownUnit = "minutes" // Если у задач спринта есть оценки
+)
+
+// BurndownPoint - состояние спринта на конец дня (для текущего дня - на момент запроса)
+type BurndownPoint struct {
+	Date      string  `json:"date"` // YYYY-MM-DD
+	Scope     int64   `json:"scope"`
+	Remaining int64   `json:"remaining"`
+	Completed int64   `json:"completed"`
+	Ideal     float64 `json:"ideal"` // Равномерное сгорание исходного объема к концу спринта
+}
+
+// SprintBurndownResponse - burndown спринта, восстановленный по истории задач.
+// В единицах minutes задачи без оценки не учитываются и перечислены в UnestimatedTasks.
+type SprintBurndownResponse struct {
+	Sprint           *SprintResponse  `json:"sprint"`
+	Unit             BurndownUnit     `json:"unit"`
+	Points           []*BurndownPoint `json:"points"`
+	UnestimatedTasks int              `json:"unestimated_tasks"`
+}
+
+// SprintVelocity - выполненная за спринт работа
+type SprintVelocity struct {
+	SprintID         uint   `json:"sprint_id"`
+	Name             string `json:"name"`
+	StartDate        string `json:"start_date"`
+	EndDate          string `json:"end_date"`
+	CommittedTasks   int64  `json:"committed_tasks"` // Задачи в спринте на момент его окончания
+	CompletedTasks   int64  `json:"completed_tasks"`
+	CompletedMinutes int64  `json:"completed_minutes"` // Сумма оценок выполненных задач
+}
+
+// SprintVelocityResponse - скорость команды по последним закрытым спринтам (от новых к старым)
+type SprintVelocityResponse struct {
+	TeamID                  uint              `json:"team_id"`
+	Sprints                 []*SprintVelocity `json:"sprints"`
+	AverageCompletedTasks   float64           `json:"average_completed_tasks"`
+	AverageCompletedMinutes float64           `json:"average_completed_minutes"`
+}
+
+// GetSprintVelocityRequest - DTO для query-параметров отчета о скорости
+type GetSprintVelocityRequest struct {
+	Limit *int `form:"limit" validate:"omitempty,min=1,max=20"`
+}
+
+// SprintHistory - задачи, входившие в спринт, и их изменения статуса и спринта (по возрастанию времени)
+type SprintHistory struct {
+	Tasks  []*Task
+	Events []*TaskEvent
+}
+
 type Controller interface {
 	CreateTask(w http.ResponseWriter, r *http.Request)
 	GetTask(w http.ResponseWriter, r *http.Request)
@@ -187,17 +1249,97 @@ type Controller interface {
 	DeleteTask(w http.ResponseWriter, r *http.Request)
 	RestoreTask(w http.ResponseWriter, r *http.Request)           // <<< ДОБАВЛЕНО
 	DeleteTaskPermanently(w http.ResponseWriter, r *http.Request) // <<< ДОБАВЛЕНО
+	MoveTask(w http.ResponseWriter, r *http.Request)
+	TransferTask(w http.ResponseWriter, r *http.Request)
+	BulkUpdateTasks(w http.ResponseWriter, r *http.Request)
+	ExportTasks(w http.ResponseWriter, r *http.Request)
+	ImportTasks(w http.ResponseWriter, r *http.Request)
+	GetTaskHistory(w http.ResponseWriter, r *http.Request)
+	GetTeamActivity(w http.ResponseWriter, r *http.Request)
+	GetSavedViews(w http.ResponseWriter, r *http.Request)
+	CreateSavedView(w http.ResponseWriter, r *http.Request)
+	UpdateSavedView(w http.ResponseWriter, r *http.Request)
+	DeleteSavedView(w http.ResponseWriter, r *http.Request)
+	ReorderSavedViews(w http.ResponseWriter, r *http.Request)
+	GetCalendarFeeds(w http.ResponseWriter, r *http.Request)
+	CreateCalendarFeed(w http.ResponseWriter, r *http.Request)
+	DeleteCalendarFeed(w http.ResponseWriter, r *http.Request)
+	GetCalendarFeed(w http.ResponseWriter, r *http.Request)
+	WatchTask(w http.ResponseWriter, r *http.Request)
+	UnwatchTask(w http.ResponseWriter, r *http.Request)
+	GetTimeEntries(w http.ResponseWriter, r *http.Request)
+	CreateTimeEntry(w http.ResponseWriter, r *http.Request)
+	StartTimer(w http.ResponseWriter, r *http.Request)
+	StopTimer(w http.ResponseWriter, r *http.Request)
+	DeleteTimeEntry(w http.ResponseWriter, r *http.Request)
+	GetRunningTimer(w http.ResponseWriter, r *http.Request)
+	GetTeamTimeReport(w http.ResponseWriter, r *http.Request)
+	GetTaskTemplates(w http.ResponseWriter, r *http.Request)
+	GetTaskTemplate(w http.ResponseWriter, r *http.Request)
+	CreateTaskTemplate(w http.ResponseWriter, r *http.Request)
+	UpdateTaskTemplate(w http.ResponseWriter, r *http.Request)
+	DeleteTaskTemplate(w http.ResponseWriter, r *http.Request)
+	InstantiateTaskTemplate(w http.ResponseWriter, r *http.Request)
+	GetTeamSprints(w http.ResponseWriter, r *http.Request)
+	CreateSprint(w http.ResponseWriter, r *http.Request)
+	GetSprint(w http.ResponseWriter, r *http.Request)
+	UpdateSprint(w http.ResponseWriter, r *http.Request)
+	DeleteSprint(w http.ResponseWriter, r *http.Request)
+	StartSprint(w http.ResponseWriter, r *http.Request)
+	CloseSprint(w http.ResponseWriter, r *http.Request)
+	GetSprintBurndown(w http.ResponseWriter, r *http.Request)
+	GetSprintVelocity(w http.ResponseWriter, r *http.Request)
 }
 
 type UseCase interface {
 	CreateTask(userID uint, req CreateTaskRequest) (*TaskResponse, error)
 	GetTask(taskID uint, userID uint) (*TaskResponse, error)
-	GetTasks(userID uint, reqParams GetTasksRequest) ([]*TaskResponse, error)
+	GetTasks(userID uint, reqParams GetTasksRequest) ([]*TaskResponse, *TaskListMeta, error)
 	UpdateTask(taskID uint, userID uint, req UpdateTaskRequest) (*TaskResponse, error)
 	PatchTask(taskID uint, userID uint, req PatchTaskRequest) (*TaskResponse, error)
 	DeleteTask(taskID uint, userID uint) error
 	RestoreTask(taskID uint, userID uint) (*TaskResponse, error) // <<< ДОБАВЛЕНО
 	DeleteTaskPermanently(taskID uint, userID uint) error        // <<< ДОБАВЛЕНО
+	MoveTask(taskID uint, userID uint, req MoveTaskRequest) (*TaskResponse, error)
+	TransferTask(taskID uint, userID uint, req TransferTaskRequest) (*TransferTaskResponse, error)
+	BulkUpdateTasks(userID uint, req BulkTaskRequest) (*BulkTaskResponse, error)
+	ExportTasks(userID uint, req TaskExportRequest, emit func(item *TaskExportItem) error) error
+	ImportTasks(userID uint, req TaskImportRequest, data []byte) (*TaskImportResult, error)
+	GetTaskHistory(taskID uint, userID uint) ([]*TaskEventResponse, error)
+	GetTeamActivity(teamID uint, userID uint, req GetTeamActivityRequest) ([]*TaskEventResponse, error)
+	GetSavedViews(userID uint, teamID *uint) ([]*SavedViewResponse, error)
+	CreateSavedView(userID uint, req CreateSavedViewRequest) (*SavedViewResponse, error)
+	UpdateSavedView(viewID uint, userID uint, req UpdateSavedViewRequest) (*SavedViewResponse, error)
+	DeleteSavedView(viewID uint, userID uint) error
+	ReorderSavedViews(userID uint, req ReorderSavedViewsRequest) ([]*SavedViewResponse, error)
+	GetCalendarFeeds(userID uint) ([]*CalendarFeedResponse, error)
+	CreateCalendarFeed(userID uint, req CreateCalendarFeedRequest) (*CalendarFeedResponse, error)
+	DeleteCalendarFeed(feedID uint, userID uint) error
+	GetCalendarFeed(query CalendarFeedQuery) (*CalendarFeedContent, error)
+	WatchTask(taskID uint, userID uint) error
+	UnwatchTask(taskID uint, userID uint) error
+	GetTimeEntries(taskID uint, userID uint) ([]*TimeEntryResponse, error)
+	CreateTimeEntry(taskID uint, userID uint, req CreateTimeEntryRequest) (*TimeEntryResponse, error)
+	StartTimer(taskID uint, userID uint, req StartTimerRequest) (*TimeEntryResponse, error)
+	StopTimer(taskID uint, userID uint) (*TimeEntryResponse, error)
+	DeleteTimeEntry(taskID uint, entryID uint, userID uint) error
+	GetRunningTimer(userID uint) (*TimeEntryResponse, error)
+	GetTeamTimeReport(teamID uint, userID uint, req GetTimeReportRequest) (*TimeReportResponse, error)
+	GetTaskTemplates(userID uint, teamID *uint) ([]*TaskTemplateResponse, error)
+	GetTaskTemplate(templateID uint, userID uint) (*TaskTemplateResponse, error)
+	CreateTaskTemplate(userID uint, req CreateTaskTemplateRequest) (*TaskTemplateResponse, error)
+	UpdateTaskTemplate(templateID uint, userID uint, req UpdateTaskTemplateRequest) (*TaskTemplateResponse, error)
+	DeleteTaskTemplate(templateID uint, userID uint) error
+	InstantiateTaskTemplate(templateID uint, userID uint, req InstantiateTaskTemplateRequest) (*InstantiateTaskTemplateResponse, error)
+	GetTeamSprints(teamID uint, userID uint) ([]*SprintResponse, error)
+	CreateSprint(teamID uint, userID uint, req CreateSprintRequest) (*SprintResponse, error)
+	GetSprint(teamID uint, sprintID uint, userID uint) (*SprintResponse, error)
+	UpdateSprint(teamID uint, sprintID uint, userID uint, req UpdateSprintRequest) (*SprintResponse, error)
+	DeleteSprint(teamID uint, sprintID uint, userID uint) error // Задачи спринта возвращаются в бэклог
+	StartSprint(teamID uint, sprintID uint, userID uint) (*SprintResponse, error)
+	CloseSprint(teamID uint, sprintID uint, userID uint, req CloseSprintRequest) (*CloseSprintResponse, error)
+	GetSprintBurndown(teamID uint, sprintID uint, userID uint) (*SprintBurndownResponse, error)
+	GetSprintVelocity(teamID uint, userID uint, req GetSprintVelocityRequest) (*SprintVelocityResponse, error)
 }
 
 type Repo interface {
@@ -205,9 +1347,61 @@ type Repo interface {
 	GetTaskByID(taskID uint, userID uint) (*Task, error)
 	GetTaskByIDIncludingDeleted(taskID uint) (*Task, error) // <<< ДОБАВЛЕНО
 	GetTasks(params GetTasksParams) ([]*Task, error)
+	CountTasks(params GetTasksParams) (int64, error)
+	GetTaskTagFacets(params GetTasksParams) (*TaskFacets, error)
 	UpdateTask(taskModel *Task) (*Task, error)
 	DeleteTask(taskID uint, userID uint, isTeamTask bool, deletedByUserID *uint) error
 	DeleteTaskPermanently(taskID uint) error // <<< ДОБАВЛЕНО
+	CreateTaskEvents(events []*TaskEvent) error
+	GetTaskEvents(taskID uint) ([]*TaskEvent, error)
+	GetTeamTaskEvents(params GetTeamActivityParams) ([]*TaskEvent, error)
+	GetMaxTaskPosition(scope TaskPositionScope, excludeTaskID uint) (*float64, error)
+	GetAdjacentTaskPosition(scope TaskPositionScope, position float64, anchorTaskID, excludeTaskID uint, after bool) (*float64, error)
+	RebalanceTaskPositions(scope TaskPositionScope) error
+	CreateSavedView(view *SavedView) (*SavedView, error)
+	GetSavedViewByID(viewID uint) (*SavedView, error)
+	GetSavedViews(userID uint, teamID *uint) ([]*SavedView, error)
+	GetMaxSavedViewPosition(userID uint, teamID *uint) (int, error)
+	UpdateSavedView(view *SavedView) (*SavedView, error)
+	DeleteSavedView(viewID uint) error
+	ReorderSavedViews(viewIDs []uint) error
+	GetTaskTagNames(taskIDs []uint) (map[uint][]string, error)
+	GetUserRefsByIDs(userIDs []uint) ([]*TaskUserRef, error)
+	FindUsersByLoginOrEmail(identifiers []string) ([]*TaskUserRef, error)
+	SaveCalendarFeed(feed *CalendarFeed) (*CalendarFeed, error)
+	GetCalendarFeedByTokenHash(tokenHash string) (*CalendarFeed, error)
+	GetCalendarFeeds(userID uint) ([]*CalendarFeed, error)
+	DeleteCalendarFeed(feedID uint, userID uint) error
+	TouchCalendarFeed(feedID uint) error
+	GetTasksVersion(params GetTasksParams) (*TasksVersion, error)
+	GetTaskAssigneeIDs(taskIDs []uint) (map[uint][]uint, error)
+	SetTaskAssignees(taskID uint, userIDs []uint) error
+	GetTaskWatcherIDs(taskIDs []uint) (map[uint][]uint, error)
+	AddTaskWatcher(taskID uint, userID uint) error
+	RemoveTaskWatcher(taskID uint, userID uint) error
+	CreateTimeEntry(entry *TimeEntry) (*TimeEntry, error)
+	GetTimeEntryByID(entryID uint) (*TimeEntry, error)
+	GetTimeEntries(taskID uint) ([]*TimeEntry, error)
+	GetRunningTimeEntry(userID uint) (*TimeEntry, error)
+	StopTimeEntry(entryID uint, endedAt time.Time) (*TimeEntry, error)
+	DeleteTimeEntry(entryID uint) error
+	GetTaskLoggedSeconds(taskIDs []uint) (map[uint]int64, error)
+	GetTimeReport(params TimeReportParams) ([]*TimeReportRow, int64, error)
+	CreateTaskTemplate(tmpl *TaskTemplate) (*TaskTemplate, error)
+	GetTaskTemplateByID(templateID uint) (*TaskTemplate, error)
+	GetTaskTemplates(userID uint, teamID *uint) ([]*TaskTemplate, error)
+	UpdateTaskTemplate(tmpl *TaskTemplate) (*TaskTemplate, error)
+	DeleteTaskTemplate(templateID uint) error
+	CreateSprint(sprint *Sprint) (*Sprint, error)
+	GetSprintByID(sprintID uint) (*Sprint, error)
+	GetTeamSprints(teamID uint) ([]*Sprint, error)
+	GetClosedSprints(teamID uint, limit int) ([]*Sprint, error)
+	GetNextPlannedSprint(teamID uint, excludeSprintID uint) (*Sprint, error) // nil, если запланированных спринтов нет
+	UpdateSprint(sprint *Sprint) (*Sprint, error)
+	DeleteSprint(sprintID uint) error
+	GetUnfinishedSprintTasks(sprintID uint) ([]*Task, error)
+	SetTasksSprint(taskIDs []uint, sprintID *uint) error
+	GetSprintHistory(sprintID uint) (*SprintHistory, error)
 
 	GetTask(taskID uint) (*Task, error)
 	SaveTask(task *Task) error
@@ -215,4 +1409,6 @@ type Repo interface {
 	GetTasksCache(cacheKey string) ([]*Task, error)
 	SaveTasks(cacheKey string, tasks []*Task) error
 	InvalidateTasks(keys ...string) error
+	GetTasksListVersion(scope string) (int64, error)
+	BumpTasksListVersion(scopes ...string) error
 }
@@ -1,6 +1,9 @@
 package task
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrTaskNotFound используется, когда задача не найдена в хранилище.
@@ -19,14 +22,29 @@ var (
 	// (например, из "done" обратно в "todo" без специальных прав или логики) - если такая логика будет.
 	ErrTaskInvalidStatusTransition = errors.New("invalid status transition for task")
 
+	// ErrTaskUnknownStatus используется, если статус не входит в набор статусов команды
+	// (или в набор по умолчанию для личных задач).
+	ErrTaskUnknownStatus = errors.New("unknown task status")
+
+	// ErrTaskInvalidCursor используется, если курсор пагинации поврежден
+	// или получен для выдачи с другой сортировкой.
+	ErrTaskInvalidCursor = errors.New("invalid pagination cursor")
+
 	// ErrTaskTeamRequired используется, если для операции с командной задачей не указан TeamID,
 	// или если личная задача ошибочно обрабатывается как командная.
 	ErrTaskTeamRequired = errors.New("team context is required for this task operation")
 
+	// ErrTaskTeamArchived используется при попытке изменить задачу архивной команды (команда доступна только для чтения).
+	// Оборачивает ErrTaskAccessDenied: везде, где обрабатывается отказ в доступе, отвечает 403 с понятным текстом.
+	ErrTaskTeamArchived = fmt.Errorf("%w: team is archived and read-only", ErrTaskAccessDenied)
+
 	// ErrTaskAssigneeNotInTeam используется, если пользователь, которому назначается командная задача,
 	// не является участником этой команды.
 	ErrTaskAssigneeNotInTeam = errors.New("assignee is not a member of the task's team")
 
+	// ErrTaskAssigneeIsGuest используется, если командную задачу назначают гостю: гости только просматривают задачи.
+	ErrTaskAssigneeIsGuest = errors.New("guests cannot be assigned to tasks")
+
 	// ErrTaskCannotChangeTeam используется, если есть попытка изменить TeamID существующей задачи,
 	// что обычно не разрешается (задачу нельзя "переместить" между командами или из личных в командные простым обновлением).
 	ErrTaskCannotChangeTeam = errors.New("cannot change the team assignment of an existing task")
@@ -41,4 +59,52 @@ var (
 
 	// ErrTaskAlreadyDeleted используется, если пытаются выполнить действие над уже удаленной задачей.
 	ErrTaskAlreadyDeleted = errors.New("operation not allowed on a deleted task")
+
+	// ErrSavedViewNotFound используется, если сохраненное представление не найдено
+	// или недоступно пользователю (чужое личное, командное без членства).
+	ErrSavedViewNotFound = errors.New("saved view not found")
+
+	// ErrSavedViewInvalid используется, если фильтры представления некорректны
+	// (например, неизвестный токен даты) или список для упорядочивания не совпадает с имеющимися представлениями.
+	ErrSavedViewInvalid = errors.New("invalid saved view")
+
+	// ErrTaskImportInvalid используется, если файл импорта не удалось разобрать
+	// (неверный формат, нет обязательной колонки, слишком много записей).
+	ErrTaskImportInvalid = errors.New("invalid import file")
+
+	// ErrCalendarFeedNotFound используется, если лента календаря не найдена: токен неизвестен или отозван,
+	// либо пользователь больше не состоит в команде ленты.
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+	// ErrTimeEntryNotFound используется, если запись учета времени не найдена у задачи,
+	// принадлежит другому пользователю или у задачи нет запущенного таймера пользователя.
+	ErrTimeEntryNotFound = errors.New("time entry not found")
+
+	// ErrTimerAlreadyRunning используется при запуске таймера, если у пользователя уже идет другой таймер.
+	ErrTimerAlreadyRunning = errors.New("another timer is already running")
+
+	// ErrTaskTemplateNotFound используется, если шаблон не найден или недоступен пользователю.
+	ErrTaskTemplateNotFound = errors.New("task template not found")
+
+	// ErrTaskTemplateInvalid используется, если при создании задач по шаблону не заданы значения переменных
+	// или подставленный текст не проходит ограничения задачи.
+	ErrTaskTemplateInvalid = errors.New("invalid task template")
+
+	// ErrTaskProjectInvalid используется, если проект указан для личной задачи
+	// или не найден среди проектов команды задачи.
+	ErrTaskProjectInvalid = errors.New("project does not belong to the task team")
+
+	// ErrSprintNotFound используется, если спринт не найден в команде.
+	ErrSprintNotFound = errors.New("sprint not found")
+
+	// ErrSprintInvalid используется, если даты спринта некорректны или операция недопустима в текущем состоянии спринта
+	// (изменение закрытого спринта, запуск не запланированного, закрытие не активного).
+	ErrSprintInvalid = errors.New("invalid sprint or sprint state")
+
+	// ErrSprintAlreadyActive используется при запуске спринта, если в команде уже есть активный спринт.
+	ErrSprintAlreadyActive = errors.New("team already has an active sprint")
+
+	// ErrTaskSprintInvalid используется, если спринт указан для личной задачи,
+	// не найден среди спринтов команды задачи или уже закрыт.
+	ErrTaskSprintInvalid = errors.New("sprint does not belong to the task team or is closed")
 )
@@ -184,3 +184,47 @@ func (c *TaskCache) InvalidateTasks(keys ...string) error {
 	}
 	return nil
 }
+
+// tasksListVersionKey: модуль команд повторяет этот формат для области "team:ID" (TeamCache.BumpTeamTasksListVersion)
+func (c *TaskCache) tasksListVersionKey(scope string) string {
+	return fmt.Sprintf("tasks:ver:%s", scope)
+}
+
+// GetTasksListVersion возвращает текущую версию кэша списков для области (пользователь или команда).
+// Версия входит в ключи страниц, поэтому ее увеличение делает все старые страницы недостижимыми.
+func (c *TaskCache) GetTasksListVersion(scope string) (int64, error) {
+	op := "TaskCache.GetTasksListVersion"
+	key := c.tasksListVersionKey(scope)
+	log := c.log.With(slog.String("op", op), slog.String("key", key))
+
+	version, err := c.rdb.Get(context.Background(), key).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		log.Error("failed to get tasks list version from cache", "error", err)
+		return 0, task.ErrTaskInternal
+	}
+	return version, nil
+}
+
+// BumpTasksListVersion увеличивает версии кэша списков; устаревшие страницы удаляются по TTL.
+func (c *TaskCache) BumpTasksListVersion(scopes ...string) error {
+	op := "TaskCache.BumpTasksListVersion"
+	if len(scopes) == 0 {
+		return nil
+	}
+	log := c.log.With(slog.String("op", op), slog.Any("scopes", scopes))
+
+	pipe := c.rdb.Pipeline()
+	for _, scope := range scopes {
+		pipe.Incr(context.Background(), c.tasksListVersionKey(scope))
+	}
+	if _, err := pipe.Exec(context.Background()); err != nil {
+		log.Error("failed to bump tasks list version in cache", "error", err)
+		return task.ErrTaskInternal
+	}
+
+	log.Debug("tasks list version bumped")
+	return nil
+}
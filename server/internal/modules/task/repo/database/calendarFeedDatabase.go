@@ -0,0 +1,156 @@
+// internal/modules/task/repo/database/calendarFeedDatabase.go
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"log/slog"
+	"server/internal/modules/task"
+	"time"
+)
+
+// calendarFeedsQuery выбирает ленты вместе с названием команды.
+func (r *TaskDatabase) calendarFeedsQuery() *gorm.DB {
+	return r.db.Model(&task.CalendarFeed{}).
+		Select("calendarfeeds.*, teams.name AS team_name").
+		Joins("LEFT JOIN teams ON teams.team_id = calendarfeeds.team_id")
+}
+
+// SaveCalendarFeed создает ленту или, если лента этой области уже есть, заменяет ее токен.
+func (r *TaskDatabase) SaveCalendarFeed(feed *task.CalendarFeed) (*task.CalendarFeed, error) {
+	op := "TaskDatabase.SaveCalendarFeed"
+	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(feed.UserID)))
+
+	query := r.db.Model(&task.CalendarFeed{}).Where("user_id = ?", feed.UserID)
+	if feed.TeamID != nil {
+		query = query.Where("team_id = ?", *feed.TeamID)
+	} else {
+		query = query.Where("team_id IS NULL")
+	}
+	var existing task.CalendarFeed
+	err := query.First(&existing).Error
+	switch {
+	case err == nil:
+		feed.FeedID = existing.FeedID
+		feed.CreatedAt = time.Now()
+		if err := r.db.Model(&existing).Updates(map[string]interface{}{
+			"token_hash":       feed.TokenHash,
+			"created_at":       feed.CreatedAt,
+			"last_accessed_at": nil,
+		}).Error; err != nil {
+			log.Error("failed to regenerate calendar feed token in DB", "error", err)
+			return nil, task.ErrTaskInternal
+		}
+		log.Info("calendar feed token regenerated in DB", slog.Uint64("feedID", uint64(feed.FeedID)))
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if err := r.db.Create(feed).Error; err != nil {
+			log.Error("failed to create calendar feed in DB", "error", err)
+			return nil, task.ErrTaskInternal
+		}
+		log.Info("calendar feed created in DB", slog.Uint64("feedID", uint64(feed.FeedID)))
+	default:
+		log.Error("failed to find calendar feed in DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return feed, nil
+}
+
+func (r *TaskDatabase) GetCalendarFeedByTokenHash(tokenHash string) (*task.CalendarFeed, error) {
+	op := "TaskDatabase.GetCalendarFeedByTokenHash"
+	log := r.log.With(slog.String("op", op))
+	var feed task.CalendarFeed
+
+	if err := r.calendarFeedsQuery().Where("calendarfeeds.token_hash = ?", tokenHash).First(&feed).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, task.ErrCalendarFeedNotFound
+		}
+		log.Error("failed to get calendar feed by token from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return &feed, nil
+}
+
+// GetCalendarFeeds возвращает ленты пользователя: сначала личную, затем командные.
+func (r *TaskDatabase) GetCalendarFeeds(userID uint) ([]*task.CalendarFeed, error) {
+	op := "TaskDatabase.GetCalendarFeeds"
+	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+	var feeds []*task.CalendarFeed
+
+	if err := r.calendarFeedsQuery().Where("calendarfeeds.user_id = ?", userID).
+		Order("calendarfeeds.team_id NULLS FIRST, calendarfeeds.feed_id").Find(&feeds).Error; err != nil {
+		log.Error("failed to get calendar feeds from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return feeds, nil
+}
+
+func (r *TaskDatabase) DeleteCalendarFeed(feedID uint, userID uint) error {
+	op := "TaskDatabase.DeleteCalendarFeed"
+	log := r.log.With(slog.String("op", op), slog.Uint64("feedID", uint64(feedID)), slog.Uint64("userID", uint64(userID)))
+
+	result := r.db.Where("user_id = ?", userID).Delete(&task.CalendarFeed{}, feedID)
+	if result.Error != nil {
+		log.Error("failed to delete calendar feed in DB", "error", result.Error)
+		return task.ErrTaskInternal
+	}
+	if result.RowsAffected == 0 {
+		return task.ErrCalendarFeedNotFound
+	}
+
+	log.Info("calendar feed revoked in DB")
+	return nil
+}
+
+// TouchCalendarFeed запоминает время последнего обращения клиента к ленте.
+func (r *TaskDatabase) TouchCalendarFeed(feedID uint) error {
+	op := "TaskDatabase.TouchCalendarFeed"
+	log := r.log.With(slog.String("op", op), slog.Uint64("feedID", uint64(feedID)))
+
+	if err := r.db.Model(&task.CalendarFeed{}).Where("feed_id = ?", feedID).
+		Update("last_accessed_at", time.Now()).Error; err != nil {
+		log.Error("failed to update calendar feed access time in DB", "error", err)
+		return task.ErrTaskInternal
+	}
+	return nil
+}
+
+// GetTasksVersion возвращает состояние выборки задач для ETag ленты: число задач и наибольший updated_at,
+// контрольную сумму привязок тегов и время изменения самих тегов, число и время изменения статусов команд
+// и время изменения команд. Вместе они меняются при любом изменении, видимом в ленте.
+func (r *TaskDatabase) GetTasksVersion(params task.GetTasksParams) (*task.TasksVersion, error) {
+	op := "TaskDatabase.GetTasksVersion"
+	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(params.UserID)))
+
+	// Каждый подзапрос строится заново: условия GORM накапливаются в запросе, и повторно его использовать нельзя
+	tasksQuery := func(columns string) *gorm.DB {
+		query, _ := r.applyTaskFilters(r.db.Model(&task.Task{}), params, log)
+		return query.Select(columns)
+	}
+	version := &task.TasksVersion{}
+	if err := tasksQuery("COUNT(*), MAX(updated_at)").Row().Scan(&version.Count, &version.LastModified); err != nil {
+		log.Error("failed to get tasks version from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	if err := r.db.Raw("SELECT COALESCE(SUM(hashtext(tt.task_id || ':' || COALESCE(tt.user_tag_id, 0) || ':' || COALESCE(tt.team_tag_id, 0))), 0), "+
+		"MAX(GREATEST(ut.updated_at, tg.updated_at)) FROM tasktags tt "+
+		"LEFT JOIN usertags ut ON ut.user_tag_id = tt.user_tag_id "+
+		"LEFT JOIN teamtags tg ON tg.team_tag_id = tt.team_tag_id "+
+		"WHERE tt.task_id IN (?)", tasksQuery("tasks.task_id")).
+		Row().Scan(&version.TagsChecksum, &version.TagsModified); err != nil {
+		log.Error("failed to get task tags version from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	// Команда ленты учитывается, даже если в выборке нет ее задач: ее название входит в имя календаря
+	if err := r.db.Raw("SELECT "+
+		"(SELECT COUNT(*) FROM teamtaskstatuses WHERE team_id IN (?) OR team_id = ?), "+
+		"(SELECT MAX(updated_at) FROM teamtaskstatuses WHERE team_id IN (?) OR team_id = ?), "+
+		"(SELECT MAX(updated_at) FROM teams WHERE team_id IN (?) OR team_id = ?)",
+		tasksQuery("DISTINCT tasks.team_id"), params.TeamID,
+		tasksQuery("DISTINCT tasks.team_id"), params.TeamID,
+		tasksQuery("DISTINCT tasks.team_id"), params.TeamID).
+		Row().Scan(&version.StatusCount, &version.StatusesModified, &version.TeamsModified); err != nil {
+		log.Error("failed to get teams version from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return version, nil
+}
@@ -0,0 +1,137 @@
+// internal/modules/task/repo/database/savedViewDatabase.go
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"log/slog"
+	"server/internal/modules/task"
+)
+
+// savedViewsScopeQuery ограничивает выборку личными представлениями пользователя или общими представлениями команды.
+func (r *TaskDatabase) savedViewsScopeQuery(userID uint, teamID *uint) *gorm.DB {
+	query := r.db.Model(&task.SavedView{})
+	if teamID != nil {
+		return query.Where("team_id = ?", *teamID)
+	}
+	return query.Where("team_id IS NULL AND owner_user_id = ?", userID)
+}
+
+func (r *TaskDatabase) CreateSavedView(view *task.SavedView) (*task.SavedView, error) {
+	op := "TaskDatabase.CreateSavedView"
+	log := r.log.With(slog.String("op", op), slog.Uint64("ownerUserID", uint64(view.OwnerUserID)))
+
+	if err := r.db.Create(view).Error; err != nil {
+		log.Error("failed to create saved view in DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Info("saved view created successfully in DB", slog.Uint64("viewID", uint64(view.ViewID)))
+	return view, nil
+}
+
+func (r *TaskDatabase) GetSavedViewByID(viewID uint) (*task.SavedView, error) {
+	op := "TaskDatabase.GetSavedViewByID"
+	log := r.log.With(slog.String("op", op), slog.Uint64("viewID", uint64(viewID)))
+	var view task.SavedView
+
+	if err := r.db.First(&view, viewID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("saved view not found by ID")
+			return nil, task.ErrSavedViewNotFound
+		}
+		log.Error("failed to get saved view by ID from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return &view, nil
+}
+
+// GetSavedViews возвращает представления для боковой панели: с teamID - общие представления команды,
+// без него - личные представления пользователя и общие представления всех его команд.
+func (r *TaskDatabase) GetSavedViews(userID uint, teamID *uint) ([]*task.SavedView, error) {
+	op := "TaskDatabase.GetSavedViews"
+	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+	var views []*task.SavedView
+
+	var query *gorm.DB
+	if teamID != nil {
+		query = r.savedViewsScopeQuery(userID, teamID)
+	} else {
+		query = r.db.Model(&task.SavedView{}).
+			Where("(team_id IS NULL AND owner_user_id = ?) OR team_id IN (SELECT team_id FROM userteammemberships WHERE user_id = ?)", userID, userID)
+	}
+	// Сначала личные представления, затем командные; внутри группы - ручной порядок
+	if err := query.Order("team_id NULLS FIRST, position ASC, view_id ASC").Find(&views).Error; err != nil {
+		log.Error("failed to get saved views from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Debug("saved views retrieved from DB", slog.Int("count", len(views)))
+	return views, nil
+}
+
+// GetMaxSavedViewPosition возвращает наибольшую позицию в списке представлений (0, если список пуст).
+func (r *TaskDatabase) GetMaxSavedViewPosition(userID uint, teamID *uint) (int, error) {
+	op := "TaskDatabase.GetMaxSavedViewPosition"
+	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	var maxPosition *int
+	if err := r.savedViewsScopeQuery(userID, teamID).Select("MAX(position)").Row().Scan(&maxPosition); err != nil {
+		log.Error("failed to get max saved view position from DB", "error", err)
+		return 0, task.ErrTaskInternal
+	}
+	if maxPosition == nil {
+		return 0, nil
+	}
+	return *maxPosition, nil
+}
+
+func (r *TaskDatabase) UpdateSavedView(view *task.SavedView) (*task.SavedView, error) {
+	op := "TaskDatabase.UpdateSavedView"
+	log := r.log.With(slog.String("op", op), slog.Uint64("viewID", uint64(view.ViewID)))
+
+	if err := r.db.Model(view).Select("name", "filters").Updates(view).Error; err != nil {
+		log.Error("failed to update saved view in DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return r.GetSavedViewByID(view.ViewID)
+}
+
+func (r *TaskDatabase) DeleteSavedView(viewID uint) error {
+	op := "TaskDatabase.DeleteSavedView"
+	log := r.log.With(slog.String("op", op), slog.Uint64("viewID", uint64(viewID)))
+
+	result := r.db.Delete(&task.SavedView{}, viewID)
+	if result.Error != nil {
+		log.Error("failed to delete saved view in DB", "error", result.Error)
+		return task.ErrTaskInternal
+	}
+	if result.RowsAffected == 0 {
+		return task.ErrSavedViewNotFound
+	}
+
+	log.Info("saved view deleted from DB")
+	return nil
+}
+
+// ReorderSavedViews выставляет позиции представлений по порядку в списке (в одной транзакции).
+func (r *TaskDatabase) ReorderSavedViews(viewIDs []uint) error {
+	op := "TaskDatabase.ReorderSavedViews"
+	log := r.log.With(slog.String("op", op), slog.Int("count", len(viewIDs)))
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i, viewID := range viewIDs {
+			if err := tx.Model(&task.SavedView{}).Where("view_id = ?", viewID).UpdateColumn("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("failed to reorder saved views in DB", "error", err)
+		return task.ErrTaskInternal
+	}
+
+	log.Info("saved views reordered in DB")
+	return nil
+}
@@ -0,0 +1,180 @@
+package database
+
+import (
+	"errors"
+	"log/slog"
+	"server/internal/modules/task"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+func (r *TaskDatabase) CreateSprint(sprint *task.Sprint) (*task.Sprint, error) {
+	op := "TaskDatabase.CreateSprint"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(sprint.TeamID)))
+
+	if err := r.db.Create(sprint).Error; err != nil {
+		log.Error("failed to create sprint in DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	log.Info("sprint created successfully in DB", slog.Uint64("sprintID", uint64(sprint.SprintID)))
+	return sprint, nil
+}
+
+func (r *TaskDatabase) GetSprintByID(sprintID uint) (*task.Sprint, error) {
+	op := "TaskDatabase.GetSprintByID"
+	log := r.log.With(slog.String("op", op), slog.Uint64("sprintID", uint64(sprintID)))
+
+	var sprint task.Sprint
+	if err := r.db.First(&sprint, sprintID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("sprint not found by ID")
+			return nil, task.ErrSprintNotFound
+		}
+		log.Error("failed to get sprint by ID from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return &sprint, nil
+}
+
+func (r *TaskDatabase) GetTeamSprints(teamID uint) ([]*task.Sprint, error) {
+	op := "TaskDatabase.GetTeamSprints"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)))
+
+	var sprints []*task.Sprint
+	if err := r.db.Where("team_id = ?", teamID).Order("start_date DESC, sprint_id DESC").Find(&sprints).Error; err != nil {
+		log.Error("failed to get team sprints from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return sprints, nil
+}
+
+// GetClosedSprints возвращает последние закрытые спринты команды, начиная с самого позднего.
+func (r *TaskDatabase) GetClosedSprints(teamID uint, limit int) ([]*task.Sprint, error) {
+	op := "TaskDatabase.GetClosedSprints"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)))
+
+	var sprints []*task.Sprint
+	err := r.db.Where("team_id = ? AND status = ?", teamID, task.SprintStatusClosed).
+		Order("end_date DESC, sprint_id DESC").Limit(limit).Find(&sprints).Error
+	if err != nil {
+		log.Error("failed to get closed sprints from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return sprints, nil
+}
+
+// GetNextPlannedSprint возвращает ближайший по дате начала запланированный спринт команды (nil, если его нет).
+func (r *TaskDatabase) GetNextPlannedSprint(teamID uint, excludeSprintID uint) (*task.Sprint, error) {
+	op := "TaskDatabase.GetNextPlannedSprint"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)))
+
+	var sprints []*task.Sprint
+	err := r.db.Where("team_id = ? AND status = ? AND sprint_id <> ?", teamID, task.SprintStatusPlanned, excludeSprintID).
+		Order("start_date ASC, sprint_id ASC").Limit(1).Find(&sprints).Error
+	if err != nil {
+		log.Error("failed to get next planned sprint from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	if len(sprints) == 0 {
+		return nil, nil
+	}
+	return sprints[0], nil
+}
+
+func (r *TaskDatabase) UpdateSprint(sprint *task.Sprint) (*task.Sprint, error) {
+	op := "TaskDatabase.UpdateSprint"
+	log := r.log.With(slog.String("op", op), slog.Uint64("sprintID", uint64(sprint.SprintID)))
+
+	if err := r.db.Save(sprint).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") &&
+			strings.Contains(err.Error(), "uq_team_sprints_active") {
+			log.Warn("team already has an active sprint")
+			return nil, task.ErrSprintAlreadyActive
+		}
+		log.Error("failed to update sprint in DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return sprint, nil
+}
+
+// DeleteSprint удаляет спринт; задачи спринта возвращаются в бэклог (sprint_id обнуляется внешним ключом).
+func (r *TaskDatabase) DeleteSprint(sprintID uint) error {
+	op := "TaskDatabase.DeleteSprint"
+	log := r.log.With(slog.String("op", op), slog.Uint64("sprintID", uint64(sprintID)))
+
+	result := r.db.Delete(&task.Sprint{}, sprintID)
+	if result.Error != nil {
+		log.Error("failed to delete sprint in DB", "error", result.Error)
+		return task.ErrTaskInternal
+	}
+	if result.RowsAffected == 0 {
+		return task.ErrSprintNotFound
+	}
+	log.Info("sprint deleted from DB")
+	return nil
+}
+
+// GetUnfinishedSprintTasks возвращает неудаленные задачи спринта, которые еще не завершены.
+func (r *TaskDatabase) GetUnfinishedSprintTasks(sprintID uint) ([]*task.Task, error) {
+	op := "TaskDatabase.GetUnfinishedSprintTasks"
+	log := r.log.With(slog.String("op", op), slog.Uint64("sprintID", uint64(sprintID)))
+
+	var tasks []*task.Task
+	err := r.db.Where("sprint_id = ? AND is_deleted = ? AND status_category <> ?", sprintID, false, "done").
+		Order("task_id ASC").Find(&tasks).Error
+	if err != nil {
+		log.Error("failed to get unfinished sprint tasks from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return tasks, nil
+}
+
+func (r *TaskDatabase) SetTasksSprint(taskIDs []uint, sprintID *uint) error {
+	op := "TaskDatabase.SetTasksSprint"
+	log := r.log.With(slog.String("op", op), slog.Int("tasks", len(taskIDs)))
+
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	if err := r.db.Model(&task.Task{}).Where("task_id IN ?", taskIDs).Update("sprint_id", sprintID).Error; err != nil {
+		log.Error("failed to set tasks sprint in DB", "error", err)
+		return task.ErrTaskInternal
+	}
+	return nil
+}
+
+// GetSprintHistory возвращает задачи, которые входят или когда-либо входили в спринт (по журналу изменений),
+// и их изменения статуса и спринта. Удаленные задачи не учитываются.
+func (r *TaskDatabase) GetSprintHistory(sprintID uint) (*task.SprintHistory, error) {
+	op := "TaskDatabase.GetSprintHistory"
+	log := r.log.With(slog.String("op", op), slog.Uint64("sprintID", uint64(sprintID)))
+
+	sprintIDStr := strconv.FormatUint(uint64(sprintID), 10)
+	history := &task.SprintHistory{}
+	err := r.db.Where("is_deleted = ?", false).
+		Where("sprint_id = ? OR task_id IN (?)", sprintID,
+			r.db.Model(&task.TaskEvent{}).Select("task_id").
+				Where("field_name = ? AND (new_value = ? OR old_value = ?)", "sprint_id", sprintIDStr, sprintIDStr)).
+		Order("task_id ASC").Find(&history.Tasks).Error
+	if err != nil {
+		log.Error("failed to get sprint tasks from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	if len(history.Tasks) == 0 {
+		return history, nil
+	}
+
+	taskIDs := make([]uint, len(history.Tasks))
+	for i, t := range history.Tasks {
+		taskIDs[i] = t.TaskID
+	}
+	err = r.db.Where("task_id IN ? AND event_type = ? AND field_name IN ?", taskIDs, task.EventTaskUpdated, []string{"status", "sprint_id"}).
+		Order("created_at ASC, event_id ASC").Find(&history.Events).Error
+	if err != nil {
+		log.Error("failed to get sprint task events from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return history, nil
+}
@@ -2,11 +2,13 @@
 package database
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"gorm.io/gorm"
 	"log/slog"
 	"server/internal/modules/task"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -74,13 +76,11 @@ func (r *TaskDatabase) GetTaskByIDIncludingDeleted(taskID uint) (*task.Task, err
 	return &taskModel, nil
 }
 
-func (r *TaskDatabase) GetTasks(params task.GetTasksParams) ([]*task.Task, error) {
-	op := "TaskDatabase.GetTasks"
-	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(params.UserID)))
-	var tasks []*task.Task
-
-	query := r.db.Model(&task.Task{})
+// taskAssignedToUserSQL - пользователь среди исполнителей задачи (не только основной исполнитель)
+const taskAssignedToUserSQL = "EXISTS (SELECT 1 FROM taskassignees ta WHERE ta.task_id = tasks.task_id AND ta.user_id = ?)"
 
+// applyTaskFilters применяет к запросу фильтры списка задач (без сортировки и пагинации).
+func (r *TaskDatabase) applyTaskFilters(query *gorm.DB, params task.GetTasksParams, log *slog.Logger) (*gorm.DB, *slog.Logger) {
 	// <<< ИЗМЕНЕНИЕ: фильтрация по is_deleted стала динамической >>>
 	if params.IsDeleted != nil {
 		query = query.Where("is_deleted = ?", *params.IsDeleted)
@@ -92,20 +92,24 @@ func (r *TaskDatabase) GetTasks(params task.GetTasksParams) ([]*task.Task, error
 
 	switch params.ViewType {
 	case task.ViewTypeUserCentricGlobal:
-		query = query.Where("(created_by_user_id = ? OR assigned_to_user_id = ?)", params.UserID, params.UserID)
+		query = query.Where("(created_by_user_id = ? OR "+taskAssignedToUserSQL+")", params.UserID, params.UserID)
 		log = log.With(slog.String("filter_logic", "global_user_centric"))
 
 	case task.ViewTypeUserPersonal:
-		query = query.Where("team_id IS NULL AND (created_by_user_id = ? OR assigned_to_user_id = ?)", params.UserID, params.UserID)
+		query = query.Where("team_id IS NULL AND (created_by_user_id = ? OR "+taskAssignedToUserSQL+")", params.UserID, params.UserID)
 		log = log.With(slog.String("filter_logic", "personal_user_centric"))
 
 	default:
 		if params.TeamID != nil {
 			query = query.Where("team_id = ?", *params.TeamID)
 			log = log.With(slog.Uint64("filter_teamID", uint64(*params.TeamID)))
+			if params.ScopeTeamTagID != nil {
+				query = query.Where("tasks.task_id IN (SELECT task_id FROM tasktags WHERE team_tag_id = ?)", *params.ScopeTeamTagID)
+				log = log.With(slog.Uint64("scope_team_tag", uint64(*params.ScopeTeamTagID)))
+			}
 		} else {
 			// Поведение по умолчанию: личные задачи, созданные пользователем или назначенные ему
-			query = query.Where("team_id IS NULL AND (created_by_user_id = ? OR assigned_to_user_id = ?)", params.UserID, params.UserID)
+			query = query.Where("team_id IS NULL AND (created_by_user_id = ? OR "+taskAssignedToUserSQL+")", params.UserID, params.UserID)
 			log = log.With(slog.String("filter_logic", "default_personal_created_or_assigned"))
 		}
 	}
@@ -114,12 +118,24 @@ func (r *TaskDatabase) GetTasks(params task.GetTasksParams) ([]*task.Task, error
 		query = query.Where("status = ?", *params.Status)
 		log = log.With(slog.String("filter_status", *params.Status))
 	}
+	if params.ProjectID != nil {
+		query = query.Where("project_id = ?", *params.ProjectID)
+		log = log.With(slog.Uint64("filter_project", uint64(*params.ProjectID)))
+	}
+	if params.SprintID != nil {
+		query = query.Where("sprint_id = ?", *params.SprintID)
+		log = log.With(slog.Uint64("filter_sprint", uint64(*params.SprintID)))
+	}
+	if params.StatusCategory != nil && *params.StatusCategory != "" {
+		query = query.Where("status_category = ?", *params.StatusCategory)
+		log = log.With(slog.String("filter_status_category", *params.StatusCategory))
+	}
 	if params.Priority != nil {
 		query = query.Where("priority = ?", *params.Priority)
 		log = log.With(slog.Int("filter_priority", *params.Priority))
 	}
 	if params.AssignedToUserID != nil {
-		query = query.Where("assigned_to_user_id = ?", *params.AssignedToUserID)
+		query = query.Where(taskAssignedToUserSQL, *params.AssignedToUserID)
 		log = log.With(slog.Uint64("filter_assigned_to_explicit", uint64(*params.AssignedToUserID)))
 	}
 	if params.DeadlineFrom != nil {
@@ -134,33 +150,173 @@ func (r *TaskDatabase) GetTasks(params task.GetTasksParams) ([]*task.Task, error
 		query = query.Where("deadline <= ?", dateEnd.Format(time.RFC3339))
 		log = log.With(slog.String("filter_deadline_to", dateEnd.Format(time.RFC3339)))
 	}
+	if params.Overdue {
+		query = query.Where("deadline < NOW() AND status_category <> ?", "done")
+		log = log.With(slog.Bool("filter_overdue", true))
+	}
 	if params.SearchQuery != nil && *params.SearchQuery != "" {
-		searchVal := "%" + strings.ToLower(*params.SearchQuery) + "%"
-		query = query.Where("LOWER(title) LIKE ? OR LOWER(description) LIKE ?", searchVal, searchVal)
+		// Совпадение по тексту задачи или по названию любого из ее тегов
+		query = query.Where("(tasks.search_vector @@ "+taskSearchQuerySQL+" OR tasks.task_id IN ("+taskTagSearchSQL+"))",
+			sql.Named("q", *params.SearchQuery))
 		log = log.With(slog.String("filter_search", *params.SearchQuery))
 	}
+	if len(params.UserTagIDs) > 0 || len(params.TeamTagIDs) > 0 || params.Untagged {
+		query = applyTaskTagFilter(query, params)
+		log = log.With(slog.Any("filter_user_tags", params.UserTagIDs), slog.Any("filter_team_tags", params.TeamTagIDs),
+			slog.Bool("filter_untagged", params.Untagged), slog.String("tag_match", string(params.TagMatch)))
+	}
+	return query, log
+}
+
+// applyTaskTagFilter фильтрует задачи по тегам внутри SQL-запроса.
+// any: есть хотя бы один из тегов (или тегов нет вовсе, если задан untagged); all: есть все перечисленные теги.
+func applyTaskTagFilter(query *gorm.DB, params task.GetTasksParams) *gorm.DB {
+	var tagConds []string
+	var tagArgs []interface{}
+	if len(params.UserTagIDs) > 0 {
+		tagConds = append(tagConds, "user_tag_id IN ?")
+		tagArgs = append(tagArgs, params.UserTagIDs)
+	}
+	if len(params.TeamTagIDs) > 0 {
+		tagConds = append(tagConds, "team_tag_id IN ?")
+		tagArgs = append(tagArgs, params.TeamTagIDs)
+	}
+	untaggedSQL := "NOT EXISTS (SELECT 1 FROM tasktags ut WHERE ut.task_id = tasks.task_id)"
+
+	if len(tagConds) == 0 {
+		return query.Where(untaggedSQL)
+	}
 
-	// <<< ИЗМЕНЕНИЕ: для удаленных сортируем по дате удаления по убыванию >>>
-	orderByClause := "updated_at DESC" // Сортировка по умолчанию
-	if params.IsDeleted != nil && *params.IsDeleted {
-		orderByClause = "deleted_at DESC"
-	} else if params.SortBy != "" {
-		orderDirection := "ASC"
-		if params.SortOrder == task.SortDirectionDesc {
-			orderDirection = "DESC"
+	tagSQL := "SELECT task_id FROM tasktags WHERE " + strings.Join(tagConds, " OR ")
+	if params.TagMatch == task.TagMatchAll {
+		// Связь задача-тег уникальна, поэтому совпадение по всем тегам - это ровно N строк
+		tagSQL += " GROUP BY task_id HAVING COUNT(*) = ?"
+		tagArgs = append(tagArgs, len(params.UserTagIDs)+len(params.TeamTagIDs))
+	}
+	if params.Untagged {
+		return query.Where("(tasks.task_id IN ("+tagSQL+") OR "+untaggedSQL+")", tagArgs...)
+	}
+	return query.Where("tasks.task_id IN ("+tagSQL+")", tagArgs...)
+}
+
+const (
+	// taskSearchQuerySQL - поисковый запрос в синтаксисе websearch (фразы в кавычках, OR, -исключение) для обоих языков
+	taskSearchQuerySQL = "(websearch_to_tsquery('russian', @q) || websearch_to_tsquery('english', @q))"
+	// taskTagSearchSQL - задачи, у которых название пользовательского или командного тега совпадает с запросом
+	taskTagSearchSQL = "SELECT tt.task_id FROM tasktags tt WHERE " +
+		"tt.user_tag_id IN (SELECT user_tag_id FROM usertags WHERE to_tsvector('simple', name) @@ websearch_to_tsquery('simple', @q)) OR " +
+		"tt.team_tag_id IN (SELECT team_tag_id FROM teamtags WHERE to_tsvector('simple', name) @@ websearch_to_tsquery('simple', @q))"
+	taskSearchRankSQL     = "ts_rank(tasks.search_vector, " + taskSearchQuerySQL + ")"
+	taskSearchHeadlineOpt = "StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2"
+)
+
+// taskSearchHeadlineSQL выделяет совпадения в колонке той же парой конфигураций, что и search_vector:
+// если текст совпадает с запросом по-русски, фрагмент строится русской конфигурацией, иначе английской.
+// Запрос и документ всегда разбираются одной конфигурацией, иначе основы слов не совпадут и выделения не будет.
+func taskSearchHeadlineSQL(column string) string {
+	return fmt.Sprintf("CASE WHEN %[1]s IS NULL THEN NULL "+
+		"WHEN to_tsvector('russian', %[1]s) @@ websearch_to_tsquery('russian', @q) "+
+		"THEN ts_headline('russian', %[1]s, websearch_to_tsquery('russian', @q), @headline_opt) "+
+		"ELSE ts_headline('english', %[1]s, websearch_to_tsquery('english', @q), @headline_opt) END", column)
+}
+
+// parseCursorValue приводит строковое значение курсора к типу колонки сортировки.
+func parseCursorValue(field task.TaskSortableField, value string) (interface{}, error) {
+	switch field {
+	case task.FieldCreatedAt, task.FieldUpdatedAt, task.FieldDeadline, task.FieldDeletedAt:
+		return time.Parse(time.RFC3339Nano, value)
+	case task.FieldPriority:
+		return strconv.Atoi(value)
+	case task.FieldPosition:
+		return strconv.ParseFloat(value, 64)
+	case task.FieldStatus, task.FieldTitle:
+		return value, nil
+	case task.FieldRelevance:
+		return strconv.ParseFloat(value, 64)
+	}
+	return nil, task.ErrTaskInvalidCursor
+}
+
+// applyTaskCursor отбирает задачи, идущие после курсора в порядке (поле, task_id).
+// Для полей, допускающих NULL, учитывается, что NULL идут последними при ASC и первыми при DESC.
+func applyTaskCursor(query *gorm.DB, params task.GetTasksParams, field task.TaskSortableField, direction task.SortDirection, cursor *task.TaskCursor) (*gorm.DB, error) {
+	col := string(field)
+	cmp := ">"
+	if direction == task.SortDirectionDesc {
+		cmp = "<"
+	}
+
+	if field == task.FieldRelevance {
+		if cursor.Value == nil || params.SearchQuery == nil {
+			return nil, task.ErrTaskInvalidCursor
 		}
-		if params.SortBy == task.FieldDeadline {
-			if orderDirection == "ASC" {
-				orderByClause = fmt.Sprintf("CASE WHEN %s IS NULL THEN 1 ELSE 0 END, %s %s", string(params.SortBy), string(params.SortBy), orderDirection)
-			} else {
-				orderByClause = fmt.Sprintf("CASE WHEN %s IS NULL THEN 0 ELSE 1 END, %s %s", string(params.SortBy), string(params.SortBy), orderDirection)
-			}
-		} else {
-			orderByClause = fmt.Sprintf("%s %s", string(params.SortBy), orderDirection)
+		value, err := parseCursorValue(field, *cursor.Value)
+		if err != nil {
+			return nil, task.ErrTaskInvalidCursor
+		}
+		return query.Where(fmt.Sprintf("(%s, tasks.task_id) %s (@cursor_value, @cursor_id)", taskSearchRankSQL, cmp),
+			sql.Named("q", *params.SearchQuery), sql.Named("cursor_value", value), sql.Named("cursor_id", cursor.TaskID)), nil
+	}
+
+	if cursor.Value == nil {
+		if !field.IsNullable() {
+			return nil, task.ErrTaskInvalidCursor
+		}
+		if direction == task.SortDirectionDesc {
+			return query.Where(fmt.Sprintf("((%s IS NULL AND task_id < ?) OR %s IS NOT NULL)", col, col), cursor.TaskID), nil
+		}
+		return query.Where(fmt.Sprintf("(%s IS NULL AND task_id > ?)", col), cursor.TaskID), nil
+	}
+
+	value, err := parseCursorValue(field, *cursor.Value)
+	if err != nil {
+		return nil, task.ErrTaskInvalidCursor
+	}
+	if field.IsNullable() && direction == task.SortDirectionAsc {
+		return query.Where(fmt.Sprintf("(%s > ? OR (%s = ? AND task_id > ?) OR %s IS NULL)", col, col, col), value, value, cursor.TaskID), nil
+	}
+	return query.Where(fmt.Sprintf("(%s, task_id) %s (?, ?)", col, cmp), value, cursor.TaskID), nil
+}
+
+func (r *TaskDatabase) GetTasks(params task.GetTasksParams) ([]*task.Task, error) {
+	op := "TaskDatabase.GetTasks"
+	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(params.UserID)))
+	var tasks []*task.Task
+
+	query, log := r.applyTaskFilters(r.db.Model(&task.Task{}), params, log)
+	if params.SearchQuery != nil && *params.SearchQuery != "" {
+		query = query.Select("tasks.*, "+taskSearchRankSQL+" AS search_rank, "+
+			taskSearchHeadlineSQL("tasks.title")+" AS title_highlight, "+
+			taskSearchHeadlineSQL("tasks.description")+" AS description_highlight",
+			sql.Named("q", *params.SearchQuery), sql.Named("headline_opt", taskSearchHeadlineOpt))
+	}
+
+	// Порядок всегда дополняется task_id, чтобы выдача была стабильной и подходила для keyset-пагинации
+	sortBy, sortOrder := params.EffectiveSort()
+	orderByClause := fmt.Sprintf("%s %s, task_id %s", string(sortBy), string(sortOrder), string(sortOrder))
+	if sortBy == task.FieldRelevance {
+		// Псевдоним из SELECT допустим в ORDER BY
+		orderByClause = fmt.Sprintf("search_rank %s, task_id %s", string(sortOrder), string(sortOrder))
+	} else if sortBy.IsNullable() {
+		nulls := "NULLS LAST"
+		if sortOrder == task.SortDirectionDesc {
+			nulls = "NULLS FIRST"
+		}
+		orderByClause = fmt.Sprintf("%s %s %s, task_id %s", string(sortBy), string(sortOrder), nulls, string(sortOrder))
+	}
+	log = log.With(slog.String("sort_by", string(sortBy)), slog.String("sort_order", string(sortOrder)))
+
+	if params.Cursor != nil {
+		var err error
+		if query, err = applyTaskCursor(query, params, sortBy, sortOrder, params.Cursor); err != nil {
+			log.Warn("invalid cursor for tasks list", "error", err)
+			return nil, err
 		}
-		log = log.With(slog.String("sort_by", string(params.SortBy)), slog.String("sort_order", orderDirection))
 	}
 	query = query.Order(orderByClause)
+	if params.Limit > 0 {
+		query = query.Limit(params.Limit)
+	}
 
 	if err := query.Find(&tasks).Error; err != nil {
 		log.Error("failed to get tasks from DB", "error", err)
@@ -171,6 +327,67 @@ func (r *TaskDatabase) GetTasks(params task.GetTasksParams) ([]*task.Task, error
 	return tasks, nil
 }
 
+// CountTasks возвращает общее количество задач по фильтрам (курсор и лимит не учитываются).
+func (r *TaskDatabase) CountTasks(params task.GetTasksParams) (int64, error) {
+	op := "TaskDatabase.CountTasks"
+	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(params.UserID)))
+
+	query, log := r.applyTaskFilters(r.db.Model(&task.Task{}), params, log)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.Error("failed to count tasks in DB", "error", err)
+		return 0, task.ErrTaskInternal
+	}
+	return total, nil
+}
+
+// GetTaskTagFacets считает задачи выборки по каждому тегу и задачи без тегов (курсор и лимит не учитываются).
+func (r *TaskDatabase) GetTaskTagFacets(params task.GetTasksParams) (*task.TaskFacets, error) {
+	op := "TaskDatabase.GetTaskTagFacets"
+	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(params.UserID)))
+
+	filtered, log := r.applyTaskFilters(r.db.Model(&task.Task{}), params, log)
+
+	var rows []struct {
+		UserTagID *uint
+		TeamTagID *uint
+		Name      string
+		Color     *string
+		Count     int64
+	}
+	err := r.db.Table("tasktags tt").
+		Select("tt.user_tag_id, tt.team_tag_id, COALESCE(ut.name, tg.name) AS name, COALESCE(ut.color, tg.color) AS color, COUNT(*) AS count").
+		Joins("LEFT JOIN usertags ut ON ut.user_tag_id = tt.user_tag_id").
+		Joins("LEFT JOIN teamtags tg ON tg.team_tag_id = tt.team_tag_id").
+		Where("tt.task_id IN (?)", filtered.Select("tasks.task_id")).
+		Group("tt.user_tag_id, tt.team_tag_id, ut.name, tg.name, ut.color, tg.color").
+		Order("count DESC, name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		log.Error("failed to get task tag facets from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	facets := &task.TaskFacets{Tags: make([]*task.TaskTagFacet, 0, len(rows))}
+	for _, row := range rows {
+		facet := &task.TaskTagFacet{Name: row.Name, Color: row.Color, Count: row.Count}
+		if row.UserTagID != nil {
+			facet.TagID, facet.Type = *row.UserTagID, "user"
+		} else if row.TeamTagID != nil {
+			facet.TagID, facet.Type = *row.TeamTagID, "team"
+		}
+		facets.Tags = append(facets.Tags, facet)
+	}
+
+	untagged, _ := r.applyTaskFilters(r.db.Model(&task.Task{}), params, log)
+	if err := untagged.Where("NOT EXISTS (SELECT 1 FROM tasktags ut WHERE ut.task_id = tasks.task_id)").Count(&facets.Untagged).Error; err != nil {
+		log.Error("failed to count untagged tasks in DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return facets, nil
+}
+
 func (r *TaskDatabase) UpdateTask(taskModel *task.Task) (*task.Task, error) {
 	op := "TaskDatabase.UpdateTask"
 	log := r.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskModel.TaskID)))
@@ -261,3 +478,134 @@ func (r *TaskDatabase) DeleteTaskPermanently(taskID uint) error {
 	log.Info("task permanently deleted successfully")
 	return nil
 }
+
+// CreateTaskEvents добавляет записи в журнал изменений задач одной пачкой.
+func (r *TaskDatabase) CreateTaskEvents(events []*task.TaskEvent) error {
+	op := "TaskDatabase.CreateTaskEvents"
+	log := r.log.With(slog.String("op", op), slog.Int("count", len(events)))
+
+	if len(events) == 0 {
+		return nil
+	}
+	if err := r.db.Create(&events).Error; err != nil {
+		log.Error("failed to create task events in DB", "error", err)
+		return task.ErrTaskInternal
+	}
+
+	log.Debug("task events created successfully in DB")
+	return nil
+}
+
+// GetTaskEvents возвращает историю изменений задачи в хронологическом порядке.
+func (r *TaskDatabase) GetTaskEvents(taskID uint) ([]*task.TaskEvent, error) {
+	op := "TaskDatabase.GetTaskEvents"
+	log := r.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)))
+	var events []*task.TaskEvent
+
+	if err := r.db.Where("task_id = ?", taskID).Order("created_at ASC, event_id ASC").Find(&events).Error; err != nil {
+		log.Error("failed to get task events from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Debug("task events retrieved from DB", slog.Int("count", len(events)))
+	return events, nil
+}
+
+// GetTeamTaskEvents возвращает ленту активности команды (новые записи первыми).
+func (r *TaskDatabase) GetTeamTaskEvents(params task.GetTeamActivityParams) ([]*task.TaskEvent, error) {
+	op := "TaskDatabase.GetTeamTaskEvents"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(params.TeamID)))
+	var events []*task.TaskEvent
+
+	query := r.db.Model(&task.TaskEvent{}).Where("team_id = ?", params.TeamID)
+	if params.ActorUserID != nil {
+		query = query.Where("actor_user_id = ?", *params.ActorUserID)
+	}
+	if params.From != nil {
+		query = query.Where("created_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		query = query.Where("created_at <= ?", *params.To)
+	}
+	if params.Limit > 0 {
+		query = query.Limit(params.Limit)
+	}
+
+	if err := query.Order("created_at DESC, event_id DESC").Find(&events).Error; err != nil {
+		log.Error("failed to get team task events from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Debug("team task events retrieved from DB", slog.Int("count", len(events)))
+	return events, nil
+}
+
+// positionScopeQuery ограничивает выборку активными задачами одной колонки.
+func (r *TaskDatabase) positionScopeQuery(scope task.TaskPositionScope, excludeTaskID uint) *gorm.DB {
+	query := r.db.Model(&task.Task{}).Where("is_deleted = ? AND status = ?", false, scope.Status)
+	if scope.TeamID != nil {
+		query = query.Where("team_id = ?", *scope.TeamID)
+	} else {
+		query = query.Where("team_id IS NULL AND created_by_user_id = ?", scope.OwnerUserID)
+	}
+	if excludeTaskID != 0 {
+		query = query.Where("task_id <> ?", excludeTaskID)
+	}
+	return query
+}
+
+// GetMaxTaskPosition возвращает наибольшую позицию в колонке или nil, если колонка пуста.
+func (r *TaskDatabase) GetMaxTaskPosition(scope task.TaskPositionScope, excludeTaskID uint) (*float64, error) {
+	op := "TaskDatabase.GetMaxTaskPosition"
+	log := r.log.With(slog.String("op", op), slog.String("status", scope.Status))
+
+	var maxPosition *float64
+	if err := r.positionScopeQuery(scope, excludeTaskID).Select("MAX(position)").Row().Scan(&maxPosition); err != nil {
+		log.Error("failed to get max task position from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return maxPosition, nil
+}
+
+// GetAdjacentTaskPosition возвращает позицию ближайшей задачи после (after = true) или перед задачей-ориентиром.
+// Порядок в колонке - (position, task_id), поэтому задача с той же позицией тоже считается соседом:
+// совпавшая позиция означает исчерпанный зазор, и колонка перенумеровывается.
+func (r *TaskDatabase) GetAdjacentTaskPosition(scope task.TaskPositionScope, position float64, anchorTaskID, excludeTaskID uint, after bool) (*float64, error) {
+	op := "TaskDatabase.GetAdjacentTaskPosition"
+	log := r.log.With(slog.String("op", op), slog.String("status", scope.Status))
+
+	query := r.positionScopeQuery(scope, excludeTaskID)
+	if after {
+		query = query.Where("(position, task_id) > (?, ?)", position, anchorTaskID).Order("position ASC, task_id ASC")
+	} else {
+		query = query.Where("(position, task_id) < (?, ?)", position, anchorTaskID).Order("position DESC, task_id DESC")
+	}
+
+	var positions []float64
+	if err := query.Limit(1).Pluck("position", &positions).Error; err != nil {
+		log.Error("failed to get adjacent task position from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	if len(positions) == 0 {
+		return nil, nil
+	}
+	return &positions[0], nil
+}
+
+// RebalanceTaskPositions перенумеровывает позиции колонки с равным шагом, сохраняя текущий порядок.
+func (r *TaskDatabase) RebalanceTaskPositions(scope task.TaskPositionScope) error {
+	op := "TaskDatabase.RebalanceTaskPositions"
+	log := r.log.With(slog.String("op", op), slog.String("status", scope.Status))
+
+	ordered := r.positionScopeQuery(scope, 0).
+		Select("task_id, ROW_NUMBER() OVER (ORDER BY position, task_id) AS rn")
+	result := r.db.Exec("UPDATE tasks SET position = ordered.rn * ? FROM (?) AS ordered WHERE tasks.task_id = ordered.task_id",
+		task.PositionStep, ordered)
+	if result.Error != nil {
+		log.Error("failed to rebalance task positions in DB", "error", result.Error)
+		return task.ErrTaskInternal
+	}
+
+	log.Info("task positions rebalanced", slog.Int64("count", result.RowsAffected))
+	return nil
+}
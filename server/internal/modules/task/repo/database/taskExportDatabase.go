@@ -0,0 +1,77 @@
+// internal/modules/task/repo/database/taskExportDatabase.go
+package database
+
+import (
+	"log/slog"
+	"server/internal/modules/task"
+	"strings"
+)
+
+// GetTaskTagNames возвращает имена тегов (пользовательских и командных) для набора задач.
+func (r *TaskDatabase) GetTaskTagNames(taskIDs []uint) (map[uint][]string, error) {
+	op := "TaskDatabase.GetTaskTagNames"
+	log := r.log.With(slog.String("op", op), slog.Int("count", len(taskIDs)))
+
+	result := make(map[uint][]string, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return result, nil
+	}
+	var rows []struct {
+		TaskID uint   `gorm:"column:task_id"`
+		Name   string `gorm:"column:name"`
+	}
+	err := r.db.Table("tasktags tt").
+		Select("tt.task_id, COALESCE(ut.name, tm.name) AS name").
+		Joins("LEFT JOIN usertags ut ON ut.user_tag_id = tt.user_tag_id").
+		Joins("LEFT JOIN teamtags tm ON tm.team_tag_id = tt.team_tag_id").
+		Where("tt.task_id IN ?", taskIDs).
+		Order("tt.task_id, name").
+		Scan(&rows).Error
+	if err != nil {
+		log.Error("failed to get task tag names from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	for _, row := range rows {
+		result[row.TaskID] = append(result[row.TaskID], row.Name)
+	}
+	return result, nil
+}
+
+// GetUserRefsByIDs возвращает логины и email пользователей по их ID.
+func (r *TaskDatabase) GetUserRefsByIDs(userIDs []uint) ([]*task.TaskUserRef, error) {
+	op := "TaskDatabase.GetUserRefsByIDs"
+	log := r.log.With(slog.String("op", op), slog.Int("count", len(userIDs)))
+
+	var refs []*task.TaskUserRef
+	if len(userIDs) == 0 {
+		return refs, nil
+	}
+	if err := r.db.Table("users").Select("user_id, login, email").Where("user_id IN ?", userIDs).Scan(&refs).Error; err != nil {
+		log.Error("failed to get users by IDs from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return refs, nil
+}
+
+// FindUsersByLoginOrEmail ищет пользователей, у которых логин или email (без учета регистра) совпадает с одним из идентификаторов.
+func (r *TaskDatabase) FindUsersByLoginOrEmail(identifiers []string) ([]*task.TaskUserRef, error) {
+	op := "TaskDatabase.FindUsersByLoginOrEmail"
+	log := r.log.With(slog.String("op", op), slog.Int("count", len(identifiers)))
+
+	var refs []*task.TaskUserRef
+	if len(identifiers) == 0 {
+		return refs, nil
+	}
+	lowered := make([]string, len(identifiers))
+	for i, identifier := range identifiers {
+		lowered[i] = strings.ToLower(identifier)
+	}
+	err := r.db.Table("users").Select("user_id, login, email").
+		Where("LOWER(login) IN ? OR LOWER(email) IN ?", lowered, lowered).
+		Scan(&refs).Error
+	if err != nil {
+		log.Error("failed to find users by login or email in DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return refs, nil
+}
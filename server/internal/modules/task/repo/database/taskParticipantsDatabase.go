@@ -0,0 +1,110 @@
+// internal/modules/task/repo/database/taskParticipantsDatabase.go
+package database
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"server/internal/modules/task"
+)
+
+// getTaskUserIDs возвращает пользователей связанной с задачами таблицы (исполнители, наблюдатели) в порядке добавления.
+func (r *TaskDatabase) getTaskUserIDs(model interface{}, orderColumn string, taskIDs []uint) (map[uint][]uint, error) {
+	result := make(map[uint][]uint, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return result, nil
+	}
+	var rows []struct {
+		TaskID uint `gorm:"column:task_id"`
+		UserID uint `gorm:"column:user_id"`
+	}
+	if err := r.db.Model(model).Select("task_id, user_id").
+		Where("task_id IN ?", taskIDs).
+		Order(orderColumn + ", user_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.TaskID] = append(result[row.TaskID], row.UserID)
+	}
+	return result, nil
+}
+
+func (r *TaskDatabase) GetTaskAssigneeIDs(taskIDs []uint) (map[uint][]uint, error) {
+	op := "TaskDatabase.GetTaskAssigneeIDs"
+	log := r.log.With(slog.String("op", op), slog.Int("count", len(taskIDs)))
+
+	result, err := r.getTaskUserIDs(&task.TaskAssignee{}, "assigned_at", taskIDs)
+	if err != nil {
+		log.Error("failed to get task assignees from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return result, nil
+}
+
+// SetTaskAssignees заменяет список исполнителей задачи; у оставшихся исполнителей сохраняется время назначения.
+func (r *TaskDatabase) SetTaskAssignees(taskID uint, userIDs []uint) error {
+	op := "TaskDatabase.SetTaskAssignees"
+	log := r.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)), slog.Int("count", len(userIDs)))
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		remove := tx.Where("task_id = ?", taskID)
+		if len(userIDs) > 0 {
+			remove = remove.Where("user_id NOT IN ?", userIDs)
+		}
+		if err := remove.Delete(&task.TaskAssignee{}).Error; err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+		rows := make([]task.TaskAssignee, len(userIDs))
+		for i, userID := range userIDs {
+			rows[i] = task.TaskAssignee{TaskID: taskID, UserID: userID}
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	})
+	if err != nil {
+		log.Error("failed to set task assignees in DB", "error", err)
+		return task.ErrTaskInternal
+	}
+	log.Debug("task assignees updated in DB")
+	return nil
+}
+
+func (r *TaskDatabase) GetTaskWatcherIDs(taskIDs []uint) (map[uint][]uint, error) {
+	op := "TaskDatabase.GetTaskWatcherIDs"
+	log := r.log.With(slog.String("op", op), slog.Int("count", len(taskIDs)))
+
+	result, err := r.getTaskUserIDs(&task.TaskWatcher{}, "created_at", taskIDs)
+	if err != nil {
+		log.Error("failed to get task watchers from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return result, nil
+}
+
+// AddTaskWatcher подписывает пользователя на задачу; повторная подписка не считается ошибкой.
+func (r *TaskDatabase) AddTaskWatcher(taskID uint, userID uint) error {
+	op := "TaskDatabase.AddTaskWatcher"
+	log := r.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)), slog.Uint64("userID", uint64(userID)))
+
+	watcher := task.TaskWatcher{TaskID: taskID, UserID: userID}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&watcher).Error; err != nil {
+		log.Error("failed to add task watcher in DB", "error", err)
+		return task.ErrTaskInternal
+	}
+	return nil
+}
+
+// RemoveTaskWatcher отписывает пользователя от задачи; отсутствие подписки не считается ошибкой.
+func (r *TaskDatabase) RemoveTaskWatcher(taskID uint, userID uint) error {
+	op := "TaskDatabase.RemoveTaskWatcher"
+	log := r.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)), slog.Uint64("userID", uint64(userID)))
+
+	if err := r.db.Where("task_id = ? AND user_id = ?", taskID, userID).Delete(&task.TaskWatcher{}).Error; err != nil {
+		log.Error("failed to remove task watcher from DB", "error", err)
+		return task.ErrTaskInternal
+	}
+	return nil
+}
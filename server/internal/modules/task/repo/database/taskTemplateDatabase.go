@@ -0,0 +1,88 @@
+// internal/modules/task/repo/database/taskTemplateDatabase.go
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"log/slog"
+	"server/internal/modules/task"
+)
+
+func (r *TaskDatabase) CreateTaskTemplate(tmpl *task.TaskTemplate) (*task.TaskTemplate, error) {
+	op := "TaskDatabase.CreateTaskTemplate"
+	log := r.log.With(slog.String("op", op), slog.Uint64("ownerUserID", uint64(tmpl.OwnerUserID)))
+
+	if err := r.db.Create(tmpl).Error; err != nil {
+		log.Error("failed to create task template in DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Info("task template created successfully in DB", slog.Uint64("templateID", uint64(tmpl.TemplateID)))
+	return tmpl, nil
+}
+
+func (r *TaskDatabase) GetTaskTemplateByID(templateID uint) (*task.TaskTemplate, error) {
+	op := "TaskDatabase.GetTaskTemplateByID"
+	log := r.log.With(slog.String("op", op), slog.Uint64("templateID", uint64(templateID)))
+	var tmpl task.TaskTemplate
+
+	if err := r.db.First(&tmpl, templateID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("task template not found by ID")
+			return nil, task.ErrTaskTemplateNotFound
+		}
+		log.Error("failed to get task template by ID from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return &tmpl, nil
+}
+
+// GetTaskTemplates возвращает шаблоны: с teamID - шаблоны команды,
+// без него - личные шаблоны пользователя и шаблоны всех его команд.
+func (r *TaskDatabase) GetTaskTemplates(userID uint, teamID *uint) ([]*task.TaskTemplate, error) {
+	op := "TaskDatabase.GetTaskTemplates"
+	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+	var templates []*task.TaskTemplate
+
+	query := r.db.Model(&task.TaskTemplate{})
+	if teamID != nil {
+		query = query.Where("team_id = ?", *teamID)
+	} else {
+		query = query.Where("(team_id IS NULL AND owner_user_id = ?) OR team_id IN (SELECT team_id FROM userteammemberships WHERE user_id = ?)", userID, userID)
+	}
+	if err := query.Order("team_id NULLS FIRST, name ASC, template_id ASC").Find(&templates).Error; err != nil {
+		log.Error("failed to get task templates from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Debug("task templates retrieved from DB", slog.Int("count", len(templates)))
+	return templates, nil
+}
+
+func (r *TaskDatabase) UpdateTaskTemplate(tmpl *task.TaskTemplate) (*task.TaskTemplate, error) {
+	op := "TaskDatabase.UpdateTaskTemplate"
+	log := r.log.With(slog.String("op", op), slog.Uint64("templateID", uint64(tmpl.TemplateID)))
+
+	if err := r.db.Model(tmpl).Select("name", "content").Updates(tmpl).Error; err != nil {
+		log.Error("failed to update task template in DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return r.GetTaskTemplateByID(tmpl.TemplateID)
+}
+
+func (r *TaskDatabase) DeleteTaskTemplate(templateID uint) error {
+	op := "TaskDatabase.DeleteTaskTemplate"
+	log := r.log.With(slog.String("op", op), slog.Uint64("templateID", uint64(templateID)))
+
+	result := r.db.Delete(&task.TaskTemplate{}, templateID)
+	if result.Error != nil {
+		log.Error("failed to delete task template in DB", "error", result.Error)
+		return task.ErrTaskInternal
+	}
+	if result.RowsAffected == 0 {
+		return task.ErrTaskTemplateNotFound
+	}
+
+	log.Info("task template deleted from DB")
+	return nil
+}
@@ -0,0 +1,213 @@
+// internal/modules/task/repo/database/timeEntryDatabase.go
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"log/slog"
+	"server/internal/modules/task"
+	"strings"
+	"time"
+)
+
+// CreateTimeEntry сохраняет запись времени. Второй запущенный таймер пользователя отклоняется
+// уникальным индексом idx_time_entries_running.
+func (r *TaskDatabase) CreateTimeEntry(entry *task.TimeEntry) (*task.TimeEntry, error) {
+	op := "TaskDatabase.CreateTimeEntry"
+	log := r.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(entry.TaskID)), slog.Uint64("userID", uint64(entry.UserID)))
+
+	if err := r.db.Create(entry).Error; err != nil {
+		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") &&
+			strings.Contains(err.Error(), "idx_time_entries_running") {
+			log.Warn("user already has a running timer")
+			return nil, task.ErrTimerAlreadyRunning
+		}
+		log.Error("failed to create time entry in DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Info("time entry created successfully in DB", slog.Uint64("entryID", uint64(entry.TimeEntryID)))
+	return entry, nil
+}
+
+func (r *TaskDatabase) GetTimeEntryByID(entryID uint) (*task.TimeEntry, error) {
+	op := "TaskDatabase.GetTimeEntryByID"
+	log := r.log.With(slog.String("op", op), slog.Uint64("entryID", uint64(entryID)))
+	var entry task.TimeEntry
+
+	if err := r.db.First(&entry, entryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("time entry not found by ID")
+			return nil, task.ErrTimeEntryNotFound
+		}
+		log.Error("failed to get time entry by ID from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return &entry, nil
+}
+
+// GetTimeEntries возвращает записи времени задачи всех пользователей, новые первыми.
+func (r *TaskDatabase) GetTimeEntries(taskID uint) ([]*task.TimeEntry, error) {
+	op := "TaskDatabase.GetTimeEntries"
+	log := r.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)))
+	var entries []*task.TimeEntry
+
+	if err := r.db.Where("task_id = ?", taskID).Order("started_at DESC, time_entry_id DESC").Find(&entries).Error; err != nil {
+		log.Error("failed to get time entries from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return entries, nil
+}
+
+// GetRunningTimeEntry возвращает запущенный таймер пользователя или ErrTimeEntryNotFound.
+func (r *TaskDatabase) GetRunningTimeEntry(userID uint) (*task.TimeEntry, error) {
+	op := "TaskDatabase.GetRunningTimeEntry"
+	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+	var entry task.TimeEntry
+
+	if err := r.db.Where("user_id = ? AND ended_at IS NULL", userID).First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, task.ErrTimeEntryNotFound
+		}
+		log.Error("failed to get running time entry from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return &entry, nil
+}
+
+// StopTimeEntry останавливает таймер. Условие ended_at IS NULL защищает от повторной остановки параллельным запросом.
+func (r *TaskDatabase) StopTimeEntry(entryID uint, endedAt time.Time) (*task.TimeEntry, error) {
+	op := "TaskDatabase.StopTimeEntry"
+	log := r.log.With(slog.String("op", op), slog.Uint64("entryID", uint64(entryID)))
+
+	result := r.db.Model(&task.TimeEntry{}).
+		Where("time_entry_id = ? AND ended_at IS NULL", entryID).
+		Update("ended_at", endedAt)
+	if result.Error != nil {
+		log.Error("failed to stop time entry in DB", "error", result.Error)
+		return nil, task.ErrTaskInternal
+	}
+	if result.RowsAffected == 0 {
+		log.Warn("running time entry not found for stop")
+		return nil, task.ErrTimeEntryNotFound
+	}
+	return r.GetTimeEntryByID(entryID)
+}
+
+func (r *TaskDatabase) DeleteTimeEntry(entryID uint) error {
+	op := "TaskDatabase.DeleteTimeEntry"
+	log := r.log.With(slog.String("op", op), slog.Uint64("entryID", uint64(entryID)))
+
+	result := r.db.Delete(&task.TimeEntry{}, entryID)
+	if result.Error != nil {
+		log.Error("failed to delete time entry from DB", "error", result.Error)
+		return task.ErrTaskInternal
+	}
+	if result.RowsAffected == 0 {
+		return task.ErrTimeEntryNotFound
+	}
+	log.Info("time entry deleted successfully from DB")
+	return nil
+}
+
+// GetTaskLoggedSeconds суммирует учтенное время по задачам; запущенные таймеры считаются до текущего момента.
+// Задачи без записей в результат не попадают.
+func (r *TaskDatabase) GetTaskLoggedSeconds(taskIDs []uint) (map[uint]int64, error) {
+	op := "TaskDatabase.GetTaskLoggedSeconds"
+	log := r.log.With(slog.String("op", op), slog.Int("count", len(taskIDs)))
+
+	result := make(map[uint]int64, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return result, nil
+	}
+	var rows []struct {
+		TaskID  uint  `gorm:"column:task_id"`
+		Seconds int64 `gorm:"column:seconds"`
+	}
+	err := r.db.Model(&task.TimeEntry{}).
+		Select("task_id, FLOOR(SUM(EXTRACT(EPOCH FROM COALESCE(ended_at, NOW()) - started_at)))::bigint AS seconds").
+		Where("task_id IN ?", taskIDs).
+		Group("task_id").
+		Scan(&rows).Error
+	if err != nil {
+		log.Error("failed to sum logged time from DB", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	for _, row := range rows {
+		result[row.TaskID] = row.Seconds
+	}
+	return result, nil
+}
+
+// timeReportQuery выбирает завершенные записи времени по задачам команды с фильтрами отчета.
+// Задачи в корзине учитываются: время на них уже потрачено.
+func (r *TaskDatabase) timeReportQuery(params task.TimeReportParams) *gorm.DB {
+	query := r.db.Table("timeentries te").
+		Joins("JOIN tasks t ON t.task_id = te.task_id").
+		Where("t.team_id = ? AND te.ended_at IS NOT NULL", params.TeamID)
+	if params.From != nil {
+		query = query.Where("te.started_at >= ?", *params.From)
+	}
+	if params.To != nil {
+		query = query.Where("te.started_at < ?", *params.To)
+	}
+	if params.MemberID != nil {
+		query = query.Where("te.user_id = ?", *params.MemberID)
+	}
+	if params.TagID != nil {
+		query = query.Where("EXISTS (SELECT 1 FROM tasktags ft WHERE ft.task_id = t.task_id AND ft.team_tag_id = ?)", *params.TagID)
+	}
+	return query
+}
+
+const timeReportMinutesExpr = "FLOOR(SUM(EXTRACT(EPOCH FROM te.ended_at - te.started_at)) / 60)::bigint"
+
+// GetTimeReport группирует учтенное время команды по участнику, тегу, задаче или дню
+// и возвращает строки отчета вместе с общим итогом в минутах.
+func (r *TaskDatabase) GetTimeReport(params task.TimeReportParams) ([]*task.TimeReportRow, int64, error) {
+	op := "TaskDatabase.GetTimeReport"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(params.TeamID)), slog.String("groupBy", string(params.GroupBy)))
+
+	query := r.timeReportQuery(params)
+	aggregates := timeReportMinutesExpr + " AS minutes, COUNT(*) AS entries"
+	switch params.GroupBy {
+	case task.TimeReportByTag:
+		query = query.
+			Joins("LEFT JOIN tasktags tt ON tt.task_id = t.task_id AND tt.team_tag_id IS NOT NULL").
+			Joins("LEFT JOIN teamtags tg ON tg.team_tag_id = tt.team_tag_id").
+			Select("tg.team_tag_id AS tag_id, tg.name AS tag_name, " + aggregates).
+			Group("tg.team_tag_id, tg.name").
+			Order("minutes DESC, tag_name NULLS LAST")
+	case task.TimeReportByTask:
+		query = query.
+			Select("t.task_id, t.title, " + aggregates).
+			Group("t.task_id, t.title").
+			Order("minutes DESC, t.task_id")
+	case task.TimeReportByDate:
+		query = query.
+			Select("TO_CHAR(te.started_at, 'YYYY-MM-DD') AS day, " + aggregates).
+			Group("day").
+			Order("day")
+	default:
+		query = query.
+			Joins("JOIN users u ON u.user_id = te.user_id").
+			Select("te.user_id, u.login, " + aggregates).
+			Group("te.user_id, u.login").
+			Order("minutes DESC, u.login")
+	}
+
+	var rows []*task.TimeReportRow
+	if err := query.Scan(&rows).Error; err != nil {
+		log.Error("failed to build time report from DB", "error", err)
+		return nil, 0, task.ErrTaskInternal
+	}
+
+	var total int64
+	if err := r.timeReportQuery(params).Select("COALESCE(" + timeReportMinutesExpr + ", 0)").Scan(&total).Error; err != nil {
+		log.Error("failed to get time report total from DB", "error", err)
+		return nil, 0, task.ErrTaskInternal
+	}
+
+	log.Debug("time report built", slog.Int("rows", len(rows)), slog.Int64("totalMinutes", total))
+	return rows, total, nil
+}
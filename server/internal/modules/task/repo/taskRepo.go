@@ -2,6 +2,7 @@ package repo
 
 import (
 	"server/internal/modules/task" // Импортируем пакет task для доступа к task.Task, task.GetTasksParams, task.Repo
+	"time"
 )
 
 type TaskDb interface {
@@ -9,9 +10,61 @@ type TaskDb interface {
 	GetTaskByID(taskID uint, userID uint) (*task.Task, error)
 	GetTaskByIDIncludingDeleted(taskID uint) (*task.Task, error) // <<< ДОБАВЛЕНО
 	GetTasks(params task.GetTasksParams) ([]*task.Task, error)   // Без totalCount
+	CountTasks(params task.GetTasksParams) (int64, error)
+	GetTaskTagFacets(params task.GetTasksParams) (*task.TaskFacets, error)
 	UpdateTask(taskModel *task.Task) (*task.Task, error)
 	DeleteTask(taskID uint, userID uint, isTeamTask bool, deletedByUserID *uint) error
 	DeleteTaskPermanently(taskID uint) error // <<< ДОБАВЛЕНО
+	CreateTaskEvents(events []*task.TaskEvent) error
+	GetTaskEvents(taskID uint) ([]*task.TaskEvent, error)
+	GetTeamTaskEvents(params task.GetTeamActivityParams) ([]*task.TaskEvent, error)
+	GetMaxTaskPosition(scope task.TaskPositionScope, excludeTaskID uint) (*float64, error)
+	GetAdjacentTaskPosition(scope task.TaskPositionScope, position float64, anchorTaskID, excludeTaskID uint, after bool) (*float64, error)
+	RebalanceTaskPositions(scope task.TaskPositionScope) error
+	CreateSavedView(view *task.SavedView) (*task.SavedView, error)
+	GetSavedViewByID(viewID uint) (*task.SavedView, error)
+	GetSavedViews(userID uint, teamID *uint) ([]*task.SavedView, error)
+	GetMaxSavedViewPosition(userID uint, teamID *uint) (int, error)
+	UpdateSavedView(view *task.SavedView) (*task.SavedView, error)
+	DeleteSavedView(viewID uint) error
+	ReorderSavedViews(viewIDs []uint) error
+	GetTaskTagNames(taskIDs []uint) (map[uint][]string, error)
+	GetUserRefsByIDs(userIDs []uint) ([]*task.TaskUserRef, error)
+	FindUsersByLoginOrEmail(identifiers []string) ([]*task.TaskUserRef, error)
+	SaveCalendarFeed(feed *task.CalendarFeed) (*task.CalendarFeed, error)
+	GetCalendarFeedByTokenHash(tokenHash string) (*task.CalendarFeed, error)
+	GetCalendarFeeds(userID uint) ([]*task.CalendarFeed, error)
+	DeleteCalendarFeed(feedID uint, userID uint) error
+	TouchCalendarFeed(feedID uint) error
+	GetTasksVersion(params task.GetTasksParams) (*task.TasksVersion, error)
+	GetTaskAssigneeIDs(taskIDs []uint) (map[uint][]uint, error)
+	SetTaskAssignees(taskID uint, userIDs []uint) error
+	GetTaskWatcherIDs(taskIDs []uint) (map[uint][]uint, error)
+	AddTaskWatcher(taskID uint, userID uint) error
+	RemoveTaskWatcher(taskID uint, userID uint) error
+	CreateTimeEntry(entry *task.TimeEntry) (*task.TimeEntry, error)
+	GetTimeEntryByID(entryID uint) (*task.TimeEntry, error)
+	GetTimeEntries(taskID uint) ([]*task.TimeEntry, error)
+	GetRunningTimeEntry(userID uint) (*task.TimeEntry, error)
+	StopTimeEntry(entryID uint, endedAt time.Time) (*task.TimeEntry, error)
+	DeleteTimeEntry(entryID uint) error
+	GetTaskLoggedSeconds(taskIDs []uint) (map[uint]int64, error)
+	GetTimeReport(params task.TimeReportParams) ([]*task.TimeReportRow, int64, error)
+	CreateTaskTemplate(tmpl *task.TaskTemplate) (*task.TaskTemplate, error)
+	GetTaskTemplateByID(templateID uint) (*task.TaskTemplate, error)
+	GetTaskTemplates(userID uint, teamID *uint) ([]*task.TaskTemplate, error)
+	UpdateTaskTemplate(tmpl *task.TaskTemplate) (*task.TaskTemplate, error)
+	DeleteTaskTemplate(templateID uint) error
+	CreateSprint(sprint *task.Sprint) (*task.Sprint, error)
+	GetSprintByID(sprintID uint) (*task.Sprint, error)
+	GetTeamSprints(teamID uint) ([]*task.Sprint, error)
+	GetClosedSprints(teamID uint, limit int) ([]*task.Sprint, error)
+	GetNextPlannedSprint(teamID uint, excludeSprintID uint) (*task.Sprint, error)
+	UpdateSprint(sprint *task.Sprint) (*task.Sprint, error)
+	DeleteSprint(sprintID uint) error
+	GetUnfinishedSprintTasks(sprintID uint) ([]*task.Task, error)
+	SetTasksSprint(taskIDs []uint, sprintID *uint) error
+	GetSprintHistory(sprintID uint) (*task.SprintHistory, error)
 }
 
 type TaskCache interface {
@@ -21,6 +74,8 @@ type TaskCache interface {
 	GetTasksCache(cacheKey string) ([]*task.Task, error)
 	SaveTasks(cacheKey string, tasks []*task.Task) error
 	InvalidateTasks(keys ...string) error
+	GetTasksListVersion(scope string) (int64, error)
+	BumpTasksListVersion(scopes ...string) error
 }
 
 type repo struct {
@@ -53,6 +108,14 @@ func (r *repo) GetTasks(params task.GetTasksParams) ([]*task.Task, error) {
 	return r.db.GetTasks(params)
 }
 
+func (r *repo) CountTasks(params task.GetTasksParams) (int64, error) {
+	return r.db.CountTasks(params)
+}
+
+func (r *repo) GetTaskTagFacets(params task.GetTasksParams) (*task.TaskFacets, error) {
+	return r.db.GetTaskTagFacets(params)
+}
+
 func (r *repo) UpdateTask(taskModel *task.Task) (*task.Task, error) {
 	return r.db.UpdateTask(taskModel)
 }
@@ -65,6 +128,206 @@ func (r *repo) DeleteTaskPermanently(taskID uint) error {
 	return r.db.DeleteTaskPermanently(taskID)
 }
 
+func (r *repo) CreateTaskEvents(events []*task.TaskEvent) error {
+	return r.db.CreateTaskEvents(events)
+}
+
+func (r *repo) GetTaskEvents(taskID uint) ([]*task.TaskEvent, error) {
+	return r.db.GetTaskEvents(taskID)
+}
+
+func (r *repo) GetTeamTaskEvents(params task.GetTeamActivityParams) ([]*task.TaskEvent, error) {
+	return r.db.GetTeamTaskEvents(params)
+}
+
+func (r *repo) GetMaxTaskPosition(scope task.TaskPositionScope, excludeTaskID uint) (*float64, error) {
+	return r.db.GetMaxTaskPosition(scope, excludeTaskID)
+}
+
+func (r *repo) GetAdjacentTaskPosition(scope task.TaskPositionScope, position float64, anchorTaskID, excludeTaskID uint, after bool) (*float64, error) {
+	return r.db.GetAdjacentTaskPosition(scope, position, anchorTaskID, excludeTaskID, after)
+}
+
+func (r *repo) RebalanceTaskPositions(scope task.TaskPositionScope) error {
+	return r.db.RebalanceTaskPositions(scope)
+}
+
+func (r *repo) CreateSavedView(view *task.SavedView) (*task.SavedView, error) {
+	return r.db.CreateSavedView(view)
+}
+
+func (r *repo) GetSavedViewByID(viewID uint) (*task.SavedView, error) {
+	return r.db.GetSavedViewByID(viewID)
+}
+
+func (r *repo) GetSavedViews(userID uint, teamID *uint) ([]*task.SavedView, error) {
+	return r.db.GetSavedViews(userID, teamID)
+}
+
+func (r *repo) GetMaxSavedViewPosition(userID uint, teamID *uint) (int, error) {
+	return r.db.GetMaxSavedViewPosition(userID, teamID)
+}
+
+func (r *repo) UpdateSavedView(view *task.SavedView) (*task.SavedView, error) {
+	return r.db.UpdateSavedView(view)
+}
+
+func (r *repo) DeleteSavedView(viewID uint) error {
+	return r.db.DeleteSavedView(viewID)
+}
+
+func (r *repo) ReorderSavedViews(viewIDs []uint) error {
+	return r.db.ReorderSavedViews(viewIDs)
+}
+
+func (r *repo) GetTaskTagNames(taskIDs []uint) (map[uint][]string, error) {
+	return r.db.GetTaskTagNames(taskIDs)
+}
+
+func (r *repo) GetUserRefsByIDs(userIDs []uint) ([]*task.TaskUserRef, error) {
+	return r.db.GetUserRefsByIDs(userIDs)
+}
+
+func (r *repo) FindUsersByLoginOrEmail(identifiers []string) ([]*task.TaskUserRef, error) {
+	return r.db.FindUsersByLoginOrEmail(identifiers)
+}
+
+func (r *repo) SaveCalendarFeed(feed *task.CalendarFeed) (*task.CalendarFeed, error) {
+	return r.db.SaveCalendarFeed(feed)
+}
+
+func (r *repo) GetCalendarFeedByTokenHash(tokenHash string) (*task.CalendarFeed, error) {
+	return r.db.GetCalendarFeedByTokenHash(tokenHash)
+}
+
+func (r *repo) GetCalendarFeeds(userID uint) ([]*task.CalendarFeed, error) {
+	return r.db.GetCalendarFeeds(userID)
+}
+
+func (r *repo) DeleteCalendarFeed(feedID uint, userID uint) error {
+	return r.db.DeleteCalendarFeed(feedID, userID)
+}
+
+func (r *repo) TouchCalendarFeed(feedID uint) error {
+	return r.db.TouchCalendarFeed(feedID)
+}
+
+func (r *repo) GetTasksVersion(params task.GetTasksParams) (*task.TasksVersion, error) {
+	return r.db.GetTasksVersion(params)
+}
+
+func (r *repo) GetTaskAssigneeIDs(taskIDs []uint) (map[uint][]uint, error) {
+	return r.db.GetTaskAssigneeIDs(taskIDs)
+}
+
+func (r *repo) SetTaskAssignees(taskID uint, userIDs []uint) error {
+	return r.db.SetTaskAssignees(taskID, userIDs)
+}
+
+func (r *repo) GetTaskWatcherIDs(taskIDs []uint) (map[uint][]uint, error) {
+	return r.db.GetTaskWatcherIDs(taskIDs)
+}
+
+func (r *repo) AddTaskWatcher(taskID uint, userID uint) error {
+	return r.db.AddTaskWatcher(taskID, userID)
+}
+
+func (r *repo) RemoveTaskWatcher(taskID uint, userID uint) error {
+	return r.db.RemoveTaskWatcher(taskID, userID)
+}
+
+func (r *repo) CreateTimeEntry(entry *task.TimeEntry) (*task.TimeEntry, error) {
+	return r.db.CreateTimeEntry(entry)
+}
+
+func (r *repo) GetTimeEntryByID(entryID uint) (*task.TimeEntry, error) {
+	return r.db.GetTimeEntryByID(entryID)
+}
+
+func (r *repo) GetTimeEntries(taskID uint) ([]*task.TimeEntry, error) {
+	return r.db.GetTimeEntries(taskID)
+}
+
+func (r *repo) GetRunningTimeEntry(userID uint) (*task.TimeEntry, error) {
+	return r.db.GetRunningTimeEntry(userID)
+}
+
+func (r *repo) StopTimeEntry(entryID uint, endedAt time.Time) (*task.TimeEntry, error) {
+	return r.db.StopTimeEntry(entryID, endedAt)
+}
+
+func (r *repo) DeleteTimeEntry(entryID uint) error {
+	return r.db.DeleteTimeEntry(entryID)
+}
+
+func (r *repo) GetTaskLoggedSeconds(taskIDs []uint) (map[uint]int64, error) {
+	return r.db.GetTaskLoggedSeconds(taskIDs)
+}
+
+func (r *repo) GetTimeReport(params task.TimeReportParams) ([]*task.TimeReportRow, int64, error) {
+	return r.db.GetTimeReport(params)
+}
+
+func (r *repo) CreateTaskTemplate(tmpl *task.TaskTemplate) (*task.TaskTemplate, error) {
+	return r.db.CreateTaskTemplate(tmpl)
+}
+
+func (r *repo) GetTaskTemplateByID(templateID uint) (*task.TaskTemplate, error) {
+	return r.db.GetTaskTemplateByID(templateID)
+}
+
+func (r *repo) GetTaskTemplates(userID uint, teamID *uint) ([]*task.TaskTemplate, error) {
+	return r.db.GetTaskTemplates(userID, teamID)
+}
+
+func (r *repo) UpdateTaskTemplate(tmpl *task.TaskTemplate) (*task.TaskTemplate, error) {
+	return r.db.UpdateTaskTemplate(tmpl)
+}
+
+func (r *repo) DeleteTaskTemplate(templateID uint) error {
+	return r.db.DeleteTaskTemplate(templateID)
+}
+
+func (r *repo) CreateSprint(sprint *task.Sprint) (*task.Sprint, error) {
+	return r.db.CreateSprint(sprint)
+}
+
+func (r *repo) GetSprintByID(sprintID uint) (*task.Sprint, error) {
+	return r.db.GetSprintByID(sprintID)
+}
+
+func (r *repo) GetTeamSprints(teamID uint) ([]*task.Sprint, error) {
+	return r.db.GetTeamSprints(teamID)
+}
+
+func (r *repo) GetClosedSprints(teamID uint, limit int) ([]*task.Sprint, error) {
+	return r.db.GetClosedSprints(teamID, limit)
+}
+
+func (r *repo) GetNextPlannedSprint(teamID uint, excludeSprintID uint) (*task.Sprint, error) {
+	return r.db.GetNextPlannedSprint(teamID, excludeSprintID)
+}
+
+func (r *repo) UpdateSprint(sprint *task.Sprint) (*task.Sprint, error) {
+	return r.db.UpdateSprint(sprint)
+}
+
+func (r *repo) DeleteSprint(sprintID uint) error {
+	return r.db.DeleteSprint(sprintID)
+}
+
+func (r *repo) GetUnfinishedSprintTasks(sprintID uint) ([]*task.Task, error) {
+	return r.db.GetUnfinishedSprintTasks(sprintID)
+}
+
+func (r *repo) SetTasksSprint(taskIDs []uint, sprintID *uint) error {
+	return r.db.SetTasksSprint(taskIDs, sprintID)
+}
+
+func (r *repo) GetSprintHistory(sprintID uint) (*task.SprintHistory, error) {
+	return r.db.GetSprintHistory(sprintID)
+}
+
 func (r *repo) GetTask(taskID uint) (*task.Task, error) {
 	return r.ch.GetTask(taskID)
 }
@@ -83,3 +346,11 @@ func (r *repo) SaveTasks(cacheKey string, tasks []*task.Task) error {
 func (r *repo) InvalidateTasks(keys ...string) error {
 	return r.ch.InvalidateTasks(keys...)
 }
+
+func (r *repo) GetTasksListVersion(scope string) (int64, error) {
+	return r.ch.GetTasksListVersion(scope)
+}
+
+func (r *repo) BumpTasksListVersion(scopes ...string) error {
+	return r.ch.BumpTasksListVersion(scopes...)
+}
@@ -0,0 +1,38 @@
+package repo
+
+import (
+	"gorm.io/gorm"
+	"log/slog"
+	"server/internal/modules/tag"
+	tagRepo "server/internal/modules/tag/repo"
+	tagDatabase "server/internal/modules/tag/repo/database"
+	"server/internal/modules/task"
+	"server/internal/modules/task/repo/database"
+)
+
+// TxRunner выполняет операции над задачами и их тегами в одной транзакции БД.
+// Кэши не транзакционны и передаются как есть.
+type TxRunner struct {
+	db     *gorm.DB
+	taskCh TaskCache
+	tagCh  tagRepo.TagCache
+	log    *slog.Logger
+}
+
+func NewTxRunner(db *gorm.DB, taskCh TaskCache, tagCh tagRepo.TagCache, log *slog.Logger) *TxRunner {
+	return &TxRunner{
+		db:     db,
+		taskCh: taskCh,
+		tagCh:  tagCh,
+		log:    log,
+	}
+}
+
+// RunInTx вызывает fn с репозиториями, привязанными к транзакции. Ошибка fn откатывает транзакцию.
+func (t *TxRunner) RunInTx(fn func(taskRepo task.Repo, tagRepo tag.Repo) error) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		txTaskRepo := NewRepo(database.NewTaskDatabase(tx, t.log), t.taskCh)
+		txTagRepo := tagRepo.NewRepo(tagDatabase.NewTagDatabase(tx, t.log), t.tagCh, t.log)
+		return fn(txTaskRepo, txTagRepo)
+	})
+}
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+	"strings"
+	"time"
+)
+
+// calendarFeedHistory - насколько далеко в прошлое лента показывает задачи по дедлайну
+const calendarFeedHistory = 90 * 24 * time.Hour
+
+// hashCalendarFeedToken возвращает SHA-256 токена: в БД токен в открытом виде не хранится.
+func hashCalendarFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateCalendarFeedToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (uc *TaskUseCase) GetCalendarFeeds(userID uint) ([]*task.CalendarFeedResponse, error) {
+	feeds, err := uc.repo.GetCalendarFeeds(userID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*task.CalendarFeedResponse, len(feeds))
+	for i, feed := range feeds {
+		responses[i] = task.ToCalendarFeedResponse(feed)
+	}
+	return responses, nil
+}
+
+// CreateCalendarFeed создает ленту личных задач или задач команды. Если лента этой области уже есть,
+// ее токен заменяется новым, и старая ссылка перестает работать.
+func (uc *TaskUseCase) CreateCalendarFeed(userID uint, req task.CreateCalendarFeedRequest) (*task.CalendarFeedResponse, error) {
+	op := "TaskUseCase.CreateCalendarFeed"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	if req.TeamID != nil {
+		log = log.With(slog.Uint64("teamID", uint64(*req.TeamID)))
+		isMember, err := uc.teamService.IsUserMember(userID, *req.TeamID)
+		if err != nil {
+			log.Error("failed to check team membership for calendar feed", "error", err)
+			return nil, task.ErrTaskInternal
+		}
+		if !isMember {
+			return nil, task.ErrTaskAccessDenied
+		}
+	}
+
+	token, err := generateCalendarFeedToken()
+	if err != nil {
+		log.Error("failed to generate calendar feed token", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	feed, err := uc.repo.SaveCalendarFeed(&task.CalendarFeed{
+		UserID:    userID,
+		TeamID:    req.TeamID,
+		TokenHash: hashCalendarFeedToken(token),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("calendar feed token issued", slog.Uint64("feedID", uint64(feed.FeedID)))
+	response := task.ToCalendarFeedResponse(feed)
+	response.Token = token
+	return response, nil
+}
+
+// DeleteCalendarFeed отзывает ленту: ссылка с ее токеном перестает работать.
+func (uc *TaskUseCase) DeleteCalendarFeed(feedID uint, userID uint) error {
+	return uc.repo.DeleteCalendarFeed(feedID, userID)
+}
+
+// GetCalendarFeed возвращает задачи с дедлайном для ленты по ее токену. Версия ленты (ETag, Last-Modified)
+// считается по числу задач и их последнему изменению; если она совпадает с версией клиента, задачи не загружаются.
+func (uc *TaskUseCase) GetCalendarFeed(query task.CalendarFeedQuery) (*task.CalendarFeedContent, error) {
+	op := "TaskUseCase.GetCalendarFeed"
+	uc = uc.forRequest()
+	log := uc.log.With(slog.String("op", op))
+
+	feed, err := uc.repo.GetCalendarFeedByTokenHash(hashCalendarFeedToken(query.Token))
+	if err != nil {
+		return nil, err
+	}
+	log = log.With(slog.Uint64("feedID", uint64(feed.FeedID)), slog.Uint64("userID", uint64(feed.UserID)))
+
+	deadlineFrom := time.Now().Add(-calendarFeedHistory).Truncate(24 * time.Hour)
+	params := task.GetTasksParams{
+		UserID:       feed.UserID,
+		ViewType:     task.ViewTypeUserCentricGlobal,
+		DeadlineFrom: &deadlineFrom,
+	}
+	content := &task.CalendarFeedContent{Name: "ToDoApp"}
+	if feed.TeamID != nil {
+		// Ушедший из команды пользователь теряет и доступ к ее ленте
+		isMember, err := uc.teamService.IsUserMember(feed.UserID, *feed.TeamID)
+		if err != nil {
+			log.Error("failed to check team membership for calendar feed", "error", err)
+			return nil, task.ErrTaskInternal
+		}
+		if !isMember {
+			log.Warn("calendar feed owner is no longer a team member")
+			return nil, task.ErrCalendarFeedNotFound
+		}
+		scopeTagID, err := uc.guestTaskTagScope(feed.UserID, feed.TeamID)
+		if err != nil {
+			log.Error("failed to get guest task scope for calendar feed", "error", err)
+			return nil, task.ErrTaskInternal
+		}
+		params.ViewType = task.ViewTypeDefault
+		params.TeamID = feed.TeamID
+		params.ScopeTeamTagID = scopeTagID
+		if feed.TeamName != nil {
+			content.Name += ": " + *feed.TeamName
+		}
+	}
+
+	version, err := uc.repo.GetTasksVersion(params)
+	if err != nil {
+		return nil, err
+	}
+	content.LastModified = feed.CreatedAt
+	if latest := version.Latest(); latest != nil && latest.After(content.LastModified) {
+		content.LastModified = *latest
+	}
+	content.LastModified = content.LastModified.UTC().Truncate(time.Second)
+	etag := sha256.Sum256([]byte(fmt.Sprintf("%d:%d:%d:%s:%s:%d:%d:%d:%d:%d", feed.FeedID, version.Count, content.LastModified.UnixNano(),
+		deadlineFrom.Format(time.DateOnly), query.EntryType, version.TagsChecksum, unixNanoOrZero(version.TagsModified),
+		version.StatusCount, unixNanoOrZero(version.StatusesModified), unixNanoOrZero(version.TeamsModified))))
+	content.ETag = `"` + hex.EncodeToString(etag[:16]) + `"`
+
+	if errTouch := uc.repo.TouchCalendarFeed(feed.FeedID); errTouch != nil {
+		log.Warn("failed to update calendar feed access time", "error", errTouch)
+	}
+
+	if query.IfNoneMatch != "" {
+		content.NotModified = query.IfNoneMatch == "*" || strings.Contains(query.IfNoneMatch, content.ETag)
+	} else if query.IfModifiedSince != nil {
+		content.NotModified = !content.LastModified.After(*query.IfModifiedSince)
+	}
+	if content.NotModified {
+		log.Debug("calendar feed not modified")
+		return content, nil
+	}
+
+	workflows := make(map[uint]*team.TaskWorkflow)
+	content.Items = make([]*task.CalendarFeedItem, 0, version.Count)
+	err = uc.forEachTaskPage(params, func(page []*task.Task) error {
+		taskIDs := make([]uint, len(page))
+		for i, t := range page {
+			taskIDs[i] = t.TaskID
+		}
+		tagNames, err := uc.repo.GetTaskTagNames(taskIDs)
+		if err != nil {
+			return err
+		}
+		for _, t := range page {
+			var workflowKey uint // 0 - набор статусов личных задач
+			if t.TeamID != nil {
+				workflowKey = *t.TeamID
+			}
+			workflow, ok := workflows[workflowKey]
+			if !ok {
+				if workflow, err = uc.getTaskWorkflow(t.TeamID); err != nil {
+					log.Error("failed to get task workflow for calendar feed", "error", err)
+					return task.ErrTaskInternal
+				}
+				workflows[workflowKey] = workflow
+			}
+			item := &task.CalendarFeedItem{Task: t, StatusName: t.Status, Tags: tagNames[t.TaskID]}
+			if status := workflow.Status(t.Status); status != nil {
+				item.StatusName = status.Name
+			}
+			content.Items = append(content.Items, item)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("calendar feed rendered", slog.Int("count", len(content.Items)))
+	return content, nil
+}
+
+func unixNanoOrZero(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.UnixNano()
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"server/internal/modules/team"
+)
+
+type teamMemberKey struct {
+	userID uint
+	teamID uint
+}
+
+type teamPermissionKey struct {
+	teamMemberKey
+	permission team.Permission
+}
+
+type teamRoleResult struct {
+	role *team.TeamMemberRole
+	err  error
+}
+
+type teamPermissionResult struct {
+	allowed bool
+	err     error
+}
+
+// requestTeamService запоминает ответы сервиса команд на время одного запроса. Списки, экспорт и массовые
+// операции проверяют права для каждой задачи, а роль и права пользователя в команде за время запроса не меняются.
+// Остальные методы передаются сервису команд без изменений.
+type requestTeamService struct {
+	TeamService
+	roles       map[teamMemberKey]teamRoleResult
+	permissions map[teamPermissionKey]teamPermissionResult
+	writable    map[uint]error
+	guestScopes map[teamMemberKey]*uint
+	workflows   map[uint]*team.TaskWorkflow
+}
+
+func newRequestTeamService(teams TeamService) *requestTeamService {
+	if memo, ok := teams.(*requestTeamService); ok {
+		return memo
+	}
+	return &requestTeamService{
+		TeamService: teams,
+		roles:       make(map[teamMemberKey]teamRoleResult),
+		permissions: make(map[teamPermissionKey]teamPermissionResult),
+		writable:    make(map[uint]error),
+		guestScopes: make(map[teamMemberKey]*uint),
+		workflows:   make(map[uint]*team.TaskWorkflow),
+	}
+}
+
+// forRequest возвращает копию usecase, которая запоминает ответы сервиса команд до конца запроса.
+func (uc *TaskUseCase) forRequest() *TaskUseCase {
+	if uc.teamService == nil {
+		return uc
+	}
+	worker := *uc
+	worker.teamService = newRequestTeamService(uc.teamService)
+	return &worker
+}
+
+func (s *requestTeamService) GetUserRoleInTeam(userID, teamID uint) (*team.TeamMemberRole, error) {
+	key := teamMemberKey{userID, teamID}
+	if res, ok := s.roles[key]; ok {
+		return res.role, res.err
+	}
+	role, err := s.TeamService.GetUserRoleInTeam(userID, teamID)
+	s.roles[key] = teamRoleResult{role, err}
+	return role, err
+}
+
+func (s *requestTeamService) IsUserMember(userID, teamID uint) (bool, error) {
+	role, err := s.GetUserRoleInTeam(userID, teamID)
+	return role != nil, err
+}
+
+func (s *requestTeamService) IsUserTeamMemberWithUserID(teamID uint, targetUserID uint) (bool, error) {
+	return s.IsUserMember(targetUserID, teamID)
+}
+
+func (s *requestTeamService) HasTeamPermission(userID, teamID uint, permission team.Permission) (bool, error) {
+	key := teamPermissionKey{teamMemberKey{userID, teamID}, permission}
+	if res, ok := s.permissions[key]; ok {
+		return res.allowed, res.err
+	}
+	allowed, err := s.TeamService.HasTeamPermission(userID, teamID, permission)
+	s.permissions[key] = teamPermissionResult{allowed, err}
+	return allowed, err
+}
+
+func (s *requestTeamService) CanUserCreateTeamTask(userID, teamID uint) (bool, error) {
+	return s.HasTeamPermission(userID, teamID, team.PermTaskCreate)
+}
+
+func (s *requestTeamService) CanUserEditTeamTaskDetails(userID, teamID uint) (bool, error) {
+	return s.HasTeamPermission(userID, teamID, team.PermTaskEditAny)
+}
+
+func (s *requestTeamService) CanUserViewTeamActivity(userID, teamID uint) (bool, error) {
+	return s.HasTeamPermission(userID, teamID, team.PermActivityView)
+}
+
+func (s *requestTeamService) CheckTeamWritable(teamID uint) error {
+	if err, ok := s.writable[teamID]; ok {
+		return err
+	}
+	err := s.TeamService.CheckTeamWritable(teamID)
+	s.writable[teamID] = err
+	return err
+}
+
+func (s *requestTeamService) GetGuestTaskTagScope(userID, teamID uint) (*uint, error) {
+	key := teamMemberKey{userID, teamID}
+	if scope, ok := s.guestScopes[key]; ok {
+		return scope, nil
+	}
+	scope, err := s.TeamService.GetGuestTaskTagScope(userID, teamID)
+	if err == nil {
+		s.guestScopes[key] = scope
+	}
+	return scope, err
+}
+
+func (s *requestTeamService) GetTaskWorkflow(teamID uint) (*team.TaskWorkflow, error) {
+	if workflow, ok := s.workflows[teamID]; ok {
+		return workflow, nil
+	}
+	workflow, err := s.TeamService.GetTaskWorkflow(teamID)
+	if err == nil {
+		s.workflows[teamID] = workflow
+	}
+	return workflow, err
+}
@@ -0,0 +1,381 @@
+package usecase
+
+import (
+	"errors"
+	"log/slog"
+	"regexp"
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+	"strconv"
+	"time"
+)
+
+// relativeDateRe - смещение относительно сегодняшнего дня: +7d, -2w, +1m
+var relativeDateRe = regexp.MustCompile(`^([+-])(\d{1,3})([dwm])$`)
+
+// resolveDateToken превращает токен даты представления в начало соответствующего дня.
+// Конкретные даты (2006-01-02 или RFC3339) возвращаются как есть.
+func resolveDateToken(token string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch token {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	if m := relativeDateRe.FindStringSubmatch(token); m != nil {
+		n, _ := strconv.Atoi(m[2])
+		if m[1] == "-" {
+			n = -n
+		}
+		switch m[3] {
+		case "d":
+			return today.AddDate(0, 0, n), nil
+		case "w":
+			return today.AddDate(0, 0, 7*n), nil
+		default:
+			return today.AddDate(0, n, 0), nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", token, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, token); err == nil {
+		return t, nil
+	}
+	return time.Time{}, task.ErrSavedViewInvalid
+}
+
+// validateSavedViewFilters проверяет то, что не покрывает валидатор: токены дат и сочетание тегов.
+func validateSavedViewFilters(f task.SavedViewFilters) error {
+	now := time.Now()
+	for _, token := range []*string{f.DeadlineFrom, f.DeadlineTo} {
+		if token == nil {
+			continue
+		}
+		if _, err := resolveDateToken(*token, now); err != nil {
+			return err
+		}
+	}
+	if f.AssignedToMe && f.AssignedToUserID != nil {
+		return task.ErrSavedViewInvalid
+	}
+	hasTagIDs := len(f.UserTagIDs) > 0 || len(f.TeamTagIDs) > 0
+	if f.Untagged && hasTagIDs && f.TagMatch != nil && *f.TagMatch == task.TagMatchAll {
+		return task.ErrSavedViewInvalid
+	}
+	return nil
+}
+
+// savedViewAccess возвращает права пользователя на представление.
+func (uc *TaskUseCase) savedViewAccess(view *task.SavedView, userID uint) (canView bool, canEdit bool, err error) {
+	return uc.sharedItemAccess(view.OwnerUserID, view.TeamID, userID, team.PermViewManage)
+}
+
+// sharedItemAccess возвращает права на личный или командный объект (представление, шаблон).
+// Личный объект доступен только владельцу; командный видят все участники команды,
+// а изменять могут участники с правом permission.
+func (uc *TaskUseCase) sharedItemAccess(ownerUserID uint, teamID *uint, userID uint, permission team.Permission) (canView bool, canEdit bool, err error) {
+	if teamID == nil {
+		isOwner := ownerUserID == userID
+		return isOwner, isOwner, nil
+	}
+	isMember, err := uc.teamService.IsUserMember(userID, *teamID)
+	if err != nil || !isMember {
+		return false, false, err
+	}
+	canEdit, err = uc.canManageTeamItems(userID, *teamID, permission)
+	if err != nil {
+		return false, false, err
+	}
+	return true, canEdit, nil
+}
+
+// getEditableSavedView загружает представление и проверяет право на его изменение.
+// Недоступное для просмотра представление выглядит как несуществующее.
+func (uc *TaskUseCase) getEditableSavedView(viewID uint, userID uint, log *slog.Logger) (*task.SavedView, error) {
+	view, err := uc.repo.GetSavedViewByID(viewID)
+	if err != nil {
+		if errors.Is(err, task.ErrSavedViewNotFound) {
+			return nil, err
+		}
+		log.Error("failed to get saved view", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	canView, canEdit, err := uc.savedViewAccess(view, userID)
+	if err != nil {
+		log.Error("failed to check saved view access", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	if !canView {
+		return nil, task.ErrSavedViewNotFound
+	}
+	if !canEdit {
+		return nil, uc.teamEditDenied(view.TeamID)
+	}
+	return view, nil
+}
+
+func (uc *TaskUseCase) GetSavedViews(userID uint, teamID *uint) ([]*task.SavedViewResponse, error) {
+	op := "TaskUseCase.GetSavedViews"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	if teamID != nil {
+		isMember, err := uc.teamService.IsUserMember(userID, *teamID)
+		if err != nil {
+			log.Error("failed to check team membership for saved views", "error", err)
+			return nil, task.ErrTaskInternal
+		}
+		if !isMember {
+			return nil, task.ErrTaskAccessDenied
+		}
+	}
+
+	views, err := uc.repo.GetSavedViews(userID, teamID)
+	if err != nil {
+		log.Error("failed to get saved views", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	// Право в каждой команде проверяется один раз
+	editableTeams := make(map[uint]bool)
+	responses := make([]*task.SavedViewResponse, 0, len(views))
+	for _, view := range views {
+		canEdit := view.OwnerUserID == userID
+		if view.TeamID != nil {
+			editable, known := editableTeams[*view.TeamID]
+			if !known {
+				var errPerm error
+				editable, errPerm = uc.canManageTeamItems(userID, *view.TeamID, team.PermViewManage)
+				if errPerm != nil {
+					log.Warn("failed to check permission for saved view, skipping", "viewID", view.ViewID, "error", errPerm)
+					continue
+				}
+				editableTeams[*view.TeamID] = editable
+			}
+			canEdit = editable
+		}
+		responses = append(responses, task.ToSavedViewResponse(view, canEdit))
+	}
+	return responses, nil
+}
+
+func (uc *TaskUseCase) CreateSavedView(userID uint, req task.CreateSavedViewRequest) (*task.SavedViewResponse, error) {
+	op := "TaskUseCase.CreateSavedView"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	if req.TeamID != nil {
+		canManage, err := uc.teamService.HasTeamPermission(userID, *req.TeamID, team.PermViewManage)
+		if err != nil {
+			log.Error("failed to check permission for saved view", "error", err)
+			return nil, teamCheckError(err)
+		}
+		if !canManage {
+			log.Warn("user cannot create shared team view", "teamID", *req.TeamID)
+			return nil, task.ErrTaskAccessDenied
+		}
+		req.Filters.TeamID = req.TeamID
+	}
+	if err := validateSavedViewFilters(req.Filters); err != nil {
+		log.Warn("invalid saved view filters", "error", err)
+		return nil, err
+	}
+
+	maxPosition, err := uc.repo.GetMaxSavedViewPosition(userID, req.TeamID)
+	if err != nil {
+		log.Error("failed to get saved view position", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	view, err := uc.repo.CreateSavedView(&task.SavedView{
+		OwnerUserID: userID,
+		TeamID:      req.TeamID,
+		Name:        req.Name,
+		Filters:     req.Filters,
+		Position:    maxPosition + 1,
+	})
+	if err != nil {
+		log.Error("failed to create saved view", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Info("saved view created", slog.Uint64("viewID", uint64(view.ViewID)))
+	return task.ToSavedViewResponse(view, true), nil
+}
+
+func (uc *TaskUseCase) UpdateSavedView(viewID uint, userID uint, req task.UpdateSavedViewRequest) (*task.SavedViewResponse, error) {
+	op := "TaskUseCase.UpdateSavedView"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("viewID", uint64(viewID)), slog.Uint64("userID", uint64(userID)))
+
+	view, err := uc.getEditableSavedView(viewID, userID, log)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		view.Name = *req.Name
+	}
+	if req.Filters != nil {
+		filters := *req.Filters
+		if view.TeamID != nil {
+			filters.TeamID = view.TeamID
+		}
+		if err := validateSavedViewFilters(filters); err != nil {
+			log.Warn("invalid saved view filters", "error", err)
+			return nil, err
+		}
+		view.Filters = filters
+	}
+
+	updated, err := uc.repo.UpdateSavedView(view)
+	if err != nil {
+		log.Error("failed to update saved view", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Info("saved view updated")
+	return task.ToSavedViewResponse(updated, true), nil
+}
+
+func (uc *TaskUseCase) DeleteSavedView(viewID uint, userID uint) error {
+	op := "TaskUseCase.DeleteSavedView"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("viewID", uint64(viewID)), slog.Uint64("userID", uint64(userID)))
+
+	if _, err := uc.getEditableSavedView(viewID, userID, log); err != nil {
+		return err
+	}
+	if err := uc.repo.DeleteSavedView(viewID); err != nil {
+		if errors.Is(err, task.ErrSavedViewNotFound) {
+			return err
+		}
+		log.Error("failed to delete saved view", "error", err)
+		return task.ErrTaskInternal
+	}
+
+	log.Info("saved view deleted")
+	return nil
+}
+
+// ReorderSavedViews задает порядок списка целиком: передаются все представления списка в новом порядке.
+func (uc *TaskUseCase) ReorderSavedViews(userID uint, req task.ReorderSavedViewsRequest) ([]*task.SavedViewResponse, error) {
+	op := "TaskUseCase.ReorderSavedViews"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	if req.TeamID != nil {
+		canManage, err := uc.teamService.HasTeamPermission(userID, *req.TeamID, team.PermViewManage)
+		if err != nil {
+			log.Error("failed to check permission for saved views order", "error", err)
+			return nil, teamCheckError(err)
+		}
+		if !canManage {
+			return nil, task.ErrTaskAccessDenied
+		}
+	}
+
+	views, err := uc.repo.GetSavedViews(userID, req.TeamID)
+	if err != nil {
+		log.Error("failed to get saved views for reorder", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	// Без team_id в списке оказываются и командные представления - упорядочиваются только личные
+	existing := make(map[uint]bool)
+	for _, view := range views {
+		if (req.TeamID == nil) == (view.TeamID == nil) {
+			existing[view.ViewID] = true
+		}
+	}
+	seen := make(map[uint]bool)
+	for _, id := range req.ViewIDs {
+		if !existing[id] || seen[id] {
+			log.Warn("reorder list does not match saved views", "viewID", id)
+			return nil, task.ErrSavedViewInvalid
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(existing) {
+		log.Warn("reorder list does not contain all saved views")
+		return nil, task.ErrSavedViewInvalid
+	}
+
+	if err := uc.repo.ReorderSavedViews(req.ViewIDs); err != nil {
+		log.Error("failed to reorder saved views", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Info("saved views reordered", slog.Int("count", len(req.ViewIDs)))
+	return uc.GetSavedViews(userID, req.TeamID)
+}
+
+// applySavedView подставляет в запрос списка фильтры и сортировку сохраненного представления.
+// Фильтры представления заменяют фильтры из query; пагинация и include_* берутся из запроса.
+func (uc *TaskUseCase) applySavedView(userID uint, req *task.GetTasksRequest) error {
+	op := "TaskUseCase.applySavedView"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("viewID", uint64(*req.ViewID)), slog.Uint64("userID", uint64(userID)))
+
+	view, err := uc.repo.GetSavedViewByID(*req.ViewID)
+	if err != nil {
+		if errors.Is(err, task.ErrSavedViewNotFound) {
+			return err
+		}
+		log.Error("failed to get saved view", "error", err)
+		return task.ErrTaskInternal
+	}
+	canView, _, err := uc.savedViewAccess(view, userID)
+	if err != nil {
+		log.Error("failed to check saved view access", "error", err)
+		return task.ErrTaskInternal
+	}
+	if !canView {
+		return task.ErrSavedViewNotFound
+	}
+
+	f := view.Filters
+	req.ViewType = f.ViewType
+	req.TeamID = f.TeamID
+	if view.TeamID != nil {
+		req.TeamID = view.TeamID
+	}
+	req.ProjectID = f.ProjectID
+	req.SprintID = f.SprintID
+	req.Status = f.Status
+	req.StatusCategory = f.StatusCategory
+	req.Priority = f.Priority
+	req.AssignedToUserID = f.AssignedToUserID
+	if f.AssignedToMe {
+		req.AssignedToUserID = &userID
+	}
+	now := time.Now()
+	req.DeadlineFrom, req.DeadlineTo = nil, nil
+	if f.DeadlineFrom != nil {
+		from, err := resolveDateToken(*f.DeadlineFrom, now)
+		if err != nil {
+			return err
+		}
+		req.DeadlineFrom = &from
+	}
+	if f.DeadlineTo != nil {
+		to, err := resolveDateToken(*f.DeadlineTo, now)
+		if err != nil {
+			return err
+		}
+		req.DeadlineTo = &to
+	}
+	req.Overdue = nil
+	if f.Overdue {
+		req.Overdue = &f.Overdue
+	}
+	req.Search = f.Search
+	req.UserTagIDs = f.UserTagIDs
+	req.TeamTagIDs = f.TeamTagIDs
+	req.Untagged = nil
+	if f.Untagged {
+		req.Untagged = &f.Untagged
+	}
+	req.TagMatch = f.TagMatch
+	req.SortBy = f.SortBy
+	req.SortOrder = f.SortOrder
+	req.IsDeleted = nil
+	return nil
+}
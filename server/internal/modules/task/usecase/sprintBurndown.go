@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+	"strconv"
+	"time"
+)
+
+const sprintDay = 24 * time.Hour
+
+// sprintTaskTimeline - восстановленная по журналу история задачи: начальные спринт и статус и их изменения.
+type sprintTaskTimeline struct {
+	task          *task.Task
+	initialSprint *uint
+	initialStatus string
+	events        []*task.TaskEvent // Изменения status и sprint_id по возрастанию времени
+}
+
+// sprintTotals - объем спринта и выполненная часть в задачах и в минутах оценки
+type sprintTotals struct {
+	scopeTasks   int64
+	doneTasks    int64
+	scopeMinutes int64
+	doneMinutes  int64
+}
+
+// sprintReplay восстанавливает состав и прогресс спринта на любой момент времени по истории задач.
+type sprintReplay struct {
+	sprintID  uint
+	timelines []*sprintTaskTimeline
+	isDone    func(t *task.Task, status string) bool
+}
+
+func parseEventUint(v *string) *uint {
+	if v == nil {
+		return nil
+	}
+	parsed, err := strconv.ParseUint(*v, 10, 32)
+	if err != nil {
+		return nil
+	}
+	id := uint(parsed)
+	return &id
+}
+
+// newSprintReplay готовит историю задач спринта. Начальные значения берутся из old_value первого изменения,
+// а если изменений не было - из текущего состояния задачи.
+func newSprintReplay(sprintID uint, history *task.SprintHistory, workflow *team.TaskWorkflow) *sprintReplay {
+	byTask := make(map[uint][]*task.TaskEvent)
+	for _, e := range history.Events {
+		byTask[e.TaskID] = append(byTask[e.TaskID], e)
+	}
+
+	replay := &sprintReplay{
+		sprintID: sprintID,
+		isDone: func(t *task.Task, status string) bool {
+			if s := workflow.Status(status); s != nil {
+				return s.Category == team.StatusCategoryDone
+			}
+			// Статус убран из набора команды: известна только категория текущего статуса задачи
+			return status == t.Status && t.StatusCategory == string(team.StatusCategoryDone)
+		},
+	}
+	for _, t := range history.Tasks {
+		timeline := &sprintTaskTimeline{task: t, initialSprint: t.SprintID, initialStatus: t.Status, events: byTask[t.TaskID]}
+		sprintSeen, statusSeen := false, false
+		for _, e := range timeline.events {
+			switch {
+			case !sprintSeen && *e.FieldName == "sprint_id":
+				timeline.initialSprint = parseEventUint(e.OldValue)
+				sprintSeen = true
+			case !statusSeen && *e.FieldName == "status" && e.OldValue != nil:
+				timeline.initialStatus = *e.OldValue
+				statusSeen = true
+			}
+		}
+		replay.timelines = append(replay.timelines, timeline)
+	}
+	return replay
+}
+
+// totalsAt считает объем спринта и выполненную часть на момент at (изменения ровно в at не учитываются).
+func (r *sprintReplay) totalsAt(at time.Time) sprintTotals {
+	var totals sprintTotals
+	for _, tl := range r.timelines {
+		if !tl.task.CreatedAt.Before(at) {
+			continue
+		}
+		sprint, status := tl.initialSprint, tl.initialStatus
+		for _, e := range tl.events {
+			if !e.CreatedAt.Before(at) {
+				break
+			}
+			if *e.FieldName == "sprint_id" {
+				sprint = parseEventUint(e.NewValue)
+			} else if e.NewValue != nil {
+				status = *e.NewValue
+			}
+		}
+		if sprint == nil || *sprint != r.sprintID {
+			continue
+		}
+
+		var minutes int64
+		if tl.task.EstimateMinutes != nil {
+			minutes = int64(*tl.task.EstimateMinutes)
+		}
+		totals.scopeTasks++
+		totals.scopeMinutes += minutes
+		if r.isDone(tl.task, status) {
+			totals.doneTasks++
+			totals.doneMinutes += minutes
+		}
+	}
+	return totals
+}
+
+// sprintCutoff - момент, на котором заканчивается история спринта: конец последнего дня,
+// момент закрытия (перенос задач при закрытии уже не учитывается) или текущий момент.
+func sprintCutoff(sprint *task.Sprint, now time.Time) time.Time {
+	cutoff := sprint.EndDate.Add(sprintDay)
+	if sprint.ClosedAt != nil && sprint.ClosedAt.Before(cutoff) {
+		cutoff = *sprint.ClosedAt
+	}
+	if now.Before(cutoff) {
+		cutoff = now
+	}
+	return cutoff
+}
+
+// buildSprintBurndown строит точки burndown по дням спринта. Остаток считается в минутах оценки,
+// если хотя бы у одной задачи спринта есть оценка, иначе - в задачах.
+func buildSprintBurndown(sprint *task.Sprint, replay *sprintReplay, now time.Time) *task.SprintBurndownResponse {
+	resp := &task.SprintBurndownResponse{
+		Sprint: task.ToSprintResponse(sprint),
+		Unit:   task.BurndownUnitTasks,
+		Points: []*task.BurndownPoint{},
+	}
+	for _, tl := range replay.timelines {
+		if tl.task.EstimateMinutes != nil {
+			resp.Unit = task.BurndownUnitMinutes
+			break
+		}
+	}
+	if resp.Unit == task.BurndownUnitMinutes {
+		for _, tl := range replay.timelines {
+			if tl.task.EstimateMinutes == nil {
+				resp.UnestimatedTasks++
+			}
+		}
+	}
+
+	cutoff := sprintCutoff(sprint, now)
+	lastIndex := int(sprint.EndDate.Sub(sprint.StartDate) / sprintDay)
+	var initialScope int64
+	for i := 0; i <= lastIndex; i++ {
+		date := sprint.StartDate.Add(time.Duration(i) * sprintDay)
+		if !date.Before(cutoff) {
+			break
+		}
+		at := date.Add(sprintDay)
+		if cutoff.Before(at) {
+			at = cutoff
+		}
+
+		totals := replay.totalsAt(at)
+		scope, completed := totals.scopeTasks, totals.doneTasks
+		if resp.Unit == task.BurndownUnitMinutes {
+			scope, completed = totals.scopeMinutes, totals.doneMinutes
+		}
+		if i == 0 {
+			initialScope = scope
+		}
+		ideal := 0.0
+		if lastIndex > 0 {
+			ideal = float64(initialScope) * float64(lastIndex-i) / float64(lastIndex)
+		}
+		resp.Points = append(resp.Points, &task.BurndownPoint{
+			Date:      date.Format("2006-01-02"),
+			Scope:     scope,
+			Remaining: scope - completed,
+			Completed: completed,
+			Ideal:     ideal,
+		})
+	}
+	return resp
+}
+
+// sprintVelocity считает выполненную за спринт работу на момент его окончания.
+func sprintVelocity(sprint *task.Sprint, replay *sprintReplay, now time.Time) *task.SprintVelocity {
+	totals := replay.totalsAt(sprintCutoff(sprint, now))
+	return &task.SprintVelocity{
+		SprintID:         sprint.SprintID,
+		Name:             sprint.Name,
+		StartDate:        sprint.StartDate.Format("2006-01-02"),
+		EndDate:          sprint.EndDate.Format("2006-01-02"),
+		CommittedTasks:   totals.scopeTasks,
+		CompletedTasks:   totals.doneTasks,
+		CompletedMinutes: totals.doneMinutes,
+	}
+}
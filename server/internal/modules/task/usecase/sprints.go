@@ -0,0 +1,456 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"server/internal/modules/tag"
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+	"time"
+)
+
+// defaultSprintVelocityLimit - число последних закрытых спринтов в отчете о скорости по умолчанию
+const defaultSprintVelocityLimit = 5
+
+// parseSprintDate разбирает дату спринта в формате YYYY-MM-DD (UTC).
+func parseSprintDate(value string) (time.Time, error) {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: date must be in YYYY-MM-DD format", task.ErrSprintInvalid)
+	}
+	return date, nil
+}
+
+// requireSprintViewer проверяет, что пользователь состоит в команде и не является гостем.
+// Гость видит только задачи с открытым ему тегом, а спринты, burndown и скорость считаются по всем задачам спринта.
+func (uc *TaskUseCase) requireSprintViewer(teamID, userID uint, log *slog.Logger) error {
+	role, err := uc.teamService.GetUserRoleInTeam(userID, teamID)
+	if err != nil {
+		log.Error("failed to get team role", "error", err)
+		return task.ErrTaskInternal
+	}
+	if role == nil {
+		log.Warn("user is not a member of the team")
+		return task.ErrTaskAccessDenied
+	}
+	if *role == team.RoleGuest {
+		log.Warn("guest cannot view team sprints")
+		return task.ErrTaskAccessDenied
+	}
+	return nil
+}
+
+// requireSprintManager проверяет право sprint.manage (в архивной команде - ErrTaskTeamArchived).
+func (uc *TaskUseCase) requireSprintManager(teamID, userID uint, log *slog.Logger) error {
+	canManage, err := uc.teamService.HasTeamPermission(userID, teamID, team.PermSprintManage)
+	if err != nil {
+		log.Error("failed to check permission for sprint", "error", err)
+		return teamCheckError(err)
+	}
+	if !canManage {
+		log.Warn("user cannot manage team sprints")
+		return task.ErrTaskAccessDenied
+	}
+	return nil
+}
+
+// getTeamSprint загружает спринт команды. Спринт другой команды выглядит как несуществующий.
+func (uc *TaskUseCase) getTeamSprint(teamID, sprintID uint, log *slog.Logger) (*task.Sprint, error) {
+	sprint, err := uc.repo.GetSprintByID(sprintID)
+	if err != nil {
+		if errors.Is(err, task.ErrSprintNotFound) {
+			return nil, err
+		}
+		log.Error("failed to get sprint", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	if sprint.TeamID != teamID {
+		return nil, task.ErrSprintNotFound
+	}
+	return sprint, nil
+}
+
+// resolveTaskSprint проверяет спринт, в который помещается задача: только задача команды
+// и только незакрытый спринт этой же команды. sprintID = 0 возвращает задачу в бэклог (возвращается nil).
+func (uc *TaskUseCase) resolveTaskSprint(teamID *uint, sprintID uint) (*uint, error) {
+	if sprintID == 0 {
+		return nil, nil
+	}
+	if teamID == nil {
+		return nil, task.ErrTaskSprintInvalid
+	}
+	sprint, err := uc.repo.GetSprintByID(sprintID)
+	if err != nil {
+		if errors.Is(err, task.ErrSprintNotFound) {
+			return nil, task.ErrTaskSprintInvalid
+		}
+		uc.log.Error("failed to get sprint", "error", err, "teamID", *teamID, "sprintID", sprintID)
+		return nil, task.ErrTaskInternal
+	}
+	if sprint.TeamID != *teamID || sprint.Status == task.SprintStatusClosed {
+		return nil, task.ErrTaskSprintInvalid
+	}
+	return &sprintID, nil
+}
+
+func (uc *TaskUseCase) GetTeamSprints(teamID uint, userID uint) ([]*task.SprintResponse, error) {
+	op := "TaskUseCase.GetTeamSprints"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	if err := uc.requireSprintViewer(teamID, userID, log); err != nil {
+		return nil, err
+	}
+	sprints, err := uc.repo.GetTeamSprints(teamID)
+	if err != nil {
+		log.Error("failed to get team sprints", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	resp := make([]*task.SprintResponse, 0, len(sprints))
+	for _, s := range sprints {
+		resp = append(resp, task.ToSprintResponse(s))
+	}
+	return resp, nil
+}
+
+func (uc *TaskUseCase) CreateSprint(teamID uint, userID uint, req task.CreateSprintRequest) (*task.SprintResponse, error) {
+	op := "TaskUseCase.CreateSprint"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	if err := uc.requireSprintManager(teamID, userID, log); err != nil {
+		return nil, err
+	}
+	startDate, err := parseSprintDate(req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	endDate, err := parseSprintDate(req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("%w: end_date must not be before start_date", task.ErrSprintInvalid)
+	}
+
+	sprint, err := uc.repo.CreateSprint(&task.Sprint{
+		TeamID:          teamID,
+		Name:            req.Name,
+		Goal:            req.Goal,
+		StartDate:       startDate,
+		EndDate:         endDate,
+		Status:          task.SprintStatusPlanned,
+		CreatedByUserID: &userID,
+	})
+	if err != nil {
+		log.Error("failed to create sprint", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Info("sprint created", slog.Uint64("sprintID", uint64(sprint.SprintID)))
+	return task.ToSprintResponse(sprint), nil
+}
+
+func (uc *TaskUseCase) GetSprint(teamID uint, sprintID uint, userID uint) (*task.SprintResponse, error) {
+	op := "TaskUseCase.GetSprint"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("sprintID", uint64(sprintID)), slog.Uint64("userID", uint64(userID)))
+
+	if err := uc.requireSprintViewer(teamID, userID, log); err != nil {
+		return nil, err
+	}
+	sprint, err := uc.getTeamSprint(teamID, sprintID, log)
+	if err != nil {
+		return nil, err
+	}
+	return task.ToSprintResponse(sprint), nil
+}
+
+func (uc *TaskUseCase) UpdateSprint(teamID uint, sprintID uint, userID uint, req task.UpdateSprintRequest) (*task.SprintResponse, error) {
+	op := "TaskUseCase.UpdateSprint"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("sprintID", uint64(sprintID)), slog.Uint64("userID", uint64(userID)))
+
+	if err := uc.requireSprintManager(teamID, userID, log); err != nil {
+		return nil, err
+	}
+	sprint, err := uc.getTeamSprint(teamID, sprintID, log)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.Status == task.SprintStatusClosed {
+		return nil, fmt.Errorf("%w: closed sprint cannot be changed", task.ErrSprintInvalid)
+	}
+
+	if req.Name != nil {
+		sprint.Name = *req.Name
+	}
+	if req.Goal != nil {
+		sprint.Goal = req.Goal
+		if *req.Goal == "" {
+			sprint.Goal = nil
+		}
+	}
+	if req.StartDate != nil {
+		if sprint.StartDate, err = parseSprintDate(*req.StartDate); err != nil {
+			return nil, err
+		}
+	}
+	if req.EndDate != nil {
+		if sprint.EndDate, err = parseSprintDate(*req.EndDate); err != nil {
+			return nil, err
+		}
+	}
+	if sprint.EndDate.Before(sprint.StartDate) {
+		return nil, fmt.Errorf("%w: end_date must not be before start_date", task.ErrSprintInvalid)
+	}
+
+	updated, err := uc.repo.UpdateSprint(sprint)
+	if err != nil {
+		log.Error("failed to update sprint", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Info("sprint updated")
+	return task.ToSprintResponse(updated), nil
+}
+
+func (uc *TaskUseCase) DeleteSprint(teamID uint, sprintID uint, userID uint) error {
+	op := "TaskUseCase.DeleteSprint"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("sprintID", uint64(sprintID)), slog.Uint64("userID", uint64(userID)))
+
+	if err := uc.requireSprintManager(teamID, userID, log); err != nil {
+		return err
+	}
+	if _, err := uc.getTeamSprint(teamID, sprintID, log); err != nil {
+		return err
+	}
+	// sprint_id задач обнуляется внешним ключом (ON DELETE SET NULL)
+	if err := uc.repo.DeleteSprint(sprintID); err != nil {
+		if errors.Is(err, task.ErrSprintNotFound) {
+			return err
+		}
+		log.Error("failed to delete sprint", "error", err)
+		return task.ErrTaskInternal
+	}
+	uc.invalidateTaskListsCache(userID, &teamID)
+
+	log.Info("sprint deleted")
+	return nil
+}
+
+func (uc *TaskUseCase) StartSprint(teamID uint, sprintID uint, userID uint) (*task.SprintResponse, error) {
+	op := "TaskUseCase.StartSprint"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("sprintID", uint64(sprintID)), slog.Uint64("userID", uint64(userID)))
+
+	if err := uc.requireSprintManager(teamID, userID, log); err != nil {
+		return nil, err
+	}
+	sprint, err := uc.getTeamSprint(teamID, sprintID, log)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.Status != task.SprintStatusPlanned {
+		return nil, fmt.Errorf("%w: only a planned sprint can be started", task.ErrSprintInvalid)
+	}
+
+	sprint.Status = task.SprintStatusActive
+	// Второй активный спринт команды отклоняется уникальным индексом
+	updated, err := uc.repo.UpdateSprint(sprint)
+	if err != nil {
+		if errors.Is(err, task.ErrSprintAlreadyActive) {
+			log.Warn("team already has an active sprint")
+			return nil, err
+		}
+		log.Error("failed to start sprint", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Info("sprint started")
+	return task.ToSprintResponse(updated), nil
+}
+
+// resolveCarryOverSprint определяет спринт, в который переносятся незавершенные задачи закрываемого спринта.
+func (uc *TaskUseCase) resolveCarryOverSprint(sprint *task.Sprint, req task.CloseSprintRequest, log *slog.Logger) (*uint, error) {
+	if req.CarryOverToSprintID == nil {
+		next, err := uc.repo.GetNextPlannedSprint(sprint.TeamID, sprint.SprintID)
+		if err != nil {
+			log.Error("failed to get next planned sprint", "error", err)
+			return nil, task.ErrTaskInternal
+		}
+		if next == nil {
+			return nil, nil
+		}
+		return &next.SprintID, nil
+	}
+	if *req.CarryOverToSprintID == 0 {
+		return nil, nil
+	}
+	if *req.CarryOverToSprintID == sprint.SprintID {
+		return nil, fmt.Errorf("%w: cannot carry over tasks to the sprint being closed", task.ErrSprintInvalid)
+	}
+	target, err := uc.getTeamSprint(sprint.TeamID, *req.CarryOverToSprintID, log)
+	if err != nil {
+		if errors.Is(err, task.ErrSprintNotFound) {
+			return nil, fmt.Errorf("%w: carry-over sprint not found in the team", task.ErrSprintInvalid)
+		}
+		return nil, err
+	}
+	if target.Status == task.SprintStatusClosed {
+		return nil, fmt.Errorf("%w: cannot carry over tasks to a closed sprint", task.ErrSprintInvalid)
+	}
+	return &target.SprintID, nil
+}
+
+func (uc *TaskUseCase) CloseSprint(teamID uint, sprintID uint, userID uint, req task.CloseSprintRequest) (*task.CloseSprintResponse, error) {
+	op := "TaskUseCase.CloseSprint"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("sprintID", uint64(sprintID)), slog.Uint64("userID", uint64(userID)))
+
+	if err := uc.requireSprintManager(teamID, userID, log); err != nil {
+		return nil, err
+	}
+	sprint, err := uc.getTeamSprint(teamID, sprintID, log)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.Status != task.SprintStatusActive {
+		return nil, fmt.Errorf("%w: only an active sprint can be closed", task.ErrSprintInvalid)
+	}
+	targetID, err := uc.resolveCarryOverSprint(sprint, req, log)
+	if err != nil {
+		return nil, err
+	}
+	if uc.txRunner == nil {
+		log.Error("transaction runner is not configured")
+		return nil, task.ErrTaskInternal
+	}
+
+	closedAt := time.Now()
+	carried := []uint{}
+	var closed *task.Sprint
+	// Перенос задач, их история и закрытие спринта фиксируются вместе: burndown строится по этой истории
+	errTx := uc.txRunner.RunInTx(func(taskRepo task.Repo, _ tag.Repo) error {
+		unfinished, err := taskRepo.GetUnfinishedSprintTasks(sprintID)
+		if err != nil {
+			return err
+		}
+		if len(unfinished) > 0 {
+			events := make([]*task.TaskEvent, 0, len(unfinished))
+			for _, t := range unfinished {
+				carried = append(carried, t.TaskID)
+				events = append(events, &task.TaskEvent{
+					TaskID:      t.TaskID,
+					TeamID:      t.TeamID,
+					ActorUserID: &userID,
+					EventType:   task.EventTaskUpdated,
+					FieldName:   strPtr("sprint_id"),
+					OldValue:    optionalUintToStr(t.SprintID),
+					NewValue:    optionalUintToStr(targetID),
+					CreatedAt:   closedAt,
+				})
+			}
+			if err := taskRepo.SetTasksSprint(carried, targetID); err != nil {
+				return err
+			}
+			if err := taskRepo.CreateTaskEvents(events); err != nil {
+				return err
+			}
+		}
+
+		sprint.Status = task.SprintStatusClosed
+		sprint.ClosedAt = &closedAt
+		closed, err = taskRepo.UpdateSprint(sprint)
+		return err
+	})
+	if errTx != nil {
+		log.Error("failed to close sprint", "error", errTx)
+		return nil, task.ErrTaskInternal
+	}
+
+	for _, taskID := range carried {
+		_ = uc.repo.DeleteTaskCache(taskID)
+	}
+	if len(carried) > 0 {
+		uc.invalidateTaskListsCache(userID, &teamID)
+	}
+
+	log.Info("sprint closed", slog.Int("carriedOver", len(carried)))
+	return &task.CloseSprintResponse{
+		Sprint:                task.ToSprintResponse(closed),
+		CarriedOverToSprintID: targetID,
+		CarriedOverTaskIDs:    carried,
+	}, nil
+}
+
+// loadSprintReplay загружает историю задач спринта и набор статусов команды.
+func (uc *TaskUseCase) loadSprintReplay(sprint *task.Sprint, workflow *team.TaskWorkflow, log *slog.Logger) (*sprintReplay, error) {
+	history, err := uc.repo.GetSprintHistory(sprint.SprintID)
+	if err != nil {
+		log.Error("failed to get sprint history", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return newSprintReplay(sprint.SprintID, history, workflow), nil
+}
+
+func (uc *TaskUseCase) GetSprintBurndown(teamID uint, sprintID uint, userID uint) (*task.SprintBurndownResponse, error) {
+	op := "TaskUseCase.GetSprintBurndown"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("sprintID", uint64(sprintID)), slog.Uint64("userID", uint64(userID)))
+
+	if err := uc.requireSprintViewer(teamID, userID, log); err != nil {
+		return nil, err
+	}
+	sprint, err := uc.getTeamSprint(teamID, sprintID, log)
+	if err != nil {
+		return nil, err
+	}
+	workflow, err := uc.getTaskWorkflow(&teamID)
+	if err != nil {
+		log.Error("failed to get team task workflow", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	replay, err := uc.loadSprintReplay(sprint, workflow, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildSprintBurndown(sprint, replay, time.Now()), nil
+}
+
+func (uc *TaskUseCase) GetSprintVelocity(teamID uint, userID uint, req task.GetSprintVelocityRequest) (*task.SprintVelocityResponse, error) {
+	op := "TaskUseCase.GetSprintVelocity"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	if err := uc.requireSprintViewer(teamID, userID, log); err != nil {
+		return nil, err
+	}
+	limit := defaultSprintVelocityLimit
+	if req.Limit != nil {
+		limit = *req.Limit
+	}
+	sprints, err := uc.repo.GetClosedSprints(teamID, limit)
+	if err != nil {
+		log.Error("failed to get closed sprints", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	workflow, err := uc.getTaskWorkflow(&teamID)
+	if err != nil {
+		log.Error("failed to get team task workflow", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	resp := &task.SprintVelocityResponse{TeamID: teamID, Sprints: make([]*task.SprintVelocity, 0, len(sprints))}
+	now := time.Now()
+	for _, sprint := range sprints {
+		replay, err := uc.loadSprintReplay(sprint, workflow, log)
+		if err != nil {
+			return nil, err
+		}
+		velocity := sprintVelocity(sprint, replay, now)
+		resp.AverageCompletedTasks += float64(velocity.CompletedTasks)
+		resp.AverageCompletedMinutes += float64(velocity.CompletedMinutes)
+		resp.Sprints = append(resp.Sprints, velocity)
+	}
+	if n := len(resp.Sprints); n > 0 {
+		resp.AverageCompletedTasks /= float64(n)
+		resp.AverageCompletedMinutes /= float64(n)
+	}
+	return resp, nil
+}
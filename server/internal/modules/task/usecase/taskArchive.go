@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"errors"
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+)
+
+// teamCheckError переводит ошибку проверки прав в команде в ошибку модуля задач:
+// архивная команда - понятный отказ, остальное - внутренняя ошибка.
+func teamCheckError(err error) error {
+	if errors.Is(err, team.ErrTeamArchived) {
+		return task.ErrTaskTeamArchived
+	}
+	return task.ErrTaskInternal
+}
+
+// checkTeamWritable запрещает изменения в задачах архивной команды там, где право команды не проверяется
+// (например, учет времени). Для личных задач ограничений нет.
+func (uc *TaskUseCase) checkTeamWritable(teamID *uint) error {
+	if teamID == nil {
+		return nil
+	}
+	if err := uc.teamService.CheckTeamWritable(*teamID); err != nil {
+		if !errors.Is(err, team.ErrTeamArchived) {
+			uc.log.Error("failed to check team archive state", "error", err, "teamID", *teamID)
+		}
+		return teamCheckError(err)
+	}
+	return nil
+}
+
+// canManageTeamItems проверяет право на командные представления и шаблоны при их просмотре:
+// в архивной команде они остаются видимыми, но недоступными для изменения.
+func (uc *TaskUseCase) canManageTeamItems(userID, teamID uint, permission team.Permission) (bool, error) {
+	allowed, err := uc.teamService.HasTeamPermission(userID, teamID, permission)
+	if errors.Is(err, team.ErrTeamArchived) {
+		return false, nil
+	}
+	return allowed, err
+}
+
+// teamEditDenied возвращает ошибку отказа в изменении командного объекта: для архивной команды - ErrTaskTeamArchived.
+func (uc *TaskUseCase) teamEditDenied(teamID *uint) error {
+	if err := uc.checkTeamWritable(teamID); err != nil {
+		return err
+	}
+	return task.ErrTaskAccessDenied
+}
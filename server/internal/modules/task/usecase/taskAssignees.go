@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"log/slog"
+	"server/internal/modules/task"
+)
+
+// assigneeUpdate - исполнители задачи до и после изменения; основной исполнитель стоит первым.
+type assigneeUpdate struct {
+	before []uint
+	after  []uint
+}
+
+func (u *assigneeUpdate) changed() bool {
+	if u == nil {
+		return false
+	}
+	if len(u.before) != len(u.after) {
+		return true
+	}
+	for i := range u.before {
+		if u.before[i] != u.after[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// affectedUsers возвращает пользователей, которые стали или перестали быть исполнителями.
+func (u *assigneeUpdate) affectedUsers() []uint {
+	var users []uint
+	for _, id := range u.after {
+		if !containsUint(u.before, id) {
+			users = append(users, id)
+		}
+	}
+	for _, id := range u.before {
+		if !containsUint(u.after, id) {
+			users = append(users, id)
+		}
+	}
+	return users
+}
+
+func containsUint(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutUint возвращает копию списка без значения id.
+func withoutUint(ids []uint, id uint) []uint {
+	result := make([]uint, 0, len(ids))
+	for _, v := range ids {
+		if v != id {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// primaryFirst ставит основного исполнителя в начало списка.
+func primaryFirst(ids []uint, primary *uint) []uint {
+	if primary == nil || !containsUint(ids, *primary) {
+		return ids
+	}
+	return append([]uint{*primary}, withoutUint(ids, *primary)...)
+}
+
+// taskAssigneeIDs возвращает исполнителей задачи, основной - первым.
+func (uc *TaskUseCase) taskAssigneeIDs(t *task.Task) ([]uint, error) {
+	assignees, err := uc.repo.GetTaskAssigneeIDs([]uint{t.TaskID})
+	if err != nil {
+		return nil, err
+	}
+	ids := assignees[t.TaskID]
+	// Основной исполнитель всегда входит в список, даже если строка в taskassignees не была создана
+	if t.AssignedToUserID != nil && !containsUint(ids, *t.AssignedToUserID) {
+		ids = append(ids, *t.AssignedToUserID)
+	}
+	return primaryFirst(ids, t.AssignedToUserID), nil
+}
+
+// resolveAssignees вычисляет исполнителей задачи по запросу и выставляет основного исполнителя в модели.
+// assigneeIDs - полный список (nil - не передан), primary и clearPrimary - поле assigned_to_user_id.
+// Возвращает nil, если исполнители не меняются.
+func (uc *TaskUseCase) resolveAssignees(t *task.Task, userID uint, assigneeIDs *[]uint, primary *uint, clearPrimary bool) (*assigneeUpdate, error) {
+	if assigneeIDs == nil && primary == nil && !clearPrimary {
+		return nil, nil
+	}
+
+	var current []uint
+	if t.TaskID != 0 {
+		var err error
+		if current, err = uc.taskAssigneeIDs(t); err != nil {
+			return nil, task.ErrTaskInternal
+		}
+	}
+
+	var after []uint
+	switch {
+	case assigneeIDs != nil:
+		after = append(after, *assigneeIDs...)
+		if primary != nil && !containsUint(after, *primary) {
+			uc.log.Warn("primary assignee is not in assignee list", "taskID", t.TaskID, "primary", *primary)
+			return nil, task.ErrTaskInvalidInput
+		}
+		after = primaryFirst(after, primary)
+	case clearPrimary:
+		after = current
+		if t.AssignedToUserID != nil {
+			after = withoutUint(current, *t.AssignedToUserID)
+		}
+	default:
+		after = current
+		if t.AssignedToUserID != nil {
+			after = withoutUint(after, *t.AssignedToUserID)
+		}
+		after = append([]uint{*primary}, withoutUint(after, *primary)...)
+	}
+
+	for _, id := range after {
+		if containsUint(current, id) {
+			continue
+		}
+		if err := uc.checkTaskAssignee(t, id, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(after) > 0 {
+		t.AssignedToUserID = &after[0]
+	} else {
+		t.AssignedToUserID = nil
+	}
+	return &assigneeUpdate{before: current, after: after}, nil
+}
+
+// saveAssignees сохраняет список исполнителей после записи задачи и возвращает события истории.
+// Исполнители, которых добавили или сняли, видят изменение в своих списках задач.
+func (uc *TaskUseCase) saveAssignees(t *task.Task, userID uint, update *assigneeUpdate) ([]*task.TaskEvent, error) {
+	if !update.changed() {
+		return nil, nil
+	}
+	if err := uc.repo.SetTaskAssignees(t.TaskID, update.after); err != nil {
+		return nil, err
+	}
+	for _, affectedUserID := range update.affectedUsers() {
+		uc.invalidateTaskListsCache(affectedUserID, nil)
+	}
+	return diffIDSetEvents(t, userID, "assignees", task.EventTaskAssigneeAdded, task.EventTaskAssigneeRemoved, update.before, update.after), nil
+}
+
+// fillTaskParticipants заполняет исполнителей и наблюдателей задач двумя запросами на всю выдачу.
+func (uc *TaskUseCase) fillTaskParticipants(responses ...*task.TaskResponse) {
+	if len(responses) == 0 {
+		return
+	}
+	taskIDs := make([]uint, len(responses))
+	for i, resp := range responses {
+		taskIDs[i] = resp.TaskID
+	}
+	assignees, err := uc.repo.GetTaskAssigneeIDs(taskIDs)
+	if err != nil {
+		uc.log.Warn("failed to get task assignees for response", "error", err, "count", len(taskIDs))
+		assignees = map[uint][]uint{}
+	}
+	watchers, err := uc.repo.GetTaskWatcherIDs(taskIDs)
+	if err != nil {
+		uc.log.Warn("failed to get task watchers for response", "error", err, "count", len(taskIDs))
+		watchers = map[uint][]uint{}
+	}
+	for _, resp := range responses {
+		ids := assignees[resp.TaskID]
+		if resp.AssignedToUserID != nil && !containsUint(ids, *resp.AssignedToUserID) {
+			ids = append(ids, *resp.AssignedToUserID)
+		}
+		resp.AssigneeIDs = primaryFirst(ids, resp.AssignedToUserID)
+		if resp.AssigneeIDs == nil {
+			resp.AssigneeIDs = []uint{}
+		}
+		resp.WatcherIDs = watchers[resp.TaskID]
+		if resp.WatcherIDs == nil {
+			resp.WatcherIDs = []uint{}
+		}
+	}
+}
+
+// WatchTask подписывает пользователя на уведомления об изменениях задачи.
+func (uc *TaskUseCase) WatchTask(taskID uint, userID uint) error {
+	op := "TaskUseCase.WatchTask"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)), slog.Uint64("userID", uint64(userID)))
+
+	if _, err := uc.getAccessibleTask(taskID, userID); err != nil {
+		return err
+	}
+	if err := uc.repo.AddTaskWatcher(taskID, userID); err != nil {
+		log.Error("failed to add task watcher", "error", err)
+		return err
+	}
+	log.Info("user is watching task")
+	return nil
+}
+
+// UnwatchTask отписывает пользователя от задачи. Доступ к задаче не проверяется:
+// отписаться можно и после выхода из команды.
+func (uc *TaskUseCase) UnwatchTask(taskID uint, userID uint) error {
+	op := "TaskUseCase.UnwatchTask"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)), slog.Uint64("userID", uint64(userID)))
+
+	if err := uc.repo.RemoveTaskWatcher(taskID, userID); err != nil {
+		log.Error("failed to remove task watcher", "error", err)
+		return err
+	}
+	log.Info("user stopped watching task")
+	return nil
+}
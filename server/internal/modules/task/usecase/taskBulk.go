@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"log/slog"
+	"server/internal/modules/tag"
+	"server/internal/modules/task"
+)
+
+const (
+	bulkErrRolledBack  = "rolled back"
+	bulkErrNotExecuted = "not executed"
+)
+
+// validateBulkTaskRequest проверяет, что для операции передан ее параметр.
+func validateBulkTaskRequest(req task.BulkTaskRequest) error {
+	switch req.Operation {
+	case task.BulkSetStatus:
+		if req.Status == nil {
+			return task.ErrTaskInvalidInput
+		}
+	case task.BulkSetPriority:
+		if req.Priority == nil {
+			return task.ErrTaskInvalidInput
+		}
+	case task.BulkAddTags, task.BulkRemoveTags:
+		if len(req.UserTagIDs) == 0 && len(req.TeamTagIDs) == 0 {
+			return task.ErrTaskInvalidInput
+		}
+	}
+	return nil
+}
+
+// BulkUpdateTasks применяет одну операцию к списку задач. Каждая задача обрабатывается
+// теми же методами, что и одиночные запросы, поэтому права проверяются для каждой задачи отдельно.
+// Кэш списков инвалидируется один раз на весь запрос.
+func (uc *TaskUseCase) BulkUpdateTasks(userID uint, req task.BulkTaskRequest) (*task.BulkTaskResponse, error) {
+	op := "TaskUseCase.BulkUpdateTasks"
+	uc = uc.forRequest() // Права проверяются для каждой задачи, роль пользователя запрашивается один раз
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)),
+		slog.String("operation", string(req.Operation)), slog.Int("count", len(req.TaskIDs)), slog.Bool("atomic", req.Atomic))
+
+	if err := validateBulkTaskRequest(req); err != nil {
+		log.Warn("bulk operation parameter is missing")
+		return nil, err
+	}
+
+	result := &task.BulkTaskResponse{
+		Operation: req.Operation,
+		Atomic:    req.Atomic,
+		Results:   make([]*task.BulkTaskResult, 0, len(req.TaskIDs)),
+	}
+	batch := make(map[string]struct{})
+	var notifications []*taskNotification
+	var events []*task.TaskEvent
+	run := func(worker *TaskUseCase) error {
+		for _, taskID := range req.TaskIDs {
+			err := worker.applyBulkOperation(taskID, userID, req)
+			res := &task.BulkTaskResult{TaskID: taskID, Success: err == nil}
+			if err != nil {
+				res.Error = err.Error()
+			}
+			result.Results = append(result.Results, res)
+			if err != nil && req.Atomic {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if req.Atomic {
+		if uc.txRunner == nil {
+			log.Error("transaction runner is not configured")
+			return nil, task.ErrTaskInternal
+		}
+		errTx := uc.txRunner.RunInTx(func(taskRepo task.Repo, tagRepo tag.Repo) error {
+			worker := *uc
+			worker.repo = taskRepo
+			worker.tagRepo = tagRepo
+			worker.listCacheBatch = batch
+			worker.notificationBatch = &notifications
+			worker.eventBatch = &events
+			return run(&worker)
+		})
+		if errTx != nil {
+			log.Warn("bulk operation rolled back", "error", errTx)
+			result.RolledBack = true
+			notifications = nil
+			events = nil
+			for _, res := range result.Results {
+				if res.Success {
+					res.Success = false
+					res.Error = bulkErrRolledBack
+				}
+				// Кэш отдельных задач мог быть обновлен внутри откатившейся транзакции
+				_ = uc.repo.DeleteTaskCache(res.TaskID)
+			}
+			for _, taskID := range req.TaskIDs[len(result.Results):] {
+				result.Results = append(result.Results, &task.BulkTaskResult{TaskID: taskID, Error: bulkErrNotExecuted})
+			}
+		}
+	} else {
+		worker := *uc
+		worker.listCacheBatch = batch
+		_ = run(&worker)
+	}
+
+	uc.flushEventBatch(events)
+	uc.flushListCacheBatch(batch)
+	uc.flushNotificationBatch(notifications)
+
+	for _, res := range result.Results {
+		if res.Success {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+	log.Info("bulk operation finished", slog.Int("succeeded", result.Succeeded), slog.Int("failed", result.Failed),
+		slog.Bool("rolled_back", result.RolledBack))
+	return result, nil
+}
+
+// applyBulkOperation выполняет операцию массового запроса для одной задачи.
+func (uc *TaskUseCase) applyBulkOperation(taskID uint, userID uint, req task.BulkTaskRequest) error {
+	var err error
+	switch req.Operation {
+	case task.BulkSetStatus:
+		_, err = uc.PatchTask(taskID, userID, task.PatchTaskRequest{Status: req.Status})
+	case task.BulkSetPriority:
+		_, err = uc.PatchTask(taskID, userID, task.PatchTaskRequest{Priority: req.Priority})
+	case task.BulkAssign:
+		if req.AssignedToUserID != nil {
+			_, err = uc.PatchTask(taskID, userID, task.PatchTaskRequest{AssignedToUserID: req.AssignedToUserID})
+		} else {
+			clearAssignee := true
+			_, err = uc.PatchTask(taskID, userID, task.PatchTaskRequest{ClearAssignedTo: &clearAssignee})
+		}
+	case task.BulkAddTags, task.BulkRemoveTags:
+		err = uc.bulkChangeTaskTags(taskID, userID, req)
+	case task.BulkTrash:
+		err = uc.DeleteTask(taskID, userID)
+	case task.BulkRestore:
+		_, err = uc.RestoreTask(taskID, userID)
+	case task.BulkDeletePermanently:
+		err = uc.DeleteTaskPermanently(taskID, userID)
+	default:
+		err = task.ErrTaskInvalidInput
+	}
+	return err
+}
+
+// bulkChangeTaskTags добавляет или снимает теги, не затрагивая остальные теги задачи.
+// Личной задаче подходят пользовательские теги, командной - теги ее команды.
+func (uc *TaskUseCase) bulkChangeTaskTags(taskID uint, userID uint, req task.BulkTaskRequest) error {
+	t, err := uc.repo.GetTaskByID(taskID, userID)
+	if err != nil {
+		return err
+	}
+	tagIDs := req.UserTagIDs
+	if t.TeamID != nil {
+		tagIDs = req.TeamTagIDs
+	}
+	if len(tagIDs) == 0 {
+		return task.ErrTaskInvalidInput
+	}
+
+	links, err := uc.tagRepo.GetTaskTags(taskID)
+	if err != nil {
+		uc.log.Error("failed to get task tags for bulk operation", "error", err, "taskID", taskID)
+		return task.ErrTaskInternal
+	}
+	userTagIDs := make([]uint, 0, len(links))
+	teamTagIDs := make([]uint, 0, len(links))
+	for _, link := range links {
+		if link.UserTagID != nil {
+			userTagIDs = append(userTagIDs, *link.UserTagID)
+		} else if link.TeamTagID != nil {
+			teamTagIDs = append(teamTagIDs, *link.TeamTagID)
+		}
+	}
+
+	if t.TeamID != nil {
+		teamTagIDs = changeTagSet(teamTagIDs, tagIDs, req.Operation == task.BulkAddTags)
+	} else {
+		userTagIDs = changeTagSet(userTagIDs, tagIDs, req.Operation == task.BulkAddTags)
+	}
+	_, err = uc.PatchTask(taskID, userID, task.PatchTaskRequest{UserTagIDs: &userTagIDs, TeamTagIDs: &teamTagIDs})
+	return err
+}
+
+// changeTagSet добавляет (add = true) или убирает теги из набора без повторов.
+func changeTagSet(current []uint, tagIDs []uint, add bool) []uint {
+	changed := make(map[uint]bool, len(tagIDs))
+	for _, id := range tagIDs {
+		changed[id] = true
+	}
+	result := make([]uint, 0, len(current)+len(tagIDs))
+	for _, id := range current {
+		if changed[id] {
+			if !add {
+				continue
+			}
+			delete(changed, id)
+		}
+		result = append(result, id)
+	}
+	if add {
+		for _, id := range tagIDs {
+			if changed[id] {
+				result = append(result, id)
+				delete(changed, id)
+			}
+		}
+	}
+	return result
+}
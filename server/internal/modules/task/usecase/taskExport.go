@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"log/slog"
+	"server/internal/modules/task"
+)
+
+// exportPageSize - сколько задач читается из БД за один запрос при потоковом экспорте
+const exportPageSize = 500
+
+// ExportTasks выгружает личные задачи пользователя или задачи команды, передавая их в emit по одной.
+// Задачи читаются страницами по дате создания, поэтому память не зависит от размера выгрузки.
+func (uc *TaskUseCase) ExportTasks(userID uint, req task.TaskExportRequest, emit func(item *task.TaskExportItem) error) error {
+	op := "TaskUseCase.ExportTasks"
+	uc = uc.forRequest()
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)), slog.String("format", string(req.Format)))
+
+	params := task.GetTasksParams{
+		UserID:   userID,
+		ViewType: task.ViewTypeUserPersonal,
+	}
+	if req.TeamID != nil {
+		isMember, err := uc.teamService.IsUserMember(userID, *req.TeamID)
+		if err != nil {
+			log.Error("failed to check team membership for export", "error", err)
+			return task.ErrTaskInternal
+		}
+		if !isMember {
+			return task.ErrTaskAccessDenied
+		}
+		scopeTagID, err := uc.guestTaskTagScope(userID, req.TeamID)
+		if err != nil {
+			log.Error("failed to get guest task scope for export", "error", err)
+			return task.ErrTaskInternal
+		}
+		params.ViewType = task.ViewTypeDefault
+		params.TeamID = req.TeamID
+		params.ScopeTeamTagID = scopeTagID
+		log = log.With(slog.Uint64("teamID", uint64(*req.TeamID)))
+	}
+
+	logins := make(map[uint]string)
+	exported := 0
+	err := uc.forEachTaskPage(params, func(page []*task.Task) error {
+		taskIDs := make([]uint, len(page))
+		var unknownUsers []uint
+		for i, t := range page {
+			taskIDs[i] = t.TaskID
+			if _, ok := logins[t.CreatedByUserID]; !ok {
+				logins[t.CreatedByUserID] = ""
+				unknownUsers = append(unknownUsers, t.CreatedByUserID)
+			}
+		}
+		tagNames, err := uc.repo.GetTaskTagNames(taskIDs)
+		if err != nil {
+			log.Error("failed to get tag names for export", "error", err)
+			return task.ErrTaskInternal
+		}
+		assignees, err := uc.repo.GetTaskAssigneeIDs(taskIDs)
+		if err != nil {
+			log.Error("failed to get task assignees for export", "error", err)
+			return task.ErrTaskInternal
+		}
+		for _, t := range page {
+			ids := assignees[t.TaskID]
+			if t.AssignedToUserID != nil && !containsUint(ids, *t.AssignedToUserID) {
+				ids = append(ids, *t.AssignedToUserID)
+			}
+			assignees[t.TaskID] = primaryFirst(ids, t.AssignedToUserID)
+			for _, id := range assignees[t.TaskID] {
+				if _, ok := logins[id]; !ok {
+					logins[id] = ""
+					unknownUsers = append(unknownUsers, id)
+				}
+			}
+		}
+		if len(unknownUsers) > 0 {
+			refs, err := uc.repo.GetUserRefsByIDs(unknownUsers)
+			if err != nil {
+				log.Error("failed to get user logins for export", "error", err)
+				return task.ErrTaskInternal
+			}
+			for _, ref := range refs {
+				logins[ref.UserID] = ref.Login
+			}
+		}
+
+		for _, t := range page {
+			if err := emit(toTaskExportItem(t, tagNames[t.TaskID], assignees[t.TaskID], logins)); err != nil {
+				log.Warn("export stream interrupted", "error", err, "exported", exported)
+				return err
+			}
+			exported++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info("tasks exported", slog.Int("count", exported))
+	return nil
+}
+
+// forEachTaskPage читает выборку страницами по exportPageSize задач в порядке создания
+// и передает каждую страницу в fn. Сортировка, курсор и лимит в params перезаписываются.
+func (uc *TaskUseCase) forEachTaskPage(params task.GetTasksParams, fn func(page []*task.Task) error) error {
+	params.SortBy = task.FieldCreatedAt
+	params.SortOrder = task.SortDirectionAsc
+	params.Limit = exportPageSize
+	params.Cursor = nil
+	for {
+		page, err := uc.repo.GetTasks(params)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if len(page) < exportPageSize {
+			return nil
+		}
+		last := page[len(page)-1]
+		params.Cursor = &task.TaskCursor{
+			SortBy:    task.FieldCreatedAt,
+			SortOrder: task.SortDirectionAsc,
+			Value:     last.SortValue(task.FieldCreatedAt),
+			TaskID:    last.TaskID,
+		}
+	}
+}
+
+// toTaskExportItem переводит задачу в формат экспорта. assigneeIDs - все исполнители, основной первым.
+func toTaskExportItem(t *task.Task, tags []string, assigneeIDs []uint, logins map[uint]string) *task.TaskExportItem {
+	item := &task.TaskExportItem{
+		TaskID:         t.TaskID,
+		Title:          t.Title,
+		Description:    t.Description,
+		Status:         t.Status,
+		StatusCategory: t.StatusCategory,
+		Priority:       t.Priority,
+		Deadline:       t.Deadline,
+		CreatedBy:      logins[t.CreatedByUserID],
+		TeamID:         t.TeamID,
+		Tags:           tags,
+		CreatedAt:      &t.CreatedAt,
+		UpdatedAt:      &t.UpdatedAt,
+		CompletedAt:    t.CompletedAt,
+	}
+	for _, id := range assigneeIDs {
+		if login := logins[id]; login != "" {
+			item.Assignees = append(item.Assignees, login)
+		}
+	}
+	if t.AssignedToUserID != nil {
+		if login := logins[*t.AssignedToUserID]; login != "" {
+			item.Assignee = &login
+		}
+	}
+	return item
+}
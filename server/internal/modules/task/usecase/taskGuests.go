@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"log/slog"
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+)
+
+// guestTaskTagScope возвращает тег, которым ограничен просмотр задач команды для гостя (nil - без ограничения).
+func (uc *TaskUseCase) guestTaskTagScope(userID uint, teamID *uint) (*uint, error) {
+	if teamID == nil {
+		return nil, nil
+	}
+	return uc.teamService.GetGuestTaskTagScope(userID, *teamID)
+}
+
+// checkGuestTaskScope проверяет, что гость с ограничением по тегу может видеть командную задачу.
+func (uc *TaskUseCase) checkGuestTaskScope(taskModel *task.Task, userID uint) error {
+	scopeTagID, err := uc.guestTaskTagScope(userID, taskModel.TeamID)
+	if err != nil {
+		uc.log.Error("failed to get guest task scope", "error", err, "taskID", taskModel.TaskID)
+		return task.ErrTaskInternal
+	}
+	if scopeTagID == nil {
+		return nil
+	}
+	links, err := uc.tagRepo.GetTaskTags(taskModel.TaskID)
+	if err != nil {
+		uc.log.Error("failed to get task tags for guest scope", "error", err, "taskID", taskModel.TaskID)
+		return task.ErrTaskInternal
+	}
+	for _, link := range links {
+		if link.TeamTagID != nil && *link.TeamTagID == *scopeTagID {
+			return nil
+		}
+	}
+	uc.log.Warn("task is outside of guest tag scope", "taskID", taskModel.TaskID, "accessorID", userID)
+	return task.ErrTaskAccessDenied
+}
+
+// checkTaskContributor запрещает гостям и участникам архивной команды изменения, которые не покрыты правами команды
+// (например, учет времени).
+func (uc *TaskUseCase) checkTaskContributor(taskModel *task.Task, userID uint) error {
+	if taskModel.TeamID == nil {
+		return nil
+	}
+	if err := uc.checkTeamWritable(taskModel.TeamID); err != nil {
+		return err
+	}
+	role, err := uc.teamService.GetUserRoleInTeam(userID, *taskModel.TeamID)
+	if err != nil {
+		uc.log.Error("failed to get team role", "error", err, "taskID", taskModel.TaskID)
+		return task.ErrTaskInternal
+	}
+	if role == nil || *role == team.RoleGuest {
+		uc.log.Warn("guest cannot modify team task", "taskID", taskModel.TaskID, "userID", userID)
+		return task.ErrTaskAccessDenied
+	}
+	return nil
+}
+
+// checkTeamAssignee проверяет, что пользователя можно назначить исполнителем задачи команды: он участник и не гость.
+func (uc *TaskUseCase) checkTeamAssignee(teamID uint, assigneeID uint) error {
+	role, err := uc.teamService.GetUserRoleInTeam(assigneeID, teamID)
+	if err != nil {
+		uc.log.Error("failed to check assignee in team", "error", err,
+			slog.Uint64("teamID", uint64(teamID)), slog.Uint64("assigneeID", uint64(assigneeID)))
+		return task.ErrTaskInternal
+	}
+	if role == nil {
+		return task.ErrTaskAssigneeNotInTeam
+	}
+	if *role == team.RoleGuest {
+		return task.ErrTaskAssigneeIsGuest
+	}
+	return nil
+}
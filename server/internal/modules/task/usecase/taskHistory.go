@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"errors"
+	"log/slog"
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultTeamActivityLimit = 100
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func optionalUintToStr(v *uint) *string {
+	if v == nil {
+		return nil
+	}
+	return strPtr(strconv.FormatUint(uint64(*v), 10))
+}
+
+func optionalIntToStr(v *int) *string {
+	if v == nil {
+		return nil
+	}
+	return strPtr(strconv.Itoa(*v))
+}
+
+func optionalTimeToStr(v *time.Time) *string {
+	if v == nil {
+		return nil
+	}
+	return strPtr(v.UTC().Format(time.RFC3339))
+}
+
+func optionalStrEqual(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// newTaskEvent создает запись журнала для задачи от имени actorUserID.
+func newTaskEvent(t *task.Task, actorUserID uint, eventType task.TaskEventType, fieldName string, oldValue, newValue *string) *task.TaskEvent {
+	event := &task.TaskEvent{
+		TaskID:      t.TaskID,
+		TeamID:      t.TeamID,
+		ActorUserID: &actorUserID,
+		EventType:   eventType,
+		OldValue:    oldValue,
+		NewValue:    newValue,
+	}
+	if fieldName != "" {
+		event.FieldName = &fieldName
+	}
+	return event
+}
+
+// diffTaskEvents сравнивает состояние задачи до и после изменения и возвращает по записи на каждое измененное поле.
+func diffTaskEvents(before, after *task.Task, actorUserID uint) []*task.TaskEvent {
+	var events []*task.TaskEvent
+	add := func(field string, oldValue, newValue *string) {
+		if optionalStrEqual(oldValue, newValue) {
+			return
+		}
+		events = append(events, newTaskEvent(after, actorUserID, task.EventTaskUpdated, field, oldValue, newValue))
+	}
+
+	add("title", strPtr(before.Title), strPtr(after.Title))
+	add("description", before.Description, after.Description)
+	add("deadline", optionalTimeToStr(before.Deadline), optionalTimeToStr(after.Deadline))
+	add("status", strPtr(before.Status), strPtr(after.Status))
+	add("priority", strPtr(strconv.Itoa(before.Priority)), strPtr(strconv.Itoa(after.Priority)))
+	add("assigned_to_user_id", optionalUintToStr(before.AssignedToUserID), optionalUintToStr(after.AssignedToUserID))
+	add("estimate_minutes", optionalIntToStr(before.EstimateMinutes), optionalIntToStr(after.EstimateMinutes))
+	add("project_id", optionalUintToStr(before.ProjectID), optionalUintToStr(after.ProjectID))
+	add("sprint_id", optionalUintToStr(before.SprintID), optionalUintToStr(after.SprintID))
+	return events
+}
+
+// diffTagEvents сравнивает наборы тегов задачи и возвращает записи о добавленных и удаленных тегах.
+func diffTagEvents(t *task.Task, actorUserID uint, fieldName string, oldIDs, newIDs []uint) []*task.TaskEvent {
+	return diffIDSetEvents(t, actorUserID, fieldName, task.EventTaskTagAdded, task.EventTaskTagRemoved, oldIDs, newIDs)
+}
+
+// diffIDSetEvents сравнивает два набора ID (теги, исполнители) и возвращает записи о добавленных и удаленных.
+func diffIDSetEvents(t *task.Task, actorUserID uint, fieldName string, addedType, removedType task.TaskEventType, oldIDs, newIDs []uint) []*task.TaskEvent {
+	oldSet := make(map[uint]struct{}, len(oldIDs))
+	for _, id := range oldIDs {
+		oldSet[id] = struct{}{}
+	}
+	newSet := make(map[uint]struct{}, len(newIDs))
+	for _, id := range newIDs {
+		newSet[id] = struct{}{}
+	}
+
+	var events []*task.TaskEvent
+	for _, id := range newIDs {
+		if _, ok := oldSet[id]; !ok {
+			events = append(events, newTaskEvent(t, actorUserID, addedType, fieldName, nil, optionalUintToStr(&id)))
+		}
+	}
+	for _, id := range oldIDs {
+		if _, ok := newSet[id]; !ok {
+			events = append(events, newTaskEvent(t, actorUserID, removedType, fieldName, optionalUintToStr(&id), nil))
+		}
+	}
+	return events
+}
+
+// recordTaskEvents сохраняет записи журнала и уведомляет участников задачи. Ошибка записи не прерывает основную операцию.
+// Внутри транзакции записи только накапливаются и сохраняются после ее фиксации (flushEventBatch).
+func (uc *TaskUseCase) recordTaskEvents(events ...*task.TaskEvent) {
+	if len(events) == 0 {
+		return
+	}
+	if uc.eventBatch != nil {
+		*uc.eventBatch = append(*uc.eventBatch, events...)
+	} else {
+		uc.saveTaskEvents(events)
+	}
+	uc.notifyTaskEvents(events)
+}
+
+func (uc *TaskUseCase) saveTaskEvents(events []*task.TaskEvent) {
+	if err := uc.repo.CreateTaskEvents(events); err != nil {
+		uc.log.Warn("failed to record task events", "error", err, "taskID", events[0].TaskID, "count", len(events))
+	}
+}
+
+// flushEventBatch сохраняет записи журнала, накопленные в зафиксированной транзакции.
+func (uc *TaskUseCase) flushEventBatch(batch []*task.TaskEvent) {
+	if len(batch) > 0 {
+		uc.saveTaskEvents(batch)
+	}
+}
+
+func (uc *TaskUseCase) GetTaskHistory(taskID uint, userID uint) ([]*task.TaskEventResponse, error) {
+	op := "TaskUseCase.GetTaskHistory"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)), slog.Uint64("userID", uint64(userID)))
+
+	// История доступна и для задач в корзине, и для окончательно удаленных: журнал переживает удаление задачи
+	taskModel, err := uc.repo.GetTaskByIDIncludingDeleted(taskID)
+	if err != nil && !errors.Is(err, task.ErrTaskNotFound) {
+		log.Error("failed to get task for history", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	if err == nil {
+		if errAccess := uc.checkTaskAccess(taskModel, userID); errAccess != nil {
+			return nil, errAccess
+		}
+	}
+
+	taskDeleted := err != nil
+	events, err := uc.repo.GetTaskEvents(taskID)
+	if err != nil {
+		log.Error("failed to get task events", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	if taskDeleted {
+		if errAccess := uc.checkDeletedTaskHistoryAccess(events, userID); errAccess != nil {
+			return nil, errAccess
+		}
+	}
+
+	log.Info("task history retrieved", slog.Int("count", len(events)))
+	return task.ToTaskEventResponseList(events), nil
+}
+
+// checkDeletedTaskHistoryAccess проверяет доступ к журналу окончательно удаленной задачи по самому журналу:
+// последняя запись хранит команду, в которой задача была на момент удаления. Историю командной задачи видят
+// участники команды, кроме гостей (их область видимости задается тегами, а теги удаленной задачи неизвестны);
+// историю личной задачи - только ее владелец, который единственный мог ее менять.
+func (uc *TaskUseCase) checkDeletedTaskHistoryAccess(events []*task.TaskEvent, userID uint) error {
+	if len(events) == 0 {
+		return task.ErrTaskNotFound
+	}
+	last := events[len(events)-1]
+	if last.TeamID == nil {
+		if last.ActorUserID == nil || *last.ActorUserID != userID {
+			uc.log.Warn("access denied to deleted personal task history", "taskID", last.TaskID, "accessorID", userID)
+			return task.ErrTaskAccessDenied
+		}
+		return nil
+	}
+
+	role, err := uc.teamService.GetUserRoleInTeam(userID, *last.TeamID)
+	if err != nil {
+		uc.log.Error("failed to get user role for deleted task history", "error", err, "teamID", *last.TeamID)
+		return task.ErrTaskInternal
+	}
+	if role == nil || *role == team.RoleGuest {
+		uc.log.Warn("access denied to deleted team task history", "taskID", last.TaskID, "teamID", *last.TeamID)
+		return task.ErrTaskAccessDenied
+	}
+	return nil
+}
+
+func (uc *TaskUseCase) GetTeamActivity(teamID uint, userID uint, req task.GetTeamActivityRequest) ([]*task.TaskEventResponse, error) {
+	op := "TaskUseCase.GetTeamActivity"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	canView, err := uc.teamService.CanUserViewTeamActivity(userID, teamID)
+	if err != nil {
+		log.Error("failed to check team activity permission", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	if !canView {
+		log.Warn("user lacks permission to view team activity")
+		return nil, task.ErrTaskAccessDenied
+	}
+
+	params := task.GetTeamActivityParams{
+		TeamID:      teamID,
+		ActorUserID: req.ActorUserID,
+		From:        req.From,
+		To:          req.To,
+		Limit:       defaultTeamActivityLimit,
+	}
+	if req.Limit != nil {
+		params.Limit = *req.Limit
+	}
+
+	events, err := uc.repo.GetTeamTaskEvents(params)
+	if err != nil {
+		log.Error("failed to get team task events", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Info("team activity retrieved", slog.Int("count", len(events)))
+	return task.ToTaskEventResponseList(events), nil
+}
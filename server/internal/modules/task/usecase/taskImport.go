@@ -0,0 +1,331 @@
+package usecase
+
+import (
+	"errors"
+	"log/slog"
+	"server/internal/modules/tag"
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+	"strconv"
+	"strings"
+)
+
+// maxTaskAssignees - наибольшее число исполнителей задачи (как в валидации CreateTaskRequest)
+const maxTaskAssignees = 20
+
+// resolveImportStatus сопоставляет статус записи с набором статусов: по ключу, затем по названию.
+// Без статуса берется первый статус категории done для выполненных записей и начальный статус для остальных.
+func resolveImportStatus(workflow *team.TaskWorkflow, row *importRow) (string, bool) {
+	if row.Status != "" {
+		if s := workflow.Status(row.Status); s != nil {
+			return s.Key, true
+		}
+		for _, s := range workflow.Statuses {
+			if strings.EqualFold(s.Name, row.Status) || strings.EqualFold(s.Key, row.Status) {
+				return s.Key, true
+			}
+		}
+		return "", false
+	}
+	if row.Completed {
+		for _, s := range workflow.Statuses {
+			if s.Category == team.StatusCategoryDone {
+				return s.Key, true
+			}
+		}
+	}
+	return workflow.InitialStatus().Key, true
+}
+
+// resolveImportAssignees сопоставляет логины и email исполнителей с пользователями.
+// Для командного импорта исполнитель должен быть участником команды, для личного - самим пользователем.
+func (uc *TaskUseCase) resolveImportAssignees(userID uint, teamID *uint, rows []*importRow) (map[string]uint, map[string]string, error) {
+	identifiers := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		for _, assignee := range row.Assignees {
+			key := strings.ToLower(assignee)
+			if !seen[key] {
+				seen[key] = true
+				identifiers = append(identifiers, assignee)
+			}
+		}
+	}
+	resolved := make(map[string]uint)
+	problems := make(map[string]string)
+	if len(identifiers) == 0 {
+		return resolved, problems, nil
+	}
+
+	refs, err := uc.repo.FindUsersByLoginOrEmail(identifiers)
+	if err != nil {
+		return nil, nil, err
+	}
+	byKey := make(map[string]uint, len(refs)*2)
+	for _, ref := range refs {
+		byKey[strings.ToLower(ref.Login)] = ref.UserID
+		byKey[strings.ToLower(ref.Email)] = ref.UserID
+	}
+	for key := range seen {
+		assigneeID, ok := byKey[key]
+		if !ok {
+			problems[key] = "assignee " + key + " not found"
+			continue
+		}
+		if teamID == nil {
+			if assigneeID != userID {
+				problems[key] = "personal task can only be assigned to yourself"
+				continue
+			}
+		} else {
+			if err := uc.checkTeamAssignee(*teamID, assigneeID); err != nil {
+				if errors.Is(err, task.ErrTaskInternal) {
+					return nil, nil, err
+				}
+				problems[key] = err.Error()
+				continue
+			}
+		}
+		resolved[key] = assigneeID
+	}
+	return resolved, problems, nil
+}
+
+// loadImportTags возвращает существующие теги области импорта (имя в нижнем регистре -> ID).
+func (uc *TaskUseCase) loadImportTags(userID uint, teamID *uint) (map[string]uint, error) {
+	var tags []*tag.TagResponse
+	var err error
+	if teamID != nil {
+		tags, err = uc.tagUC.GetTeamTags(*teamID, userID)
+	} else {
+		tags, err = uc.tagUC.GetUserTags(userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]uint, len(tags))
+	for _, t := range tags {
+		byName[strings.ToLower(t.Name)] = t.ID
+	}
+	return byName, nil
+}
+
+// createImportTag создает недостающий тег в области импорта.
+func (uc *TaskUseCase) createImportTag(userID uint, teamID *uint, name string) (uint, error) {
+	var created *tag.TagResponse
+	var err error
+	if teamID != nil {
+		created, err = uc.tagUC.CreateTeamTag(*teamID, userID, tag.CreateTeamTagRequest{Name: name})
+	} else {
+		created, err = uc.tagUC.CreateUserTag(userID, tag.CreateUserTagRequest{Name: name})
+	}
+	if err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+// ImportTasks импортирует задачи из файла в личную область пользователя или в команду.
+// Каждая запись проверяется отдельно; записи с ошибками не создаются и не мешают остальным.
+// При DryRun возвращается предпросмотр без изменений в БД.
+func (uc *TaskUseCase) ImportTasks(userID uint, req task.TaskImportRequest, data []byte) (*task.TaskImportResult, error) {
+	op := "TaskUseCase.ImportTasks"
+	uc = uc.forRequest()
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)),
+		slog.String("format", string(req.Format)), slog.Bool("dryRun", req.DryRun))
+
+	if req.TeamID != nil {
+		log = log.With(slog.Uint64("teamID", uint64(*req.TeamID)))
+		canCreate, err := uc.teamService.CanUserCreateTeamTask(userID, *req.TeamID)
+		if err != nil {
+			log.Error("failed to check team create permission for import", "error", err)
+			return nil, teamCheckError(err)
+		}
+		if !canCreate {
+			return nil, task.ErrTaskAccessDenied
+		}
+	}
+
+	rows, err := parseImportFile(req.Format, data, req.Mapping)
+	if err != nil {
+		log.Warn("failed to parse import file", "error", err)
+		return nil, err
+	}
+
+	workflow, err := uc.getTaskWorkflow(req.TeamID)
+	if err != nil {
+		log.Error("failed to get task workflow for import", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	assignees, assigneeProblems, err := uc.resolveImportAssignees(userID, req.TeamID, rows)
+	if err != nil {
+		log.Error("failed to resolve import assignees", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	existingTags, err := uc.loadImportTags(userID, req.TeamID)
+	if err != nil {
+		log.Error("failed to load tags for import", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	result := &task.TaskImportResult{
+		DryRun:      req.DryRun,
+		Total:       len(rows),
+		TagsCreated: []string{},
+		Rows:        make([]*task.TaskImportRowResult, 0, len(rows)),
+	}
+	statuses := make([]string, len(rows))
+	missingTags := make(map[string]string) // имя в нижнем регистре -> имя из файла
+	for i, row := range rows {
+		res := &task.TaskImportRowResult{Row: row.Row, Title: row.Title, Assignees: row.Assignees, Tags: row.Tags, Errors: row.Errors}
+		if len(row.Assignees) > 0 {
+			res.Assignee = &row.Assignees[0]
+		}
+		result.Rows = append(result.Rows, res)
+		if row.SkipReason != "" {
+			res.Skipped = true
+			res.Errors = nil
+			continue
+		}
+
+		if row.Title == "" {
+			res.Errors = append(res.Errors, "title is required")
+		} else if len([]rune(row.Title)) > 255 {
+			res.Errors = append(res.Errors, "title is longer than 255 characters")
+		}
+		if row.Description != nil && len(*row.Description) > 65535 {
+			res.Errors = append(res.Errors, "description is too long")
+		}
+		if row.Priority < 0 || row.Priority > 3 {
+			res.Errors = append(res.Errors, "priority must be between 1 and 3")
+		}
+		if status, ok := resolveImportStatus(workflow, row); ok {
+			statuses[i] = status
+			res.Status = status
+		} else {
+			res.Errors = append(res.Errors, "unknown status "+row.Status)
+		}
+		if len(row.Assignees) > maxTaskAssignees {
+			res.Errors = append(res.Errors, "more than "+strconv.Itoa(maxTaskAssignees)+" assignees")
+		}
+		for _, assignee := range row.Assignees {
+			if problem, bad := assigneeProblems[strings.ToLower(assignee)]; bad {
+				res.Errors = append(res.Errors, problem)
+			}
+		}
+		for _, name := range row.Tags {
+			key := strings.ToLower(name)
+			if len([]rune(name)) > 50 {
+				res.Errors = append(res.Errors, "tag "+name+" is longer than 50 characters")
+				continue
+			}
+			if _, ok := existingTags[key]; !ok {
+				missingTags[key] = name
+			}
+		}
+	}
+
+	// Недостающие теги создаются только для записей без ошибок
+	neededTags := make(map[string]bool)
+	for i, row := range rows {
+		if result.Rows[i].Skipped || len(result.Rows[i].Errors) > 0 {
+			continue
+		}
+		for _, name := range row.Tags {
+			if _, missing := missingTags[strings.ToLower(name)]; missing {
+				neededTags[strings.ToLower(name)] = true
+			}
+		}
+	}
+	tagErrors := make(map[string]string)
+	for key := range neededTags {
+		name := missingTags[key]
+		if req.DryRun {
+			result.TagsCreated = append(result.TagsCreated, name)
+			continue
+		}
+		tagID, errTag := uc.createImportTag(userID, req.TeamID, name)
+		if errTag != nil {
+			log.Warn("failed to create tag for import", "tag", name, "error", errTag)
+			tagErrors[key] = "cannot create tag " + name + ": " + errTag.Error()
+			continue
+		}
+		existingTags[key] = tagID
+		result.TagsCreated = append(result.TagsCreated, name)
+	}
+
+	// Задачи создаются обычным CreateTask; кэш списков инвалидируется один раз в конце
+	batch := make(map[string]struct{})
+	worker := *uc
+	worker.listCacheBatch = batch
+	for i, row := range rows {
+		res := result.Rows[i]
+		if res.Skipped {
+			result.Skipped++
+			continue
+		}
+		for _, name := range row.Tags {
+			if problem, bad := tagErrors[strings.ToLower(name)]; bad {
+				res.Errors = append(res.Errors, problem)
+			}
+		}
+		if len(res.Errors) > 0 {
+			result.Failed++
+			continue
+		}
+		result.Valid++
+		if req.DryRun {
+			continue
+		}
+
+		createReq := task.CreateTaskRequest{
+			Title:       row.Title,
+			Description: row.Description,
+			Deadline:    row.Deadline,
+			Status:      &statuses[i],
+			TeamID:      req.TeamID,
+		}
+		if row.Priority > 0 {
+			priority := row.Priority
+			createReq.Priority = &priority
+		}
+		for _, assignee := range row.Assignees {
+			if assigneeID := assignees[strings.ToLower(assignee)]; !containsUint(createReq.AssigneeIDs, assigneeID) {
+				createReq.AssigneeIDs = append(createReq.AssigneeIDs, assigneeID)
+			}
+		}
+		if len(createReq.AssigneeIDs) > 0 {
+			createReq.AssignedToUserID = &createReq.AssigneeIDs[0]
+		}
+		tagIDs := make([]uint, 0, len(row.Tags))
+		seenTags := make(map[uint]bool, len(row.Tags))
+		for _, name := range row.Tags {
+			if tagID := existingTags[strings.ToLower(name)]; !seenTags[tagID] {
+				seenTags[tagID] = true
+				tagIDs = append(tagIDs, tagID)
+			}
+		}
+		if req.TeamID != nil {
+			createReq.TeamTagIDs = tagIDs
+		} else {
+			createReq.UserTagIDs = tagIDs
+		}
+
+		created, errCreate := worker.CreateTask(userID, createReq)
+		if errCreate != nil {
+			log.Warn("failed to create imported task", "row", row.Row, "error", errCreate)
+			res.Errors = append(res.Errors, errCreate.Error())
+			result.Valid--
+			result.Failed++
+			continue
+		}
+		res.TaskID = &created.TaskID
+		result.Created++
+	}
+
+	uc.flushListCacheBatch(batch)
+
+	log.Info("tasks import finished", slog.Int("total", result.Total), slog.Int("created", result.Created),
+		slog.Int("failed", result.Failed), slog.Int("skipped", result.Skipped), slog.Int("tagsCreated", len(result.TagsCreated)))
+	return result, nil
+}
@@ -0,0 +1,361 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"server/internal/modules/task"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxImportRows - ограничение на число записей в одном файле импорта
+const maxImportRows = 5000
+
+// importRow - запись импорта, приведенная к общему виду независимо от исходного формата
+type importRow struct {
+	Row         int
+	Title       string
+	Description *string
+	Status      string // Ключ или название статуса; пусто - по Completed
+	Completed   bool
+	Priority    int // 0 - по умолчанию
+	Deadline    *time.Time
+	Assignees   []string // Логины или email, основной исполнитель первым
+	Tags        []string
+	SkipReason  string
+	Errors      []string
+}
+
+// parseImportFile разбирает файл в записи импорта. Ошибки отдельных полей попадают в записи,
+// ошибка всего файла возвращается как task.ErrTaskImportInvalid.
+func parseImportFile(format task.TaskImportFormat, data []byte, mapping map[string]string) ([]*importRow, error) {
+	var rows []*importRow
+	var err error
+	switch format {
+	case task.ImportFormatJSON:
+		rows, err = parseNativeImport(data)
+	case task.ImportFormatCSV:
+		rows, err = parseCSVImport(data, mapping)
+	case task.ImportFormatTodoist:
+		rows, err = parseTodoistImport(data)
+	case task.ImportFormatTrello:
+		rows, err = parseTrelloImport(data)
+	default:
+		return nil, task.ErrTaskImportInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", task.ErrTaskImportInvalid, err)
+	}
+	if len(rows) > maxImportRows {
+		return nil, fmt.Errorf("%w: more than %d records", task.ErrTaskImportInvalid, maxImportRows)
+	}
+	return rows, nil
+}
+
+// parseImportDate принимает RFC3339, дату с временем без зоны и просто дату.
+func parseImportDate(value string) (*time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid date %q", value)
+}
+
+// parseImportPriority принимает 1-3 или low/medium/high.
+func parseImportPriority(value string) (int, error) {
+	switch strings.ToLower(value) {
+	case "low":
+		return 1, nil
+	case "medium":
+		return 2, nil
+	case "high":
+		return 3, nil
+	}
+	p, err := strconv.Atoi(value)
+	if err != nil || p < 1 || p > 3 {
+		return 0, fmt.Errorf("invalid priority %q", value)
+	}
+	return p, nil
+}
+
+// importAssignees собирает исполнителей записи: основной первым, повторы без учета регистра отбрасываются.
+func importAssignees(primary *string, others []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, value := range append([]string{stringValue(primary)}, others...) {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[strings.ToLower(value)] {
+			continue
+		}
+		seen[strings.ToLower(value)] = true
+		result = append(result, value)
+	}
+	return result
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func optionalString(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func parseNativeImport(data []byte) ([]*importRow, error) {
+	var items []*task.TaskExportItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	rows := make([]*importRow, 0, len(items))
+	for i, item := range items {
+		if item == nil {
+			continue
+		}
+		rows = append(rows, &importRow{
+			Row:         i + 1,
+			Title:       strings.TrimSpace(item.Title),
+			Description: item.Description,
+			Status:      item.Status,
+			Priority:    item.Priority,
+			Deadline:    item.Deadline,
+			Assignees:   importAssignees(item.Assignee, item.Assignees),
+			Tags:        item.Tags,
+		})
+	}
+	return rows, nil
+}
+
+// parseCSVImport читает CSV с заголовком. mapping задает колонку для поля задачи;
+// без него поле ищется в колонке с тем же именем, что и в экспорте. Теги и исполнители разделяются ";".
+func parseCSVImport(data []byte, mapping map[string]string) ([]*importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read CSV header: %v", err)
+	}
+	headerIndex := make(map[string]int, len(header))
+	for i, name := range header {
+		headerIndex[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	columns := make(map[string]int)
+	for _, field := range []string{"title", "description", "status", "priority", "deadline", "assignee", "assignees", "tags"} {
+		column := field
+		if mapped, ok := mapping[field]; ok {
+			column = mapped
+		}
+		if idx, ok := headerIndex[strings.ToLower(strings.TrimSpace(column))]; ok {
+			columns[field] = idx
+		} else if _, mapped := mapping[field]; mapped {
+			return nil, fmt.Errorf("column %q for field %s not found", column, field)
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("title column not found")
+	}
+
+	var rows []*importRow
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %v", n, err)
+		}
+		value := func(field string) string {
+			idx, ok := columns[field]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+		row := &importRow{
+			Row:         n,
+			Title:       value("title"),
+			Description: optionalString(value("description")),
+			Status:      value("status"),
+			Assignees:   importAssignees(optionalString(value("assignee")), strings.Split(value("assignees"), ";")),
+		}
+		if v := value("priority"); v != "" {
+			if row.Priority, err = parseImportPriority(v); err != nil {
+				row.Errors = append(row.Errors, err.Error())
+			}
+		}
+		if v := value("deadline"); v != "" {
+			if row.Deadline, err = parseImportDate(v); err != nil {
+				row.Errors = append(row.Errors, err.Error())
+			}
+		}
+		for _, name := range strings.Split(value("tags"), ";") {
+			if name = strings.TrimSpace(name); name != "" {
+				row.Tags = append(row.Tags, name)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// todoistItem - задача из JSON-выгрузки Todoist (Sync API: items)
+type todoistItem struct {
+	Content     string   `json:"content"`
+	Description string   `json:"description"`
+	Priority    int      `json:"priority"` // 4 - наивысший
+	Checked     bool     `json:"checked"`
+	IsCompleted bool     `json:"is_completed"`
+	Labels      []string `json:"labels"`
+	Due         *struct {
+		Date string `json:"date"`
+	} `json:"due"`
+}
+
+// parseTodoistImport принимает объект с полем items или массив задач.
+func parseTodoistImport(data []byte) ([]*importRow, error) {
+	var items []*todoistItem
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+	} else {
+		var export struct {
+			Items []*todoistItem `json:"items"`
+		}
+		if err := json.Unmarshal(data, &export); err != nil {
+			return nil, err
+		}
+		items = export.Items
+	}
+
+	rows := make([]*importRow, 0, len(items))
+	for i, item := range items {
+		if item == nil {
+			continue
+		}
+		row := &importRow{
+			Row:         i + 1,
+			Title:       strings.TrimSpace(item.Content),
+			Description: optionalString(item.Description),
+			Completed:   item.Checked || item.IsCompleted,
+			Tags:        item.Labels,
+		}
+		// Todoist: 4 (p1) - срочно, 1 (p4) - обычная
+		switch item.Priority {
+		case 4:
+			row.Priority = 3
+		case 3:
+			row.Priority = 2
+		default:
+			row.Priority = 1
+		}
+		if item.Due != nil && item.Due.Date != "" {
+			deadline, err := parseImportDate(item.Due.Date)
+			if err != nil {
+				row.Errors = append(row.Errors, err.Error())
+			}
+			row.Deadline = deadline
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// trelloBoard - нужная часть JSON-экспорта доски Trello
+type trelloBoard struct {
+	Cards []struct {
+		Name        string   `json:"name"`
+		Desc        string   `json:"desc"`
+		Due         *string  `json:"due"`
+		DueComplete bool     `json:"dueComplete"`
+		Closed      bool     `json:"closed"`
+		IDList      string   `json:"idList"`
+		IDLabels    []string `json:"idLabels"`
+		IDMembers   []string `json:"idMembers"`
+	} `json:"cards"`
+	Labels []struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+	Lists []struct {
+		ID     string `json:"id"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Members []struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"members"`
+}
+
+// parseTrelloImport превращает карточки доски в задачи: метки - в теги (метка без имени получает имя цвета),
+// участники - в исполнителей (первый - основной). Архивные карточки и карточки из архивных списков пропускаются.
+func parseTrelloImport(data []byte) ([]*importRow, error) {
+	var board trelloBoard
+	if err := json.Unmarshal(data, &board); err != nil {
+		return nil, err
+	}
+	labels := make(map[string]string, len(board.Labels))
+	for _, l := range board.Labels {
+		name := strings.TrimSpace(l.Name)
+		if name == "" {
+			name = l.Color
+		}
+		labels[l.ID] = name
+	}
+	closedLists := make(map[string]bool)
+	for _, l := range board.Lists {
+		if l.Closed {
+			closedLists[l.ID] = true
+		}
+	}
+	members := make(map[string]string, len(board.Members))
+	for _, m := range board.Members {
+		members[m.ID] = m.Username
+	}
+
+	rows := make([]*importRow, 0, len(board.Cards))
+	for i, card := range board.Cards {
+		row := &importRow{
+			Row:         i + 1,
+			Title:       strings.TrimSpace(card.Name),
+			Description: optionalString(card.Desc),
+			Completed:   card.DueComplete,
+		}
+		if card.Closed || closedLists[card.IDList] {
+			row.SkipReason = "archived card"
+		}
+		if card.Due != nil && *card.Due != "" {
+			deadline, err := parseImportDate(*card.Due)
+			if err != nil {
+				row.Errors = append(row.Errors, err.Error())
+			}
+			row.Deadline = deadline
+		}
+		for _, id := range card.IDLabels {
+			if name := labels[id]; name != "" {
+				row.Tags = append(row.Tags, name)
+			}
+		}
+		usernames := make([]string, 0, len(card.IDMembers))
+		for _, id := range card.IDMembers {
+			usernames = append(usernames, members[id])
+		}
+		row.Assignees = importAssignees(nil, usernames)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
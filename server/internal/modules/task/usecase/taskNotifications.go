@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"server/internal/modules/task"
+	gouser "server/internal/modules/user"
+	"server/pkg/lib/pushsender"
+	"strconv"
+	"time"
+)
+
+const notificationSendTimeout = 10 * time.Second
+
+// UserNotificationProvider - настройки уведомлений и токены устройств пользователей (модуль profile).
+type UserNotificationProvider interface {
+	GetUserNotificationSettings(userID uint) (*gouser.UserSetting, error)
+	GetUserDeviceTokens(userID uint) ([]gouser.UserDeviceToken, error)
+}
+
+// Notifier доставляет уведомление пользователям. Настройки получателей учитывает реализация.
+type Notifier interface {
+	Notify(userIDs []uint, msg pushsender.PushMessage)
+}
+
+type pushNotifier struct {
+	sender pushsender.Sender
+	users  UserNotificationProvider
+	log    *slog.Logger
+}
+
+// NewPushNotifier создает Notifier, отправляющий Push-уведомления на все устройства получателей.
+func NewPushNotifier(sender pushsender.Sender, users UserNotificationProvider, log *slog.Logger) Notifier {
+	return &pushNotifier{sender: sender, users: users, log: log}
+}
+
+// Notify пропускает пользователей, отключивших уведомления о задачах, и отправляет одно сообщение на все их устройства.
+func (n *pushNotifier) Notify(userIDs []uint, msg pushsender.PushMessage) {
+	op := "pushNotifier.Notify"
+	log := n.log.With(slog.String("op", op), slog.Int("recipients", len(userIDs)))
+
+	var tokens []string
+	for _, userID := range userIDs {
+		settings, err := n.users.GetUserNotificationSettings(userID)
+		if err != nil {
+			log.Warn("failed to get notification settings, skipping user", "userID", userID, "error", err)
+			continue
+		}
+		if settings != nil && settings.PushNotificationsTasksLevel == gouser.PushTaskNotificationLevelNone {
+			continue
+		}
+		devices, err := n.users.GetUserDeviceTokens(userID)
+		if err != nil {
+			log.Warn("failed to get device tokens, skipping user", "userID", userID, "error", err)
+			continue
+		}
+		for _, device := range devices {
+			tokens = append(tokens, device.DeviceToken)
+		}
+	}
+	if len(tokens) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notificationSendTimeout)
+	defer cancel()
+	msg.Tokens = tokens
+	result, err := n.sender.Send(ctx, msg)
+	if err != nil {
+		log.Error("failed to send push notification", "error", err)
+		return
+	}
+	log.Debug("push notification sent", slog.Int("success", result.SuccessCount), slog.Int("failure", result.FailureCount))
+}
+
+// taskNotification - уведомление, отложенное до конца массовой операции.
+type taskNotification struct {
+	userIDs []uint
+	msg     pushsender.PushMessage
+}
+
+// notifyTaskEvents уведомляет исполнителей и наблюдателей задачи об изменениях. Автор изменений уведомление не получает.
+// Новые исполнители получают отдельное сообщение о назначении.
+func (uc *TaskUseCase) notifyTaskEvents(events []*task.TaskEvent) {
+	if uc.notifier == nil || len(events) == 0 {
+		return
+	}
+	first := events[0]
+	if first.EventType == task.EventTaskDeletedPermanently {
+		return
+	}
+	log := uc.log.With(slog.String("op", "TaskUseCase.notifyTaskEvents"), slog.Uint64("taskID", uint64(first.TaskID)))
+
+	var assignedNow []uint
+	var mainEvent *task.TaskEvent
+	for _, event := range events {
+		switch event.EventType {
+		case task.EventTaskAssigneeAdded:
+			if id, err := strconv.ParseUint(derefStr(event.NewValue), 10, 64); err == nil {
+				assignedNow = append(assignedNow, uint(id))
+			}
+		case task.EventTaskTagAdded, task.EventTaskTagRemoved, task.EventTaskCopied:
+			// Теги - служебная разметка; о копии уже уведомляет событие создания
+		default:
+			// Смена статуса важнее прочих правок полей
+			if mainEvent == nil || (mainEvent.EventType == task.EventTaskUpdated && isStatusEvent(event)) {
+				mainEvent = event
+			}
+		}
+	}
+	if mainEvent == nil && len(assignedNow) == 0 {
+		return
+	}
+
+	t, err := uc.repo.GetTaskByIDIncludingDeleted(first.TaskID)
+	if err != nil {
+		log.Warn("failed to get task for notification", "error", err)
+		return
+	}
+	assignees, err := uc.taskAssigneeIDs(t)
+	if err != nil {
+		log.Warn("failed to get task assignees for notification", "error", err)
+		return
+	}
+	watchers, err := uc.repo.GetTaskWatcherIDs([]uint{t.TaskID})
+	if err != nil {
+		log.Warn("failed to get task watchers for notification", "error", err)
+		return
+	}
+
+	var actorID uint
+	if first.ActorUserID != nil {
+		actorID = *first.ActorUserID
+	}
+	data := map[string]string{
+		"type":    "task",
+		"task_id": strconv.FormatUint(uint64(t.TaskID), 10),
+	}
+
+	if assigned := withoutUint(assignedNow, actorID); len(assigned) > 0 {
+		uc.dispatchNotification(assigned, pushsender.PushMessage{
+			Title: t.Title,
+			Body:  "Вас назначили исполнителем",
+			Data:  withEvent(data, task.EventTaskAssigneeAdded),
+		})
+	}
+	if mainEvent == nil {
+		return
+	}
+	var recipients []uint
+	for _, id := range append(assignees, watchers[t.TaskID]...) {
+		if id != actorID && !containsUint(assignedNow, id) && !containsUint(recipients, id) {
+			recipients = append(recipients, id)
+		}
+	}
+	if len(recipients) > 0 {
+		uc.dispatchNotification(recipients, pushsender.PushMessage{
+			Title: t.Title,
+			Body:  uc.taskEventSummary(t, mainEvent),
+			Data:  withEvent(data, mainEvent.EventType),
+		})
+	}
+}
+
+// dispatchNotification отправляет уведомление в фоне; в массовой операции - откладывает до ее завершения.
+func (uc *TaskUseCase) dispatchNotification(userIDs []uint, msg pushsender.PushMessage) {
+	if uc.notificationBatch != nil {
+		*uc.notificationBatch = append(*uc.notificationBatch, &taskNotification{userIDs: userIDs, msg: msg})
+		return
+	}
+	go uc.notifier.Notify(userIDs, msg)
+}
+
+// flushNotificationBatch отправляет уведомления, отложенные массовой операцией.
+func (uc *TaskUseCase) flushNotificationBatch(batch []*taskNotification) {
+	for _, n := range batch {
+		go uc.notifier.Notify(n.userIDs, n.msg)
+	}
+}
+
+// taskEventSummary - текст уведомления по первому значимому событию; для статуса - его название в наборе команды.
+func (uc *TaskUseCase) taskEventSummary(t *task.Task, event *task.TaskEvent) string {
+	switch event.EventType {
+	case task.EventTaskCreated:
+		return "Новая задача"
+	case task.EventTaskDeleted:
+		return "Задача перемещена в корзину"
+	case task.EventTaskRestored:
+		return "Задача восстановлена"
+	case task.EventTaskMoved:
+		return "Задача перенесена"
+	}
+	if isStatusEvent(event) && event.NewValue != nil {
+		name := *event.NewValue
+		if workflow, err := uc.getTaskWorkflow(t.TeamID); err == nil {
+			if status := workflow.Status(name); status != nil {
+				name = status.Name
+			}
+		}
+		return "Новый статус: " + name
+	}
+	return "Задача изменена"
+}
+
+func isStatusEvent(event *task.TaskEvent) bool {
+	return event.EventType == task.EventTaskUpdated && event.FieldName != nil && *event.FieldName == "status"
+}
+
+func withEvent(data map[string]string, eventType task.TaskEventType) map[string]string {
+	result := make(map[string]string, len(data)+1)
+	for k, v := range data {
+		result[k] = v
+	}
+	result["event"] = string(eventType)
+	return result
+}
+
+func derefStr(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
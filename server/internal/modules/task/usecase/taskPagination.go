@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"server/internal/modules/task"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultTasksPageLimit = 100
+)
+
+// encodeTaskCursor упаковывает курсор в непрозрачную для клиента строку.
+func encodeTaskCursor(c task.TaskCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeTaskCursor распаковывает курсор и проверяет, что он выдан для той же сортировки.
+func decodeTaskCursor(value string, sortBy task.TaskSortableField, sortOrder task.SortDirection) (*task.TaskCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, task.ErrTaskInvalidCursor
+	}
+	var c task.TaskCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, task.ErrTaskInvalidCursor
+	}
+	if c.SortBy != sortBy || c.SortOrder != sortOrder || c.TaskID == 0 {
+		return nil, task.ErrTaskInvalidCursor
+	}
+	return &c, nil
+}
+
+func userTasksScope(userID uint) string {
+	return "user:" + strconv.FormatUint(uint64(userID), 10)
+}
+
+func teamTasksScope(teamID uint) string {
+	return "team:" + strconv.FormatUint(uint64(teamID), 10)
+}
+
+// teamIDFromScope возвращает ID команды для области кэша, созданной teamTasksScope.
+func teamIDFromScope(scope string) (uint, bool) {
+	idStr, ok := strings.CutPrefix(scope, "team:")
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// tasksCacheVersionPrefix возвращает префикс ключа страницы с текущими версиями кэша:
+// версия пользователя для личных выборок, версия команды - для командных (глобальное представление не кэшируется).
+func (uc *TaskUseCase) tasksCacheVersionPrefix(userID uint, teamID *uint) (string, error) {
+	scope := userTasksScope(userID)
+	if teamID != nil {
+		scope = teamTasksScope(*teamID)
+	}
+	version, err := uc.repo.GetTasksListVersion(scope)
+	if err != nil {
+		return "", err
+	}
+	return scope + ":v" + strconv.FormatInt(version, 10), nil
+}
+
+// joinSortedIDs формирует часть ключа кэша из набора ID, не зависящую от порядка в запросе.
+func joinSortedIDs(ids []uint) string {
+	sorted := append([]uint(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	parts := make([]string, len(sorted))
+	for i, id := range sorted {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"server/internal/modules/task"
+	"testing"
+)
+
+func TestTaskCursorRoundTrip(t *testing.T) {
+	value := "2026-10-19T08:00:00.123456Z"
+	tests := []struct {
+		name   string
+		cursor task.TaskCursor
+	}{
+		{name: "with value", cursor: task.TaskCursor{SortBy: task.FieldCreatedAt, SortOrder: task.SortDirectionDesc, Value: &value, TaskID: 42}},
+		{name: "null deadline", cursor: task.TaskCursor{SortBy: task.FieldDeadline, SortOrder: task.SortDirectionAsc, TaskID: 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeTaskCursor(encodeTaskCursor(tt.cursor), tt.cursor.SortBy, tt.cursor.SortOrder)
+			if err != nil {
+				t.Fatalf("decodeTaskCursor() error = %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.cursor) {
+				t.Errorf("decodeTaskCursor() = %+v, want %+v", *got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeTaskCursorInvalid(t *testing.T) {
+	valid := encodeTaskCursor(task.TaskCursor{SortBy: task.FieldTitle, SortOrder: task.SortDirectionAsc, TaskID: 3})
+
+	tests := []struct {
+		name      string
+		value     string
+		sortBy    task.TaskSortableField
+		sortOrder task.SortDirection
+	}{
+		{name: "other sort field", value: valid, sortBy: task.FieldPriority, sortOrder: task.SortDirectionAsc},
+		{name: "other sort order", value: valid, sortBy: task.FieldTitle, sortOrder: task.SortDirectionDesc},
+		{name: "not base64", value: "not a cursor!", sortBy: task.FieldTitle, sortOrder: task.SortDirectionAsc},
+		{name: "not json", value: base64.RawURLEncoding.EncodeToString([]byte("{broken")), sortBy: task.FieldTitle, sortOrder: task.SortDirectionAsc},
+		{name: "missing task id", value: encodeTaskCursor(task.TaskCursor{SortBy: task.FieldTitle, SortOrder: task.SortDirectionAsc}), sortBy: task.FieldTitle, sortOrder: task.SortDirectionAsc},
+		{name: "empty", value: "", sortBy: task.FieldTitle, sortOrder: task.SortDirectionAsc},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeTaskCursor(tt.value, tt.sortBy, tt.sortOrder)
+			if !errors.Is(err, task.ErrTaskInvalidCursor) {
+				t.Fatalf("decodeTaskCursor() error = %v, want %v", err, task.ErrTaskInvalidCursor)
+			}
+			if got != nil {
+				t.Errorf("decodeTaskCursor() = %+v, want nil", got)
+			}
+		})
+	}
+}
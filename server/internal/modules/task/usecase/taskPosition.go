@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"errors"
+	"log/slog"
+	"server/internal/modules/task"
+)
+
+// minPositionGap - минимальный зазор между соседями; при меньшем колонка перенумеровывается.
+const minPositionGap = 1e-6
+
+// endOfColumnPosition возвращает позицию для задачи, добавляемой в конец своей колонки.
+func (uc *TaskUseCase) endOfColumnPosition(t *task.Task) (float64, error) {
+	maxPosition, err := uc.repo.GetMaxTaskPosition(task.ScopeOfTask(t), t.TaskID)
+	if err != nil {
+		return 0, err
+	}
+	if maxPosition == nil {
+		return task.PositionStep, nil
+	}
+	return *maxPosition + task.PositionStep, nil
+}
+
+// getPositionAnchor загружает соседнюю задачу и проверяет, что она находится в той же колонке.
+func (uc *TaskUseCase) getPositionAnchor(t *task.Task, anchorID uint) (*task.Task, error) {
+	if anchorID == t.TaskID {
+		return nil, task.ErrTaskInvalidInput
+	}
+	anchor, err := uc.repo.GetTaskByIDIncludingDeleted(anchorID)
+	if err != nil {
+		return nil, err
+	}
+	if anchor.IsDeleted || !task.ScopeOfTask(anchor).Equal(task.ScopeOfTask(t)) {
+		return nil, task.ErrTaskInvalidInput
+	}
+	return anchor, nil
+}
+
+// computeMovePosition вычисляет позицию между соседями. Второй результат сообщает, что зазор исчерпан.
+func (uc *TaskUseCase) computeMovePosition(t *task.Task, req task.MoveTaskRequest) (float64, bool, error) {
+	scope := task.ScopeOfTask(t)
+
+	var lower, upper *float64
+	switch {
+	case req.AfterTaskID != nil:
+		anchor, err := uc.getPositionAnchor(t, *req.AfterTaskID)
+		if err != nil {
+			return 0, false, err
+		}
+		lower = &anchor.Position
+		if upper, err = uc.repo.GetAdjacentTaskPosition(scope, anchor.Position, anchor.TaskID, t.TaskID, true); err != nil {
+			return 0, false, err
+		}
+	case req.BeforeTaskID != nil:
+		anchor, err := uc.getPositionAnchor(t, *req.BeforeTaskID)
+		if err != nil {
+			return 0, false, err
+		}
+		upper = &anchor.Position
+		if lower, err = uc.repo.GetAdjacentTaskPosition(scope, anchor.Position, anchor.TaskID, t.TaskID, false); err != nil {
+			return 0, false, err
+		}
+	default:
+		position, err := uc.endOfColumnPosition(t)
+		return position, false, err
+	}
+
+	switch {
+	case lower != nil && upper != nil:
+		if *upper-*lower < minPositionGap {
+			return 0, true, nil
+		}
+		return (*lower + *upper) / 2, false, nil
+	case lower != nil:
+		return *lower + task.PositionStep, false, nil
+	default:
+		return *upper - task.PositionStep, false, nil
+	}
+}
+
+func (uc *TaskUseCase) MoveTask(taskID uint, userID uint, req task.MoveTaskRequest) (*task.TaskResponse, error) {
+	op := "TaskUseCase.MoveTask"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)), slog.Uint64("userID", uint64(userID)))
+
+	existingTask, err := uc.repo.GetTaskByID(taskID, userID)
+	if err != nil {
+		if errors.Is(err, task.ErrTaskNotFound) {
+			return nil, task.ErrTaskNotFound
+		}
+		log.Error("failed to get task for move", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	// Перемещение по доске приравнивается к смене статуса
+	if errAccess := uc.checkTaskEditAccess(existingTask, userID, true); errAccess != nil {
+		return nil, errAccess
+	}
+
+	taskBefore := *existingTask
+	if req.Status != nil && *req.Status != existingTask.Status {
+		if err := uc.applyTaskStatus(existingTask, *req.Status, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	position, exhausted, err := uc.computeMovePosition(existingTask, req)
+	if err == nil && exhausted {
+		log.Info("position gap exhausted, rebalancing column")
+		if err = uc.repo.RebalanceTaskPositions(task.ScopeOfTask(existingTask)); err == nil {
+			position, exhausted, err = uc.computeMovePosition(existingTask, req)
+			if err == nil && exhausted {
+				err = task.ErrTaskInternal
+			}
+		}
+	}
+	if err != nil {
+		if errors.Is(err, task.ErrTaskNotFound) || errors.Is(err, task.ErrTaskInvalidInput) {
+			log.Warn("invalid move anchor", "error", err)
+			return nil, task.ErrTaskInvalidInput
+		}
+		log.Error("failed to compute task position", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	existingTask.Position = position
+
+	updatedTaskModel, err := uc.repo.UpdateTask(existingTask)
+	if err != nil {
+		log.Error("failed to update task position in repo", "error", err)
+		return nil, err
+	}
+	uc.recordTaskEvents(diffTaskEvents(&taskBefore, updatedTaskModel, userID)...)
+
+	_ = uc.repo.DeleteTaskCache(taskID)
+	uc.invalidateTaskListsCache(userID, updatedTaskModel.TeamID)
+
+	log.Info("task moved successfully", slog.String("status", updatedTaskModel.Status), slog.Float64("position", position))
+	return uc.buildTaskResponse(updatedTaskModel, userID)
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"server/internal/modules/task"
+	"testing"
+)
+
+// positionRepo - колонка задач в памяти: позиции по ID, остальные методы репозитория не вызываются.
+type positionRepo struct {
+	task.Repo
+	tasks map[uint]*task.Task
+}
+
+func (r *positionRepo) GetTaskByIDIncludingDeleted(taskID uint) (*task.Task, error) {
+	t, ok := r.tasks[taskID]
+	if !ok {
+		return nil, task.ErrTaskNotFound
+	}
+	return t, nil
+}
+
+func (r *positionRepo) GetMaxTaskPosition(scope task.TaskPositionScope, excludeTaskID uint) (*float64, error) {
+	var max *float64
+	for _, t := range r.tasks {
+		if t.TaskID == excludeTaskID || !task.ScopeOfTask(t).Equal(scope) {
+			continue
+		}
+		if max == nil || t.Position > *max {
+			position := t.Position
+			max = &position
+		}
+	}
+	return max, nil
+}
+
+// GetAdjacentTaskPosition сравнивает пары (position, task_id), как запрос в БД.
+func (r *positionRepo) GetAdjacentTaskPosition(scope task.TaskPositionScope, position float64, anchorTaskID, excludeTaskID uint, after bool) (*float64, error) {
+	var best *task.Task
+	for _, t := range r.tasks {
+		if t.TaskID == excludeTaskID || !task.ScopeOfTask(t).Equal(scope) {
+			continue
+		}
+		isAfter := t.Position > position || (t.Position == position && t.TaskID > anchorTaskID)
+		isBefore := t.Position < position || (t.Position == position && t.TaskID < anchorTaskID)
+		if after && isAfter && (best == nil || t.Position < best.Position || (t.Position == best.Position && t.TaskID < best.TaskID)) {
+			best = t
+		}
+		if !after && isBefore && (best == nil || t.Position > best.Position || (t.Position == best.Position && t.TaskID > best.TaskID)) {
+			best = t
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+	return &best.Position, nil
+}
+
+func TestComputeMovePosition(t *testing.T) {
+	const ownerID = 1
+	column := func(positions map[uint]float64) map[uint]*task.Task {
+		tasks := make(map[uint]*task.Task, len(positions))
+		for id, position := range positions {
+			tasks[id] = &task.Task{TaskID: id, CreatedByUserID: ownerID, Status: "todo", Position: position}
+		}
+		return tasks
+	}
+	id := func(v uint) *uint { return &v }
+
+	tests := []struct {
+		name          string
+		positions     map[uint]float64
+		moving        uint
+		req           task.MoveTaskRequest
+		want          float64
+		wantExhausted bool
+		wantErr       error
+	}{
+		{
+			name:      "end of column without anchor",
+			positions: map[uint]float64{1: 1024, 2: 2048, 10: 512},
+			moving:    10,
+			want:      2048 + task.PositionStep,
+		},
+		{
+			name:      "end of empty column",
+			positions: map[uint]float64{10: 512},
+			moving:    10,
+			want:      task.PositionStep,
+		},
+		{
+			name:      "between anchor and its next neighbour",
+			positions: map[uint]float64{1: 1024, 2: 2048, 10: 4096},
+			moving:    10,
+			req:       task.MoveTaskRequest{AfterTaskID: id(1)},
+			want:      1536,
+		},
+		{
+			name:      "after the last task",
+			positions: map[uint]float64{1: 1024, 2: 2048, 10: 512},
+			moving:    10,
+			req:       task.MoveTaskRequest{AfterTaskID: id(2)},
+			want:      2048 + task.PositionStep,
+		},
+		{
+			name:      "before the first task",
+			positions: map[uint]float64{1: 1024, 2: 2048, 10: 4096},
+			moving:    10,
+			req:       task.MoveTaskRequest{BeforeTaskID: id(1)},
+			want:      1024 - task.PositionStep,
+		},
+		{
+			name:      "between anchor and its previous neighbour",
+			positions: map[uint]float64{1: 1024, 2: 2048, 10: 4096},
+			moving:    10,
+			req:       task.MoveTaskRequest{BeforeTaskID: id(2)},
+			want:      1536,
+		},
+		{
+			name:          "tied positions require rebalance",
+			positions:     map[uint]float64{1: 1024, 2: 1024, 10: 4096},
+			moving:        10,
+			req:           task.MoveTaskRequest{AfterTaskID: id(1)},
+			wantExhausted: true,
+		},
+		{
+			name:          "tied positions before anchor require rebalance",
+			positions:     map[uint]float64{1: 1024, 2: 1024, 10: 4096},
+			moving:        10,
+			req:           task.MoveTaskRequest{BeforeTaskID: id(2)},
+			wantExhausted: true,
+		},
+		{
+			name:          "gap below minimum requires rebalance",
+			positions:     map[uint]float64{1: 1024, 2: 1024 + minPositionGap/2, 10: 4096},
+			moving:        10,
+			req:           task.MoveTaskRequest{AfterTaskID: id(1)},
+			wantExhausted: true,
+		},
+		{
+			name:      "moving task is not its own neighbour",
+			positions: map[uint]float64{1: 1024, 10: 1536, 2: 2048},
+			moving:    10,
+			req:       task.MoveTaskRequest{AfterTaskID: id(1)},
+			want:      1536,
+		},
+		{
+			name:      "anchor cannot be the moving task",
+			positions: map[uint]float64{1: 1024, 10: 2048},
+			moving:    10,
+			req:       task.MoveTaskRequest{AfterTaskID: id(10)},
+			wantErr:   task.ErrTaskInvalidInput,
+		},
+		{
+			name:      "unknown anchor",
+			positions: map[uint]float64{1: 1024, 10: 2048},
+			moving:    10,
+			req:       task.MoveTaskRequest{BeforeTaskID: id(99)},
+			wantErr:   task.ErrTaskNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &positionRepo{tasks: column(tt.positions)}
+			uc := &TaskUseCase{repo: repo, log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+			got, exhausted, err := uc.computeMovePosition(repo.tasks[tt.moving], tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("computeMovePosition() error = %v, want %v", err, tt.wantErr)
+			}
+			if exhausted != tt.wantExhausted {
+				t.Fatalf("computeMovePosition() exhausted = %v, want %v", exhausted, tt.wantExhausted)
+			}
+			if err == nil && !exhausted && got != tt.want {
+				t.Errorf("computeMovePosition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeMovePositionAnchorInOtherColumn(t *testing.T) {
+	teamID := uint(3)
+	repo := &positionRepo{tasks: map[uint]*task.Task{
+		1:  {TaskID: 1, CreatedByUserID: 1, Status: "done", Position: 1024},
+		2:  {TaskID: 2, CreatedByUserID: 1, TeamID: &teamID, Status: "todo", Position: 1024},
+		10: {TaskID: 10, CreatedByUserID: 1, Status: "todo", Position: 2048},
+	}}
+	uc := &TaskUseCase{repo: repo, log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	for _, anchorID := range []uint{1, 2} {
+		anchor := anchorID
+		if _, _, err := uc.computeMovePosition(repo.tasks[10], task.MoveTaskRequest{AfterTaskID: &anchor}); !errors.Is(err, task.ErrTaskInvalidInput) {
+			t.Errorf("anchor %d: error = %v, want %v", anchorID, err, task.ErrTaskInvalidInput)
+		}
+	}
+}
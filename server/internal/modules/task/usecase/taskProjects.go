@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"errors"
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+)
+
+// resolveTaskProject проверяет проект, в который помещается задача: только задача команды
+// и только проект этой же команды. projectID = 0 убирает задачу из проекта (возвращается nil).
+func (uc *TaskUseCase) resolveTaskProject(teamID *uint, projectID uint) (*uint, error) {
+	if projectID == 0 {
+		return nil, nil
+	}
+	if teamID == nil {
+		return nil, task.ErrTaskProjectInvalid
+	}
+	if _, err := uc.teamService.GetTeamProject(*teamID, projectID); err != nil {
+		if errors.Is(err, team.ErrProjectNotFound) {
+			return nil, task.ErrTaskProjectInvalid
+		}
+		uc.log.Error("failed to get team project", "error", err, "teamID", *teamID, "projectID", projectID)
+		return nil, task.ErrTaskInternal
+	}
+	return &projectID, nil
+}
+
+// isTaskProjectLead сообщает, руководит ли пользователь проектом задачи. Руководитель может менять
+// задачи своего проекта без прав команды, пока остается участником команды (но не гостем).
+func (uc *TaskUseCase) isTaskProjectLead(t *task.Task, userID uint) (bool, error) {
+	if t.TeamID == nil || t.ProjectID == nil {
+		return false, nil
+	}
+	project, err := uc.teamService.GetTeamProject(*t.TeamID, *t.ProjectID)
+	if errors.Is(err, team.ErrProjectNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if project.LeadUserID == nil || *project.LeadUserID != userID {
+		return false, nil
+	}
+	role, err := uc.teamService.GetUserRoleInTeam(userID, *t.TeamID)
+	if err != nil {
+		return false, err
+	}
+	return role != nil && *role != team.RoleGuest, nil
+}
+
+// checkTaskProjectChange проверяет перенос задачи в другой проект: у пользователя должно быть право
+// на изменение задачи и в новом проекте (руководитель одного проекта не переносит задачи в чужой).
+func (uc *TaskUseCase) checkTaskProjectChange(t *task.Task, userID uint, newProjectID *uint) error {
+	if newProjectID == nil || (t.ProjectID != nil && *t.ProjectID == *newProjectID) {
+		return nil
+	}
+	target := *t
+	target.ProjectID = newProjectID
+	return uc.checkTaskEditAccess(&target, userID, false)
+}
@@ -0,0 +1,349 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"server/internal/modules/tag"
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// templateVariableRe - переменная шаблона: {{name}}, пробелы внутри скобок допускаются
+var templateVariableRe = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// templateTexts возвращает все тексты шаблона, в которых могут встречаться переменные.
+func templateTexts(c task.TaskTemplateContent) []string {
+	texts := append([]string{c.Title}, c.Checklist...)
+	if c.Description != nil {
+		texts = append(texts, *c.Description)
+	}
+	for _, sub := range c.Subtasks {
+		texts = append(texts, sub.Title)
+		texts = append(texts, sub.Checklist...)
+		if sub.Description != nil {
+			texts = append(texts, *sub.Description)
+		}
+	}
+	return texts
+}
+
+// templateVariables возвращает имена переменных шаблона в порядке первого появления.
+func templateVariables(c task.TaskTemplateContent) []string {
+	variables := []string{}
+	seen := make(map[string]bool)
+	for _, text := range templateTexts(c) {
+		for _, m := range templateVariableRe.FindAllStringSubmatch(text, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				variables = append(variables, m[1])
+			}
+		}
+	}
+	return variables
+}
+
+// renderTemplateText подставляет значения переменных. Переменные без значения не заменяются.
+func renderTemplateText(text string, values map[string]string) string {
+	return templateVariableRe.ReplaceAllStringFunc(text, func(match string) string {
+		name := templateVariableRe.FindStringSubmatch(match)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// templateTaskRequest собирает запрос на создание задачи из текста шаблона.
+// Чек-лист добавляется в конец описания списком Markdown.
+func templateTaskRequest(title string, description *string, priority *int, checklist []string, deadlineOffsetDays *int,
+	values map[string]string, startDate time.Time) (task.CreateTaskRequest, error) {
+	req := task.CreateTaskRequest{
+		Title:    strings.TrimSpace(renderTemplateText(title, values)),
+		Priority: priority,
+	}
+	if req.Title == "" || utf8.RuneCountInString(req.Title) > 255 {
+		return req, fmt.Errorf("%w: rendered title must be 1-255 characters", task.ErrTaskTemplateInvalid)
+	}
+
+	var body strings.Builder
+	if description != nil {
+		body.WriteString(renderTemplateText(*description, values))
+	}
+	if len(checklist) > 0 {
+		if body.Len() > 0 {
+			body.WriteString("\n\n")
+		}
+		for i, item := range checklist {
+			if i > 0 {
+				body.WriteString("\n")
+			}
+			body.WriteString("- [ ] " + renderTemplateText(item, values))
+		}
+	}
+	if body.Len() > 0 {
+		if utf8.RuneCountInString(body.String()) > 65535 {
+			return req, fmt.Errorf("%w: rendered description is too long", task.ErrTaskTemplateInvalid)
+		}
+		text := body.String()
+		req.Description = &text
+	}
+
+	if deadlineOffsetDays != nil {
+		// Дедлайн - конец дня, отстоящего от даты начала на заданное число дней
+		deadline := time.Date(startDate.Year(), startDate.Month(), startDate.Day()+*deadlineOffsetDays, 23, 59, 59, 0, startDate.Location())
+		req.Deadline = &deadline
+	}
+	return req, nil
+}
+
+// getTaskTemplate загружает шаблон и права пользователя на него. Недоступный шаблон выглядит как несуществующий.
+func (uc *TaskUseCase) getTaskTemplate(templateID uint, userID uint, log *slog.Logger) (*task.TaskTemplate, bool, error) {
+	tmpl, err := uc.repo.GetTaskTemplateByID(templateID)
+	if err != nil {
+		if errors.Is(err, task.ErrTaskTemplateNotFound) {
+			return nil, false, err
+		}
+		log.Error("failed to get task template", "error", err)
+		return nil, false, task.ErrTaskInternal
+	}
+	canView, canEdit, err := uc.sharedItemAccess(tmpl.OwnerUserID, tmpl.TeamID, userID, team.PermTemplateManage)
+	if err != nil {
+		log.Error("failed to check task template access", "error", err)
+		return nil, false, task.ErrTaskInternal
+	}
+	if !canView {
+		return nil, false, task.ErrTaskTemplateNotFound
+	}
+	return tmpl, canEdit, nil
+}
+
+// getEditableTaskTemplate загружает шаблон и проверяет право на его изменение.
+func (uc *TaskUseCase) getEditableTaskTemplate(templateID uint, userID uint, log *slog.Logger) (*task.TaskTemplate, error) {
+	tmpl, canEdit, err := uc.getTaskTemplate(templateID, userID, log)
+	if err != nil {
+		return nil, err
+	}
+	if !canEdit {
+		return nil, uc.teamEditDenied(tmpl.TeamID)
+	}
+	return tmpl, nil
+}
+
+func (uc *TaskUseCase) GetTaskTemplates(userID uint, teamID *uint) ([]*task.TaskTemplateResponse, error) {
+	op := "TaskUseCase.GetTaskTemplates"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	if teamID != nil {
+		isMember, err := uc.teamService.IsUserMember(userID, *teamID)
+		if err != nil {
+			log.Error("failed to check team membership for task templates", "error", err)
+			return nil, task.ErrTaskInternal
+		}
+		if !isMember {
+			return nil, task.ErrTaskAccessDenied
+		}
+	}
+
+	templates, err := uc.repo.GetTaskTemplates(userID, teamID)
+	if err != nil {
+		log.Error("failed to get task templates", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	// Право в каждой команде проверяется один раз
+	editableTeams := make(map[uint]bool)
+	responses := make([]*task.TaskTemplateResponse, 0, len(templates))
+	for _, tmpl := range templates {
+		canEdit := tmpl.OwnerUserID == userID
+		if tmpl.TeamID != nil {
+			editable, known := editableTeams[*tmpl.TeamID]
+			if !known {
+				var errPerm error
+				editable, errPerm = uc.canManageTeamItems(userID, *tmpl.TeamID, team.PermTemplateManage)
+				if errPerm != nil {
+					log.Warn("failed to check permission for task template, skipping", "templateID", tmpl.TemplateID, "error", errPerm)
+					continue
+				}
+				editableTeams[*tmpl.TeamID] = editable
+			}
+			canEdit = editable
+		}
+		responses = append(responses, task.ToTaskTemplateResponse(tmpl, templateVariables(tmpl.Content), canEdit))
+	}
+	return responses, nil
+}
+
+func (uc *TaskUseCase) GetTaskTemplate(templateID uint, userID uint) (*task.TaskTemplateResponse, error) {
+	op := "TaskUseCase.GetTaskTemplate"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("templateID", uint64(templateID)), slog.Uint64("userID", uint64(userID)))
+
+	tmpl, canEdit, err := uc.getTaskTemplate(templateID, userID, log)
+	if err != nil {
+		return nil, err
+	}
+	return task.ToTaskTemplateResponse(tmpl, templateVariables(tmpl.Content), canEdit), nil
+}
+
+func (uc *TaskUseCase) CreateTaskTemplate(userID uint, req task.CreateTaskTemplateRequest) (*task.TaskTemplateResponse, error) {
+	op := "TaskUseCase.CreateTaskTemplate"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	if req.TeamID != nil {
+		canManage, err := uc.teamService.HasTeamPermission(userID, *req.TeamID, team.PermTemplateManage)
+		if err != nil {
+			log.Error("failed to check permission for task template", "error", err)
+			return nil, teamCheckError(err)
+		}
+		if !canManage {
+			log.Warn("user cannot create team task template", "teamID", *req.TeamID)
+			return nil, task.ErrTaskAccessDenied
+		}
+	}
+
+	tmpl, err := uc.repo.CreateTaskTemplate(&task.TaskTemplate{
+		OwnerUserID: userID,
+		TeamID:      req.TeamID,
+		Name:        req.Name,
+		Content:     req.Content,
+	})
+	if err != nil {
+		log.Error("failed to create task template", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Info("task template created", slog.Uint64("templateID", uint64(tmpl.TemplateID)))
+	return task.ToTaskTemplateResponse(tmpl, templateVariables(tmpl.Content), true), nil
+}
+
+func (uc *TaskUseCase) UpdateTaskTemplate(templateID uint, userID uint, req task.UpdateTaskTemplateRequest) (*task.TaskTemplateResponse, error) {
+	op := "TaskUseCase.UpdateTaskTemplate"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("templateID", uint64(templateID)), slog.Uint64("userID", uint64(userID)))
+
+	tmpl, err := uc.getEditableTaskTemplate(templateID, userID, log)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		tmpl.Name = *req.Name
+	}
+	if req.Content != nil {
+		tmpl.Content = *req.Content
+	}
+
+	updated, err := uc.repo.UpdateTaskTemplate(tmpl)
+	if err != nil {
+		log.Error("failed to update task template", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Info("task template updated")
+	return task.ToTaskTemplateResponse(updated, templateVariables(updated.Content), true), nil
+}
+
+func (uc *TaskUseCase) DeleteTaskTemplate(templateID uint, userID uint) error {
+	op := "TaskUseCase.DeleteTaskTemplate"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("templateID", uint64(templateID)), slog.Uint64("userID", uint64(userID)))
+
+	if _, err := uc.getEditableTaskTemplate(templateID, userID, log); err != nil {
+		return err
+	}
+	if err := uc.repo.DeleteTaskTemplate(templateID); err != nil {
+		if errors.Is(err, task.ErrTaskTemplateNotFound) {
+			return err
+		}
+		log.Error("failed to delete task template", "error", err)
+		return task.ErrTaskInternal
+	}
+
+	log.Info("task template deleted")
+	return nil
+}
+
+// InstantiateTaskTemplate создает задачу и подзадачи шаблона в одной транзакции: при ошибке не создается ничего.
+// Задачи создаются в области шаблона (личные или командные) с правами пользователя, как через CreateTask.
+func (uc *TaskUseCase) InstantiateTaskTemplate(templateID uint, userID uint, req task.InstantiateTaskTemplateRequest) (*task.InstantiateTaskTemplateResponse, error) {
+	op := "TaskUseCase.InstantiateTaskTemplate"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("templateID", uint64(templateID)), slog.Uint64("userID", uint64(userID)))
+
+	tmpl, _, err := uc.getTaskTemplate(templateID, userID, log)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, name := range templateVariables(tmpl.Content) {
+		if _, ok := req.Variables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		log.Warn("template variables are not set", "missing", missing)
+		return nil, fmt.Errorf("%w: missing variables: %s", task.ErrTaskTemplateInvalid, strings.Join(missing, ", "))
+	}
+
+	startDate := time.Now()
+	if req.StartDate != nil {
+		startDate = *req.StartDate
+	}
+	content := tmpl.Content
+	mainReq, err := templateTaskRequest(content.Title, content.Description, content.Priority, content.Checklist,
+		content.DeadlineOffsetDays, req.Variables, startDate)
+	if err != nil {
+		return nil, err
+	}
+	requests := []task.CreateTaskRequest{mainReq}
+	for _, sub := range content.Subtasks {
+		subReq, err := templateTaskRequest(sub.Title, sub.Description, sub.Priority, sub.Checklist,
+			sub.DeadlineOffsetDays, req.Variables, startDate)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, subReq)
+	}
+	for i := range requests {
+		requests[i].TeamID = tmpl.TeamID
+		requests[i].AssignedToUserID = req.AssignedToUserID
+		requests[i].UserTagIDs = content.UserTagIDs
+		requests[i].TeamTagIDs = content.TeamTagIDs
+	}
+
+	if uc.txRunner == nil {
+		log.Error("transaction runner is not configured")
+		return nil, task.ErrTaskInternal
+	}
+	batch := make(map[string]struct{})
+	var notifications []*taskNotification
+	var events []*task.TaskEvent
+	var created []*task.TaskResponse
+	errTx := uc.txRunner.RunInTx(func(taskRepo task.Repo, tagRepo tag.Repo) error {
+		worker := *uc
+		worker.repo = taskRepo
+		worker.tagRepo = tagRepo
+		worker.listCacheBatch = batch
+		worker.notificationBatch = &notifications
+		worker.eventBatch = &events
+		for _, createReq := range requests {
+			resp, err := worker.CreateTask(userID, createReq)
+			if err != nil {
+				return err
+			}
+			created = append(created, resp)
+		}
+		return nil
+	})
+	if errTx != nil {
+		log.Warn("template instantiation rolled back", "error", errTx)
+		return nil, errTx
+	}
+	uc.flushEventBatch(events)
+	uc.flushListCacheBatch(batch)
+	uc.flushNotificationBatch(notifications)
+
+	log.Info("task template instantiated", slog.Uint64("taskID", uint64(created[0].TaskID)), slog.Int("subtasks", len(created)-1))
+	return &task.InstantiateTaskTemplateResponse{Task: created[0], Subtasks: created[1:]}, nil
+}
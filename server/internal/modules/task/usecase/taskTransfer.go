@@ -0,0 +1,444 @@
+package usecase
+
+import (
+	"errors"
+	"log/slog"
+	"server/internal/modules/tag"
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// transferTags - теги задачи, сопоставленные с тегами целевого пространства по имени.
+// Недостающие теги (missing) создаются в транзакции переноса, чтобы при откате не оставалось лишних тегов.
+type transferTags struct {
+	userTagIDs []uint
+	teamTagIDs []uint
+	missing    []transferTag
+	created    []string
+	dropped    []string
+}
+
+// transferTag - тег исходного пространства, которого нет в целевом.
+type transferTag struct {
+	name  string
+	color *string
+}
+
+// TransferTask переносит задачу между личным пространством и командами или создает ее копию там.
+// Перенос требует права на удаление задачи в исходном пространстве, копирование - только доступа на чтение;
+// в целевом пространстве пользователь должен иметь право создавать задачи.
+// При переносе строка задачи сохраняется, поэтому история и учтенное время остаются при ней.
+func (uc *TaskUseCase) TransferTask(taskID uint, userID uint, req task.TransferTaskRequest) (*task.TransferTaskResponse, error) {
+	op := "TaskUseCase.TransferTask"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)), slog.Uint64("userID", uint64(userID)),
+		slog.String("mode", string(req.Mode)))
+
+	source, err := uc.getAccessibleTask(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	move := req.Mode == task.TaskTransferMove
+	if move {
+		if sameTaskSpace(source, req.TeamID, userID) {
+			log.Warn("task is already in the target space")
+			return nil, task.ErrTaskInvalidInput
+		}
+		if err := uc.checkTaskDeleteAccess(source, userID); err != nil {
+			return nil, err
+		}
+	}
+	if req.TeamID != nil {
+		canCreate, err := uc.teamService.CanUserCreateTeamTask(userID, *req.TeamID)
+		if err != nil {
+			log.Error("failed to check task creation permission in target team", "error", err)
+			return nil, teamCheckError(err)
+		}
+		if !canCreate {
+			log.Warn("user cannot create tasks in target team", "teamID", *req.TeamID)
+			return nil, task.ErrTaskAccessDenied
+		}
+	}
+
+	target := *source
+	target.TeamID = req.TeamID
+	if target.TeamID == nil {
+		target.CreatedByUserID = userID
+	}
+	if !sameTaskSpace(source, req.TeamID, userID) {
+		target.ProjectID = nil // Проекты и спринты принадлежат команде и в другое пространство не переносятся
+		target.SprintID = nil
+	}
+	if err := uc.mapTransferStatus(&target); err != nil {
+		log.Error("failed to map task status to target workflow", "error", err)
+		return nil, err
+	}
+	currentAssignees, err := uc.taskAssigneeIDs(source)
+	if err != nil {
+		log.Error("failed to get task assignees", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	assignees, err := uc.transferAssignees(&target, userID, currentAssignees, req.AssigneeIDs)
+	if err != nil {
+		log.Warn("assignees cannot be kept in target space", "error", err)
+		return nil, err
+	}
+	tags, err := uc.mapTransferTags(source, target.TeamID, userID, req.CreateMissingTags)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags.missing) > 0 && target.TeamID != nil {
+		canManageTags, err := uc.teamService.HasTeamPermission(userID, *target.TeamID, team.PermTagManage)
+		if err != nil {
+			log.Error("failed to check tag permission in target team", "error", err)
+			return nil, teamCheckError(err)
+		}
+		if !canManageTags {
+			log.Warn("user cannot create missing tags in target team", "teamID", *target.TeamID)
+			return nil, task.ErrTaskAccessDenied
+		}
+	}
+
+	if uc.txRunner == nil {
+		log.Error("transaction runner is not configured")
+		return nil, task.ErrTaskInternal
+	}
+	batch := make(map[string]struct{})
+	var notifications []*taskNotification
+	var events []*task.TaskEvent
+	var result *task.TaskResponse
+	errTx := uc.txRunner.RunInTx(func(taskRepo task.Repo, tagRepo tag.Repo) error {
+		worker := *uc
+		worker.repo = taskRepo
+		worker.tagRepo = tagRepo
+		worker.listCacheBatch = batch
+		worker.notificationBatch = &notifications
+		worker.eventBatch = &events
+		if err := worker.createTransferTags(tags, target.TeamID, userID); err != nil {
+			return err
+		}
+		var err error
+		if move {
+			result, err = worker.moveTaskToSpace(source, &target, userID, &assigneeUpdate{before: currentAssignees, after: assignees}, tags)
+		} else {
+			result, err = worker.copyTaskToSpace(source, &target, userID, assignees, tags)
+		}
+		return err
+	})
+	if errTx != nil {
+		log.Warn("task transfer rolled back", "error", errTx)
+		_ = uc.repo.DeleteTaskCache(taskID)
+		return nil, errTx
+	}
+	uc.flushEventBatch(events)
+	uc.flushListCacheBatch(batch)
+	uc.flushNotificationBatch(notifications)
+
+	log.Info("task transferred", slog.Uint64("resultTaskID", uint64(result.TaskID)),
+		slog.Int("createdTags", len(tags.created)), slog.Int("droppedTags", len(tags.dropped)))
+	return &task.TransferTaskResponse{Task: result, CreatedTags: tags.created, DroppedTags: tags.dropped}, nil
+}
+
+// sameTaskSpace сообщает, что задача уже находится в целевом пространстве (личном - текущего пользователя).
+func sameTaskSpace(t *task.Task, teamID *uint, userID uint) bool {
+	if t.TeamID == nil || teamID == nil {
+		return t.TeamID == nil && teamID == nil && t.CreatedByUserID == userID
+	}
+	return *t.TeamID == *teamID
+}
+
+// mapTransferStatus подбирает статус в наборе целевого пространства: тот же ключ, иначе первый статус
+// той же категории, иначе начальный. Правила переходов при переносе не применяются.
+func (uc *TaskUseCase) mapTransferStatus(t *task.Task) error {
+	workflow, err := uc.getTaskWorkflow(t.TeamID)
+	if err != nil {
+		return task.ErrTaskInternal
+	}
+	status := workflow.Status(t.Status)
+	if status == nil {
+		for _, s := range workflow.Statuses {
+			if string(s.Category) == t.StatusCategory {
+				status = s
+				break
+			}
+		}
+	}
+	if status == nil {
+		status = workflow.InitialStatus()
+	}
+	if status == nil {
+		return task.ErrTaskUnknownStatus
+	}
+
+	t.Status = status.Key
+	t.StatusCategory = string(status.Category)
+	if status.Category == team.StatusCategoryDone {
+		if t.CompletedAt == nil {
+			now := time.Now()
+			t.CompletedAt = &now
+		}
+	} else {
+		t.CompletedAt = nil
+	}
+	return nil
+}
+
+// transferAssignees возвращает исполнителей в целевом пространстве. Явно переданный список проверяется целиком.
+// Без него в личное пространство переходит только сам пользователь (если был исполнителем),
+// а в команду - текущие исполнители, каждый из которых должен быть ее участником.
+func (uc *TaskUseCase) transferAssignees(target *task.Task, userID uint, current []uint, requested *[]uint) ([]uint, error) {
+	var candidates []uint
+	switch {
+	case requested != nil:
+		candidates = *requested
+	case target.TeamID == nil:
+		if containsUint(current, userID) {
+			candidates = []uint{userID}
+		}
+	default:
+		candidates = current
+	}
+
+	assignees := make([]uint, 0, len(candidates))
+	for _, id := range candidates {
+		if err := uc.checkTaskAssignee(target, id, userID); err != nil {
+			return nil, err
+		}
+		assignees = append(assignees, id)
+	}
+	return assignees, nil
+}
+
+// mapTransferTags сопоставляет теги задачи с тегами целевого пространства по имени без учета регистра.
+// Недостающие теги при createMissing отмечаются для создания (см. createTransferTags), иначе не переносятся.
+func (uc *TaskUseCase) mapTransferTags(source *task.Task, targetTeamID *uint, userID uint, createMissing bool) (*transferTags, error) {
+	op := "TaskUseCase.mapTransferTags"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(source.TaskID)))
+
+	result := &transferTags{created: []string{}, dropped: []string{}}
+	links, err := uc.tagRepo.GetTaskTags(source.TaskID)
+	if err != nil {
+		log.Error("failed to get task tags", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	var sourceUserTagIDs, sourceTeamTagIDs []uint
+	for _, link := range links {
+		if link.UserTagID != nil {
+			sourceUserTagIDs = append(sourceUserTagIDs, *link.UserTagID)
+		} else if link.TeamTagID != nil {
+			sourceTeamTagIDs = append(sourceTeamTagIDs, *link.TeamTagID)
+		}
+	}
+
+	var sourceTags []transferTag
+	if len(sourceUserTagIDs) > 0 {
+		userTags, err := uc.tagRepo.FindUserTagsByIDs(source.CreatedByUserID, sourceUserTagIDs)
+		if err != nil {
+			log.Error("failed to get source user tags", "error", err)
+			return nil, task.ErrTaskInternal
+		}
+		for _, t := range userTags {
+			sourceTags = append(sourceTags, transferTag{name: t.Name, color: t.Color})
+		}
+	}
+	if len(sourceTeamTagIDs) > 0 && source.TeamID != nil {
+		teamTags, err := uc.tagRepo.FindTeamTagsByIDs(*source.TeamID, sourceTeamTagIDs)
+		if err != nil {
+			log.Error("failed to get source team tags", "error", err)
+			return nil, task.ErrTaskInternal
+		}
+		for _, t := range teamTags {
+			sourceTags = append(sourceTags, transferTag{name: t.Name, color: t.Color})
+		}
+	}
+	if len(sourceTags) == 0 {
+		return result, nil
+	}
+
+	targetIDs := make(map[string]uint)
+	if targetTeamID == nil {
+		userTags, err := uc.tagRepo.GetUserTagsByOwnerID(userID)
+		if err != nil {
+			log.Error("failed to get target user tags", "error", err)
+			return nil, task.ErrTaskInternal
+		}
+		for _, t := range userTags {
+			targetIDs[strings.ToLower(t.Name)] = t.UserTagID
+		}
+	} else {
+		teamTags, err := uc.tagRepo.GetTeamTagsByTeamID(*targetTeamID)
+		if err != nil {
+			log.Error("failed to get target team tags", "error", err)
+			return nil, task.ErrTaskInternal
+		}
+		for _, t := range teamTags {
+			targetIDs[strings.ToLower(t.Name)] = t.TeamTagID
+		}
+	}
+
+	missing := make(map[string]struct{})
+	for _, st := range sourceTags {
+		key := strings.ToLower(st.name)
+		id, ok := targetIDs[key]
+		if !ok {
+			if !createMissing {
+				result.dropped = append(result.dropped, st.name)
+				continue
+			}
+			if _, seen := missing[key]; !seen {
+				missing[key] = struct{}{}
+				result.missing = append(result.missing, st)
+			}
+			continue
+		}
+		result.addTagID(targetTeamID, id)
+	}
+	return result, nil
+}
+
+// addTagID добавляет тег целевого пространства к тегам задачи без повторов.
+func (t *transferTags) addTagID(targetTeamID *uint, id uint) {
+	if targetTeamID == nil {
+		if !containsUint(t.userTagIDs, id) {
+			t.userTagIDs = append(t.userTagIDs, id)
+		}
+	} else if !containsUint(t.teamTagIDs, id) {
+		t.teamTagIDs = append(t.teamTagIDs, id)
+	}
+}
+
+// createTransferTags создает недостающие теги в целевом пространстве. Вызывается внутри транзакции переноса
+// после проверки прав, поэтому при откате переноса созданные теги тоже откатываются.
+func (uc *TaskUseCase) createTransferTags(tags *transferTags, targetTeamID *uint, userID uint) error {
+	op := "TaskUseCase.createTransferTags"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	for _, mt := range tags.missing {
+		var id uint
+		var err error
+		if targetTeamID == nil {
+			var created *tag.UserTag
+			created, err = uc.tagRepo.CreateUserTag(&tag.UserTag{OwnerUserID: userID, Name: mt.name, Color: mt.color})
+			if err == nil {
+				id = created.UserTagID
+			}
+		} else {
+			var created *tag.TeamTag
+			created, err = uc.tagRepo.CreateTeamTag(&tag.TeamTag{TeamID: *targetTeamID, Name: mt.name, Color: mt.color})
+			if err == nil {
+				id = created.TeamTagID
+			}
+		}
+		if err != nil {
+			log.Warn("failed to create missing tag in target space", "tag", mt.name, "error", err)
+			if errors.Is(err, tag.ErrUserTagNameConflict) || errors.Is(err, tag.ErrTeamTagNameConflict) {
+				return err
+			}
+			return task.ErrTaskInternal
+		}
+		tags.addTagID(targetTeamID, id)
+		tags.created = append(tags.created, mt.name)
+	}
+	return nil
+}
+
+// moveTaskToSpace записывает задачу в целевое пространство вместе с тегами и исполнителями.
+// Наблюдатели, потерявшие доступ к задаче, отписываются.
+func (uc *TaskUseCase) moveTaskToSpace(source, target *task.Task, userID uint, assignees *assigneeUpdate, tags *transferTags) (*task.TaskResponse, error) {
+	op := "TaskUseCase.moveTaskToSpace"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(source.TaskID)))
+
+	position, err := uc.endOfColumnPosition(target)
+	if err != nil {
+		log.Error("failed to get position in target column", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	target.Position = position
+	target.AssignedToUserID = nil
+	if len(assignees.after) > 0 {
+		target.AssignedToUserID = &assignees.after[0]
+	}
+
+	moved, err := uc.repo.UpdateTask(target)
+	if err != nil {
+		log.Error("failed to update task", "error", err)
+		return nil, err
+	}
+	assigneeEvents, err := uc.saveAssignees(moved, userID, assignees)
+	if err != nil {
+		log.Error("failed to save task assignees", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	if err := uc.removeWatchersWithoutAccess(moved, userID); err != nil {
+		log.Error("failed to remove watchers without access", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	events := []*task.TaskEvent{newTaskEvent(moved, userID, task.EventTaskMoved, "team_id",
+		optionalUintToStr(source.TeamID), optionalUintToStr(moved.TeamID))}
+	events = append(events, diffTaskEvents(source, moved, userID)...)
+	uc.recordTaskEvents(append(events, assigneeEvents...)...)
+
+	if err := uc.updateTaskTags(moved.TaskID, userID, moved.TeamID, tags.userTagIDs, tags.teamTagIDs); err != nil {
+		log.Error("failed to update task tags", "error", err)
+		return nil, err
+	}
+
+	_ = uc.repo.DeleteTaskCache(moved.TaskID)
+	uc.invalidateTaskListsCache(source.CreatedByUserID, source.TeamID)
+	uc.invalidateTaskListsCache(userID, moved.TeamID)
+	return uc.buildTaskResponse(moved, userID)
+}
+
+// removeWatchersWithoutAccess отписывает наблюдателей, которые не видят задачу в ее новом пространстве:
+// личную задачу видит только владелец, командную - участники команды.
+func (uc *TaskUseCase) removeWatchersWithoutAccess(t *task.Task, userID uint) error {
+	watchers, err := uc.repo.GetTaskWatcherIDs([]uint{t.TaskID})
+	if err != nil {
+		return err
+	}
+	for _, watcherID := range watchers[t.TaskID] {
+		keep := t.TeamID == nil && watcherID == userID
+		if t.TeamID != nil {
+			if keep, err = uc.teamService.IsUserTeamMemberWithUserID(*t.TeamID, watcherID); err != nil {
+				return err
+			}
+		}
+		if keep {
+			continue
+		}
+		if err := uc.repo.RemoveTaskWatcher(t.TaskID, watcherID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyTaskToSpace создает копию задачи в целевом пространстве тем же путем, что и обычное создание задачи.
+func (uc *TaskUseCase) copyTaskToSpace(source, target *task.Task, userID uint, assignees []uint, tags *transferTags) (*task.TaskResponse, error) {
+	priority := target.Priority
+	createReq := task.CreateTaskRequest{
+		Title:           target.Title,
+		Description:     target.Description,
+		Deadline:        target.Deadline,
+		Status:          &target.Status,
+		Priority:        &priority,
+		AssigneeIDs:     assignees,
+		TeamID:          target.TeamID,
+		ProjectID:       target.ProjectID,
+		SprintID:        target.SprintID,
+		EstimateMinutes: target.EstimateMinutes,
+		UserTagIDs:      tags.userTagIDs,
+		TeamTagIDs:      tags.teamTagIDs,
+	}
+	created, err := uc.CreateTask(userID, createReq)
+	if err != nil {
+		return nil, err
+	}
+	copyRef := &task.Task{TaskID: created.TaskID, TeamID: target.TeamID}
+	uc.recordTaskEvents(newTaskEvent(copyRef, userID, task.EventTaskCopied, "source_task_id",
+		nil, strPtr(strconv.FormatUint(uint64(source.TaskID), 10))))
+	return created, nil
+}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"server/internal/modules/tag"
 	"server/internal/modules/task" // Пакет task (entity, repo, errors)
+	"server/internal/modules/team"
 	"strconv"
 	"strings"
 	"time"
@@ -18,13 +19,26 @@ type TeamService interface {
 	IsUserMember(userID, teamID uint) (bool, error)
 	CanUserCreateTeamTask(userID, teamID uint) (bool, error)
 	CanUserEditTeamTaskDetails(userID, teamID uint) (bool, error)
-	CanUserChangeTeamTaskStatus(userID, teamID uint, taskAssignedToUserID *uint) (bool, error) // Обычно все участники могут менять статус
+	CanUserChangeTeamTaskStatus(userID, teamID uint, taskAssigneeIDs []uint) (bool, error) // task.status_any или task.status_assigned для исполнителя
 	CanUserDeleteTeamTask(userID, teamID uint, taskCreatorID uint) (bool, error)
-	IsUserTeamMemberWithUserID(teamID uint, targetUserID uint) (bool, error) // Проверяет, является ли targetUserID участником teamID
+	IsUserTeamMemberWithUserID(teamID uint, targetUserID uint) (bool, error)         // Проверяет, является ли targetUserID участником teamID
+	CanUserViewTeamActivity(userID, teamID uint) (bool, error)                       // Лента активности команды (право activity.view)
+	GetUserRoleInTeam(userID, teamID uint) (*team.TeamMemberRole, error)             // Роль нужна для правил переходов между статусами и ограничений гостя
+	HasTeamPermission(userID, teamID uint, permission team.Permission) (bool, error) // Проверка права по матрице прав команды (в архивной команде - team.ErrTeamArchived)
+	CheckTeamWritable(teamID uint) error                                             // team.ErrTeamArchived, если команда в архиве
+	GetGuestTaskTagScope(userID, teamID uint) (*uint, error)                         // Тег, которым ограничен просмотр задач гостем (nil - без ограничения)
+	GetTaskWorkflow(teamID uint) (*team.TaskWorkflow, error)                         // Набор статусов команды (или набор по умолчанию)
+	GetTeamProject(teamID, projectID uint) (*team.TeamProject, error)                // Проект команды (team.ErrProjectNotFound, если его нет)
+	InvalidateTeamStats(teamID uint)                                                 // Сброс кэша статистики команды после изменения ее задач
 }
 
 // --- Конец заглушки для TeamService ---
 
+// TxRunner выполняет функцию в одной транзакции БД, передавая привязанные к ней репозитории задач и тегов.
+type TxRunner interface {
+	RunInTx(fn func(taskRepo task.Repo, tagRepo tag.Repo) error) error
+}
+
 // TaskUseCase реализует интерфейс task.UseCase
 type TaskUseCase struct {
 	repo        task.Repo // Интерфейс репозитория (который включает TaskDb и TaskCache)
@@ -32,7 +46,17 @@ type TaskUseCase struct {
 	tagRepo     tag.Repo
 	log         *slog.Logger
 	teamService TeamService   // Зависимость от сервиса команд (пока заглушка)
+	txRunner    TxRunner      // Транзакции для массовых операций
 	cacheTTL    time.Duration // TTL для кэша списков задач
+	notifier    Notifier      // Уведомления исполнителям и наблюдателям (nil - отключены)
+
+	// listCacheBatch собирает области кэша списков вместо немедленной инвалидации (массовые операции)
+	listCacheBatch map[string]struct{}
+	// notificationBatch откладывает уведомления до завершения массовой операции
+	notificationBatch *[]*taskNotification
+	// eventBatch откладывает запись журнала до фиксации транзакции: ошибка записи внутри транзакции
+	// прервала бы ее целиком, хотя журнал не должен влиять на основную операцию
+	eventBatch *[]*task.TaskEvent
 }
 
 // NewTaskUseCase создает новый экземпляр TaskUseCase.
@@ -41,6 +65,8 @@ func NewTaskUseCase(
 	tagUC tag.UseCase,
 	tagRepo tag.Repo,
 	teamService TeamService,
+	txRunner TxRunner,
+	notifier Notifier,
 	log *slog.Logger,
 	cacheTTL time.Duration,
 ) task.UseCase {
@@ -53,14 +79,19 @@ func NewTaskUseCase(
 		tagRepo:     tagRepo,
 		log:         log,
 		teamService: teamService,
+		txRunner:    txRunner,
+		notifier:    notifier,
 		cacheTTL:    cacheTTL,
 	}
 }
 
 // generateTasksCacheKey создает ключ для кэширования списка задач
-func (uc *TaskUseCase) generateTasksCacheKey(userID uint, reqParams task.GetTasksRequest) string {
+func (uc *TaskUseCase) generateTasksCacheKey(userID uint, viewType task.GetTasksViewType, reqParams task.GetTasksRequest) string {
 	var keyParts []string
 	keyParts = append(keyParts, "tasks", "user", strconv.FormatUint(uint64(userID), 10))
+	if viewType != task.ViewTypeDefault {
+		keyParts = append(keyParts, "view", string(viewType)) // Разные представления возвращают разные наборы задач
+	}
 
 	if reqParams.TeamID != nil {
 		keyParts = append(keyParts, "team", strconv.FormatUint(uint64(*reqParams.TeamID), 10))
@@ -71,10 +102,19 @@ func (uc *TaskUseCase) generateTasksCacheKey(userID uint, reqParams task.GetTask
 	if reqParams.IsDeleted != nil && *reqParams.IsDeleted {
 		keyParts = append(keyParts, "deleted") // <<< ВАЖНО для корзины
 	}
+	if reqParams.ProjectID != nil {
+		keyParts = append(keyParts, "project", strconv.FormatUint(uint64(*reqParams.ProjectID), 10))
+	}
+	if reqParams.SprintID != nil {
+		keyParts = append(keyParts, "sprint", strconv.FormatUint(uint64(*reqParams.SprintID), 10))
+	}
 
 	if reqParams.Status != nil {
 		keyParts = append(keyParts, "status", *reqParams.Status)
 	}
+	if reqParams.StatusCategory != nil {
+		keyParts = append(keyParts, "scat", *reqParams.StatusCategory)
+	}
 	// ... остальная часть функции без изменений
 	if reqParams.Priority != nil {
 		keyParts = append(keyParts, "priority", strconv.Itoa(*reqParams.Priority))
@@ -83,10 +123,13 @@ func (uc *TaskUseCase) generateTasksCacheKey(userID uint, reqParams task.GetTask
 		keyParts = append(keyParts, "assignee", strconv.FormatUint(uint64(*reqParams.AssignedToUserID), 10))
 	}
 	if reqParams.DeadlineFrom != nil {
-		keyParts = append(keyParts, "dfrom", reqParams.DeadlineFrom.Format("20060102"))
+		keyParts = append(keyParts, "dfrom", reqParams.DeadlineFrom.UTC().Format(time.RFC3339Nano))
 	}
 	if reqParams.DeadlineTo != nil {
-		keyParts = append(keyParts, "dto", reqParams.DeadlineTo.Format("20060102"))
+		keyParts = append(keyParts, "dto", reqParams.DeadlineTo.UTC().Format(time.RFC3339Nano))
+	}
+	if reqParams.Overdue != nil && *reqParams.Overdue {
+		keyParts = append(keyParts, "overdue")
 	}
 	if reqParams.Search != nil {
 		h := sha256.New()
@@ -101,6 +144,29 @@ func (uc *TaskUseCase) generateTasksCacheKey(userID uint, reqParams task.GetTask
 			keyParts = append(keyParts, string(task.SortDirectionAsc))
 		}
 	}
+	if len(reqParams.UserTagIDs) > 0 {
+		keyParts = append(keyParts, "utags", joinSortedIDs(reqParams.UserTagIDs))
+	}
+	if len(reqParams.TeamTagIDs) > 0 {
+		keyParts = append(keyParts, "ttags", joinSortedIDs(reqParams.TeamTagIDs))
+	}
+	if reqParams.Untagged != nil && *reqParams.Untagged {
+		keyParts = append(keyParts, "untagged")
+	}
+	if reqParams.TagMatch != nil && *reqParams.TagMatch == task.TagMatchAll {
+		keyParts = append(keyParts, "tagmatch", string(task.TagMatchAll))
+	}
+	// Кэшируется каждая страница отдельно
+	limit := defaultTasksPageLimit
+	if reqParams.Limit != nil {
+		limit = *reqParams.Limit
+	}
+	keyParts = append(keyParts, "limit", strconv.Itoa(limit))
+	if reqParams.Cursor != nil {
+		h := sha256.New()
+		h.Write([]byte(*reqParams.Cursor))
+		keyParts = append(keyParts, "cursor", hex.EncodeToString(h.Sum(nil))[:16])
+	}
 	return strings.Join(keyParts, ":")
 }
 
@@ -108,27 +174,44 @@ func (uc *TaskUseCase) updateTaskTags(taskID uint, userID uint, teamID *uint, us
 	op := "TaskUseCase.updateTaskTags"
 	log := uc.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)))
 
+	// Запоминаем текущие теги, чтобы записать изменения в историю
+	var oldUserTagIDs, oldTeamTagIDs []uint
+	oldLinks, err := uc.tagRepo.GetTaskTags(taskID)
+	if err != nil {
+		log.Warn("failed to get current task tags for history", "error", err)
+	}
+	for _, link := range oldLinks {
+		if link.UserTagID != nil {
+			oldUserTagIDs = append(oldUserTagIDs, *link.UserTagID)
+		} else if link.TeamTagID != nil {
+			oldTeamTagIDs = append(oldTeamTagIDs, *link.TeamTagID)
+		}
+	}
+
 	if err := uc.tagRepo.ClearTaskTags(taskID); err != nil {
 		log.Error("failed to clear existing task tags", "error", err)
 		return task.ErrTaskInternal // Используем ошибку из task модуля
 	}
 
+	var newUserTagIDs, newTeamTagIDs []uint
+
 	// Обработка пользовательских тегов
 	if len(userTagIDs) > 0 {
 		if teamID != nil { // Командным задачам не должны присваиваться пользовательские теги (согласно нашему решению)
 			log.Warn("attempted to assign user tags to a team task", "userTagIDs", userTagIDs)
 			return task.ErrTaskInvalidInput // Или более специфичная ошибка
 		}
-		validUserTags, err := uc.tagUC.ValidateAndGetUserTags(userID, userTagIDs)
+		validUserTags, err := uc.validateUserTags(userID, userTagIDs)
 		if err != nil {
 			log.Error("user tags validation failed", "error", err, "userTagIDs", userTagIDs)
-			return err // Ошибку вернет TagUseCase (ErrTagNotFound или ErrTagInternal)
+			return err // ErrTagNotFound или ErrTagInternal
 		}
 		for _, t := range validUserTags {
 			if err := uc.tagRepo.AddTaskUserTag(taskID, t.UserTagID); err != nil {
 				log.Error("failed to add user tag to task", "error", err, "userTagID", t.UserTagID)
 				return task.ErrTaskInternal // Ошибка привязки
 			}
+			newUserTagIDs = append(newUserTagIDs, t.UserTagID)
 		}
 	}
 
@@ -138,21 +221,62 @@ func (uc *TaskUseCase) updateTaskTags(taskID uint, userID uint, teamID *uint, us
 			log.Warn("attempted to assign team tags to a personal task", "teamTagIDs", teamTagIDs)
 			return task.ErrTaskInvalidInput
 		}
-		validTeamTags, err := uc.tagUC.ValidateAndGetTeamTags(*teamID, userID, teamTagIDs) // userID для проверки прав на команду
+		validTeamTags, err := uc.validateTeamTags(*teamID, userID, teamTagIDs) // userID для проверки прав на команду
 		if err != nil {
 			log.Error("team tags validation failed", "error", err, "teamTagIDs", teamTagIDs)
-			return err // Ошибку вернет TagUseCase (ErrTagNotFound, ErrTeamAccessDenied или ErrTagInternal)
+			return err // ErrTagNotFound, ErrTeamAccessDenied или ErrTagInternal
 		}
 		for _, t := range validTeamTags {
 			if err := uc.tagRepo.AddTaskTeamTag(taskID, t.TeamTagID); err != nil {
 				log.Error("failed to add team tag to task", "error", err, "teamTagID", t.TeamTagID)
 				return task.ErrTaskInternal
 			}
+			newTeamTagIDs = append(newTeamTagIDs, t.TeamTagID)
 		}
 	}
+
+	taskRef := &task.Task{TaskID: taskID, TeamID: teamID}
+	events := diffTagEvents(taskRef, userID, "user_tag", oldUserTagIDs, newUserTagIDs)
+	events = append(events, diffTagEvents(taskRef, userID, "team_tag", oldTeamTagIDs, newTeamTagIDs)...)
+	uc.recordTaskEvents(events...)
 	return nil
 }
 
+// validateUserTags проверяет, что все теги принадлежат пользователю. Теги читаются через tagRepo задачи,
+// поэтому внутри транзакции видны и созданные в ней теги (см. createTransferTags).
+func (uc *TaskUseCase) validateUserTags(userID uint, tagIDs []uint) ([]*tag.UserTag, error) {
+	foundTags, err := uc.tagRepo.FindUserTagsByIDs(userID, tagIDs)
+	if err != nil {
+		uc.log.Error("failed to find user tags by IDs", "error", err, "userID", userID)
+		return nil, tag.ErrTagInternal
+	}
+	if len(foundTags) != len(tagIDs) {
+		return nil, tag.ErrTagNotFound
+	}
+	return foundTags, nil
+}
+
+// validateTeamTags проверяет, что пользователь состоит в команде, а все теги принадлежат ей.
+func (uc *TaskUseCase) validateTeamTags(teamID uint, userID uint, tagIDs []uint) ([]*tag.TeamTag, error) {
+	isMember, err := uc.teamService.IsUserMember(userID, teamID)
+	if err != nil {
+		uc.log.Error("failed to check team membership for tags", "error", err, "teamID", teamID)
+		return nil, tag.ErrTagInternal
+	}
+	if !isMember {
+		return nil, team.ErrTeamAccessDenied
+	}
+	foundTags, err := uc.tagRepo.FindTeamTagsByIDs(teamID, tagIDs)
+	if err != nil {
+		uc.log.Error("failed to find team tags by IDs", "error", err, "teamID", teamID)
+		return nil, tag.ErrTagInternal
+	}
+	if len(foundTags) != len(tagIDs) {
+		return nil, tag.ErrTagNotFound
+	}
+	return foundTags, nil
+}
+
 // --- Приватный метод для получения тегов задачи ---
 func (uc *TaskUseCase) getTaskTags(taskID uint, ownerOrTeamUserID uint, teamID *uint) ([]*tag.TagResponse, error) {
 	op := "TaskUseCase.getTaskTags"
@@ -181,9 +305,7 @@ func (uc *TaskUseCase) getTaskTags(taskID uint, ownerOrTeamUserID uint, teamID *
 
 	if len(userTagIDs) > 0 {
 		// ownerOrTeamUserID здесь - это userID владельца задачи (для личных задач)
-		userTags, err := uc.tagUC.ValidateAndGetUserTags(ownerOrTeamUserID, userTagIDs) // Валидация не нужна, если мы просто читаем
-		// Лучше использовать прямой вызов репо, если GetUserTags в TagUseCase делает доп. проверки, не нужные здесь
-		// userTags, err := uc.tagRepo.FindUserTagsByIDs(ownerOrTeamUserID, userTagIDs)
+		userTags, err := uc.validateUserTags(ownerOrTeamUserID, userTagIDs) // Через tagRepo задачи: в транзакции видны созданные в ней теги
 		if err != nil {
 			log.Error("failed to get user tag details for task", "error", err)
 			// Продолжаем, чтобы не терять командные теги, но логируем
@@ -199,8 +321,7 @@ func (uc *TaskUseCase) getTaskTags(taskID uint, ownerOrTeamUserID uint, teamID *
 
 	if len(teamTagIDs) > 0 && teamID != nil {
 		// ownerOrTeamUserID здесь - это userID текущего пользователя, для проверки доступа к командным тегам
-		teamTags, err := uc.tagUC.ValidateAndGetTeamTags(*teamID, ownerOrTeamUserID, teamTagIDs)
-		// teamTags, err := uc.tagRepo.FindTeamTagsByIDs(*teamID, teamTagIDs)
+		teamTags, err := uc.validateTeamTags(*teamID, ownerOrTeamUserID, teamTagIDs)
 		if err != nil {
 			log.Error("failed to get team tag details for task", "error", err)
 		} else {
@@ -226,11 +347,7 @@ func (uc *TaskUseCase) CreateTask(userID uint, req task.CreateTaskRequest) (*tas
 		CreatedByUserID:  userID,
 		TeamID:           req.TeamID,
 		AssignedToUserID: req.AssignedToUserID,
-	}
-	if req.Status != nil {
-		taskModel.Status = *req.Status
-	} else {
-		taskModel.Status = "todo"
+		EstimateMinutes:  req.EstimateMinutes,
 	}
 	if req.Priority != nil {
 		taskModel.Priority = *req.Priority
@@ -243,35 +360,74 @@ func (uc *TaskUseCase) CreateTask(userID uint, req task.CreateTaskRequest) (*tas
 		canCreate, err := uc.teamService.CanUserCreateTeamTask(userID, teamID)
 		if err != nil {
 			log.Error("failed to check team create permission", "error", err)
-			return nil, task.ErrTaskInternal
+			return nil, teamCheckError(err)
 		}
 		if !canCreate {
 			log.Warn("user no permission to create task in team")
 			return nil, task.ErrTaskAccessDenied
 		}
-		if req.AssignedToUserID != nil {
-			isMember, err := uc.teamService.IsUserTeamMemberWithUserID(teamID, *req.AssignedToUserID)
-			if err != nil {
-				log.Error("failed to check assignee team member", "error", err)
-				return nil, task.ErrTaskInternal
-			}
-			if !isMember {
-				log.Warn("assignee not team member")
-				return nil, task.ErrTaskAssigneeNotInTeam
-			}
+	}
+
+	if req.ProjectID != nil {
+		projectID, err := uc.resolveTaskProject(req.TeamID, *req.ProjectID)
+		if err != nil {
+			log.Warn("invalid task project", "error", err)
+			return nil, err
+		}
+		taskModel.ProjectID = projectID
+	}
+	if req.SprintID != nil {
+		sprintID, err := uc.resolveTaskSprint(req.TeamID, *req.SprintID)
+		if err != nil {
+			log.Warn("invalid task sprint", "error", err)
+			return nil, err
 		}
+		taskModel.SprintID = sprintID
+	}
+
+	var assigneeIDs *[]uint
+	if len(req.AssigneeIDs) > 0 {
+		assigneeIDs = &req.AssigneeIDs
+	}
+	assignees, err := uc.resolveAssignees(&taskModel, userID, assigneeIDs, req.AssignedToUserID, false)
+	if err != nil {
+		log.Warn("invalid task assignees", "error", err)
+		return nil, err
+	}
+
+	// Статус проверяется по набору статусов команды (для личных задач - набор по умолчанию)
+	status := ""
+	if req.Status != nil {
+		status = *req.Status
 	} else {
-		if req.AssignedToUserID != nil && *req.AssignedToUserID != userID {
-			log.Warn("attempt assign personal task to another user")
-			return nil, task.ErrTaskInvalidInput
+		workflow, err := uc.getTaskWorkflow(req.TeamID)
+		if err != nil {
+			log.Error("failed to get task workflow", "error", err)
+			return nil, task.ErrTaskInternal
 		}
+		status = workflow.InitialStatus().Key
+	}
+	if err := uc.applyTaskStatus(&taskModel, status, userID); err != nil {
+		return nil, err
+	}
+	position, err := uc.endOfColumnPosition(&taskModel)
+	if err != nil {
+		log.Error("failed to get position for new task", "error", err)
+		return nil, task.ErrTaskInternal
 	}
+	taskModel.Position = position
 
 	createdTask, err := uc.repo.CreateTask(&taskModel)
 	if err != nil {
 		log.Error("failed to create task in repo", "error", err)
 		return nil, err
 	}
+	assigneeEvents, err := uc.saveAssignees(createdTask, userID, assignees)
+	if err != nil {
+		log.Error("failed to save assignees for new task, but task created", "error", err, "taskID", createdTask.TaskID)
+		return nil, err
+	}
+	uc.recordTaskEvents(append([]*task.TaskEvent{newTaskEvent(createdTask, userID, task.EventTaskCreated, "", nil, nil)}, assigneeEvents...)...)
 
 	if err := uc.updateTaskTags(createdTask.TaskID, userID, createdTask.TeamID, req.UserTagIDs, req.TeamTagIDs); err != nil {
 		log.Error("failed to update tags for new task, but task created", "error", err, "taskID", createdTask.TaskID)
@@ -292,8 +448,24 @@ func (uc *TaskUseCase) CreateTask(userID uint, req task.CreateTaskRequest) (*tas
 	return taskResp, nil
 }
 
-// buildTaskResponse - вспомогательная функция для сборки TaskResponse с тегами
+// buildTaskResponse - вспомогательная функция для сборки TaskResponse с тегами, учтенным временем и участниками
 func (uc *TaskUseCase) buildTaskResponse(taskModel *task.Task, currentUserID uint) (*task.TaskResponse, error) {
+	resp, err := uc.buildTaskResponseWithTags(taskModel, currentUserID)
+	if resp != nil {
+		uc.fillTaskDetails(resp)
+	}
+	return resp, err
+}
+
+// fillTaskDetails дополняет ответы учтенным временем, исполнителями и наблюдателями
+// запросом на таблицу сразу для всей выдачи.
+func (uc *TaskUseCase) fillTaskDetails(responses ...*task.TaskResponse) {
+	uc.fillLoggedMinutes(responses...)
+	uc.fillTaskParticipants(responses...)
+}
+
+// buildTaskResponseWithTags собирает TaskResponse с тегами; остальное списки заполняют через fillTaskDetails.
+func (uc *TaskUseCase) buildTaskResponseWithTags(taskModel *task.Task, currentUserID uint) (*task.TaskResponse, error) {
 	if taskModel == nil {
 		return nil, nil
 	}
@@ -363,6 +535,22 @@ func (uc *TaskUseCase) GetTask(taskID uint, userID uint) (*task.TaskResponse, er
 	return uc.buildTaskResponse(dbTaskModel, userID)
 }
 
+// getAccessibleTask возвращает задачу (не из корзины), к которой у пользователя есть доступ на чтение.
+func (uc *TaskUseCase) getAccessibleTask(taskID uint, userID uint) (*task.Task, error) {
+	taskModel, err := uc.repo.GetTaskByID(taskID, userID)
+	if err != nil {
+		if errors.Is(err, task.ErrTaskNotFound) {
+			return nil, task.ErrTaskNotFound
+		}
+		uc.log.Error("failed to get task", "error", err, "taskID", taskID)
+		return nil, task.ErrTaskInternal
+	}
+	if errAccess := uc.checkTaskAccess(taskModel, userID); errAccess != nil {
+		return nil, errAccess
+	}
+	return taskModel, nil
+}
+
 // checkTaskAccess - приватный метод для проверки прав доступа к задаче
 func (uc *TaskUseCase) checkTaskAccess(taskModel *task.Task, userID uint) error {
 	if taskModel.TeamID == nil { // Личная задача
@@ -380,41 +568,71 @@ func (uc *TaskUseCase) checkTaskAccess(taskModel *task.Task, userID uint) error
 			uc.log.Warn("user not member of team for task access", "teamID", *taskModel.TeamID)
 			return task.ErrTaskAccessDenied
 		}
+		return uc.checkGuestTaskScope(taskModel, userID)
 	}
 	return nil
 }
 
-func (uc *TaskUseCase) GetTasks(userID uint, reqParams task.GetTasksRequest) ([]*task.TaskResponse, error) {
+func (uc *TaskUseCase) GetTasks(userID uint, reqParams task.GetTasksRequest) ([]*task.TaskResponse, *task.TaskListMeta, error) {
 	// ... (в основном без изменений, кроме передачи IsDeleted) ...
 	op := "TaskUseCase.GetTasks"
+	uc = uc.forRequest() // Членство в команде проверяется для каждой задачи страницы
+	if reqParams.ViewID != nil {
+		if err := uc.applySavedView(userID, &reqParams); err != nil {
+			return nil, nil, err
+		}
+	}
 	viewTypeToUse := task.ViewTypeDefault
 	if reqParams.ViewType != nil {
 		viewTypeToUse = *reqParams.ViewType
 	}
 	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)), slog.String("viewType", string(viewTypeToUse)))
 
+	meta := &task.TaskListMeta{}
 	if reqParams.TeamID != nil {
 		isMember, teamErr := uc.teamService.IsUserMember(userID, *reqParams.TeamID)
 		if teamErr != nil {
 			log.Error("failed to check team membership for GetTasks", "error", teamErr, "teamID", *reqParams.TeamID)
-			return nil, task.ErrTaskInternal
+			return nil, nil, task.ErrTaskInternal
 		}
 		if !isMember {
 			log.Warn("user not member of requested team for GetTasks", "teamID", *reqParams.TeamID)
-			return []*task.TaskResponse{}, nil
+			return []*task.TaskResponse{}, meta, nil
 		}
 	}
+	scopeTagID, err := uc.guestTaskTagScope(userID, reqParams.TeamID)
+	if err != nil {
+		log.Error("failed to get guest task scope for GetTasks", "error", err)
+		return nil, nil, task.ErrTaskInternal
+	}
 	paramsForRepo := task.GetTasksParams{
 		UserID:           userID,
 		ViewType:         viewTypeToUse,
 		TeamID:           reqParams.TeamID,
+		ProjectID:        reqParams.ProjectID,
+		SprintID:         reqParams.SprintID,
 		Status:           reqParams.Status,
+		StatusCategory:   reqParams.StatusCategory,
 		Priority:         reqParams.Priority,
 		AssignedToUserID: reqParams.AssignedToUserID,
 		DeadlineFrom:     reqParams.DeadlineFrom,
 		DeadlineTo:       reqParams.DeadlineTo,
+		Overdue:          reqParams.Overdue != nil && *reqParams.Overdue,
 		SearchQuery:      reqParams.Search,
 		IsDeleted:        reqParams.IsDeleted, // <<< ПЕРЕДАЕМ IsDeleted
+		UserTagIDs:       reqParams.UserTagIDs,
+		TeamTagIDs:       reqParams.TeamTagIDs,
+		Untagged:         reqParams.Untagged != nil && *reqParams.Untagged,
+		TagMatch:         task.TagMatchAny,
+		ScopeTeamTagID:   scopeTagID,
+	}
+	if reqParams.TagMatch != nil {
+		paramsForRepo.TagMatch = *reqParams.TagMatch
+	}
+	hasTagIDs := len(paramsForRepo.UserTagIDs) > 0 || len(paramsForRepo.TeamTagIDs) > 0
+	if paramsForRepo.Untagged && hasTagIDs && paramsForRepo.TagMatch == task.TagMatchAll {
+		log.Warn("untagged filter cannot be combined with tag_match=all")
+		return nil, nil, task.ErrTaskInvalidInput
 	}
 	if reqParams.SortBy != nil {
 		paramsForRepo.SortBy = *reqParams.SortBy
@@ -423,91 +641,129 @@ func (uc *TaskUseCase) GetTasks(userID uint, reqParams task.GetTasksRequest) ([]
 		} else {
 			paramsForRepo.SortOrder = task.SortDirectionAsc // По умолчанию ASC, если поле сортировки указано
 		}
-	} else {
+	} else if reqParams.Search == nil || *reqParams.Search == "" {
 		paramsForRepo.SortBy = task.FieldUpdatedAt // Сортировка по умолчанию
 		paramsForRepo.SortOrder = task.SortDirectionDesc
 	}
+	// При поиске без явной сортировки выдача упорядочена по релевантности (см. EffectiveSort)
 
-	cacheKey := uc.generateTasksCacheKey(userID, reqParams)
-	log = log.With(slog.String("cacheKey", cacheKey))
-
-	cachedTaskModels, errCache := uc.repo.GetTasksCache(cacheKey)
-	if errCache == nil && cachedTaskModels != nil {
-		log.Info("task models list retrieved from cache", slog.Int("count", len(cachedTaskModels)))
-
-		var accessibleCachedTasks []*task.Task
-		for _, tm := range cachedTaskModels {
-			// Проверяем, не удалена ли задача в кеше, если мы ищем не удаленные
-			if reqParams.IsDeleted == nil || !*reqParams.IsDeleted {
-				if tm.IsDeleted {
-					continue
-				}
-			}
-			if tm.TeamID != nil {
-				isMember, teamErr := uc.teamService.IsUserMember(userID, *tm.TeamID)
-				if teamErr != nil || !isMember {
-					continue
-				}
-			}
-			accessibleCachedTasks = append(accessibleCachedTasks, tm)
-		}
-		// ... остальная часть кеш-логики ...
-		responses := make([]*task.TaskResponse, 0, len(accessibleCachedTasks))
-		for _, tm := range accessibleCachedTasks {
-			resp, buildErr := uc.buildTaskResponse(tm, userID)
-			if buildErr != nil {
-				log.Warn("failed to build task response for cached task, skipping", "taskID", tm.TaskID, "error", buildErr)
-				continue
-			}
-			if resp != nil {
-				responses = append(responses, resp)
-			}
+	sortBy, sortOrder := paramsForRepo.EffectiveSort()
+	if reqParams.Cursor != nil {
+		cursor, err := decodeTaskCursor(*reqParams.Cursor, sortBy, sortOrder)
+		if err != nil {
+			log.Warn("invalid cursor for GetTasks", "error", err)
+			return nil, nil, err
 		}
-		return responses, nil
+		paramsForRepo.Cursor = cursor
 	}
+	limit := defaultTasksPageLimit
+	if reqParams.Limit != nil {
+		limit = *reqParams.Limit
+	}
+	// Лишняя строка показывает, есть ли следующая страница
+	paramsForRepo.Limit = limit + 1
 
-	dbTaskModels, err := uc.repo.GetTasks(paramsForRepo)
-	if err != nil {
-		log.Error("failed to get tasks from DB repo", "error", err)
-		return nil, task.ErrTaskInternal
+	if reqParams.IncludeTotal != nil && *reqParams.IncludeTotal {
+		countParams := paramsForRepo
+		countParams.Cursor = nil
+		total, err := uc.repo.CountTasks(countParams)
+		if err != nil {
+			log.Error("failed to count tasks", "error", err)
+			return nil, nil, task.ErrTaskInternal
+		}
+		meta.Total = &total
+	}
+	if reqParams.IncludeFacets != nil && *reqParams.IncludeFacets {
+		facetParams := paramsForRepo
+		facetParams.Cursor = nil
+		facets, err := uc.repo.GetTaskTagFacets(facetParams)
+		if err != nil {
+			log.Error("failed to get task tag facets", "error", err)
+			return nil, nil, task.ErrTaskInternal
+		}
+		meta.Facets = facets
+	}
+
+	// Глобальное представление включает задачи всех команд пользователя, а изменения в них
+	// не сбрасывают версию кэша пользователя, поэтому такие страницы не кэшируются
+	cacheKey := ""
+	if viewTypeToUse == task.ViewTypeUserCentricGlobal {
+		log.Debug("global view is not cached")
+	} else if versionPrefix, errVersion := uc.tasksCacheVersionPrefix(userID, reqParams.TeamID); errVersion == nil {
+		cacheKey = versionPrefix + ":" + uc.generateTasksCacheKey(userID, viewTypeToUse, reqParams)
+		if scopeTagID != nil {
+			cacheKey += ":gscope:" + strconv.FormatUint(uint64(*scopeTagID), 10) // Смена тега гостей не должна отдавать старые страницы
+		}
+		log = log.With(slog.String("cacheKey", cacheKey))
+	} else {
+		log.Warn("failed to get tasks cache version, skipping cache", "error", errVersion)
 	}
 
-	var finalTasksToRespond []*task.Task
-	if viewTypeToUse == task.ViewTypeUserCentricGlobal || reqParams.TeamID != nil {
-		for _, tm := range dbTaskModels {
-			if tm.TeamID != nil {
-				isMember, teamErr := uc.teamService.IsUserMember(userID, *tm.TeamID)
-				if teamErr != nil {
-					log.Error("error checking team membership for task in GetTasks", "taskID", tm.TaskID, "teamID", *tm.TeamID, "error", teamErr)
-					continue
-				}
-				if !isMember {
-					continue
-				}
+	// В кэше хранится сырая страница из БД (limit + 1 строка), проверка доступа выполняется всегда
+	var pageTaskModels []*task.Task
+	if cacheKey != "" {
+		if cachedTaskModels, errCache := uc.repo.GetTasksCache(cacheKey); errCache == nil && cachedTaskModels != nil {
+			log.Info("task models page retrieved from cache", slog.Int("count", len(cachedTaskModels)))
+			pageTaskModels = cachedTaskModels
+		}
+	}
+	if pageTaskModels == nil {
+		dbTaskModels, err := uc.repo.GetTasks(paramsForRepo)
+		if err != nil {
+			if errors.Is(err, task.ErrTaskInvalidCursor) {
+				return nil, nil, err
+			}
+			log.Error("failed to get tasks from DB repo", "error", err)
+			return nil, nil, task.ErrTaskInternal
+		}
+		pageTaskModels = dbTaskModels
+		if cacheKey != "" {
+			if errSave := uc.repo.SaveTasks(cacheKey, pageTaskModels); errSave != nil {
+				log.Warn("failed to save tasks page (models) to cache", "error", errSave)
 			}
-			finalTasksToRespond = append(finalTasksToRespond, tm)
 		}
-	} else {
-		finalTasksToRespond = dbTaskModels
 	}
 
-	if errSave := uc.repo.SaveTasks(cacheKey, finalTasksToRespond); errSave != nil {
-		log.Warn("failed to save tasks list (models) to cache", "error", errSave)
+	if len(pageTaskModels) > limit {
+		pageTaskModels = pageTaskModels[:limit]
+		last := pageTaskModels[len(pageTaskModels)-1]
+		nextCursor := encodeTaskCursor(task.TaskCursor{
+			SortBy:    sortBy,
+			SortOrder: sortOrder,
+			Value:     last.SortValue(sortBy),
+			TaskID:    last.TaskID,
+		})
+		meta.NextCursor = &nextCursor
 	}
 
-	responses := make([]*task.TaskResponse, 0, len(finalTasksToRespond))
-	for _, tm := range finalTasksToRespond {
-		resp, buildErr := uc.buildTaskResponse(tm, userID)
+	responses := make([]*task.TaskResponse, 0, len(pageTaskModels))
+	for _, tm := range pageTaskModels {
+		// Проверяем, не удалена ли задача в кеше, если мы ищем не удаленные
+		if (reqParams.IsDeleted == nil || !*reqParams.IsDeleted) && tm.IsDeleted {
+			continue
+		}
+		if tm.TeamID != nil && (viewTypeToUse == task.ViewTypeUserCentricGlobal || reqParams.TeamID != nil) {
+			isMember, teamErr := uc.teamService.IsUserMember(userID, *tm.TeamID)
+			if teamErr != nil {
+				log.Error("error checking team membership for task in GetTasks", "taskID", tm.TaskID, "teamID", *tm.TeamID, "error", teamErr)
+				continue
+			}
+			if !isMember {
+				continue
+			}
+		}
+		resp, buildErr := uc.buildTaskResponseWithTags(tm, userID)
 		if buildErr != nil {
-			log.Warn("failed to build task response for DB task, skipping", "taskID", tm.TaskID, "error", buildErr)
+			log.Warn("failed to build task response, skipping", "taskID", tm.TaskID, "error", buildErr)
 			continue
 		}
 		if resp != nil {
 			responses = append(responses, resp)
 		}
 	}
-	log.Info("tasks list retrieved from DB", slog.Int("count", len(responses)))
-	return responses, nil
+	uc.fillTaskDetails(responses...)
+	log.Info("tasks page retrieved", slog.Int("count", len(responses)), slog.Bool("has_more", meta.NextCursor != nil))
+	return responses, meta, nil
 }
 
 func (uc *TaskUseCase) UpdateTask(taskID uint, userID uint, req task.UpdateTaskRequest) (*task.TaskResponse, error) {
@@ -527,32 +783,43 @@ func (uc *TaskUseCase) UpdateTask(taskID uint, userID uint, req task.UpdateTaskR
 		return nil, errAccess
 	}
 
-	if existingTask.TeamID != nil && req.AssignedToUserID != nil {
-		isMember, teamErr := uc.teamService.IsUserTeamMemberWithUserID(*existingTask.TeamID, *req.AssignedToUserID)
-		if teamErr != nil {
-			log.Error("failed to check assignee in team", "error", teamErr)
-			return nil, task.ErrTaskInternal
-		}
-		if !isMember {
-			return nil, task.ErrTaskAssigneeNotInTeam
-		}
-	} else if existingTask.TeamID == nil && req.AssignedToUserID != nil && *req.AssignedToUserID != userID {
-		return nil, task.ErrTaskInvalidInput
+	taskBefore := *existingTask
+	// PUT без assignee_ids и assigned_to_user_id снимает основного исполнителя, как и раньше
+	assignees, err := uc.resolveAssignees(existingTask, userID, req.AssigneeIDs, req.AssignedToUserID, req.AssigneeIDs == nil && req.AssignedToUserID == nil)
+	if err != nil {
+		return nil, err
 	}
-
 	existingTask.Title = req.Title
 	existingTask.Description = req.Description
 	existingTask.Deadline = req.Deadline
-	existingTask.Status = req.Status
 	existingTask.Priority = req.Priority
-	existingTask.AssignedToUserID = req.AssignedToUserID
-
-	if existingTask.Status == "done" && existingTask.CompletedAt == nil {
-		now := time.Now()
-		existingTask.CompletedAt = &now
+	existingTask.EstimateMinutes = req.EstimateMinutes
+	if req.ProjectID != nil {
+		projectID, err := uc.resolveTaskProject(existingTask.TeamID, *req.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if err := uc.checkTaskProjectChange(&taskBefore, userID, projectID); err != nil {
+			return nil, err
+		}
+		existingTask.ProjectID = projectID
 	}
-	if existingTask.Status != "done" && existingTask.CompletedAt != nil {
-		existingTask.CompletedAt = nil
+	if req.SprintID != nil {
+		sprintID, err := uc.resolveTaskSprint(existingTask.TeamID, *req.SprintID)
+		if err != nil {
+			return nil, err
+		}
+		existingTask.SprintID = sprintID
+	}
+	if err := uc.applyTaskStatus(existingTask, req.Status, userID); err != nil {
+		return nil, err
+	}
+	if existingTask.Status != taskBefore.Status {
+		// При смене статуса задача встает в конец новой колонки
+		if existingTask.Position, err = uc.endOfColumnPosition(existingTask); err != nil {
+			log.Error("failed to get position in new status column", "error", err)
+			return nil, task.ErrTaskInternal
+		}
 	}
 
 	updatedTaskModel, err := uc.repo.UpdateTask(existingTask)
@@ -560,6 +827,12 @@ func (uc *TaskUseCase) UpdateTask(taskID uint, userID uint, req task.UpdateTaskR
 		log.Error("failed to update task in repo", "error", err)
 		return nil, err
 	}
+	assigneeEvents, err := uc.saveAssignees(updatedTaskModel, userID, assignees)
+	if err != nil {
+		log.Error("failed to save task assignees, but task core updated", "error", err)
+		return nil, err
+	}
+	uc.recordTaskEvents(append(diffTaskEvents(&taskBefore, updatedTaskModel, userID), assigneeEvents...)...)
 
 	var userTagsToUpdate []uint
 	var teamTagsToUpdate []uint
@@ -600,6 +873,7 @@ func (uc *TaskUseCase) PatchTask(taskID uint, userID uint, req task.PatchTaskReq
 		return nil, task.ErrTaskAlreadyDeleted
 	}
 
+	taskBefore := *existingTask
 	madeChangesToDetails := false
 	statusChanged := false
 
@@ -622,12 +896,66 @@ func (uc *TaskUseCase) PatchTask(taskID uint, userID uint, req task.PatchTaskReq
 		existingTask.Priority = *req.Priority
 		madeChangesToDetails = true
 	}
-	if req.ClearAssignedTo != nil && *req.ClearAssignedTo {
-		existingTask.AssignedToUserID = nil
+	assignees, err := uc.resolveAssignees(existingTask, userID, req.AssigneeIDs, req.AssignedToUserID, req.ClearAssignedTo != nil && *req.ClearAssignedTo)
+	if err != nil {
+		return nil, err
+	}
+	if assignees != nil {
+		madeChangesToDetails = true
+	}
+	if req.ClearEstimate != nil && *req.ClearEstimate {
+		existingTask.EstimateMinutes = nil
 		madeChangesToDetails = true
-	} else if req.AssignedToUserID != nil {
-		existingTask.AssignedToUserID = req.AssignedToUserID
+	} else if req.EstimateMinutes != nil {
+		existingTask.EstimateMinutes = req.EstimateMinutes
+		madeChangesToDetails = true
+	}
+
+	if req.ClearSprint != nil && *req.ClearSprint {
+		existingTask.SprintID = nil
 		madeChangesToDetails = true
+	} else if req.SprintID != nil {
+		if existingTask.SprintID, err = uc.resolveTaskSprint(existingTask.TeamID, *req.SprintID); err != nil {
+			return nil, err
+		}
+		madeChangesToDetails = true
+	}
+
+	projectRequested := req.ProjectID != nil || (req.ClearProject != nil && *req.ClearProject)
+	tagsRequested := req.UserTagIDs != nil || req.TeamTagIDs != nil
+	if madeChangesToDetails || tagsRequested || projectRequested {
+		if errAccess := uc.checkTaskEditAccess(existingTask, userID, false); errAccess != nil {
+			return nil, errAccess
+		}
+	}
+	// Проект меняется после проверки прав: руководителю проекта права даются по текущему проекту задачи
+	if projectRequested {
+		var projectID *uint
+		if req.ClearProject == nil || !*req.ClearProject {
+			if projectID, err = uc.resolveTaskProject(existingTask.TeamID, *req.ProjectID); err != nil {
+				return nil, err
+			}
+		}
+		if err := uc.checkTaskProjectChange(existingTask, userID, projectID); err != nil {
+			return nil, err
+		}
+		existingTask.ProjectID = projectID
+		madeChangesToDetails = true
+	}
+	if req.Status != nil && *req.Status != existingTask.Status {
+		if !madeChangesToDetails && !tagsRequested {
+			if errAccess := uc.checkTaskEditAccess(existingTask, userID, true); errAccess != nil {
+				return nil, errAccess
+			}
+		}
+		if err := uc.applyTaskStatus(existingTask, *req.Status, userID); err != nil {
+			return nil, err
+		}
+		if existingTask.Position, err = uc.endOfColumnPosition(existingTask); err != nil {
+			log.Error("failed to get position in new status column", "error", err)
+			return nil, task.ErrTaskInternal
+		}
+		statusChanged = true
 	}
 
 	// <<< НОВАЯ ЛОГИКА для is_deleted >>>
@@ -646,21 +974,24 @@ func (uc *TaskUseCase) PatchTask(taskID uint, userID uint, req task.PatchTaskReq
 		}
 	}
 
-	if existingTask.Status == "done" && existingTask.CompletedAt == nil {
-		now := time.Now()
-		existingTask.CompletedAt = &now
-	}
-	if existingTask.Status != "done" && existingTask.CompletedAt != nil {
-		existingTask.CompletedAt = nil
-	}
-
 	updatedTaskModel, err := uc.repo.UpdateTask(existingTask)
 	if err != nil {
 		return nil, err
 	}
+	assigneeEvents, err := uc.saveAssignees(updatedTaskModel, userID, assignees)
+	if err != nil {
+		log.Error("failed to save task assignees, but task core updated", "error", err)
+		return nil, err
+	}
+	var events []*task.TaskEvent
+	if taskBefore.IsDeleted && !updatedTaskModel.IsDeleted {
+		events = append(events, newTaskEvent(updatedTaskModel, userID, task.EventTaskRestored, "", nil, nil))
+	}
+	events = append(events, diffTaskEvents(&taskBefore, updatedTaskModel, userID)...)
+	uc.recordTaskEvents(append(events, assigneeEvents...)...)
 
 	tagsUpdated := false
-	if req.UserTagIDs != nil || req.TeamTagIDs != nil {
+	if tagsRequested {
 		var userTagsToUpdate []uint
 		var teamTagsToUpdate []uint
 		if req.UserTagIDs != nil {
@@ -699,55 +1030,95 @@ func (uc *TaskUseCase) checkTaskEditAccess(taskToEdit *task.Task, userID uint, s
 	} else { // Командная задача
 		teamID := *taskToEdit.TeamID
 		if statusOnly {
-			canChangeStatus, teamErr := uc.teamService.CanUserChangeTeamTaskStatus(userID, teamID, taskToEdit.AssignedToUserID)
+			assigneeIDs, assigneesErr := uc.taskAssigneeIDs(taskToEdit)
+			if assigneesErr != nil {
+				uc.log.Error("failed to get task assignees for status permission", "error", assigneesErr)
+				return task.ErrTaskInternal
+			}
+			canChangeStatus, teamErr := uc.teamService.CanUserChangeTeamTaskStatus(userID, teamID, assigneeIDs)
 			if teamErr != nil {
 				uc.log.Error("failed to check team change status permission", "error", teamErr)
-				return task.ErrTaskInternal
+				return teamCheckError(teamErr)
 			}
 			if !canChangeStatus {
-				uc.log.Warn("user lacks permission to change task status in team")
-				return task.ErrTaskAccessDenied
+				return uc.projectLeadAccess(taskToEdit, userID, "user lacks permission to change task status in team")
 			}
 		} else {
 			canEditDetails, teamErr := uc.teamService.CanUserEditTeamTaskDetails(userID, teamID)
 			if teamErr != nil {
 				uc.log.Error("failed to check team edit details permission", "error", teamErr)
-				return task.ErrTaskInternal
+				return teamCheckError(teamErr)
 			}
 			if !canEditDetails {
-				uc.log.Warn("user lacks permission to edit task details in team")
-				return task.ErrTaskAccessDenied
+				return uc.projectLeadAccess(taskToEdit, userID, "user lacks permission to edit task details in team")
 			}
 		}
 	}
 	return nil
 }
 
-// invalidateTaskListsCache - приватный метод для инвалидации кэшей списков задач
-func (uc *TaskUseCase) invalidateTaskListsCache(userID uint, teamID *uint) {
-	keysToInvalidate := []string{
-		uc.generateTasksCacheKey(userID, task.GetTasksRequest{TeamID: nil}),
+// projectLeadAccess разрешает изменение задачи руководителю ее проекта, если прав команды не хватило.
+func (uc *TaskUseCase) projectLeadAccess(taskToEdit *task.Task, userID uint, deniedMsg string) error {
+	isLead, err := uc.isTaskProjectLead(taskToEdit, userID)
+	if err != nil {
+		uc.log.Error("failed to check task project lead", "error", err)
+		return task.ErrTaskInternal
+	}
+	if !isLead {
+		uc.log.Warn(deniedMsg)
+		return task.ErrTaskAccessDenied
 	}
+	return nil
+}
+
+// invalidateTaskListsCache - приватный метод для инвалидации кэшей списков задач.
+// Страницы списков кэшируются под версией пользователя или команды, поэтому достаточно увеличить версии:
+// старые страницы (включая корзину и все курсоры) становятся недостижимыми и истекают по TTL.
+func (uc *TaskUseCase) invalidateTaskListsCache(userID uint, teamID *uint) {
+	scopes := []string{userTasksScope(userID)}
 	if teamID != nil {
-		keysToInvalidate = append(keysToInvalidate, uc.generateTasksCacheKey(userID, task.GetTasksRequest{TeamID: teamID}))
-	}
-
-	// Дополнительно инвалидируем ключи без фильтров, но с разными сортировками, если они часто используются
-	// Это упрощенный вариант, можно сделать более умную инвалидацию по паттерну, если Redis поддерживает
-	// Пример:
-	// commonSorts := []task.TaskSortableField{task.FieldCreatedAt, task.FieldPriority, task.FieldDeadline}
-	// for _, sortBy := range commonSorts {
-	// 	keysToInvalidate = append(keysToInvalidate, uc.generateTasksCacheKey(userID, task.GetTasksRequest{TeamID: nil, SortBy: &sortBy, SortOrder: task.SortDirectionDesc}))
-	// 	if teamID != nil {
-	// 		keysToInvalidate = append(keysToInvalidate, uc.generateTasksCacheKey(userID, task.GetTasksRequest{TeamID: teamID, SortBy: &sortBy, SortOrder: task.SortDirectionDesc}))
-	// 	}
-	// }
-
-	if err := uc.repo.InvalidateTasks(keysToInvalidate...); err != nil {
-		uc.log.Warn("failed to invalidate tasks list cache", "error", err, "keys", keysToInvalidate)
+		scopes = append(scopes, teamTasksScope(*teamID))
+	}
+	if uc.listCacheBatch != nil {
+		for _, scope := range scopes {
+			uc.listCacheBatch[scope] = struct{}{}
+		}
+		return
+	}
+
+	if err := uc.repo.BumpTasksListVersion(scopes...); err != nil {
+		uc.log.Warn("failed to invalidate tasks list cache", "error", err, "scopes", scopes)
 	} else {
-		uc.log.Info("successfully invalidated task list cache keys", "keys", keysToInvalidate)
+		uc.log.Info("successfully invalidated task list cache", "scopes", scopes)
 	}
+	uc.invalidateTeamStats(scopes)
+}
+
+// invalidateTeamStats сбрасывает статистику команд, чьи списки задач изменились. Вызывается только
+// после фиксации изменений, иначе статистика могла бы закэшироваться по незафиксированным данным.
+func (uc *TaskUseCase) invalidateTeamStats(scopes []string) {
+	for _, scope := range scopes {
+		if teamID, ok := teamIDFromScope(scope); ok {
+			uc.teamService.InvalidateTeamStats(teamID)
+		}
+	}
+}
+
+// flushListCacheBatch инвалидирует одним вызовом области кэша списков, собранные массовой операцией.
+func (uc *TaskUseCase) flushListCacheBatch(batch map[string]struct{}) {
+	if len(batch) == 0 {
+		return
+	}
+	scopes := make([]string, 0, len(batch))
+	for scope := range batch {
+		scopes = append(scopes, scope)
+	}
+	if err := uc.repo.BumpTasksListVersion(scopes...); err != nil {
+		uc.log.Warn("failed to invalidate tasks list cache", "error", err, "scopes", scopes)
+	} else {
+		uc.log.Info("successfully invalidated task list cache", "scopes", scopes)
+	}
+	uc.invalidateTeamStats(scopes)
 }
 
 func (uc *TaskUseCase) DeleteTask(taskID uint, userID uint) error {
@@ -774,7 +1145,7 @@ func (uc *TaskUseCase) DeleteTask(taskID uint, userID uint) error {
 		teamID := *taskToDelete.TeamID
 		canDelete, teamErr := uc.teamService.CanUserDeleteTeamTask(userID, teamID, taskToDelete.CreatedByUserID)
 		if teamErr != nil {
-			return task.ErrTaskInternal
+			return teamCheckError(teamErr)
 		}
 		if !canDelete {
 			return task.ErrTaskAccessDenied
@@ -786,15 +1157,27 @@ func (uc *TaskUseCase) DeleteTask(taskID uint, userID uint) error {
 	if err != nil {
 		return err
 	}
+	uc.recordTaskEvents(newTaskEvent(taskToDelete, userID, task.EventTaskDeleted, "", nil, nil))
 	_ = uc.repo.DeleteTaskCache(taskID)
 	// Инвалидируем кэш для активных и удаленных задач
 	uc.invalidateTaskListsCache(userID, taskToDelete.TeamID)
-	uc.invalidateDeletedTasksCache(userID, taskToDelete.TeamID)
 
 	log.Info("task deleted successfully")
 	return nil
 }
 
+// checkTaskAssignee проверяет исполнителя: для командной задачи - участник команды (не гость),
+// личную задачу можно назначить только на себя.
+func (uc *TaskUseCase) checkTaskAssignee(t *task.Task, assigneeID uint, userID uint) error {
+	if t.TeamID == nil {
+		if assigneeID != userID {
+			return task.ErrTaskInvalidInput
+		}
+		return nil
+	}
+	return uc.checkTeamAssignee(*t.TeamID, assigneeID)
+}
+
 // <<< НОВАЯ ВСПОМОГАТЕЛЬНАЯ ФУНКЦИЯ >>>
 func (uc *TaskUseCase) checkTaskDeleteAccess(taskToDelete *task.Task, userID uint) error {
 	if taskToDelete.TeamID == nil { // Личная задача
@@ -806,7 +1189,7 @@ func (uc *TaskUseCase) checkTaskDeleteAccess(taskToDelete *task.Task, userID uin
 		canDelete, teamErr := uc.teamService.CanUserDeleteTeamTask(userID, *taskToDelete.TeamID, taskToDelete.CreatedByUserID)
 		if teamErr != nil {
 			uc.log.Error("failed to check team delete permission", "error", teamErr)
-			return task.ErrTaskInternal
+			return teamCheckError(teamErr)
 		}
 		if !canDelete {
 			uc.log.Warn("user lacks permission to delete task in team")
@@ -816,23 +1199,6 @@ func (uc *TaskUseCase) checkTaskDeleteAccess(taskToDelete *task.Task, userID uin
 	return nil
 }
 
-// <<< НОВАЯ ВСПОМОГАТЕЛЬНАЯ ФУНКЦИЯ >>>
-func (uc *TaskUseCase) invalidateDeletedTasksCache(userID uint, teamID *uint) {
-	isDeleted := true
-	keysToInvalidate := []string{
-		uc.generateTasksCacheKey(userID, task.GetTasksRequest{TeamID: nil, IsDeleted: &isDeleted}),
-	}
-	if teamID != nil {
-		keysToInvalidate = append(keysToInvalidate, uc.generateTasksCacheKey(userID, task.GetTasksRequest{TeamID: teamID, IsDeleted: &isDeleted}))
-	}
-
-	if err := uc.repo.InvalidateTasks(keysToInvalidate...); err != nil {
-		uc.log.Warn("failed to invalidate deleted tasks list cache", "error", err, "keys", keysToInvalidate)
-	} else {
-		uc.log.Info("successfully invalidated deleted task list cache keys", "keys", keysToInvalidate)
-	}
-}
-
 // <<< НОВЫЙ МЕТОД >>>
 func (uc *TaskUseCase) RestoreTask(taskID uint, userID uint) (*task.TaskResponse, error) {
 	op := "TaskUseCase.RestoreTask"
@@ -864,10 +1230,10 @@ func (uc *TaskUseCase) RestoreTask(taskID uint, userID uint) (*task.TaskResponse
 	if err != nil {
 		return nil, err
 	}
+	uc.recordTaskEvents(newTaskEvent(restoredTask, userID, task.EventTaskRestored, "", nil, nil))
 
 	_ = uc.repo.DeleteTaskCache(taskID) // Удаляем из кэша, если он там был с флагом is_deleted=true
 	uc.invalidateTaskListsCache(userID, restoredTask.TeamID)
-	uc.invalidateDeletedTasksCache(userID, restoredTask.TeamID)
 
 	log.Info("task restored successfully")
 	return uc.buildTaskResponse(restoredTask, userID)
@@ -891,10 +1257,11 @@ func (uc *TaskUseCase) DeleteTaskPermanently(taskID uint, userID uint) error {
 	if err := uc.repo.DeleteTaskPermanently(taskID); err != nil {
 		return err
 	}
+	uc.recordTaskEvents(newTaskEvent(taskToDelete, userID, task.EventTaskDeletedPermanently, "", nil, nil))
 
-	// Инвалидация кэшей
+	// Инвалидация кэшей (задача пропадает из корзины)
 	_ = uc.repo.DeleteTaskCache(taskID)
-	uc.invalidateDeletedTasksCache(userID, taskToDelete.TeamID) // Инвалидируем кэш корзины
+	uc.invalidateTaskListsCache(userID, taskToDelete.TeamID)
 
 	log.Info("task permanently deleted successfully")
 	return nil
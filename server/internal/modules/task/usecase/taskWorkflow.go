@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"log/slog"
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+	"time"
+)
+
+// getTaskWorkflow возвращает набор статусов для задачи: командный для командных задач, по умолчанию - для личных.
+func (uc *TaskUseCase) getTaskWorkflow(teamID *uint) (*team.TaskWorkflow, error) {
+	if teamID == nil {
+		return team.DefaultTaskWorkflow(), nil
+	}
+	return uc.teamService.GetTaskWorkflow(*teamID)
+}
+
+// applyTaskStatus проверяет статус по набору статусов задачи и правилам переходов,
+// после чего выставляет статус, его категорию и CompletedAt.
+func (uc *TaskUseCase) applyTaskStatus(t *task.Task, newStatus string, userID uint) error {
+	op := "TaskUseCase.applyTaskStatus"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(t.TaskID)), slog.String("status", newStatus))
+
+	workflow, err := uc.getTaskWorkflow(t.TeamID)
+	if err != nil {
+		log.Error("failed to get task workflow", "error", err)
+		return task.ErrTaskInternal
+	}
+	status := workflow.Status(newStatus)
+	if status == nil {
+		log.Warn("status is not part of the workflow")
+		return task.ErrTaskUnknownStatus
+	}
+
+	// Правила переходов проверяются только для существующих командных задач
+	if t.TaskID != 0 && t.TeamID != nil && t.Status != newStatus && len(workflow.Transitions) > 0 {
+		role, err := uc.teamService.GetUserRoleInTeam(userID, *t.TeamID)
+		if err != nil {
+			log.Error("failed to get user role for status transition", "error", err)
+			return task.ErrTaskInternal
+		}
+		if role == nil {
+			return task.ErrTaskAccessDenied
+		}
+		if !workflow.CanTransition(t.Status, newStatus, role) {
+			log.Warn("status transition not allowed", "from", t.Status, "role", *role)
+			return task.ErrTaskInvalidStatusTransition
+		}
+	}
+
+	t.Status = status.Key
+	t.StatusCategory = string(status.Category)
+	if status.Category == team.StatusCategoryDone {
+		if t.CompletedAt == nil {
+			now := time.Now()
+			t.CompletedAt = &now
+		}
+	} else {
+		t.CompletedAt = nil
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"errors"
+	"log/slog"
+	"server/internal/modules/task"
+	"time"
+)
+
+// fillLoggedMinutes заполняет учтенное время задач одним запросом. Ошибка не прерывает выдачу задач.
+func (uc *TaskUseCase) fillLoggedMinutes(responses ...*task.TaskResponse) {
+	if len(responses) == 0 {
+		return
+	}
+	taskIDs := make([]uint, len(responses))
+	for i, resp := range responses {
+		taskIDs[i] = resp.TaskID
+	}
+	seconds, err := uc.repo.GetTaskLoggedSeconds(taskIDs)
+	if err != nil {
+		uc.log.Warn("failed to get logged time for task response", "error", err, "count", len(taskIDs))
+		return
+	}
+	for _, resp := range responses {
+		resp.LoggedMinutes = seconds[resp.TaskID] / 60
+	}
+}
+
+func (uc *TaskUseCase) GetTimeEntries(taskID uint, userID uint) ([]*task.TimeEntryResponse, error) {
+	op := "TaskUseCase.GetTimeEntries"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)), slog.Uint64("userID", uint64(userID)))
+
+	if _, err := uc.getAccessibleTask(taskID, userID); err != nil {
+		return nil, err
+	}
+
+	entries, err := uc.repo.GetTimeEntries(taskID)
+	if err != nil {
+		log.Error("failed to get time entries", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	log.Info("time entries retrieved", slog.Int("count", len(entries)))
+	return task.ToTimeEntryResponseList(entries), nil
+}
+
+// CreateTimeEntry добавляет ручную запись. Запись не может заканчиваться в будущем.
+func (uc *TaskUseCase) CreateTimeEntry(taskID uint, userID uint, req task.CreateTimeEntryRequest) (*task.TimeEntryResponse, error) {
+	op := "TaskUseCase.CreateTimeEntry"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)), slog.Uint64("userID", uint64(userID)))
+
+	t, err := uc.getAccessibleTask(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkTaskContributor(t, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	duration := time.Duration(req.Minutes) * time.Minute
+	startedAt := now.Add(-duration)
+	if req.StartedAt != nil {
+		startedAt = *req.StartedAt
+	}
+	endedAt := startedAt.Add(duration)
+	if endedAt.After(now.Add(time.Minute)) {
+		log.Warn("time entry ends in the future", "endedAt", endedAt)
+		return nil, task.ErrTaskInvalidInput
+	}
+
+	entry, err := uc.repo.CreateTimeEntry(&task.TimeEntry{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: startedAt,
+		EndedAt:   &endedAt,
+		Note:      req.Note,
+	})
+	if err != nil {
+		log.Error("failed to create time entry", "error", err)
+		return nil, err
+	}
+
+	log.Info("time entry created", slog.Uint64("entryID", uint64(entry.TimeEntryID)), slog.Int("minutes", req.Minutes))
+	return task.ToTimeEntryResponse(entry), nil
+}
+
+// StartTimer запускает таймер пользователя на задаче. Одновременно у пользователя может идти только один таймер.
+func (uc *TaskUseCase) StartTimer(taskID uint, userID uint, req task.StartTimerRequest) (*task.TimeEntryResponse, error) {
+	op := "TaskUseCase.StartTimer"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)), slog.Uint64("userID", uint64(userID)))
+
+	t, err := uc.getAccessibleTask(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkTaskContributor(t, userID); err != nil {
+		return nil, err
+	}
+
+	running, err := uc.repo.GetRunningTimeEntry(userID)
+	if err == nil {
+		log.Warn("user already has a running timer", "runningTaskID", running.TaskID)
+		return nil, task.ErrTimerAlreadyRunning
+	}
+	if !errors.Is(err, task.ErrTimeEntryNotFound) {
+		log.Error("failed to check running timer", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+
+	// Параллельный запуск отсекается уникальным индексом, репозиторий вернет ErrTimerAlreadyRunning
+	entry, err := uc.repo.CreateTimeEntry(&task.TimeEntry{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: time.Now(),
+		Note:      req.Note,
+	})
+	if err != nil {
+		log.Error("failed to start timer", "error", err)
+		return nil, err
+	}
+
+	log.Info("timer started", slog.Uint64("entryID", uint64(entry.TimeEntryID)))
+	return task.ToTimeEntryResponse(entry), nil
+}
+
+// StopTimer останавливает таймер пользователя на задаче. Доступ к задаче не проверяется:
+// свой таймер можно остановить, даже если задача уже в корзине или пользователь вышел из команды.
+func (uc *TaskUseCase) StopTimer(taskID uint, userID uint) (*task.TimeEntryResponse, error) {
+	op := "TaskUseCase.StopTimer"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)), slog.Uint64("userID", uint64(userID)))
+
+	running, err := uc.repo.GetRunningTimeEntry(userID)
+	if err != nil {
+		if errors.Is(err, task.ErrTimeEntryNotFound) {
+			return nil, task.ErrTimeEntryNotFound
+		}
+		log.Error("failed to get running timer", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	if running.TaskID != taskID {
+		log.Warn("running timer belongs to another task", "runningTaskID", running.TaskID)
+		return nil, task.ErrTimeEntryNotFound
+	}
+
+	entry, err := uc.repo.StopTimeEntry(running.TimeEntryID, time.Now())
+	if err != nil {
+		log.Error("failed to stop timer", "error", err)
+		return nil, err
+	}
+
+	log.Info("timer stopped", slog.Uint64("entryID", uint64(entry.TimeEntryID)))
+	return task.ToTimeEntryResponse(entry), nil
+}
+
+// DeleteTimeEntry удаляет запись (в том числе запущенный таймер). Удалить можно только свою запись.
+func (uc *TaskUseCase) DeleteTimeEntry(taskID uint, entryID uint, userID uint) error {
+	op := "TaskUseCase.DeleteTimeEntry"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("taskID", uint64(taskID)), slog.Uint64("entryID", uint64(entryID)), slog.Uint64("userID", uint64(userID)))
+
+	entry, err := uc.repo.GetTimeEntryByID(entryID)
+	if err != nil {
+		if errors.Is(err, task.ErrTimeEntryNotFound) {
+			return task.ErrTimeEntryNotFound
+		}
+		log.Error("failed to get time entry", "error", err)
+		return task.ErrTaskInternal
+	}
+	if entry.TaskID != taskID || entry.UserID != userID {
+		log.Warn("time entry does not belong to task or user", "entryTaskID", entry.TaskID, "entryUserID", entry.UserID)
+		return task.ErrTimeEntryNotFound
+	}
+
+	if err := uc.repo.DeleteTimeEntry(entryID); err != nil {
+		log.Error("failed to delete time entry", "error", err)
+		return err
+	}
+
+	log.Info("time entry deleted")
+	return nil
+}
+
+// GetRunningTimer возвращает запущенный таймер пользователя или nil, если таймер не идет.
+func (uc *TaskUseCase) GetRunningTimer(userID uint) (*task.TimeEntryResponse, error) {
+	op := "TaskUseCase.GetRunningTimer"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	running, err := uc.repo.GetRunningTimeEntry(userID)
+	if err != nil {
+		if errors.Is(err, task.ErrTimeEntryNotFound) {
+			return nil, nil
+		}
+		log.Error("failed to get running timer", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	return task.ToTimeEntryResponse(running), nil
+}
+
+// GetTeamTimeReport строит отчет по учтенному времени команды. Доступен владельцу и администраторам команды.
+func (uc *TaskUseCase) GetTeamTimeReport(teamID uint, userID uint, req task.GetTimeReportRequest) (*task.TimeReportResponse, error) {
+	op := "TaskUseCase.GetTeamTimeReport"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	canView, err := uc.teamService.CanUserViewTeamActivity(userID, teamID)
+	if err != nil {
+		log.Error("failed to check team report permission", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	if !canView {
+		log.Warn("user lacks permission to view team time report")
+		return nil, task.ErrTaskAccessDenied
+	}
+
+	params := task.TimeReportParams{
+		TeamID:   teamID,
+		From:     req.From,
+		To:       req.To,
+		GroupBy:  task.TimeReportByMember,
+		MemberID: req.MemberID,
+		TagID:    req.TagID,
+	}
+	if req.GroupBy != nil {
+		params.GroupBy = *req.GroupBy
+	}
+	if params.From != nil && params.To != nil && !params.From.Before(*params.To) {
+		log.Warn("empty report period", "from", params.From, "to", params.To)
+		return nil, task.ErrTaskInvalidInput
+	}
+
+	rows, total, err := uc.repo.GetTimeReport(params)
+	if err != nil {
+		log.Error("failed to get time report", "error", err)
+		return nil, task.ErrTaskInternal
+	}
+	if rows == nil {
+		rows = []*task.TimeReportRow{}
+	}
+
+	log.Info("team time report built", slog.Int("rows", len(rows)), slog.Int64("totalMinutes", total))
+	return &task.TimeReportResponse{
+		TeamID:       teamID,
+		From:         params.From,
+		To:           params.To,
+		GroupBy:      params.GroupBy,
+		TotalMinutes: total,
+		Rows:         rows,
+	}, nil
+}
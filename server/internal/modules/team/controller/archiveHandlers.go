@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+
+	"server/internal/modules/team"
+	resp "server/pkg/lib/response"
+)
+
+func (c *TeamController) ArchiveTeam(w http.ResponseWriter, r *http.Request) {
+	c.changeTeamArchiveState(w, r, "TeamController.ArchiveTeam", c.useCase.ArchiveTeam)
+}
+
+func (c *TeamController) UnarchiveTeam(w http.ResponseWriter, r *http.Request) {
+	c.changeTeamArchiveState(w, r, "TeamController.UnarchiveTeam", c.useCase.UnarchiveTeam)
+}
+
+func (c *TeamController) changeTeamArchiveState(w http.ResponseWriter, r *http.Request, op string,
+	change func(teamID uint, userID uint) (*team.TeamResponse, error)) {
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	teamIDStr := chi.URLParam(r, "teamID")
+	teamID, err := strconv.ParseUint(teamIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return
+	}
+	log = log.With(slog.Uint64("teamID", teamID))
+
+	updated, err := change(uint(teamID), userID)
+	if err != nil {
+		log.Warn("usecase failed to change team archive state", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrTeamAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to change team archive state")
+		}
+		return
+	}
+	log.Info("team archive state changed", slog.Bool("archived", updated.IsArchived))
+	resp.SendSuccess(w, r, http.StatusOK, updated)
+}
@@ -101,13 +101,15 @@ func (c *TeamController) JoinTeamByToken(w http.ResponseWriter, r *http.Request)
 
 	log = log.With(slog.String("token_value_hash", hashTokenForLog(req.InviteToken)))
 
-	teamResponse, err := c.useCase.JoinTeamByToken(req.InviteToken, userID)
+	result, err := c.useCase.JoinTeamByToken(req.InviteToken, userID)
 	if err != nil {
 		log.Error("usecase JoinTeamByToken failed", "error", err)
 		switch {
 		case errors.Is(err, team.ErrTeamInviteTokenInvalid):
 			resp.SendError(w, r, http.StatusConflict, err.Error())
-		case errors.Is(err, team.ErrUserAlreadyMember):
+		case errors.Is(err, team.ErrTeamInviteEmailMismatch):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		case errors.Is(err, team.ErrUserAlreadyMember), errors.Is(err, team.ErrJoinRequestAlreadyPending):
 			resp.SendError(w, r, http.StatusConflict, err.Error())
 		case errors.Is(err, team.ErrTeamNotFound):
 			resp.SendError(w, r, http.StatusNotFound, err.Error())
@@ -116,8 +118,89 @@ func (c *TeamController) JoinTeamByToken(w http.ResponseWriter, r *http.Request)
 		}
 		return
 	}
+	if result.Request != nil {
+		log.Info("invite routed to join request approval", slog.Uint64("requestID", uint64(result.Request.RequestID)))
+		resp.SendSuccess(w, r, http.StatusAccepted, result.Request)
+		return
+	}
 	log.Info("user successfully joined team by token")
-	resp.SendSuccess(w, r, http.StatusOK, teamResponse)
+	resp.SendSuccess(w, r, http.StatusOK, result.Team)
+}
+
+func (c *TeamController) GetTeamInvites(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.GetTeamInvites"
+	log := c.log.With(slog.String("op", op))
+
+	currentUserID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("currentUserID", uint64(currentUserID)))
+
+	teamIDStr := chi.URLParam(r, "teamID")
+	teamID, err := strconv.ParseUint(teamIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return
+	}
+	log = log.With(slog.Uint64("parsedTeamID", teamID))
+
+	invites, err := c.useCase.GetTeamInvites(uint(teamID), currentUserID)
+	if err != nil {
+		log.Warn("usecase GetTeamInvites failed", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrTeamAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to retrieve team invites")
+		}
+		return
+	}
+	resp.SendSuccess(w, r, http.StatusOK, invites)
+}
+
+func (c *TeamController) RevokeTeamInvite(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.RevokeTeamInvite"
+	log := c.log.With(slog.String("op", op))
+
+	currentUserID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("currentUserID", uint64(currentUserID)))
+
+	teamIDStr := chi.URLParam(r, "teamID")
+	teamID, err := strconv.ParseUint(teamIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return
+	}
+	inviteIDStr := chi.URLParam(r, "inviteID")
+	inviteID, err := strconv.ParseUint(inviteIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Invite ID format")
+		return
+	}
+	log = log.With(slog.Uint64("parsedTeamID", teamID), slog.Uint64("inviteID", inviteID))
+
+	if err := c.useCase.RevokeTeamInvite(uint(teamID), currentUserID, uint(inviteID)); err != nil {
+		log.Error("usecase RevokeTeamInvite failed", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound), errors.Is(err, team.ErrTeamInviteNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrTeamAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to revoke team invite")
+		}
+		return
+	}
+	log.Info("team invite revoked successfully")
+	resp.SendOK(w, r, http.StatusNoContent)
 }
 
 func hashTokenForLog(token string) string {
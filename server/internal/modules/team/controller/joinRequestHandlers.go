@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	"server/internal/modules/team"
+	resp "server/pkg/lib/response"
+)
+
+func (c *TeamController) RequestToJoinTeam(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.RequestToJoinTeam"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	var req team.CreateJoinRequestRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body for RequestToJoinTeam", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed for CreateJoinRequestRequest", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	result, err := c.useCase.RequestToJoinTeam(userID, req)
+	if err != nil {
+		log.Warn("usecase RequestToJoinTeam failed", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrTeamJoinNotAllowed):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		case errors.Is(err, team.ErrUserAlreadyMember), errors.Is(err, team.ErrJoinRequestAlreadyPending):
+			resp.SendError(w, r, http.StatusConflict, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to request to join team")
+		}
+		return
+	}
+
+	if result.Request != nil {
+		log.Info("join request submitted", slog.Uint64("requestID", uint64(result.Request.RequestID)))
+		resp.SendSuccess(w, r, http.StatusAccepted, result.Request)
+		return
+	}
+	log.Info("user joined team without approval")
+	resp.SendSuccess(w, r, http.StatusOK, result.Team)
+}
+
+func (c *TeamController) GetTeamJoinRequests(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.GetTeamJoinRequests"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	teamIDStr := chi.URLParam(r, "teamID")
+	teamID, err := strconv.ParseUint(teamIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return
+	}
+	log = log.With(slog.Uint64("teamID", teamID))
+
+	requests, err := c.useCase.GetTeamJoinRequests(uint(teamID), userID)
+	if err != nil {
+		log.Warn("usecase GetTeamJoinRequests failed", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrTeamAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to retrieve join requests")
+		}
+		return
+	}
+	resp.SendSuccess(w, r, http.StatusOK, requests)
+}
+
+func (c *TeamController) ApproveJoinRequest(w http.ResponseWriter, r *http.Request) {
+	c.decideJoinRequest(w, r, "TeamController.ApproveJoinRequest", c.useCase.ApproveJoinRequest)
+}
+
+func (c *TeamController) RejectJoinRequest(w http.ResponseWriter, r *http.Request) {
+	c.decideJoinRequest(w, r, "TeamController.RejectJoinRequest", c.useCase.RejectJoinRequest)
+}
+
+// decideJoinRequest - общий обработчик одобрения и отклонения заявки на вступление
+func (c *TeamController) decideJoinRequest(w http.ResponseWriter, r *http.Request, op string,
+	decide func(teamID uint, userID uint, requestID uint) (*team.TeamJoinRequestResponse, error)) {
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	teamIDStr := chi.URLParam(r, "teamID")
+	teamID, err := strconv.ParseUint(teamIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return
+	}
+	requestIDStr := chi.URLParam(r, "requestID")
+	requestID, err := strconv.ParseUint(requestIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Request ID format")
+		return
+	}
+	log = log.With(slog.Uint64("teamID", teamID), slog.Uint64("requestID", requestID))
+
+	request, err := decide(uint(teamID), userID, uint(requestID))
+	if err != nil {
+		log.Error("join request decision failed", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound), errors.Is(err, team.ErrJoinRequestNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrTeamAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to process join request")
+		}
+		return
+	}
+
+	log.Info("join request decided", slog.String("status", string(request.Status)))
+	resp.SendSuccess(w, r, http.StatusOK, request)
+}
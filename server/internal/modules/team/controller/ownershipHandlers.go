@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	"server/internal/modules/team"
+	resp "server/pkg/lib/response"
+)
+
+func (c *TeamController) StartOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.StartOwnershipTransfer"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	teamIDStr := chi.URLParam(r, "teamID")
+	teamID, err := strconv.ParseUint(teamIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return
+	}
+	log = log.With(slog.Uint64("teamID", teamID))
+
+	var req team.StartOwnershipTransferRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body for StartOwnershipTransfer", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed for StartOwnershipTransferRequest", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	transfer, err := c.useCase.StartOwnershipTransfer(uint(teamID), userID, req)
+	if err != nil {
+		log.Error("usecase StartOwnershipTransfer failed", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrUserNotMember):
+			resp.SendError(w, r, http.StatusNotFound, "Target user is not a member of this team")
+		case errors.Is(err, team.ErrTeamAccessDenied), errors.Is(err, team.ErrCannotPerformActionOnSelf), errors.Is(err, team.ErrOwnershipTransferToGuest):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to start ownership transfer")
+		}
+		return
+	}
+
+	log.Info("ownership transfer started")
+	resp.SendSuccess(w, r, http.StatusCreated, transfer)
+}
+
+func (c *TeamController) GetOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.GetOwnershipTransfer"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	teamIDStr := chi.URLParam(r, "teamID")
+	teamID, err := strconv.ParseUint(teamIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return
+	}
+	log = log.With(slog.Uint64("teamID", teamID))
+
+	transfer, err := c.useCase.GetOwnershipTransfer(uint(teamID), userID)
+	if err != nil {
+		log.Warn("usecase GetOwnershipTransfer failed", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound), errors.Is(err, team.ErrOwnershipTransferNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrTeamAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to get ownership transfer")
+		}
+		return
+	}
+
+	resp.SendSuccess(w, r, http.StatusOK, transfer)
+}
+
+func (c *TeamController) CancelOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.CancelOwnershipTransfer"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	teamIDStr := chi.URLParam(r, "teamID")
+	teamID, err := strconv.ParseUint(teamIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return
+	}
+	log = log.With(slog.Uint64("teamID", teamID))
+
+	if err := c.useCase.CancelOwnershipTransfer(uint(teamID), userID); err != nil {
+		log.Error("usecase CancelOwnershipTransfer failed", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound), errors.Is(err, team.ErrOwnershipTransferNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrTeamAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to cancel ownership transfer")
+		}
+		return
+	}
+
+	log.Info("ownership transfer canceled")
+	resp.SendOK(w, r, http.StatusNoContent)
+}
+
+func (c *TeamController) AcceptOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.AcceptOwnershipTransfer"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	teamIDStr := chi.URLParam(r, "teamID")
+	teamID, err := strconv.ParseUint(teamIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return
+	}
+	log = log.With(slog.Uint64("teamID", teamID))
+
+	teamResponse, err := c.useCase.AcceptOwnershipTransfer(uint(teamID), userID)
+	if err != nil {
+		log.Error("usecase AcceptOwnershipTransfer failed", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound), errors.Is(err, team.ErrOwnershipTransferNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrTeamAccessDenied), errors.Is(err, team.ErrOwnershipTransferToGuest):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to accept ownership transfer")
+		}
+		return
+	}
+
+	log.Info("ownership transfer accepted")
+	resp.SendSuccess(w, r, http.StatusOK, teamResponse)
+}
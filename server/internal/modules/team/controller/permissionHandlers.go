@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	"server/internal/modules/team"
+	resp "server/pkg/lib/response"
+)
+
+func (c *TeamController) GetTeamPermissions(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.GetTeamPermissions"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	teamIDStr := chi.URLParam(r, "teamID")
+	teamID, err := strconv.ParseUint(teamIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return
+	}
+	log = log.With(slog.Uint64("teamID", teamID))
+
+	permissions, err := c.useCase.GetTeamPermissions(uint(teamID), userID)
+	if err != nil {
+		log.Warn("usecase GetTeamPermissions failed", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrTeamAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to get team permissions")
+		}
+		return
+	}
+
+	resp.SendSuccess(w, r, http.StatusOK, permissions)
+}
+
+func (c *TeamController) UpdateTeamPermissions(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.UpdateTeamPermissions"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	teamIDStr := chi.URLParam(r, "teamID")
+	teamID, err := strconv.ParseUint(teamIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return
+	}
+	log = log.With(slog.Uint64("teamID", teamID))
+
+	var req team.UpdateTeamPermissionsRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body for UpdateTeamPermissions", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed for UpdateTeamPermissionsRequest", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	permissions, err := c.useCase.UpdateTeamPermissions(uint(teamID), userID, req)
+	if err != nil {
+		log.Error("usecase UpdateTeamPermissions failed", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrTeamAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		case errors.Is(err, team.ErrTeamPermissionInvalid):
+			resp.SendError(w, r, http.StatusUnprocessableEntity, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to update team permissions")
+		}
+		return
+	}
+
+	log.Info("team permissions updated")
+	resp.SendSuccess(w, r, http.StatusOK, permissions)
+}
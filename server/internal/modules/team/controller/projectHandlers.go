@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	"server/internal/modules/team"
+	resp "server/pkg/lib/response"
+)
+
+// parseProjectRoute достает из запроса пользователя, команду и (если нужно) проект.
+// При ошибке ответ уже отправлен и ok = false.
+func (c *TeamController) parseProjectRoute(w http.ResponseWriter, r *http.Request, withProject bool) (userID, teamID, projectID uint, ok bool) {
+	userID, ok = r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return 0, 0, 0, false
+	}
+	parsedTeamID, err := strconv.ParseUint(chi.URLParam(r, "teamID"), 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return 0, 0, 0, false
+	}
+	if withProject {
+		parsedProjectID, err := strconv.ParseUint(chi.URLParam(r, "projectID"), 10, 32)
+		if err != nil {
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid Project ID format")
+			return 0, 0, 0, false
+		}
+		projectID = uint(parsedProjectID)
+	}
+	return userID, uint(parsedTeamID), projectID, true
+}
+
+// sendProjectError отвечает на ошибку usecase проектов команды.
+func sendProjectError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	switch {
+	case errors.Is(err, team.ErrTeamNotFound), errors.Is(err, team.ErrProjectNotFound):
+		resp.SendError(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, team.ErrTeamAccessDenied):
+		resp.SendError(w, r, http.StatusForbidden, err.Error())
+	case errors.Is(err, team.ErrProjectInvalid):
+		resp.SendError(w, r, http.StatusUnprocessableEntity, err.Error())
+	default:
+		resp.SendError(w, r, http.StatusInternalServerError, fallback)
+	}
+}
+
+func (c *TeamController) GetTeamProjects(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.GetTeamProjects"
+	userID, teamID, _, ok := c.parseProjectRoute(w, r, false)
+	if !ok {
+		return
+	}
+	log := c.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(teamID)))
+
+	projects, err := c.useCase.GetTeamProjects(teamID, userID)
+	if err != nil {
+		log.Warn("usecase GetTeamProjects failed", "error", err)
+		sendProjectError(w, r, err, "Failed to get team projects")
+		return
+	}
+	resp.SendSuccess(w, r, http.StatusOK, projects)
+}
+
+func (c *TeamController) CreateTeamProject(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.CreateTeamProject"
+	userID, teamID, _, ok := c.parseProjectRoute(w, r, false)
+	if !ok {
+		return
+	}
+	log := c.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(teamID)))
+
+	var req team.CreateProjectRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body for CreateTeamProject", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed for CreateProjectRequest", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	project, err := c.useCase.CreateTeamProject(teamID, userID, req)
+	if err != nil {
+		log.Error("usecase CreateTeamProject failed", "error", err)
+		sendProjectError(w, r, err, "Failed to create team project")
+		return
+	}
+	log.Info("team project created", slog.Uint64("projectID", uint64(project.ProjectID)))
+	resp.SendSuccess(w, r, http.StatusCreated, project)
+}
+
+func (c *TeamController) GetTeamProject(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.GetTeamProject"
+	userID, teamID, projectID, ok := c.parseProjectRoute(w, r, true)
+	if !ok {
+		return
+	}
+	log := c.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(teamID)),
+		slog.Uint64("projectID", uint64(projectID)))
+
+	project, err := c.useCase.GetTeamProjectDetails(teamID, userID, projectID)
+	if err != nil {
+		log.Warn("usecase GetTeamProjectDetails failed", "error", err)
+		sendProjectError(w, r, err, "Failed to get team project")
+		return
+	}
+	resp.SendSuccess(w, r, http.StatusOK, project)
+}
+
+func (c *TeamController) UpdateTeamProject(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.UpdateTeamProject"
+	userID, teamID, projectID, ok := c.parseProjectRoute(w, r, true)
+	if !ok {
+		return
+	}
+	log := c.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(teamID)),
+		slog.Uint64("projectID", uint64(projectID)))
+
+	var req team.UpdateProjectRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body for UpdateTeamProject", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed for UpdateProjectRequest", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	project, err := c.useCase.UpdateTeamProject(teamID, userID, projectID, req)
+	if err != nil {
+		log.Error("usecase UpdateTeamProject failed", "error", err)
+		sendProjectError(w, r, err, "Failed to update team project")
+		return
+	}
+	log.Info("team project updated")
+	resp.SendSuccess(w, r, http.StatusOK, project)
+}
+
+func (c *TeamController) DeleteTeamProject(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.DeleteTeamProject"
+	userID, teamID, projectID, ok := c.parseProjectRoute(w, r, true)
+	if !ok {
+		return
+	}
+	log := c.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", uint64(teamID)),
+		slog.Uint64("projectID", uint64(projectID)))
+
+	if err := c.useCase.DeleteTeamProject(teamID, userID, projectID); err != nil {
+		log.Error("usecase DeleteTeamProject failed", "error", err)
+		sendProjectError(w, r, err, "Failed to delete team project")
+		return
+	}
+	log.Info("team project deleted")
+	resp.SendOK(w, r, http.StatusNoContent)
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+
+	"server/internal/modules/team"
+	resp "server/pkg/lib/response"
+)
+
+func (c *TeamController) GetTeamStats(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.GetTeamStats"
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	teamID, err := strconv.ParseUint(chi.URLParam(r, "teamID"), 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return
+	}
+	log := c.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)), slog.Uint64("teamID", teamID))
+
+	var req team.TeamStatsRequest
+	if from := r.URL.Query().Get("from"); from != "" {
+		req.From = &from
+	}
+	if to := r.URL.Query().Get("to"); to != "" {
+		req.To = &to
+	}
+	if err := c.validate.Struct(req); err != nil {
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	stats, err := c.useCase.GetTeamStats(uint(teamID), userID, req)
+	if err != nil {
+		log.Warn("usecase GetTeamStats failed", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrTeamAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		case errors.Is(err, team.ErrTeamStatsPeriodInvalid):
+			resp.SendError(w, r, http.StatusUnprocessableEntity, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to get team stats")
+		}
+		return
+	}
+	resp.SendSuccess(w, r, http.StatusOK, stats)
+}
@@ -114,6 +114,14 @@ func (c *TeamController) GetMyTeams(w http.ResponseWriter, r *http.Request) {
 	if searchStr := r.URL.Query().Get("search"); searchStr != "" {
 		reqParams.Search = &searchStr
 	}
+	if includeArchived := r.URL.Query().Get("include_archived"); includeArchived != "" {
+		parsed, err := strconv.ParseBool(includeArchived)
+		if err != nil {
+			resp.SendError(w, r, http.StatusBadRequest, "Invalid include_archived value")
+			return
+		}
+		reqParams.IncludeArchived = parsed
+	}
 
 	if err := c.validate.Struct(reqParams); err != nil {
 		log.Warn("validation failed for GetMyTeamsRequest", "error", err)
@@ -220,6 +228,10 @@ func (c *TeamController) UpdateTeam(w http.ResponseWriter, r *http.Request) {
 			resp.SendError(w, r, http.StatusConflict, err.Error())
 		case errors.Is(err, team.ErrTeamNoChanges): // Если не было изменений
 			resp.SendError(w, r, http.StatusBadRequest, err.Error()) // 400 Bad Request
+		case errors.Is(err, team.ErrTeamSlugInvalid), errors.Is(err, team.ErrTeamJoinPolicyInvalid), errors.Is(err, team.ErrTeamGuestTagInvalid):
+			resp.SendError(w, r, http.StatusBadRequest, err.Error())
+		case errors.Is(err, team.ErrTeamSlugTaken):
+			resp.SendError(w, r, http.StatusConflict, err.Error())
 		default:
 			resp.SendError(w, r, http.StatusInternalServerError, "Failed to update team")
 		}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+
+	"server/internal/modules/team"
+	resp "server/pkg/lib/response"
+)
+
+func (c *TeamController) GetDeletedTeams(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.GetDeletedTeams"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	teams, err := c.useCase.GetDeletedTeams(userID)
+	if err != nil {
+		log.Error("usecase GetDeletedTeams failed", "error", err)
+		resp.SendError(w, r, http.StatusInternalServerError, "Failed to get deleted teams")
+		return
+	}
+	resp.SendSuccess(w, r, http.StatusOK, teams)
+}
+
+func (c *TeamController) RestoreTeam(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.RestoreTeam"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	teamIDStr := chi.URLParam(r, "teamID")
+	teamID, err := strconv.ParseUint(teamIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return
+	}
+	log = log.With(slog.Uint64("teamID", teamID))
+
+	restored, err := c.useCase.RestoreTeam(uint(teamID), userID)
+	if err != nil {
+		log.Warn("usecase RestoreTeam failed", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrTeamAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to restore team")
+		}
+		return
+	}
+	log.Info("team restored")
+	resp.SendSuccess(w, r, http.StatusOK, restored)
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"log/slog"
+
+	"server/internal/modules/team"
+	resp "server/pkg/lib/response"
+)
+
+func (c *TeamController) GetTaskWorkflow(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.GetTaskWorkflow"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	teamIDStr := chi.URLParam(r, "teamID")
+	teamID, err := strconv.ParseUint(teamIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return
+	}
+	log = log.With(slog.Uint64("teamID", teamID))
+
+	workflow, err := c.useCase.GetTeamTaskWorkflow(uint(teamID), userID)
+	if err != nil {
+		log.Error("usecase GetTeamTaskWorkflow failed", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrTeamAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to get team task statuses")
+		}
+		return
+	}
+
+	resp.SendSuccess(w, r, http.StatusOK, workflow)
+}
+
+func (c *TeamController) UpdateTaskWorkflow(w http.ResponseWriter, r *http.Request) {
+	op := "TeamController.UpdateTaskWorkflow"
+	log := c.log.With(slog.String("op", op))
+
+	userID, ok := r.Context().Value("userId").(uint)
+	if !ok {
+		resp.SendError(w, r, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	log = log.With(slog.Uint64("userID", uint64(userID)))
+
+	teamIDStr := chi.URLParam(r, "teamID")
+	teamID, err := strconv.ParseUint(teamIDStr, 10, 32)
+	if err != nil {
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid Team ID format")
+		return
+	}
+	log = log.With(slog.Uint64("teamID", teamID))
+
+	var req team.UpdateTaskWorkflowRequest
+	if err := render.DecodeJSON(r.Body, &req); err != nil {
+		log.Warn("failed to decode request body for UpdateTaskWorkflow", "error", err)
+		resp.SendError(w, r, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := c.validate.Struct(req); err != nil {
+		log.Warn("validation failed for UpdateTaskWorkflowRequest", "error", err)
+		resp.SendValidationError(w, r, err)
+		return
+	}
+
+	workflow, err := c.useCase.UpdateTeamTaskWorkflow(uint(teamID), userID, req)
+	if err != nil {
+		log.Error("usecase UpdateTeamTaskWorkflow failed", "error", err)
+		switch {
+		case errors.Is(err, team.ErrTeamNotFound):
+			resp.SendError(w, r, http.StatusNotFound, err.Error())
+		case errors.Is(err, team.ErrTeamAccessDenied):
+			resp.SendError(w, r, http.StatusForbidden, err.Error())
+		case errors.Is(err, team.ErrTeamWorkflowInvalid):
+			resp.SendError(w, r, http.StatusUnprocessableEntity, err.Error())
+		case errors.Is(err, team.ErrTeamStatusInUse), errors.Is(err, team.ErrTeamIsDeleted):
+			resp.SendError(w, r, http.StatusConflict, err.Error())
+		default:
+			resp.SendError(w, r, http.StatusInternalServerError, "Failed to update team task statuses")
+		}
+		return
+	}
+
+	log.Info("team task statuses updated")
+	resp.SendSuccess(w, r, http.StatusOK, workflow)
+}
@@ -19,6 +19,7 @@ const (
 	RoleAdmin  TeamMemberRole = "admin"
 	RoleEditor TeamMemberRole = "editor" // Может управлять задачами команды
 	RoleMember TeamMemberRole = "member" // Может создавать задачи и менять статус назначенных ему
+	RoleGuest  TeamMemberRole = "guest"  // Только просмотр задач (возможно, лишь с тегом команды); чат - по настройке команды
 )
 
 // Scan Yemeni, чтобы GORM мог читать enum из БД
@@ -32,7 +33,7 @@ func (r *TeamMemberRole) Scan(value interface{}) error {
 		strVal = string(byteVal)
 	}
 	switch strVal {
-	case "owner", "admin", "editor", "member":
+	case "owner", "admin", "editor", "member", "guest":
 		*r = TeamMemberRole(strVal)
 		return nil
 	default:
@@ -43,7 +44,7 @@ func (r *TeamMemberRole) Scan(value interface{}) error {
 // Value Yemeni, чтобы GORM мог записывать enum в БД
 func (r TeamMemberRole) Value() (driver.Value, error) {
 	switch r {
-	case RoleOwner, RoleAdmin, RoleEditor, RoleMember:
+	case RoleOwner, RoleAdmin, RoleEditor, RoleMember, RoleGuest:
 		return string(r), nil
 	default:
 		return nil, fmt.Errorf("invalid TeamMemberRole value: %s", r)
@@ -53,12 +54,30 @@ func (r TeamMemberRole) Value() (driver.Value, error) {
 // IsValid проверяет, является ли значение TeamMemberRole допустимым
 func (r TeamMemberRole) IsValid() bool {
 	switch r {
-	case RoleOwner, RoleAdmin, RoleEditor, RoleMember:
+	case RoleOwner, RoleAdmin, RoleEditor, RoleMember, RoleGuest:
 		return true
 	}
 	return false
 }
 
+// TeamJoinPolicy - способ вступления в команду без приглашения
+type TeamJoinPolicy string
+
+const (
+	JoinPolicyInviteOnly       TeamJoinPolicy = "invite_only"        // Только по приглашению
+	JoinPolicyRequest          TeamJoinPolicy = "request"            // По заявке, которую одобряет администратор
+	JoinPolicyOpenWithinDomain TeamJoinPolicy = "open_within_domain" // Сразу, если подтвержденный email в домене команды
+)
+
+// JoinRequestStatus - статус заявки на вступление
+type JoinRequestStatus string
+
+const (
+	JoinRequestPending  JoinRequestStatus = "pending"
+	JoinRequestApproved JoinRequestStatus = "approved"
+	JoinRequestRejected JoinRequestStatus = "rejected"
+)
+
 // --- GORM Модели ---
 
 // Team - GORM модель для таблицы 'teams'
@@ -74,6 +93,17 @@ type Team struct {
 	IsDeleted       bool       `gorm:"default:false;not null;column:is_deleted"`
 	DeletedAt       *time.Time `gorm:"column:deleted_at"`
 
+	JoinPolicy             TeamJoinPolicy `gorm:"type:varchar(20);not null;default:'invite_only';column:join_policy"`
+	Slug                   *string        `gorm:"type:varchar(50);uniqueIndex;column:slug"`               // Публичный идентификатор для заявок на вступление
+	JoinEmailDomain        *string        `gorm:"type:varchar(100);column:join_email_domain"`             // Домен для политики open_within_domain
+	InviteApprovalRequired bool           `gorm:"not null;default:false;column:invite_approval_required"` // Вступление по приглашению тоже через заявку
+
+	GuestTagID       *uint `gorm:"column:guest_tag_id"`                              // Гости видят только задачи с этим тегом команды
+	GuestChatEnabled bool  `gorm:"not null;default:false;column:guest_chat_enabled"` // Гостям доступен чат команды
+
+	IsArchived bool       `gorm:"not null;default:false;column:is_archived"` // Архивная команда доступна только для чтения
+	ArchivedAt *time.Time `gorm:"column:archived_at"`
+
 	// Отношения для GORM (если нужны для Preload/Joins)
 	// Members []UserTeamMembership `gorm:"foreignKey:TeamID"`
 	// Tasks   []task.Task          `gorm:"foreignKey:TeamID"` // Потребует импорта task
@@ -83,6 +113,14 @@ func (Team) TableName() string {
 	return "teams"
 }
 
+// EffectiveJoinPolicy возвращает политику вступления; для записей без нее (старый кэш) - invite_only.
+func (t *Team) EffectiveJoinPolicy() TeamJoinPolicy {
+	if t.JoinPolicy == "" {
+		return JoinPolicyInviteOnly
+	}
+	return t.JoinPolicy
+}
+
 // UserTeamMembership - GORM модель для таблицы 'user_team_memberships'
 type UserTeamMembership struct {
 	UserID   uint           `gorm:"primaryKey;column:user_id;not null"` // Внешний ключ к Users
@@ -99,6 +137,168 @@ func (UserTeamMembership) TableName() string {
 	return "userteammemberships" // Обязательно с двойными кавычками
 }
 
+// TeamInvite - GORM модель для таблицы 'teaminvites' (приглашения в команду).
+// Токен хранится только в виде SHA-256; MaxUses = nil - без ограничения числа вступлений.
+type TeamInvite struct {
+	InviteID        uint           `gorm:"primaryKey;column:invite_id;autoIncrement"`
+	TeamID          uint           `gorm:"not null;column:team_id"`
+	TokenHash       string         `gorm:"type:char(64);not null;uniqueIndex;column:token_hash"`
+	CreatedByUserID *uint          `gorm:"column:created_by_user_id"`
+	Role            TeamMemberRole `gorm:"type:team_member_role;not null;default:'member';column:role"`
+	MaxUses         *int           `gorm:"column:max_uses"`
+	UseCount        int            `gorm:"not null;default:0;column:use_count"`
+	TargetEmail     *string        `gorm:"type:varchar(100);column:target_email"`
+	ExpiresAt       time.Time      `gorm:"not null;column:expires_at"`
+	RevokedAt       *time.Time     `gorm:"column:revoked_at"`
+	CreatedAt       time.Time      `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (TeamInvite) TableName() string {
+	return "teaminvites"
+}
+
+// IsActive сообщает, что по приглашению еще можно вступить в команду.
+func (i *TeamInvite) IsActive(now time.Time) bool {
+	return i.RevokedAt == nil && now.Before(i.ExpiresAt) && (i.MaxUses == nil || i.UseCount < *i.MaxUses)
+}
+
+// TeamJoinRequest - GORM модель для таблицы 'teamjoinrequests' (заявки на вступление в команду).
+// InviteID указан, если заявка создана при вступлении по приглашению; Role - роль после одобрения.
+type TeamJoinRequest struct {
+	RequestID       uint              `gorm:"primaryKey;column:request_id;autoIncrement"`
+	TeamID          uint              `gorm:"not null;column:team_id"`
+	UserID          uint              `gorm:"not null;column:user_id"`
+	InviteID        *uint             `gorm:"column:invite_id"`
+	Role            TeamMemberRole    `gorm:"type:team_member_role;not null;default:'member';column:role"`
+	Message         *string           `gorm:"type:varchar(500);column:message"`
+	Status          JoinRequestStatus `gorm:"type:varchar(10);not null;default:'pending';column:status"`
+	DecidedByUserID *uint             `gorm:"column:decided_by_user_id"`
+	DecidedAt       *time.Time        `gorm:"column:decided_at"`
+	CreatedAt       time.Time         `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (TeamJoinRequest) TableName() string {
+	return "teamjoinrequests"
+}
+
+// OwnershipTransfer - GORM модель для таблицы 'teamownershiptransfers' (ожидающая передача владения командой).
+// У команды не больше одной заявки; истекшая заявка считается отсутствующей.
+type OwnershipTransfer struct {
+	TeamID     uint      `gorm:"primaryKey;column:team_id;autoIncrement:false"`
+	FromUserID uint      `gorm:"not null;column:from_user_id"`
+	ToUserID   uint      `gorm:"not null;column:to_user_id"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+	ExpiresAt  time.Time `gorm:"column:expires_at;not null"`
+}
+
+func (OwnershipTransfer) TableName() string {
+	return "teamownershiptransfers"
+}
+
+// --- Статусы задач команды ---
+
+// TaskStatusCategory - категория статуса задачи. Определяет CompletedAt и фильтрацию.
+type TaskStatusCategory string
+
+const (
+	StatusCategoryOpen   TaskStatusCategory = "open"
+	StatusCategoryActive TaskStatusCategory = "active"
+	StatusCategoryDone   TaskStatusCategory = "done"
+)
+
+// TeamTaskStatus - GORM модель для таблицы 'teamtaskstatuses'
+type TeamTaskStatus struct {
+	StatusID  uint               `gorm:"primaryKey;column:status_id;autoIncrement"`
+	TeamID    uint               `gorm:"column:team_id;not null"`
+	Key       string             `gorm:"type:varchar(50);not null;column:status_key"`
+	Name      string             `gorm:"type:varchar(50);not null;column:name"`
+	Category  TaskStatusCategory `gorm:"type:varchar(10);not null;column:category"`
+	Position  int                `gorm:"not null;default:0;column:position"`
+	Color     *string            `gorm:"type:varchar(7);column:color"`
+	CreatedAt time.Time          `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time          `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (TeamTaskStatus) TableName() string {
+	return "teamtaskstatuses"
+}
+
+// TeamStatusTransition - GORM модель для таблицы 'teamstatustransitions'.
+// Role == nil означает правило для всех ролей.
+type TeamStatusTransition struct {
+	TransitionID  uint            `gorm:"primaryKey;column:transition_id;autoIncrement"`
+	TeamID        uint            `gorm:"column:team_id;not null"`
+	FromStatusKey string          `gorm:"type:varchar(50);not null;column:from_status_key"`
+	ToStatusKey   string          `gorm:"type:varchar(50);not null;column:to_status_key"`
+	Role          *TeamMemberRole `gorm:"type:team_member_role;column:role"`
+}
+
+func (TeamStatusTransition) TableName() string {
+	return "teamstatustransitions"
+}
+
+// TaskWorkflow - упорядоченный набор статусов и правила переходов между ними
+type TaskWorkflow struct {
+	Statuses    []*TeamTaskStatus
+	Transitions []*TeamStatusTransition
+	IsDefault   bool
+}
+
+// DefaultTaskWorkflow возвращает набор статусов по умолчанию (личные задачи и команды без своего набора).
+func DefaultTaskWorkflow() *TaskWorkflow {
+	return &TaskWorkflow{
+		Statuses: []*TeamTaskStatus{
+			{Key: "todo", Name: "To Do", Category: StatusCategoryOpen, Position: 0},
+			{Key: "in_progress", Name: "In Progress", Category: StatusCategoryActive, Position: 1},
+			{Key: "deferred", Name: "Deferred", Category: StatusCategoryOpen, Position: 2},
+			{Key: "done", Name: "Done", Category: StatusCategoryDone, Position: 3},
+		},
+		IsDefault: true,
+	}
+}
+
+// Status возвращает статус по ключу или nil, если такого статуса нет в наборе.
+func (w *TaskWorkflow) Status(key string) *TeamTaskStatus {
+	for _, s := range w.Statuses {
+		if s.Key == key {
+			return s
+		}
+	}
+	return nil
+}
+
+// InitialStatus возвращает статус для новых задач: первый статус категории open (или просто первый).
+func (w *TaskWorkflow) InitialStatus() *TeamTaskStatus {
+	for _, s := range w.Statuses {
+		if s.Category == StatusCategoryOpen {
+			return s
+		}
+	}
+	if len(w.Statuses) > 0 {
+		return w.Statuses[0]
+	}
+	return nil
+}
+
+// CanTransition проверяет, разрешен ли переход для роли.
+// Если для роли нет ни одного правила (включая общие с Role == nil), переход разрешен.
+func (w *TaskWorkflow) CanTransition(from, to string, role *TeamMemberRole) bool {
+	if from == to {
+		return true
+	}
+	hasRules := false
+	for _, t := range w.Transitions {
+		if t.Role != nil && (role == nil || *t.Role != *role) {
+			continue
+		}
+		hasRules = true
+		if t.FromStatusKey == from && t.ToStatusKey == to {
+			return true
+		}
+	}
+	return !hasRules
+}
+
 // --- DTO для Ответов API ---
 
 // UserLiteResponse - упрощенное DTO для отображения информации о пользователе (например, в списке участников)
@@ -129,6 +329,20 @@ type TeamResponse struct {
 	IsDeleted       bool            `json:"is_deleted"`
 	CurrentUserRole *TeamMemberRole `json:"current_user_role,omitempty"` // Роль текущего пользователя в этой команде
 	MemberCount     int             `json:"member_count"`
+
+	JoinPolicy             TeamJoinPolicy `json:"join_policy"`
+	Slug                   *string        `json:"slug,omitempty"`
+	JoinEmailDomain        *string        `json:"join_email_domain,omitempty"`
+	InviteApprovalRequired bool           `json:"invite_approval_required"`
+
+	GuestTagID       *uint `json:"guest_tag_id,omitempty"`
+	GuestChatEnabled bool  `json:"guest_chat_enabled"`
+
+	IsArchived bool       `json:"is_archived"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+
+	DeletedAt *time.Time `json:"deleted_at,omitempty"` // Для команд в корзине
+	PurgeAt   *time.Time `json:"purge_at,omitempty"`   // Когда команда будет удалена окончательно
 }
 
 // TeamDetailResponse - DTO для ответа API при получении детальной информации о команде (с участниками)
@@ -144,16 +358,104 @@ type TeamInviteLinkResponse struct {
 	ExpiresAt  time.Time `json:"expires_at"` // Когда ссылка перестанет действовать
 }
 
-// TeamInviteTokenResponse - DTO для ответа при генерации токена-приглашения
+// TeamInviteTokenResponse - DTO для ответа при генерации токена-приглашения.
+// Токен возвращается только здесь: в БД хранится его хэш.
 type TeamInviteTokenResponse struct {
-	InviteToken string         `json:"invite_token"` // Сам токен
-	InviteLink  string         `json:"invite_link"`  // Полная ссылка-приглашение (формируется на фронте или здесь)
-	ExpiresAt   time.Time      `json:"expires_at"`   // Когда токен перестанет действовать
-	RoleOnJoin  TeamMemberRole `json:"role_on_join"` // Роль, которая будет назначена при вступлении
+	InviteID    uint           `json:"invite_id"`
+	InviteToken string         `json:"invite_token"`       // Сам токен
+	InviteLink  string         `json:"invite_link"`        // Полная ссылка-приглашение (формируется на фронте или здесь)
+	ExpiresAt   time.Time      `json:"expires_at"`         // Когда токен перестанет действовать
+	RoleOnJoin  TeamMemberRole `json:"role_on_join"`       // Роль, которая будет назначена при вступлении
+	MaxUses     *int           `json:"max_uses,omitempty"` // Не указано - без ограничения
+	Email       *string        `json:"email,omitempty"`    // Адрес, на который отправлено приглашение
+	EmailSent   bool           `json:"email_sent"`         // Письмо с приглашением отправлено
+}
+
+// TeamInviteResponse - DTO приглашения в списке приглашений команды (без токена)
+type TeamInviteResponse struct {
+	InviteID   uint              `json:"invite_id"`
+	RoleOnJoin TeamMemberRole    `json:"role_on_join"`
+	CreatedBy  *UserLiteResponse `json:"created_by,omitempty"`
+	MaxUses    *int              `json:"max_uses,omitempty"`
+	UseCount   int               `json:"use_count"`
+	Email      *string           `json:"email,omitempty"`
+	ExpiresAt  time.Time         `json:"expires_at"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// TeamJoinRequestResponse - DTO заявки на вступление в команду
+type TeamJoinRequestResponse struct {
+	RequestID       uint              `json:"request_id"`
+	TeamID          uint              `json:"team_id"`
+	TeamName        string            `json:"team_name"`
+	User            UserLiteResponse  `json:"user"`
+	Role            TeamMemberRole    `json:"role"` // Роль после одобрения
+	Message         *string           `json:"message,omitempty"`
+	Status          JoinRequestStatus `json:"status"`
+	ViaInvite       bool              `json:"via_invite"` // Заявка создана при вступлении по приглашению
+	DecidedByUserID *uint             `json:"decided_by_user_id,omitempty"`
+	DecidedAt       *time.Time        `json:"decided_at,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+}
+
+// JoinTeamResult - результат попытки вступить в команду: либо пользователь уже участник (Team),
+// либо создана заявка, ожидающая одобрения администратора (Request).
+type JoinTeamResult struct {
+	Team    *TeamResponse
+	Request *TeamJoinRequestResponse
+}
+
+// TaskStatusResponse - DTO статуса задачи команды
+type TaskStatusResponse struct {
+	Key      string             `json:"key"`
+	Name     string             `json:"name"`
+	Category TaskStatusCategory `json:"category"`
+	Position int                `json:"position"`
+	Color    *string            `json:"color,omitempty"`
+}
+
+// StatusTransitionResponse - DTO правила перехода между статусами
+type StatusTransitionResponse struct {
+	From string          `json:"from"`
+	To   string          `json:"to"`
+	Role *TeamMemberRole `json:"role,omitempty"`
+}
+
+// OwnershipTransferResponse - DTO ожидающей передачи владения
+type OwnershipTransferResponse struct {
+	TeamID    uint             `json:"team_id"`
+	FromUser  UserLiteResponse `json:"from_user"`
+	ToUser    UserLiteResponse `json:"to_user"`
+	CreatedAt time.Time        `json:"created_at"`
+	ExpiresAt time.Time        `json:"expires_at"`
+}
+
+// TaskWorkflowResponse - DTO набора статусов команды
+type TaskWorkflowResponse struct {
+	Statuses    []*TaskStatusResponse       `json:"statuses"`
+	Transitions []*StatusTransitionResponse `json:"transitions"`
+	IsDefault   bool                        `json:"is_default"` // true, если команда использует набор по умолчанию
 }
 
 // --- Конвертеры (примеры, будут расширяться) ---
 
+func ToTaskWorkflowResponse(w *TaskWorkflow) *TaskWorkflowResponse {
+	resp := &TaskWorkflowResponse{
+		Statuses:    make([]*TaskStatusResponse, 0, len(w.Statuses)),
+		Transitions: make([]*StatusTransitionResponse, 0, len(w.Transitions)),
+		IsDefault:   w.IsDefault,
+	}
+	for _, s := range w.Statuses {
+		resp.Statuses = append(resp.Statuses, &TaskStatusResponse{
+			Key: s.Key, Name: s.Name, Category: s.Category, Position: s.Position, Color: s.Color,
+		})
+	}
+	for _, t := range w.Transitions {
+		resp.Transitions = append(resp.Transitions, &StatusTransitionResponse{From: t.FromStatusKey, To: t.ToStatusKey, Role: t.Role})
+	}
+	return resp
+}
+
 func ToTeamResponse(team *Team, imageBaseURL string, currentUserRole *TeamMemberRole) *TeamResponse {
 	if team == nil {
 		return nil
@@ -209,11 +511,22 @@ type UpdateTeamDetailsRequest struct {
 	Description *string `json:"description,omitempty" validate:"omitempty,max=65535"`
 	Color       *string `json:"color,omitempty" validate:"omitempty,hexcolor|rgb|rgba"`
 	ResetImage  *bool   `json:"reset_image,omitempty"` // Флаг для сброса изображения команды
+
+	// Настройки вступления. Пустая строка в slug или join_email_domain сбрасывает значение.
+	JoinPolicy             *TeamJoinPolicy `json:"join_policy,omitempty" validate:"omitempty,oneof=invite_only request open_within_domain"`
+	Slug                   *string         `json:"slug,omitempty" validate:"omitempty,max=50"`
+	JoinEmailDomain        *string         `json:"join_email_domain,omitempty" validate:"omitempty,max=100"`
+	InviteApprovalRequired *bool           `json:"invite_approval_required,omitempty"`
+
+	// Настройки гостей. guest_tag_id = 0 снимает ограничение по тегу.
+	GuestTagID       *uint `json:"guest_tag_id,omitempty"`
+	GuestChatEnabled *bool `json:"guest_chat_enabled,omitempty"`
 }
 
 // GetMyTeamsRequest - DTO для параметров запроса списка команд пользователя.
 type GetMyTeamsRequest struct {
-	Search *string `form:"search" validate:"omitempty,min=1"` // Поисковый запрос по названию команды
+	Search          *string `form:"search" validate:"omitempty,min=1"` // Поисковый запрос по названию команды
+	IncludeArchived bool    `form:"include_archived"`                  // По умолчанию архивные команды скрыты
 	// TODO: Добавить параметры сортировки, если потребуется в будущем
 	// SortBy    *string `form:"sort_by"`
 	// SortOrder *string `form:"sort_order"`
@@ -228,12 +541,19 @@ type AddTeamMemberRequest struct {
 	// Если добавляем по логину/email, то нужен будет UseCase пользователя для поиска.
 	// Для простоты начнем с UserID.
 	UserID uint            `json:"user_id" validate:"required,gt=0"`
-	Role   *TeamMemberRole `json:"role,omitempty" validate:"omitempty,oneof=admin editor member"` // Owner не назначается так
+	Role   *TeamMemberRole `json:"role,omitempty" validate:"omitempty,oneof=admin editor member guest"` // Owner не назначается так
 }
 
 // UpdateTeamMemberRoleRequest - DTO для изменения роли участника.
 type UpdateTeamMemberRoleRequest struct {
-	Role TeamMemberRole `json:"role" validate:"required,oneof=admin editor member"` // Owner не изменяется так
+	Role TeamMemberRole `json:"role" validate:"required,oneof=admin editor member guest"` // Owner не изменяется так
+}
+
+// StartOwnershipTransferRequest - DTO для передачи владения командой другому участнику.
+// Получатель должен принять передачу до истечения срока, иначе она отменяется.
+type StartOwnershipTransferRequest struct {
+	ToUserID       uint  `json:"to_user_id" validate:"required,gt=0"`
+	ExpiresInHours *uint `json:"expires_in_hours,omitempty" validate:"omitempty,min=1,max=168"` // По умолчанию 72 часа
 }
 
 type GenerateInviteTokenRequest struct {
@@ -241,9 +561,11 @@ type GenerateInviteTokenRequest struct {
 	ExpiresInHours *uint `json:"expires_in_hours,omitempty" validate:"omitempty,min=1,max=720"` // Например, от 1 часа до 30 дней (720 часов)
 	// Роль, которая будет назначена пользователю при вступлении по этому токену.
 	// По умолчанию 'member'. Owner/Admin не могут быть назначены через токен.
-	RoleToAssign *TeamMemberRole `json:"role_to_assign,omitempty" validate:"omitempty,oneof=editor member"`
-	// Можно добавить поле для максимального количества использований токена (пока не будем усложнять)
-	// MaxUses *int `json:"max_uses,omitempty" validate:"omitempty,min=1"`
+	RoleToAssign *TeamMemberRole `json:"role_to_assign,omitempty" validate:"omitempty,oneof=editor member guest"`
+	// Сколько раз можно вступить по приглашению. Не указано - без ограничения.
+	MaxUses *int `json:"max_uses,omitempty" validate:"omitempty,min=1,max=1000"`
+	// Адрес, на который приглашение отправляется письмом. Вступить сможет только пользователь с этим подтвержденным email.
+	Email *string `json:"email,omitempty" validate:"omitempty,email,max=100"`
 }
 
 // JoinTeamByTokenRequest - DTO для присоединения к команде по токену.
@@ -251,6 +573,37 @@ type JoinTeamByTokenRequest struct {
 	InviteToken string `json:"invite_token" validate:"required"`
 }
 
+// CreateJoinRequestRequest - DTO заявки на вступление. Команда указывается по ID или по публичному slug.
+type CreateJoinRequestRequest struct {
+	TeamID  *uint   `json:"team_id,omitempty" validate:"required_without=Slug,omitempty,gt=0"`
+	Slug    *string `json:"slug,omitempty" validate:"required_without=TeamID,omitempty,min=1,max=50"`
+	Message *string `json:"message,omitempty" validate:"omitempty,max=500"`
+}
+
+// --- DTO для настройки статусов задач ---
+
+// TaskStatusInput - описание статуса в запросе. Порядок в массиве задает порядок колонок.
+type TaskStatusInput struct {
+	Key      string             `json:"key" validate:"required,min=1,max=50"`
+	Name     string             `json:"name" validate:"required,min=1,max=50"`
+	Category TaskStatusCategory `json:"category" validate:"required,oneof=open active done"`
+	Color    *string            `json:"color,omitempty" validate:"omitempty,hexcolor"`
+}
+
+// StatusTransitionInput - правило перехода в запросе. Role не указана - правило для всех ролей.
+type StatusTransitionInput struct {
+	From string          `json:"from" validate:"required"`
+	To   string          `json:"to" validate:"required"`
+	Role *TeamMemberRole `json:"role,omitempty" validate:"omitempty,oneof=owner admin editor member guest"`
+}
+
+// UpdateTaskWorkflowRequest - DTO для замены набора статусов команды целиком.
+// Пустой список statuses возвращает команду к набору по умолчанию.
+type UpdateTaskWorkflowRequest struct {
+	Statuses    []TaskStatusInput       `json:"statuses" validate:"max=30,dive"`
+	Transitions []StatusTransitionInput `json:"transitions,omitempty" validate:"omitempty,max=200,dive"`
+}
+
 // --- Интерфейсы для модуля team ---
 
 type Controller interface {
@@ -259,6 +612,10 @@ type Controller interface {
 	GetMyTeams(w http.ResponseWriter, r *http.Request)
 	UpdateTeam(w http.ResponseWriter, r *http.Request)
 	DeleteTeam(w http.ResponseWriter, r *http.Request)
+	GetDeletedTeams(w http.ResponseWriter, r *http.Request)
+	RestoreTeam(w http.ResponseWriter, r *http.Request)
+	ArchiveTeam(w http.ResponseWriter, r *http.Request)
+	UnarchiveTeam(w http.ResponseWriter, r *http.Request)
 
 	GetTeamMembers(w http.ResponseWriter, r *http.Request)
 	AddTeamMember(w http.ResponseWriter, r *http.Request)
@@ -268,6 +625,32 @@ type Controller interface {
 
 	GenerateInviteToken(w http.ResponseWriter, r *http.Request) // Новый метод
 	JoinTeamByToken(w http.ResponseWriter, r *http.Request)     // Новый метод
+	GetTeamInvites(w http.ResponseWriter, r *http.Request)
+	RevokeTeamInvite(w http.ResponseWriter, r *http.Request)
+
+	RequestToJoinTeam(w http.ResponseWriter, r *http.Request)
+	GetTeamJoinRequests(w http.ResponseWriter, r *http.Request)
+	ApproveJoinRequest(w http.ResponseWriter, r *http.Request)
+	RejectJoinRequest(w http.ResponseWriter, r *http.Request)
+
+	GetTaskWorkflow(w http.ResponseWriter, r *http.Request)
+	UpdateTaskWorkflow(w http.ResponseWriter, r *http.Request)
+
+	StartOwnershipTransfer(w http.ResponseWriter, r *http.Request)
+	GetOwnershipTransfer(w http.ResponseWriter, r *http.Request)
+	CancelOwnershipTransfer(w http.ResponseWriter, r *http.Request)
+	AcceptOwnershipTransfer(w http.ResponseWriter, r *http.Request)
+
+	GetTeamPermissions(w http.ResponseWriter, r *http.Request)
+	UpdateTeamPermissions(w http.ResponseWriter, r *http.Request)
+
+	GetTeamProjects(w http.ResponseWriter, r *http.Request)
+	CreateTeamProject(w http.ResponseWriter, r *http.Request)
+	GetTeamProject(w http.ResponseWriter, r *http.Request)
+	UpdateTeamProject(w http.ResponseWriter, r *http.Request)
+	DeleteTeamProject(w http.ResponseWriter, r *http.Request)
+
+	GetTeamStats(w http.ResponseWriter, r *http.Request)
 }
 
 type UseCase interface {
@@ -275,7 +658,12 @@ type UseCase interface {
 	GetTeamByID(teamID uint, userID uint) (*TeamDetailResponse, error)
 	GetMyTeams(userID uint, params GetMyTeamsRequest) ([]*TeamResponse, error)
 	UpdateTeamDetails(teamID uint, userID uint, req UpdateTeamDetailsRequest, imageFileHeader interface{}) (*TeamResponse, error)
-	DeleteTeam(teamID uint, userID uint) error
+	DeleteTeam(teamID uint, userID uint) error // Команда попадает в корзину на 30 дней
+	GetDeletedTeams(userID uint) ([]*TeamResponse, error)
+	RestoreTeam(teamID uint, userID uint) (*TeamResponse, error)
+	PurgeExpiredTeams() // Окончательное удаление команд с истекшим сроком в корзине (по расписанию)
+	ArchiveTeam(teamID uint, userID uint) (*TeamResponse, error)
+	UnarchiveTeam(teamID uint, userID uint) (*TeamResponse, error)
 
 	GetTeamMembers(teamID uint, userID uint) ([]*TeamMemberResponse, error)
 	AddTeamMember(teamID uint, currentUserID uint, req AddTeamMemberRequest) (*TeamMemberResponse, error)
@@ -284,16 +672,50 @@ type UseCase interface {
 	LeaveTeam(teamID uint, userID uint) error
 
 	GenerateInviteToken(teamID uint, userID uint, req GenerateInviteTokenRequest) (*TeamInviteTokenResponse, error) // Новый метод
-	JoinTeamByToken(tokenValue string, userID uint) (*TeamResponse, error)                                          // Новый метод
+	JoinTeamByToken(tokenValue string, userID uint) (*JoinTeamResult, error)                                        // Request != nil, если нужно одобрение
+	GetTeamInvites(teamID uint, userID uint) ([]*TeamInviteResponse, error)
+	RevokeTeamInvite(teamID uint, userID uint, inviteID uint) error
+
+	RequestToJoinTeam(userID uint, req CreateJoinRequestRequest) (*JoinTeamResult, error)
+	GetTeamJoinRequests(teamID uint, userID uint) ([]*TeamJoinRequestResponse, error)
+	ApproveJoinRequest(teamID uint, userID uint, requestID uint) (*TeamJoinRequestResponse, error)
+	RejectJoinRequest(teamID uint, userID uint, requestID uint) (*TeamJoinRequestResponse, error)
+
+	GetTeamTaskWorkflow(teamID uint, userID uint) (*TaskWorkflowResponse, error)
+	UpdateTeamTaskWorkflow(teamID uint, userID uint, req UpdateTaskWorkflowRequest) (*TaskWorkflowResponse, error)
+
+	StartOwnershipTransfer(teamID uint, userID uint, req StartOwnershipTransferRequest) (*OwnershipTransferResponse, error)
+	GetOwnershipTransfer(teamID uint, userID uint) (*OwnershipTransferResponse, error)
+	CancelOwnershipTransfer(teamID uint, userID uint) error // Отмена владельцем или отказ получателя
+	AcceptOwnershipTransfer(teamID uint, userID uint) (*TeamResponse, error)
+
+	GetTeamPermissions(teamID uint, userID uint) (*TeamPermissionsResponse, error)
+	UpdateTeamPermissions(teamID uint, userID uint, req UpdateTeamPermissionsRequest) (*TeamPermissionsResponse, error)
+
+	GetTeamProjects(teamID uint, userID uint) ([]*ProjectResponse, error)
+	CreateTeamProject(teamID uint, userID uint, req CreateProjectRequest) (*ProjectResponse, error)
+	GetTeamProjectDetails(teamID uint, userID uint, projectID uint) (*ProjectResponse, error)
+	UpdateTeamProject(teamID uint, userID uint, projectID uint, req UpdateProjectRequest) (*ProjectResponse, error) // Право project.manage или руководитель проекта
+	DeleteTeamProject(teamID uint, userID uint, projectID uint) error
+
+	GetTeamStats(teamID uint, userID uint, req TeamStatsRequest) (*TeamStatsResponse, error) // Право activity.view
 
 	// Методы TeamService ... (без изменений)
 	IsUserMember(userID, teamID uint) (bool, error)
 	GetUserRoleInTeam(userID, teamID uint) (*TeamMemberRole, error)
+	HasTeamPermission(userID, teamID uint, permission Permission) (bool, error) // Все проверки прав в команде идут через матрицу
+	CheckTeamWritable(teamID uint) error                                        // ErrTeamArchived для архивной команды
+	GetGuestTaskTagScope(userID, teamID uint) (*uint, error)                    // Тег, которым ограничен просмотр задач для гостя
+	CanUserAccessTeamChat(userID, teamID uint) (bool, error)                    // Гостям чат доступен только по настройке команды
 	CanUserCreateTeamTask(userID, teamID uint) (bool, error)
 	CanUserEditTeamTaskDetails(userID, teamID uint) (bool, error)
-	CanUserChangeTeamTaskStatus(userID, teamID uint, taskAssignedToUserID *uint) (bool, error)
+	CanUserChangeTeamTaskStatus(userID, teamID uint, taskAssigneeIDs []uint) (bool, error)
 	CanUserDeleteTeamTask(userID, teamID uint, taskCreatorID uint) (bool, error)
 	IsUserTeamMemberWithUserID(teamID uint, targetUserID uint) (bool, error)
+	CanUserViewTeamActivity(userID, teamID uint) (bool, error)
+	GetTaskWorkflow(teamID uint) (*TaskWorkflow, error)
+	GetTeamProject(teamID, projectID uint) (*TeamProject, error) // ErrProjectNotFound, если проекта нет в команде
+	InvalidateTeamStats(teamID uint)                             // Вызывается модулями задач и чата после изменений
 }
 
 // Repo определяет методы для взаимодействия с хранилищем данных для команд.
@@ -332,10 +754,71 @@ type Repo interface {
 
 	UploadTeamImage(bucketName string, s3Key string, imageBytes []byte, contentType string) error
 	DeleteTeamImage(bucketName string, s3Key string) error
+	MoveTeamImage(bucketName string, fromKey string, toKey string) error // Перенос изображения в корзину и обратно
+	DeleteTeamImagesByPrefix(bucketName string, prefix string) error
 	GetTeamImagePublicURL(s3Key string) string
 
-	// Методы для хранения и проверки токенов приглашений (могут быть реализованы через Cache)
-	SaveInviteToken(token string, teamID uint, roleToAssign TeamMemberRole, expiresAt time.Time) error
-	GetInviteTokenData(token string) (teamID uint, roleToAssign TeamMemberRole, isValid bool, err error)
-	DeleteInviteToken(token string) error
+	// Приглашения в команду
+	CreateTeamInvite(invite *TeamInvite) (*TeamInvite, error)
+	GetTeamInviteByTokenHash(tokenHash string) (*TeamInvite, error)
+	GetActiveTeamInvites(teamID uint) ([]*TeamInvite, error)
+	RevokeTeamInvite(teamID uint, inviteID uint) error
+	DeleteTeamInvite(inviteID uint) error
+	RedeemTeamInvite(inviteID uint, membership *UserTeamMembership) error // Учитывает использование и создает членство атомарно
+	GetUserByID(userID uint) (*usermodels.User, error)
+
+	// Вступление по заявке
+	GetTeamBySlug(slug string) (*Team, error)
+	CreateJoinRequest(request *TeamJoinRequest) error // Для заявки по приглашению атомарно учитывает использование приглашения
+	GetJoinRequest(teamID uint, requestID uint) (*TeamJoinRequest, error)
+	GetPendingJoinRequests(teamID uint) ([]*TeamJoinRequest, error)
+	ApproveJoinRequest(request *TeamJoinRequest, decidedByUserID uint) error // Одобряет заявку и создает членство в одной транзакции
+	RejectJoinRequest(request *TeamJoinRequest, decidedByUserID uint) error
+
+	// Передача владения: заявка хранится в БД, смена ролей и удаление заявки выполняются одной транзакцией
+	SaveOwnershipTransfer(transfer *OwnershipTransfer) error      // Новая заявка заменяет прежнюю
+	GetOwnershipTransfer(teamID uint) (*OwnershipTransfer, error) // nil, если заявки нет или срок истек
+	DeleteOwnershipTransfer(teamID uint) error
+	TransferTeamOwnership(teamID, fromUserID, toUserID uint) error
+
+	// Матрица прав: в БД хранятся только отличия от прав по умолчанию
+	GetTeamPermissionOverrides(teamID uint) ([]*TeamRolePermission, error)
+	ReplaceTeamPermissionOverrides(teamID uint, overrides []*TeamRolePermission) error
+
+	// Данные для проверки прав: читаются из кэша, при промахе собираются из БД.
+	// Кэш сбрасывается изменениями команды, состава, ролей и матрицы прав
+	GetTeamAccess(teamID uint) (*TeamAccess, error)
+	InvalidateTeamAccess(teamID uint) error
+	InvalidateTeamCache(teamID uint) error // Команда, ее участники и данные для проверки прав
+
+	// Тег команды, которым ограничен просмотр задач гостями
+	TeamTagExists(teamID, tagID uint) (bool, error)
+
+	// Корзина команд: восстановление и окончательное удаление по истечении срока
+	GetDeletedTeamByID(teamID uint) (*Team, error)
+	GetDeletedTeamsByOwner(userID uint) ([]*Team, error)
+	GetExpiredDeletedTeams(deletedBefore time.Time, limit int) ([]*Team, error)
+	RestoreTeam(teamModel *Team, tasksDeletedSince time.Time) error // Команда и задачи, удаленные вместе с ней, в одной транзакции
+	HardDeleteTeam(teamID uint) error
+
+	// Статусы задач команды
+	GetTeamTaskStatuses(teamID uint) ([]*TeamTaskStatus, error)
+	GetTeamStatusTransitions(teamID uint) ([]*TeamStatusTransition, error)
+	ReplaceTeamTaskWorkflow(teamID uint, statuses []*TeamTaskStatus, transitions []*TeamStatusTransition) error // ErrTeamStatusInUse, если убираемый статус еще у задач
+	InvalidateTeamTaskLists(teamID uint) error                                                                  // Устаревание закэшированных списков задач команды
+
+	// Проекты команды и их прогресс по задачам
+	CreateTeamProject(project *TeamProject) (*TeamProject, error)
+	GetTeamProject(teamID, projectID uint) (*TeamProject, error)
+	GetTeamProjects(teamID uint) ([]*TeamProject, error)
+	UpdateTeamProject(project *TeamProject) (*TeamProject, error)
+	DeleteTeamProject(teamID, projectID uint) error
+	GetProjectsProgress(teamID uint) (map[uint]*ProjectProgress, error)
+
+	// Статистика команды: расчет в БД и кэш под версией, которая растет при изменениях
+	ComputeTeamStats(teamID uint, from, to time.Time) (*TeamStatsResponse, error) // Период [from, to)
+	GetTeamStatsVersion(teamID uint) (int64, error)
+	GetCachedTeamStats(teamID uint, version int64, from, to string) (*TeamStatsResponse, error) // nil, если в кэше нет
+	SaveTeamStats(version int64, stats *TeamStatsResponse) error
+	BumpTeamStatsVersion(teamID uint) error
 }
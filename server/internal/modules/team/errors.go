@@ -1,6 +1,9 @@
 package team
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrTeamNotFound используется, когда команда не найдена.
@@ -36,7 +39,7 @@ var (
 	ErrCannotRemoveLastOwner = errors.New("cannot remove the last owner of the team")
 
 	// ErrCannotChangeOwnerRole используется, если делается попытка изменить роль владельца команды.
-	// (т.к. передача владения не предусмотрена, роль owner-а не меняется).
+	// Роль owner-а меняется только через передачу владения.
 	ErrCannotChangeOwnerRole = errors.New("cannot change the role of the team owner")
 
 	// ErrCannotPerformActionOnSelf используется, когда пользователь пытается выполнить определенное действие над собой, которое не разрешено (например, админ пытается кикнуть сам себя).
@@ -60,9 +63,70 @@ var (
 	// ErrTeamInviteTokenInvalid или Expired.
 	ErrTeamInviteTokenInvalid = errors.New("team invite link/token is invalid or expired")
 
+	// ErrTeamInviteNotFound используется, если приглашение не найдено среди действующих приглашений команды.
+	ErrTeamInviteNotFound = errors.New("team invite not found")
+
+	// ErrTeamInviteEmailMismatch используется, если приглашение выдано на другой email или email пользователя не подтвержден.
+	ErrTeamInviteEmailMismatch = errors.New("team invite is addressed to another verified email")
+
+	// ErrTeamJoinNotAllowed используется, если политика вступления команды не позволяет вступить без приглашения
+	// (invite_only или email пользователя не подходит под домен команды).
+	ErrTeamJoinNotAllowed = errors.New("joining this team without an invite is not allowed")
+
+	// ErrJoinRequestNotFound используется, если заявка на вступление не найдена или уже рассмотрена.
+	ErrJoinRequestNotFound = errors.New("join request not found")
+
+	// ErrJoinRequestAlreadyPending используется, если у пользователя уже есть ожидающая заявка в эту команду.
+	ErrJoinRequestAlreadyPending = errors.New("join request is already pending")
+
+	// ErrTeamSlugInvalid используется, если slug не соответствует формату (3-50 символов: a-z, 0-9 и дефисы между ними).
+	ErrTeamSlugInvalid = errors.New("invalid team slug (3-50 chars: lowercase letters, digits and single hyphens)")
+
+	// ErrTeamSlugTaken используется, если slug уже занят другой командой.
+	ErrTeamSlugTaken = errors.New("team slug is already taken")
+
+	// ErrTeamJoinPolicyInvalid используется, если настройки вступления противоречивы
+	// (например, open_within_domain без домена или некорректный домен).
+	ErrTeamJoinPolicyInvalid = errors.New("invalid team join settings")
+
 	// ErrTeamIsDeleted используется при попытке выполнить операции с логически удаленной командой (кроме, возможно, восстановления).
 	ErrTeamIsDeleted = errors.New("operation not allowed on a deleted team")
 
+	// ErrTeamWorkflowInvalid используется, если набор статусов или правила переходов некорректны
+	// (дубликаты ключей, нет статуса категории open или done, переход ссылается на неизвестный статус).
+	ErrTeamWorkflowInvalid = errors.New("invalid team task workflow")
+
+	// ErrTeamStatusInUse используется при попытке убрать из набора статус, который еще используется задачами команды.
+	ErrTeamStatusInUse = errors.New("task status is still used by team tasks")
+
+	// ErrOwnershipTransferNotFound используется, если у команды нет ожидающей передачи владения (или ее срок истек).
+	ErrOwnershipTransferNotFound = errors.New("no pending ownership transfer for this team")
+
+	// ErrOwnershipTransferToGuest используется, если владение передается гостю команды:
+	// владельцем может стать только участник с ролью выше гостя.
+	ErrOwnershipTransferToGuest = errors.New("ownership cannot be transferred to a team guest")
+
+	// ErrTeamPermissionInvalid используется, если в переопределениях прав указано неизвестное право
+	// или одно право роли указано несколько раз.
+	ErrTeamPermissionInvalid = errors.New("invalid team permission override")
+
+	// ErrTeamGuestTagInvalid используется, если тег для гостей не принадлежит команде.
+	ErrTeamGuestTagInvalid = errors.New("guest tag does not belong to this team")
+
+	// ErrProjectNotFound используется, если проект не найден в команде.
+	ErrProjectNotFound = errors.New("team project not found")
+
+	// ErrProjectInvalid используется, если данные проекта некорректны: дата начала позже целевой даты
+	// или руководитель не является участником команды (гость руководителем быть не может).
+	ErrProjectInvalid = errors.New("invalid team project")
+
+	// ErrTeamStatsPeriodInvalid используется, если начало периода статистики позже его конца или период длиннее года.
+	ErrTeamStatsPeriodInvalid = errors.New("invalid team stats period")
+
+	// ErrTeamArchived используется при попытке изменить задачи, теги или чат архивной команды.
+	// Оборачивает ErrTeamAccessDenied, поэтому везде, где обрабатывается отказ в доступе, отвечает 403 с понятным текстом.
+	ErrTeamArchived = fmt.Errorf("%w: team is archived and read-only", ErrTeamAccessDenied)
+
 	// ErrTeamInternal специфичная для модуля ошибка, если не подходит общая из usermodels.
 	ErrTeamInternal = errors.New("team module internal error")
 )
@@ -0,0 +1,179 @@
+package team
+
+// Permission - именованное право внутри команды. Роли получают права по умолчанию (DefaultRolePermissions),
+// которые команда может переопределить. Владелец всегда имеет все права; удаление команды,
+// передача владения и изменение матрицы прав доступны только ему и не настраиваются.
+// Гость только читает: у него нет прав, и переопределить их нельзя.
+type Permission string
+
+const (
+	PermTaskCreate         Permission = "task.create"          // Создание задач команды
+	PermTaskEditAny        Permission = "task.edit_any"        // Изменение деталей любой задачи
+	PermTaskDeleteAny      Permission = "task.delete_any"      // Удаление любой задачи
+	PermTaskStatusAny      Permission = "task.status_any"      // Смена статуса любой задачи
+	PermTaskStatusAssigned Permission = "task.status_assigned" // Смена статуса задач, где пользователь среди исполнителей
+	PermActivityView       Permission = "activity.view"        // Лента активности и отчет по времени команды
+	PermViewManage         Permission = "view.manage"          // Общие сохраненные представления
+	PermTemplateManage     Permission = "template.manage"      // Шаблоны задач команды
+	PermTagManage          Permission = "tag.manage"           // Теги команды
+	PermInviteCreate       Permission = "invite.create"        // Создание, просмотр и отзыв приглашений
+	PermMemberManage       Permission = "member.manage"        // Рассмотрение заявок на вступление
+	PermTeamEdit           Permission = "team.edit"            // Название, описание, изображение и настройки вступления
+	PermWorkflowManage     Permission = "workflow.manage"      // Набор статусов и правила переходов
+	PermChatModerate       Permission = "chat.moderate"        // Удаление чужих сообщений в чате команды
+	PermProjectManage      Permission = "project.manage"       // Создание, изменение и удаление проектов команды
+	PermSprintManage       Permission = "sprint.manage"        // Планирование, запуск и закрытие спринтов команды
+)
+
+// AllPermissions - все права в порядке отображения
+var AllPermissions = []Permission{
+	PermTaskCreate, PermTaskEditAny, PermTaskDeleteAny, PermTaskStatusAny, PermTaskStatusAssigned,
+	PermActivityView, PermViewManage, PermTemplateManage, PermTagManage,
+	PermInviteCreate, PermMemberManage, PermTeamEdit, PermWorkflowManage, PermChatModerate,
+	PermProjectManage, PermSprintManage,
+}
+
+// DefaultRolePermissions - права ролей, если команда их не переопределила.
+// Владелец в таблице не нужен: ему разрешено все.
+var DefaultRolePermissions = map[TeamMemberRole][]Permission{
+	RoleAdmin: AllPermissions,
+	RoleEditor: {
+		PermTaskCreate, PermTaskEditAny, PermTaskDeleteAny, PermTaskStatusAny, PermTaskStatusAssigned,
+		PermViewManage, PermTemplateManage, PermTagManage, PermProjectManage, PermSprintManage,
+	},
+	RoleMember: {PermTaskCreate, PermTaskStatusAssigned},
+}
+
+// ConfigurableRoles - роли, права которых команда может переопределить (без владельца и гостя)
+var ConfigurableRoles = []TeamMemberRole{RoleAdmin, RoleEditor, RoleMember}
+
+// IsValid проверяет, что право известно системе
+func (p Permission) IsValid() bool {
+	for _, known := range AllPermissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ModifiesContent сообщает, меняет ли право задачи, теги, представления, шаблоны или чат команды.
+// Такие права не действуют, пока команда в архиве; просмотр и управление составом остаются доступны.
+func (p Permission) ModifiesContent() bool {
+	switch p {
+	case PermActivityView, PermInviteCreate, PermMemberManage, PermTeamEdit:
+		return false
+	}
+	return true
+}
+
+// TeamRolePermission - GORM модель для таблицы 'teamrolepermissions' (переопределение права роли в команде)
+type TeamRolePermission struct {
+	TeamID     uint           `gorm:"primaryKey;column:team_id"`
+	Role       TeamMemberRole `gorm:"primaryKey;type:team_member_role;column:role"`
+	Permission Permission     `gorm:"primaryKey;type:varchar(50);column:permission"`
+	Allowed    bool           `gorm:"not null;column:allowed"`
+}
+
+func (TeamRolePermission) TableName() string {
+	return "teamrolepermissions"
+}
+
+// PermissionMatrix - действующие права ролей команды: значения по умолчанию с учетом переопределений
+type PermissionMatrix map[TeamMemberRole]map[Permission]bool
+
+// NewPermissionMatrix строит матрицу из прав по умолчанию и переопределений команды.
+func NewPermissionMatrix(overrides []*TeamRolePermission) PermissionMatrix {
+	matrix := make(PermissionMatrix, len(ConfigurableRoles))
+	for _, role := range ConfigurableRoles {
+		matrix[role] = make(map[Permission]bool, len(AllPermissions))
+		for _, p := range DefaultRolePermissions[role] {
+			matrix[role][p] = true
+		}
+	}
+	for _, o := range overrides {
+		if perms, ok := matrix[o.Role]; ok && o.Permission.IsValid() {
+			perms[o.Permission] = o.Allowed
+		}
+	}
+	return matrix
+}
+
+// Allows сообщает, есть ли у роли право. Владельцу разрешено все.
+func (m PermissionMatrix) Allows(role TeamMemberRole, p Permission) bool {
+	if role == RoleOwner {
+		return true
+	}
+	return m[role][p]
+}
+
+// RolePermissions возвращает права роли в порядке AllPermissions
+func (m PermissionMatrix) RolePermissions(role TeamMemberRole) []Permission {
+	perms := make([]Permission, 0, len(AllPermissions))
+	for _, p := range AllPermissions {
+		if m.Allows(role, p) {
+			perms = append(perms, p)
+		}
+	}
+	return perms
+}
+
+// IsDefaultPermission сообщает, входит ли право в набор роли по умолчанию
+func IsDefaultPermission(role TeamMemberRole, p Permission) bool {
+	for _, d := range DefaultRolePermissions[role] {
+		if d == p {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionOverride - переопределение права роли в запросе и ответе API
+type PermissionOverride struct {
+	Role       TeamMemberRole `json:"role" validate:"required,oneof=admin editor member"`
+	Permission Permission     `json:"permission" validate:"required,max=50"`
+	Allowed    bool           `json:"allowed"`
+}
+
+// UpdateTeamPermissionsRequest - DTO для замены всех переопределений прав команды.
+// Пустой список возвращает команде права по умолчанию.
+type UpdateTeamPermissionsRequest struct {
+	Overrides []PermissionOverride `json:"overrides" validate:"max=200,dive"`
+}
+
+// TeamPermissionsResponse - DTO матрицы прав команды
+type TeamPermissionsResponse struct {
+	Permissions            []Permission                    `json:"permissions"`              // Все известные права
+	Roles                  map[TeamMemberRole][]Permission `json:"roles"`                    // Действующие права каждой роли
+	Defaults               map[TeamMemberRole][]Permission `json:"defaults"`                 // Права ролей по умолчанию
+	Overrides              []PermissionOverride            `json:"overrides"`                // Переопределения команды
+	CurrentUserPermissions []Permission                    `json:"current_user_permissions"` // Права текущего пользователя
+}
+
+// TeamAccess - все, что нужно для проверки прав в команде: роли участников, режим архива, тег для гостей
+// и переопределения прав. Кэшируется целиком, поэтому проверка права не обращается к БД.
+type TeamAccess struct {
+	TeamID     uint                    `json:"team_id"`
+	IsArchived bool                    `json:"is_archived"`
+	GuestTagID *uint                   `json:"guest_tag_id,omitempty"`
+	Roles      map[uint]TeamMemberRole `json:"roles"`
+	Overrides  []*TeamRolePermission   `json:"overrides"`
+}
+
+// Role возвращает роль пользователя в команде (nil - не участник)
+func (a *TeamAccess) Role(userID uint) *TeamMemberRole {
+	role, ok := a.Roles[userID]
+	if !ok {
+		return nil
+	}
+	return &role
+}
+
+// Allows сообщает, есть ли у пользователя право по матрице команды. Режим архива здесь не учитывается.
+func (a *TeamAccess) Allows(userID uint, p Permission) bool {
+	role := a.Role(userID)
+	if role == nil {
+		return false
+	}
+	return NewPermissionMatrix(a.Overrides).Allows(*role, p)
+}
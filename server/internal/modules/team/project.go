@@ -0,0 +1,113 @@
+package team
+
+import "time"
+
+// ProjectStatus - этап жизни проекта команды
+type ProjectStatus string
+
+const (
+	ProjectStatusPlanned   ProjectStatus = "planned"
+	ProjectStatusActive    ProjectStatus = "active"
+	ProjectStatusPaused    ProjectStatus = "paused"
+	ProjectStatusCompleted ProjectStatus = "completed"
+	ProjectStatusCancelled ProjectStatus = "cancelled"
+)
+
+// TeamProject - GORM модель для таблицы 'teamprojects'. Проект группирует задачи команды;
+// права на проект наследуются от команды, а руководитель проекта может редактировать его задачи.
+type TeamProject struct {
+	ProjectID       uint          `gorm:"primaryKey;column:project_id;autoIncrement"`
+	TeamID          uint          `gorm:"column:team_id;not null"`
+	Name            string        `gorm:"type:varchar(100);not null;column:name"`
+	Description     *string       `gorm:"type:text;column:description"`
+	Color           *string       `gorm:"type:varchar(7);column:color"`
+	Status          ProjectStatus `gorm:"type:varchar(20);not null;default:'planned';column:status"`
+	StartDate       *time.Time    `gorm:"type:date;column:start_date"`
+	TargetDate      *time.Time    `gorm:"type:date;column:target_date"`
+	LeadUserID      *uint         `gorm:"column:lead_user_id"`
+	CreatedByUserID *uint         `gorm:"column:created_by_user_id"`
+	CreatedAt       time.Time     `gorm:"column:created_at;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time     `gorm:"column:updated_at;not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (TeamProject) TableName() string {
+	return "teamprojects"
+}
+
+// ProjectProgress - прогресс проекта по его задачам (без удаленных)
+type ProjectProgress struct {
+	Total   int `json:"total" gorm:"column:total"`
+	Done    int `json:"done" gorm:"column:done"`       // Задачи в статусах категории done
+	Overdue int `json:"overdue" gorm:"column:overdue"` // Дедлайн прошел, а задача не завершена
+}
+
+// ProjectResponse - DTO проекта команды
+type ProjectResponse struct {
+	ProjectID       uint              `json:"project_id"`
+	TeamID          uint              `json:"team_id"`
+	Name            string            `json:"name"`
+	Description     *string           `json:"description,omitempty"`
+	Color           *string           `json:"color,omitempty"`
+	Status          ProjectStatus     `json:"status"`
+	StartDate       *string           `json:"start_date,omitempty"`  // YYYY-MM-DD
+	TargetDate      *string           `json:"target_date,omitempty"` // YYYY-MM-DD
+	Lead            *UserLiteResponse `json:"lead,omitempty"`
+	CreatedByUserID *uint             `json:"created_by_user_id,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	Progress        ProjectProgress   `json:"progress"`
+}
+
+// CreateProjectRequest - DTO создания проекта. Даты - в формате YYYY-MM-DD.
+type CreateProjectRequest struct {
+	Name        string         `json:"name" validate:"required,min=1,max=100"`
+	Description *string        `json:"description,omitempty" validate:"omitempty,max=65535"`
+	Color       *string        `json:"color,omitempty" validate:"omitempty,hexcolor"`
+	Status      *ProjectStatus `json:"status,omitempty" validate:"omitempty,oneof=planned active paused completed cancelled"`
+	StartDate   *string        `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	TargetDate  *string        `json:"target_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	LeadUserID  *uint          `json:"lead_user_id,omitempty"`
+}
+
+// UpdateProjectRequest - DTO частичного изменения проекта.
+// Пустая строка в датах и 0 в lead_user_id очищают значение.
+type UpdateProjectRequest struct {
+	Name        *string        `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Description *string        `json:"description,omitempty" validate:"omitempty,max=65535"`
+	Color       *string        `json:"color,omitempty" validate:"omitempty,hexcolor"`
+	Status      *ProjectStatus `json:"status,omitempty" validate:"omitempty,oneof=planned active paused completed cancelled"`
+	StartDate   *string        `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	TargetDate  *string        `json:"target_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	LeadUserID  *uint          `json:"lead_user_id,omitempty"`
+}
+
+// formatProjectDate возвращает дату проекта в формате YYYY-MM-DD.
+func formatProjectDate(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format("2006-01-02")
+	return &s
+}
+
+// ToProjectResponse собирает DTO проекта; руководитель и прогресс передаются отдельно.
+func ToProjectResponse(p *TeamProject, lead *UserLiteResponse, progress *ProjectProgress) *ProjectResponse {
+	resp := &ProjectResponse{
+		ProjectID:       p.ProjectID,
+		TeamID:          p.TeamID,
+		Name:            p.Name,
+		Description:     p.Description,
+		Color:           p.Color,
+		Status:          p.Status,
+		StartDate:       formatProjectDate(p.StartDate),
+		TargetDate:      formatProjectDate(p.TargetDate),
+		Lead:            lead,
+		CreatedByUserID: p.CreatedByUserID,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+	}
+	if progress != nil {
+		resp.Progress = *progress
+	}
+	return resp
+}
@@ -10,7 +10,6 @@ import (
 	"server/internal/init/cache" // Ваш пакет инициализации кэша
 	"server/internal/modules/team"
 	usermodels "server/internal/modules/user" // Для общих ошибок
-	"time"
 
 	"github.com/go-redis/redis/v8"
 )
@@ -44,85 +43,22 @@ func userTeamsKey(userID uint) string {
 	return fmt.Sprintf("user:%d:teams", userID)
 }
 
-func inviteTokenKey(token string) string { return fmt.Sprintf("invite_token:%s", token) }
-
-type InviteTokenData struct {
-	TeamID       uint                `json:"team_id"`
-	RoleToAssign team.TeamMemberRole `json:"role_to_assign"`
-	// ExpiresAt не хранится здесь, т.к. TTL Redis управляет истечением
+func teamAccessKey(teamID uint) string {
+	return fmt.Sprintf("team:%d:access", teamID)
 }
 
-func (c *TeamCache) SaveInviteToken(token string, teamID uint, roleToAssign team.TeamMemberRole, expiresAt time.Time) error {
-	op := "TeamCache.SaveInviteToken"
-	key := inviteTokenKey(token)
-	log := c.log.With(slog.String("op", op), slog.String("key", key), slog.Uint64("teamID", uint64(teamID)))
-
-	ttl := time.Until(expiresAt)
-	if ttl <= 0 {
-		log.Warn("invite token already expired or invalid expiration time", "expiresAt", expiresAt)
-		return team.ErrTeamInviteTokenInvalid // Или другая ошибка "срок истек"
-	}
-
-	data := InviteTokenData{
-		TeamID:       teamID,
-		RoleToAssign: roleToAssign,
-	}
-	val, err := json.Marshal(data)
-	if err != nil {
-		log.Error("failed to marshal invite token data for cache", "error", err)
-		return team.ErrTeamInternal
-	}
-
-	if err := c.rdb.Set(context.Background(), key, val, ttl).Err(); err != nil {
-		log.Error("failed to save invite token to cache", "error", err)
-		return team.ErrTeamInternal
-	}
-	log.Info("invite token saved to cache", "ttl", ttl.String())
-	return nil
+// teamTasksListVersionKey - версия кэша списков задач команды; формат совпадает с ключом модуля задач
+// (TaskCache.tasksListVersionKey для области "team:ID")
+func teamTasksListVersionKey(teamID uint) string {
+	return fmt.Sprintf("tasks:ver:team:%d", teamID)
 }
 
-func (c *TeamCache) GetInviteTokenData(token string) (teamID uint, roleToAssign team.TeamMemberRole, isValid bool, err error) {
-	op := "TeamCache.GetInviteTokenData"
-	key := inviteTokenKey(token)
-	log := c.log.With(slog.String("op", op), slog.String("key", key))
-
-	val, redisErr := c.rdb.Get(context.Background(), key).Bytes()
-	if redisErr != nil {
-		if errors.Is(redisErr, redis.Nil) {
-			log.Info("invite token not found in cache or expired")
-			return 0, "", false, nil // Не ошибка, просто токен невалиден/не найден
-		}
-		log.Error("failed to get invite token from cache", "error", redisErr)
-		return 0, "", false, team.ErrTeamInternal
-	}
-
-	var data InviteTokenData
-	if errUnmarshal := json.Unmarshal(val, &data); errUnmarshal != nil {
-		log.Error("failed to unmarshal invite token data from cache", "error", errUnmarshal)
-		// Если данные повреждены, удаляем ключ
-		_ = c.rdb.Del(context.Background(), key)
-		return 0, "", false, team.ErrTeamInternal
-	}
-
-	log.Info("invite token data retrieved from cache")
-	return data.TeamID, data.RoleToAssign, true, nil
+func teamStatsVersionKey(teamID uint) string {
+	return fmt.Sprintf("team:%d:stats:version", teamID)
 }
 
-func (c *TeamCache) DeleteInviteToken(token string) error {
-	op := "TeamCache.DeleteInviteToken"
-	key := inviteTokenKey(token)
-	log := c.log.With(slog.String("op", op), slog.String("key", key))
-
-	if err := c.rdb.Del(context.Background(), key).Err(); err != nil {
-		// Если токена уже нет, это не ошибка для этой операции
-		if !errors.Is(err, redis.Nil) {
-			log.Error("failed to delete invite token from cache", "error", err)
-			// Можно не возвращать ошибку, если удаление некритично
-		}
-	} else {
-		log.Info("invite token deleted from cache")
-	}
-	return nil
+func teamStatsKey(teamID uint, version int64, from, to string) string {
+	return fmt.Sprintf("team:%d:stats:v%d:%s:%s", teamID, version, from, to)
 }
 
 // --- Team Cache ---
@@ -300,3 +236,151 @@ func (c *TeamCache) DeleteUserTeams(userID uint) error {
 	}
 	return nil
 }
+
+// --- Team Access Cache ---
+
+// GetTeamAccess возвращает nil без ошибки, если данных для проверки прав нет в кэше.
+func (c *TeamCache) GetTeamAccess(teamID uint) (*team.TeamAccess, error) {
+	op := "TeamCache.GetTeamAccess"
+	key := teamAccessKey(teamID)
+	log := c.log.With(slog.String("op", op), slog.String("key", key))
+
+	val, err := c.rdb.Get(context.Background(), key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			log.Debug("team access not found in cache")
+			return nil, nil
+		}
+		log.Error("failed to get team access from cache", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	var access team.TeamAccess
+	if err := json.Unmarshal(val, &access); err != nil {
+		log.Error("failed to unmarshal team access from cache", "error", err)
+		_ = c.rdb.Del(context.Background(), key)
+		return nil, team.ErrTeamInternal
+	}
+	return &access, nil
+}
+
+func (c *TeamCache) SaveTeamAccess(access *team.TeamAccess) error {
+	op := "TeamCache.SaveTeamAccess"
+	key := teamAccessKey(access.TeamID)
+	log := c.log.With(slog.String("op", op), slog.String("key", key))
+
+	val, err := json.Marshal(access)
+	if err != nil {
+		log.Error("failed to marshal team access for cache", "error", err)
+		return team.ErrTeamInternal
+	}
+	if err := c.rdb.Set(context.Background(), key, val, c.ttlCfg.DefaultTeamCacheTtl).Err(); err != nil {
+		log.Error("failed to save team access to cache", "error", err)
+		return team.ErrTeamInternal
+	}
+	log.Debug("team access saved to cache", slog.Int("members", len(access.Roles)))
+	return nil
+}
+
+func (c *TeamCache) DeleteTeamAccess(teamID uint) error {
+	op := "TeamCache.DeleteTeamAccess"
+	key := teamAccessKey(teamID)
+	log := c.log.With(slog.String("op", op), slog.String("key", key))
+
+	if err := c.rdb.Del(context.Background(), key).Err(); err != nil {
+		log.Error("failed to delete team access from cache", "error", err)
+		return team.ErrTeamInternal
+	}
+	log.Debug("team access deleted from cache")
+	return nil
+}
+
+// BumpTeamTasksListVersion делает устаревшими закэшированные страницы списков задач команды,
+// когда команда меняет сами задачи в обход модуля задач (например, категории статусов при замене набора).
+func (c *TeamCache) BumpTeamTasksListVersion(teamID uint) error {
+	op := "TeamCache.BumpTeamTasksListVersion"
+	key := teamTasksListVersionKey(teamID)
+	log := c.log.With(slog.String("op", op), slog.String("key", key))
+
+	if err := c.rdb.Incr(context.Background(), key).Err(); err != nil {
+		log.Error("failed to bump team tasks list version in cache", "error", err)
+		return team.ErrTeamInternal
+	}
+	log.Debug("team tasks list version bumped")
+	return nil
+}
+
+// --- Team Stats Cache ---
+// Статистика кэшируется под версией команды: любое изменение задач, состава или чата увеличивает версию,
+// и сохраненные за все периоды значения становятся недостижимыми (удаляются по TTL).
+
+func (c *TeamCache) GetTeamStatsVersion(teamID uint) (int64, error) {
+	op := "TeamCache.GetTeamStatsVersion"
+	key := teamStatsVersionKey(teamID)
+	log := c.log.With(slog.String("op", op), slog.String("key", key))
+
+	version, err := c.rdb.Get(context.Background(), key).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		log.Error("failed to get team stats version from cache", "error", err)
+		return 0, team.ErrTeamInternal
+	}
+	return version, nil
+}
+
+// GetTeamStats возвращает nil без ошибки, если статистики за период нет в кэше.
+func (c *TeamCache) GetTeamStats(teamID uint, version int64, from, to string) (*team.TeamStatsResponse, error) {
+	op := "TeamCache.GetTeamStats"
+	key := teamStatsKey(teamID, version, from, to)
+	log := c.log.With(slog.String("op", op), slog.String("key", key))
+
+	val, err := c.rdb.Get(context.Background(), key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			log.Debug("team stats not found in cache")
+			return nil, nil
+		}
+		log.Error("failed to get team stats from cache", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	var stats team.TeamStatsResponse
+	if err := json.Unmarshal(val, &stats); err != nil {
+		log.Error("failed to unmarshal team stats from cache", "error", err)
+		_ = c.rdb.Del(context.Background(), key)
+		return nil, team.ErrTeamInternal
+	}
+	return &stats, nil
+}
+
+func (c *TeamCache) SaveTeamStats(version int64, stats *team.TeamStatsResponse) error {
+	op := "TeamCache.SaveTeamStats"
+	key := teamStatsKey(stats.TeamID, version, stats.From, stats.To)
+	log := c.log.With(slog.String("op", op), slog.String("key", key))
+
+	val, err := json.Marshal(stats)
+	if err != nil {
+		log.Error("failed to marshal team stats for cache", "error", err)
+		return team.ErrTeamInternal
+	}
+	if err := c.rdb.Set(context.Background(), key, val, c.ttlCfg.DefaultTeamCacheTtl).Err(); err != nil {
+		log.Error("failed to save team stats to cache", "error", err)
+		return team.ErrTeamInternal
+	}
+	log.Debug("team stats saved to cache")
+	return nil
+}
+
+// BumpTeamStatsVersion делает устаревшей всю закэшированную статистику команды.
+func (c *TeamCache) BumpTeamStatsVersion(teamID uint) error {
+	op := "TeamCache.BumpTeamStatsVersion"
+	key := teamStatsVersionKey(teamID)
+	log := c.log.With(slog.String("op", op), slog.String("key", key))
+
+	if err := c.rdb.Incr(context.Background(), key).Err(); err != nil {
+		log.Error("failed to bump team stats version in cache", "error", err)
+		return team.ErrTeamInternal
+	}
+	log.Debug("team stats version bumped")
+	return nil
+}
@@ -0,0 +1,19 @@
+package database
+
+import (
+	"log/slog"
+	"server/internal/modules/team"
+)
+
+// TeamTagExists проверяет, что тег принадлежит команде (тег для ограничения видимости задач гостям).
+func (r *TeamDatabase) TeamTagExists(teamID, tagID uint) (bool, error) {
+	op := "TeamDatabase.TeamTagExists"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("tagID", uint64(tagID)))
+
+	var count int64
+	if err := r.db.Table("teamtags").Where("team_tag_id = ? AND team_id = ?", tagID, teamID).Count(&count).Error; err != nil {
+		log.Error("failed to check team tag in DB", "error", err)
+		return false, team.ErrTeamInternal
+	}
+	return count > 0, nil
+}
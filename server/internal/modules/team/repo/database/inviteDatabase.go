@@ -0,0 +1,134 @@
+package database
+
+import (
+	"errors"
+	"log/slog"
+	"server/internal/modules/team"
+	usermodels "server/internal/modules/user"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// activeInviteCondition - приглашение не отозвано, не истекло и не исчерпало лимит вступлений.
+const activeInviteCondition = "revoked_at IS NULL AND expires_at > NOW() AND (max_uses IS NULL OR use_count < max_uses)"
+
+func (r *TeamDatabase) CreateTeamInvite(invite *team.TeamInvite) (*team.TeamInvite, error) {
+	op := "TeamDatabase.CreateTeamInvite"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(invite.TeamID)))
+
+	if err := r.db.Create(invite).Error; err != nil {
+		log.Error("failed to create team invite in DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	log.Info("team invite created successfully", slog.Uint64("inviteID", uint64(invite.InviteID)))
+	return invite, nil
+}
+
+func (r *TeamDatabase) GetTeamInviteByTokenHash(tokenHash string) (*team.TeamInvite, error) {
+	op := "TeamDatabase.GetTeamInviteByTokenHash"
+	log := r.log.With(slog.String("op", op))
+
+	var invite team.TeamInvite
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&invite).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Info("team invite not found by token")
+			return nil, team.ErrTeamInviteTokenInvalid
+		}
+		log.Error("failed to get team invite from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	return &invite, nil
+}
+
+func (r *TeamDatabase) GetActiveTeamInvites(teamID uint) ([]*team.TeamInvite, error) {
+	op := "TeamDatabase.GetActiveTeamInvites"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)))
+
+	var invites []*team.TeamInvite
+	if err := r.db.Where("team_id = ?", teamID).Where(activeInviteCondition).
+		Order("created_at DESC").Find(&invites).Error; err != nil {
+		log.Error("failed to get team invites from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	return invites, nil
+}
+
+func (r *TeamDatabase) RevokeTeamInvite(teamID uint, inviteID uint) error {
+	op := "TeamDatabase.RevokeTeamInvite"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("inviteID", uint64(inviteID)))
+
+	result := r.db.Model(&team.TeamInvite{}).
+		Where("invite_id = ? AND team_id = ? AND revoked_at IS NULL", inviteID, teamID).
+		Update("revoked_at", gorm.Expr("NOW()"))
+	if result.Error != nil {
+		log.Error("failed to revoke team invite in DB", "error", result.Error)
+		return team.ErrTeamInternal
+	}
+	if result.RowsAffected == 0 {
+		return team.ErrTeamInviteNotFound
+	}
+	log.Info("team invite revoked successfully")
+	return nil
+}
+
+func (r *TeamDatabase) DeleteTeamInvite(inviteID uint) error {
+	op := "TeamDatabase.DeleteTeamInvite"
+	log := r.log.With(slog.String("op", op), slog.Uint64("inviteID", uint64(inviteID)))
+
+	if err := r.db.Delete(&team.TeamInvite{}, inviteID).Error; err != nil {
+		log.Error("failed to delete team invite from DB", "error", err)
+		return team.ErrTeamInternal
+	}
+	return nil
+}
+
+// RedeemTeamInvite в одной транзакции учитывает вступление по приглашению и создает членство.
+// Условие на лимит проверяется в UPDATE, поэтому одновременные вступления не превышают max_uses.
+func (r *TeamDatabase) RedeemTeamInvite(inviteID uint, membership *team.UserTeamMembership) error {
+	op := "TeamDatabase.RedeemTeamInvite"
+	log := r.log.With(slog.String("op", op), slog.Uint64("inviteID", uint64(inviteID)),
+		slog.Uint64("userID", uint64(membership.UserID)), slog.Uint64("teamID", uint64(membership.TeamID)))
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		used := tx.Model(&team.TeamInvite{}).
+			Where("invite_id = ?", inviteID).Where(activeInviteCondition).
+			Update("use_count", gorm.Expr("use_count + 1"))
+		if used.Error != nil {
+			return used.Error
+		}
+		if used.RowsAffected == 0 {
+			return team.ErrTeamInviteTokenInvalid
+		}
+		return tx.Create(membership).Error
+	})
+	if err != nil {
+		if errors.Is(err, team.ErrTeamInviteTokenInvalid) {
+			log.Info("team invite is no longer active")
+			return err
+		}
+		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") &&
+			strings.Contains(err.Error(), "userteammemberships_pkey") {
+			return team.ErrUserAlreadyMember
+		}
+		log.Error("failed to redeem team invite", "error", err)
+		return team.ErrTeamInternal
+	}
+	log.Info("team invite redeemed successfully")
+	return nil
+}
+
+func (r *TeamDatabase) GetUserByID(userID uint) (*usermodels.User, error) {
+	op := "TeamDatabase.GetUserByID"
+	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	var user usermodels.User
+	if err := r.db.Where("user_id = ?", userID).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, usermodels.ErrUserNotFound
+		}
+		log.Error("failed to get user from DB", "error", err)
+		return nil, usermodels.ErrInternal
+	}
+	return &user, nil
+}
@@ -0,0 +1,154 @@
+package database
+
+import (
+	"errors"
+	"log/slog"
+	"server/internal/modules/team"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *TeamDatabase) GetTeamBySlug(slug string) (*team.Team, error) {
+	op := "TeamDatabase.GetTeamBySlug"
+	log := r.log.With(slog.String("op", op), slog.String("slug", slug))
+
+	var teamModel team.Team
+	if err := r.db.Where("slug = ? AND is_deleted = ?", slug, false).First(&teamModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Info("team not found by slug")
+			return nil, team.ErrTeamNotFound
+		}
+		log.Error("failed to get team by slug from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	return &teamModel, nil
+}
+
+// CreateJoinRequest создает заявку на вступление. Если заявка создается по приглашению,
+// в той же транзакции учитывается использование приглашения.
+func (r *TeamDatabase) CreateJoinRequest(request *team.TeamJoinRequest) error {
+	op := "TeamDatabase.CreateJoinRequest"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(request.TeamID)), slog.Uint64("userID", uint64(request.UserID)))
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if request.InviteID != nil {
+			used := tx.Model(&team.TeamInvite{}).
+				Where("invite_id = ?", *request.InviteID).Where(activeInviteCondition).
+				Update("use_count", gorm.Expr("use_count + 1"))
+			if used.Error != nil {
+				return used.Error
+			}
+			if used.RowsAffected == 0 {
+				return team.ErrTeamInviteTokenInvalid
+			}
+		}
+		return tx.Create(request).Error
+	})
+	if err != nil {
+		if errors.Is(err, team.ErrTeamInviteTokenInvalid) {
+			log.Info("team invite is no longer active")
+			return err
+		}
+		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") &&
+			strings.Contains(err.Error(), "idx_team_join_requests_pending") {
+			return team.ErrJoinRequestAlreadyPending
+		}
+		log.Error("failed to create join request in DB", "error", err)
+		return team.ErrTeamInternal
+	}
+	log.Info("join request created successfully", slog.Uint64("requestID", uint64(request.RequestID)))
+	return nil
+}
+
+func (r *TeamDatabase) GetJoinRequest(teamID uint, requestID uint) (*team.TeamJoinRequest, error) {
+	op := "TeamDatabase.GetJoinRequest"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("requestID", uint64(requestID)))
+
+	var request team.TeamJoinRequest
+	if err := r.db.Where("request_id = ? AND team_id = ?", requestID, teamID).First(&request).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, team.ErrJoinRequestNotFound
+		}
+		log.Error("failed to get join request from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	return &request, nil
+}
+
+func (r *TeamDatabase) GetPendingJoinRequests(teamID uint) ([]*team.TeamJoinRequest, error) {
+	op := "TeamDatabase.GetPendingJoinRequests"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)))
+
+	var requests []*team.TeamJoinRequest
+	if err := r.db.Where("team_id = ? AND status = ?", teamID, team.JoinRequestPending).
+		Order("created_at ASC").Find(&requests).Error; err != nil {
+		log.Error("failed to get join requests from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	return requests, nil
+}
+
+// ApproveJoinRequest одобряет ожидающую заявку и создает членство в одной транзакции.
+// Если пользователь уже вступил другим способом, членство не меняется.
+func (r *TeamDatabase) ApproveJoinRequest(request *team.TeamJoinRequest, decidedByUserID uint) error {
+	op := "TeamDatabase.ApproveJoinRequest"
+	log := r.log.With(slog.String("op", op), slog.Uint64("requestID", uint64(request.RequestID)))
+
+	now := time.Now()
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := decideJoinRequest(tx, request.RequestID, team.JoinRequestApproved, decidedByUserID, now); err != nil {
+			return err
+		}
+		membership := team.UserTeamMembership{UserID: request.UserID, TeamID: request.TeamID, Role: request.Role}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&membership).Error
+	})
+	if err != nil {
+		if errors.Is(err, team.ErrJoinRequestNotFound) {
+			return err
+		}
+		log.Error("failed to approve join request", "error", err)
+		return team.ErrTeamInternal
+	}
+	request.Status, request.DecidedByUserID, request.DecidedAt = team.JoinRequestApproved, &decidedByUserID, &now
+	log.Info("join request approved successfully")
+	return nil
+}
+
+func (r *TeamDatabase) RejectJoinRequest(request *team.TeamJoinRequest, decidedByUserID uint) error {
+	op := "TeamDatabase.RejectJoinRequest"
+	log := r.log.With(slog.String("op", op), slog.Uint64("requestID", uint64(request.RequestID)))
+
+	now := time.Now()
+	if err := decideJoinRequest(r.db, request.RequestID, team.JoinRequestRejected, decidedByUserID, now); err != nil {
+		if errors.Is(err, team.ErrJoinRequestNotFound) {
+			return err
+		}
+		log.Error("failed to reject join request", "error", err)
+		return team.ErrTeamInternal
+	}
+	request.Status, request.DecidedByUserID, request.DecidedAt = team.JoinRequestRejected, &decidedByUserID, &now
+	log.Info("join request rejected successfully")
+	return nil
+}
+
+// decideJoinRequest переводит заявку из pending в итоговый статус. Условие на статус в UPDATE
+// не дает двум администраторам одновременно рассмотреть одну заявку.
+func decideJoinRequest(db *gorm.DB, requestID uint, status team.JoinRequestStatus, decidedByUserID uint, decidedAt time.Time) error {
+	result := db.Model(&team.TeamJoinRequest{}).
+		Where("request_id = ? AND status = ?", requestID, team.JoinRequestPending).
+		Updates(map[string]interface{}{
+			"status":             status,
+			"decided_by_user_id": decidedByUserID,
+			"decided_at":         decidedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return team.ErrJoinRequestNotFound
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"errors"
+	"log/slog"
+	"server/internal/modules/team"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SaveOwnershipTransfer сохраняет заявку на передачу владения; прежняя заявка команды заменяется.
+func (r *TeamDatabase) SaveOwnershipTransfer(transfer *team.OwnershipTransfer) error {
+	op := "TeamDatabase.SaveOwnershipTransfer"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(transfer.TeamID)))
+
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "team_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"from_user_id", "to_user_id", "created_at", "expires_at"}),
+	}).Create(transfer).Error
+	if err != nil {
+		log.Error("failed to save ownership transfer to DB", "error", err)
+		return team.ErrTeamInternal
+	}
+	log.Info("ownership transfer saved to DB", "expiresAt", transfer.ExpiresAt)
+	return nil
+}
+
+// GetOwnershipTransfer возвращает nil без ошибки, если заявки нет или ее срок истек.
+func (r *TeamDatabase) GetOwnershipTransfer(teamID uint) (*team.OwnershipTransfer, error) {
+	op := "TeamDatabase.GetOwnershipTransfer"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)))
+
+	var transfer team.OwnershipTransfer
+	if err := r.db.Where("team_id = ? AND expires_at > ?", teamID, time.Now()).First(&transfer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Error("failed to get ownership transfer from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	return &transfer, nil
+}
+
+func (r *TeamDatabase) DeleteOwnershipTransfer(teamID uint) error {
+	op := "TeamDatabase.DeleteOwnershipTransfer"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)))
+
+	if err := r.db.Where("team_id = ?", teamID).Delete(&team.OwnershipTransfer{}).Error; err != nil {
+		log.Error("failed to delete ownership transfer from DB", "error", err)
+		return team.ErrTeamInternal
+	}
+	log.Debug("ownership transfer deleted from DB")
+	return nil
+}
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"log/slog"
+	"server/internal/modules/team"
+
+	"gorm.io/gorm"
+)
+
+func (r *TeamDatabase) GetTeamPermissionOverrides(teamID uint) ([]*team.TeamRolePermission, error) {
+	op := "TeamDatabase.GetTeamPermissionOverrides"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)))
+
+	var overrides []*team.TeamRolePermission
+	if err := r.db.Where("team_id = ?", teamID).Order("role, permission").Find(&overrides).Error; err != nil {
+		log.Error("failed to get team permission overrides from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	return overrides, nil
+}
+
+// ReplaceTeamPermissionOverrides заменяет все переопределения прав команды в одной транзакции.
+func (r *TeamDatabase) ReplaceTeamPermissionOverrides(teamID uint, overrides []*team.TeamRolePermission) error {
+	op := "TeamDatabase.ReplaceTeamPermissionOverrides"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Int("overrides", len(overrides)))
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("team_id = ?", teamID).Delete(&team.TeamRolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(overrides) > 0 {
+			return tx.Create(&overrides).Error
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("failed to replace team permission overrides in DB", "error", err)
+		return team.ErrTeamInternal
+	}
+	log.Info("team permission overrides replaced successfully")
+	return nil
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"errors"
+	"log/slog"
+	"server/internal/modules/team"
+
+	"gorm.io/gorm"
+)
+
+func (r *TeamDatabase) CreateTeamProject(project *team.TeamProject) (*team.TeamProject, error) {
+	op := "TeamDatabase.CreateTeamProject"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(project.TeamID)))
+
+	if err := r.db.Create(project).Error; err != nil {
+		log.Error("failed to create team project in DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	log.Info("team project created successfully", slog.Uint64("projectID", uint64(project.ProjectID)))
+	return project, nil
+}
+
+func (r *TeamDatabase) GetTeamProject(teamID, projectID uint) (*team.TeamProject, error) {
+	op := "TeamDatabase.GetTeamProject"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("projectID", uint64(projectID)))
+
+	var project team.TeamProject
+	if err := r.db.Where("project_id = ? AND team_id = ?", projectID, teamID).First(&project).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Info("team project not found")
+			return nil, team.ErrProjectNotFound
+		}
+		log.Error("failed to get team project from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	return &project, nil
+}
+
+func (r *TeamDatabase) GetTeamProjects(teamID uint) ([]*team.TeamProject, error) {
+	op := "TeamDatabase.GetTeamProjects"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)))
+
+	var projects []*team.TeamProject
+	if err := r.db.Where("team_id = ?", teamID).Order("created_at ASC").Find(&projects).Error; err != nil {
+		log.Error("failed to get team projects from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	return projects, nil
+}
+
+func (r *TeamDatabase) UpdateTeamProject(project *team.TeamProject) (*team.TeamProject, error) {
+	op := "TeamDatabase.UpdateTeamProject"
+	log := r.log.With(slog.String("op", op), slog.Uint64("projectID", uint64(project.ProjectID)))
+
+	if err := r.db.Save(project).Error; err != nil {
+		log.Error("failed to update team project in DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	log.Info("team project updated successfully")
+	return project, nil
+}
+
+// DeleteTeamProject удаляет проект; задачи остаются в команде, project_id обнуляется внешним ключом.
+func (r *TeamDatabase) DeleteTeamProject(teamID, projectID uint) error {
+	op := "TeamDatabase.DeleteTeamProject"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("projectID", uint64(projectID)))
+
+	result := r.db.Where("project_id = ? AND team_id = ?", projectID, teamID).Delete(&team.TeamProject{})
+	if result.Error != nil {
+		log.Error("failed to delete team project from DB", "error", result.Error)
+		return team.ErrTeamInternal
+	}
+	if result.RowsAffected == 0 {
+		return team.ErrProjectNotFound
+	}
+	log.Info("team project deleted successfully")
+	return nil
+}
+
+// GetProjectsProgress считает прогресс проектов команды одним запросом.
+// Проекты без задач в результат не попадают.
+func (r *TeamDatabase) GetProjectsProgress(teamID uint) (map[uint]*team.ProjectProgress, error) {
+	op := "TeamDatabase.GetProjectsProgress"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)))
+
+	var rows []struct {
+		ProjectID uint `gorm:"column:project_id"`
+		team.ProjectProgress
+	}
+	err := r.db.Table("tasks").
+		Select(`project_id,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE status_category = 'done') AS done,
+			COUNT(*) FILTER (WHERE deadline < NOW() AND status_category <> 'done') AS overdue`).
+		Where("team_id = ? AND project_id IS NOT NULL AND is_deleted = false", teamID).
+		Group("project_id").
+		Scan(&rows).Error
+	if err != nil {
+		log.Error("failed to get team projects progress from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+
+	progress := make(map[uint]*team.ProjectProgress, len(rows))
+	for i := range rows {
+		progress[rows[i].ProjectID] = &rows[i].ProjectProgress
+	}
+	return progress, nil
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"log/slog"
+	"server/internal/modules/team"
+	"time"
+)
+
+// GetTeamStats считает статистику команды агрегирующими запросами: задачи, статусы, приоритеты,
+// нагрузку участников и активность чата. Период - [from, to), границы уже приведены к UTC.
+func (r *TeamDatabase) GetTeamStats(teamID uint, from, to time.Time) (*team.TeamStatsResponse, error) {
+	op := "TeamDatabase.GetTeamStats"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)))
+
+	stats := &team.TeamStatsResponse{TeamID: teamID}
+
+	var totals struct {
+		team.TeamTaskTotals
+		AvgCycleTimeHours *float64 `gorm:"column:avg_cycle_time_hours"`
+	}
+	err := r.db.Table("tasks").
+		Select(`COUNT(*) FILTER (WHERE status_category <> 'done') AS open,
+			COUNT(*) FILTER (WHERE deadline < NOW() AND status_category <> 'done') AS overdue,
+			COUNT(*) FILTER (WHERE created_at >= ? AND created_at < ?) AS created,
+			COUNT(*) FILTER (WHERE status_category = 'done' AND completed_at >= ? AND completed_at < ?) AS completed,
+			AVG(EXTRACT(EPOCH FROM completed_at - created_at) / 3600)
+				FILTER (WHERE status_category = 'done' AND completed_at >= ? AND completed_at < ?) AS avg_cycle_time_hours`,
+			from, to, from, to, from, to).
+		Where("team_id = ? AND is_deleted = false", teamID).
+		Scan(&totals).Error
+	if err != nil {
+		log.Error("failed to get team task totals from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	stats.Tasks = totals.TeamTaskTotals
+	stats.AvgCycleTimeHours = totals.AvgCycleTimeHours
+
+	err = r.db.Table("tasks").
+		Select("status, status_category, COUNT(*) AS count").
+		Where("team_id = ? AND is_deleted = false", teamID).
+		Group("status, status_category").
+		Order("status").
+		Scan(&stats.ByStatus).Error
+	if err != nil {
+		log.Error("failed to get team tasks by status from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+
+	err = r.db.Table("tasks").
+		Select("priority, COUNT(*) AS count").
+		Where("team_id = ? AND is_deleted = false AND status_category <> 'done'", teamID).
+		Group("priority").
+		Order("priority DESC").
+		Scan(&stats.ByPriority).Error
+	if err != nil {
+		log.Error("failed to get team tasks by priority from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+
+	// Задачи других команд отсекаются условием соединения, поэтому у участника без задач будут нули
+	err = r.db.Table("userteammemberships m").
+		Select(`m.user_id, u.login, m.role,
+			COUNT(t.task_id) FILTER (WHERE t.status_category <> 'done') AS open,
+			COUNT(t.task_id) FILTER (WHERE t.status_category <> 'done' AND t.deadline < NOW()) AS overdue,
+			COUNT(t.task_id) FILTER (WHERE t.status_category = 'done' AND t.completed_at >= ? AND t.completed_at < ?) AS completed`,
+			from, to).
+		Joins("JOIN users u ON u.user_id = m.user_id").
+		Joins("LEFT JOIN taskassignees ta ON ta.user_id = m.user_id").
+		Joins("LEFT JOIN tasks t ON t.task_id = ta.task_id AND t.team_id = m.team_id AND t.is_deleted = false").
+		Where("m.team_id = ?", teamID).
+		Group("m.user_id, u.login, m.role").
+		Order("open DESC, u.login").
+		Scan(&stats.Members).Error
+	if err != nil {
+		log.Error("failed to get team members workload from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	stats.MemberCount = len(stats.Members)
+
+	err = r.db.Table("chatmessages").
+		Select("COUNT(*) AS messages, COUNT(DISTINCT sender_user_id) AS active_members, MAX(sent_at) AS last_message_at").
+		Where("team_id = ? AND is_deleted = false AND sent_at >= ? AND sent_at < ?", teamID, from, to).
+		Scan(&stats.Chat).Error
+	if err != nil {
+		log.Error("failed to get team chat activity from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+
+	log.Debug("team stats computed", slog.Int("members", stats.MemberCount))
+	return stats, nil
+}
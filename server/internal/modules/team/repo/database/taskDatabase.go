@@ -97,6 +97,11 @@ func (r *TeamDatabase) UpdateTeam(teamModel *team.Team) (*team.Team, error) {
 
 	result := r.db.Save(teamModel) // Save обновит все поля, включая ImageS3Key на nil, если так установлено
 	if result.Error != nil {
+		if strings.Contains(result.Error.Error(), "duplicate key value violates unique constraint") &&
+			strings.Contains(result.Error.Error(), "teams_slug_key") {
+			log.Warn("team slug is already taken")
+			return nil, team.ErrTeamSlugTaken
+		}
 		log.Error("failed to update team in DB", "error", result.Error)
 		return nil, team.ErrTeamInternal
 	}
@@ -358,3 +363,156 @@ func (r *TeamDatabase) GetUserByLoginOrEmail(identifier string) (*usermodels.Use
 	log.Info("user found by login or email", "userID", user.UserId, "login", user.Login)
 	return &user, nil
 }
+
+func (r *TeamDatabase) GetTeamTaskStatuses(teamID uint) ([]*team.TeamTaskStatus, error) {
+	op := "TeamDatabase.GetTeamTaskStatuses"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)))
+	var statuses []*team.TeamTaskStatus
+
+	if err := r.db.Where("team_id = ?", teamID).Order("position ASC, status_id ASC").Find(&statuses).Error; err != nil {
+		log.Error("failed to get team task statuses from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	log.Debug("team task statuses retrieved", slog.Int("count", len(statuses)))
+	return statuses, nil
+}
+
+func (r *TeamDatabase) GetTeamStatusTransitions(teamID uint) ([]*team.TeamStatusTransition, error) {
+	op := "TeamDatabase.GetTeamStatusTransitions"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)))
+	var transitions []*team.TeamStatusTransition
+
+	if err := r.db.Where("team_id = ?", teamID).Order("transition_id ASC").Find(&transitions).Error; err != nil {
+		log.Error("failed to get team status transitions from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	log.Debug("team status transitions retrieved", slog.Int("count", len(transitions)))
+	return transitions, nil
+}
+
+// ReplaceTeamTaskWorkflow заменяет набор статусов и правила переходов команды в одной транзакции
+// и пересчитывает категорию статуса (и CompletedAt) у задач команды. Если у задач команды (включая задачи
+// в корзине) остался статус, которого нет в новом наборе, ничего не меняется и возвращается ErrTeamStatusInUse.
+func (r *TeamDatabase) ReplaceTeamTaskWorkflow(teamID uint, statuses []*team.TeamTaskStatus, transitions []*team.TeamStatusTransition) error {
+	op := "TeamDatabase.ReplaceTeamTaskWorkflow"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Int("statuses", len(statuses)))
+
+	// Пустой набор означает возврат к статусам по умолчанию
+	effective := statuses
+	if len(effective) == 0 {
+		effective = team.DefaultTaskWorkflow().Statuses
+	}
+	keys := make([]string, 0, len(effective))
+	for _, st := range effective {
+		keys = append(keys, st.Key)
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Команда и ее статусы блокируются до конца транзакции: параллельная замена набора ждет,
+		// а проверка используемых статусов идет по тому же состоянию, которое будет заменено
+		if err := tx.Exec("SELECT 1 FROM teams WHERE team_id = ? FOR UPDATE", teamID).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("SELECT 1 FROM teamtaskstatuses WHERE team_id = ? FOR UPDATE", teamID).Error; err != nil {
+			return err
+		}
+		var inUse []string
+		if err := tx.Model(&task.Task{}).Where("team_id = ? AND status NOT IN ?", teamID, keys).
+			Distinct().Pluck("status", &inUse).Error; err != nil {
+			return err
+		}
+		if len(inUse) > 0 {
+			log.Warn("status is still used by team tasks", "statuses", inUse)
+			return team.ErrTeamStatusInUse
+		}
+
+		if err := tx.Where("team_id = ?", teamID).Delete(&team.TeamStatusTransition{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("team_id = ?", teamID).Delete(&team.TeamTaskStatus{}).Error; err != nil {
+			return err
+		}
+		if len(statuses) > 0 {
+			if err := tx.Create(&statuses).Error; err != nil {
+				return err
+			}
+		}
+		if len(transitions) > 0 {
+			if err := tx.Create(&transitions).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, st := range effective {
+			updates := map[string]interface{}{"status_category": st.Category, "completed_at": nil}
+			if st.Category == team.StatusCategoryDone {
+				updates["completed_at"] = gorm.Expr("COALESCE(completed_at, NOW())")
+			}
+			if err := tx.Model(&task.Task{}).
+				Where("team_id = ? AND status = ? AND status_category <> ?", teamID, st.Key, st.Category).
+				Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, team.ErrTeamStatusInUse) {
+		return err
+	}
+	if err != nil {
+		log.Error("failed to replace team task workflow in DB", "error", err)
+		return team.ErrTeamInternal
+	}
+	log.Info("team task workflow replaced successfully")
+	return nil
+}
+
+// TransferTeamOwnership в одной транзакции понижает текущего владельца до администратора,
+// назначает владельцем получателя и удаляет заявку на передачу. Если владелец сменился или получатель
+// вышел из команды, ничего не меняется.
+func (r *TeamDatabase) TransferTeamOwnership(teamID, fromUserID, toUserID uint) error {
+	op := "TeamDatabase.TransferTeamOwnership"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)),
+		slog.Uint64("fromUserID", uint64(fromUserID)), slog.Uint64("toUserID", uint64(toUserID)))
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		demoted := tx.Model(&team.UserTeamMembership{}).
+			Where("user_id = ? AND team_id = ? AND role = ?", fromUserID, teamID, team.RoleOwner).
+			Update("role", team.RoleAdmin)
+		if demoted.Error != nil {
+			return demoted.Error
+		}
+		if demoted.RowsAffected == 0 {
+			return team.ErrTeamAccessDenied
+		}
+		// Гость не может стать владельцем, даже если его роль понизили уже после создания заявки
+		promoted := tx.Model(&team.UserTeamMembership{}).
+			Where("user_id = ? AND team_id = ? AND role <> ?", toUserID, teamID, team.RoleGuest).
+			Update("role", team.RoleOwner)
+		if promoted.Error != nil {
+			return promoted.Error
+		}
+		if promoted.RowsAffected == 0 {
+			var memberships int64
+			if err := tx.Model(&team.UserTeamMembership{}).
+				Where("user_id = ? AND team_id = ?", toUserID, teamID).Count(&memberships).Error; err != nil {
+				return err
+			}
+			if memberships > 0 {
+				return team.ErrOwnershipTransferToGuest
+			}
+			return team.ErrUserNotMember
+		}
+		return tx.Where("team_id = ?", teamID).Delete(&team.OwnershipTransfer{}).Error
+	})
+	if err != nil {
+		if errors.Is(err, team.ErrTeamAccessDenied) || errors.Is(err, team.ErrUserNotMember) || errors.Is(err, team.ErrOwnershipTransferToGuest) {
+			log.Warn("ownership transfer is no longer valid", "error", err)
+			return err
+		}
+		log.Error("failed to transfer team ownership in DB", "error", err)
+		return team.ErrTeamInternal
+	}
+	log.Info("team ownership transferred successfully")
+	return nil
+}
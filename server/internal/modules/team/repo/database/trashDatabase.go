@@ -0,0 +1,152 @@
+package database
+
+import (
+	"errors"
+	"log/slog"
+	"server/internal/modules/task"
+	"server/internal/modules/team"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetDeletedTeamByID ищет команду в корзине.
+func (r *TeamDatabase) GetDeletedTeamByID(teamID uint) (*team.Team, error) {
+	op := "TeamDatabase.GetDeletedTeamByID"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)))
+
+	var teamModel team.Team
+	if err := r.db.Where("is_deleted = ?", true).First(&teamModel, teamID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn("deleted team not found by ID")
+			return nil, team.ErrTeamNotFound
+		}
+		log.Error("failed to get deleted team by ID from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	return &teamModel, nil
+}
+
+// GetDeletedTeamsByOwner возвращает команды в корзине, владельцем которых является пользователь (новые - первыми).
+func (r *TeamDatabase) GetDeletedTeamsByOwner(userID uint) ([]*team.Team, error) {
+	op := "TeamDatabase.GetDeletedTeamsByOwner"
+	log := r.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	var teams []*team.Team
+	err := r.db.Joins("JOIN userteammemberships utm ON utm.team_id = teams.team_id").
+		Where("utm.user_id = ? AND utm.role = ? AND teams.is_deleted = ?", userID, team.RoleOwner, true).
+		Order("teams.deleted_at DESC").
+		Find(&teams).Error
+	if err != nil {
+		log.Error("failed to get deleted teams by owner from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	return teams, nil
+}
+
+// GetExpiredDeletedTeams возвращает до limit команд, удаленных раньше deletedBefore.
+func (r *TeamDatabase) GetExpiredDeletedTeams(deletedBefore time.Time, limit int) ([]*team.Team, error) {
+	op := "TeamDatabase.GetExpiredDeletedTeams"
+	log := r.log.With(slog.String("op", op), slog.Time("deletedBefore", deletedBefore))
+
+	var teams []*team.Team
+	err := r.db.Where("is_deleted = ? AND deleted_at < ?", true, deletedBefore).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Find(&teams).Error
+	if err != nil {
+		log.Error("failed to get expired deleted teams from DB", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	return teams, nil
+}
+
+// RestoreTeam возвращает команду из корзины вместе с задачами, удаленными не раньше tasksDeletedSince
+// (задачи, которые были в корзине до удаления команды, там и остаются).
+func (r *TeamDatabase) RestoreTeam(teamModel *team.Team, tasksDeletedSince time.Time) error {
+	op := "TeamDatabase.RestoreTeam"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamModel.TeamID)))
+
+	var restoredTasks int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&team.Team{}).
+			Where("team_id = ? AND is_deleted = ?", teamModel.TeamID, true).
+			Updates(map[string]interface{}{
+				"is_deleted":       false,
+				"deleted_at":       nil,
+				"image_url_s3_key": teamModel.ImageURLS3Key,
+				"updated_at":       time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return team.ErrTeamNotFound
+		}
+
+		tasksResult := tx.Model(&task.Task{}).
+			Where("team_id = ? AND is_deleted = ? AND deleted_at >= ?", teamModel.TeamID, true, tasksDeletedSince).
+			Updates(map[string]interface{}{
+				"is_deleted":         false,
+				"deleted_at":         nil,
+				"deleted_by_user_id": nil,
+			})
+		if tasksResult.Error != nil {
+			return tasksResult.Error
+		}
+		restoredTasks = tasksResult.RowsAffected
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			log.Warn("team is not in trash anymore")
+			return err
+		}
+		log.Error("failed to restore team in DB", "error", err)
+		return team.ErrTeamInternal
+	}
+
+	teamModel.IsDeleted = false
+	teamModel.DeletedAt = nil
+	log.Info("team restored successfully", slog.Int64("restoredTasks", restoredTasks))
+	return nil
+}
+
+// HardDeleteTeam окончательно удаляет команду из корзины. Задачи удаляются явно (в Tasks у team_id ON DELETE SET NULL,
+// иначе они стали бы личными), участники, теги, чат, приглашения и настройки - каскадно вместе с командой.
+func (r *TeamDatabase) HardDeleteTeam(teamID uint) error {
+	op := "TeamDatabase.HardDeleteTeam"
+	log := r.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)))
+
+	var deletedTasks int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Блокируем строку команды: параллельное восстановление должно либо завершиться раньше, либо не найти команду
+		var teamModel team.Team
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("is_deleted = ?", true).First(&teamModel, teamID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return team.ErrTeamNotFound
+			}
+			return err
+		}
+
+		tasksResult := tx.Where("team_id = ?", teamID).Delete(&task.Task{})
+		if tasksResult.Error != nil {
+			return tasksResult.Error
+		}
+		deletedTasks = tasksResult.RowsAffected
+
+		return tx.Delete(&team.Team{}, teamID).Error
+	})
+	if err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			log.Warn("team is not in trash anymore, skipping hard delete")
+			return err
+		}
+		log.Error("failed to hard delete team from DB", "error", err)
+		return team.ErrTeamInternal
+	}
+	log.Info("team hard deleted successfully", slog.Int64("deletedTasks", deletedTasks))
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"log/slog"
 	"server/config"
 	s3init "server/internal/init/s3" // Пакет инициализации S3 клиента
@@ -82,6 +83,77 @@ func (s *TeamS3) DeleteTeamImage(bucketName string, s3Key string) error {
 	return nil
 }
 
+// MoveTeamImage переносит объект внутри бакета (копирование и удаление исходного).
+// Ключи изображений команд состоят из безопасных символов, поэтому CopySource не экранируется.
+func (s *TeamS3) MoveTeamImage(bucketName string, fromKey string, toKey string) error {
+	op := "TeamS3.MoveTeamImage"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("bucket", bucketName),
+		slog.String("from", fromKey),
+		slog.String("to", toKey),
+	)
+
+	_, err := s.client.CopyObject(context.TODO(), &s3.CopyObjectInput{
+		Bucket:     aws.String(bucketName),
+		CopySource: aws.String(bucketName + "/" + fromKey),
+		Key:        aws.String(toKey),
+	})
+	if err != nil {
+		log.Error("failed to copy team image in S3", "error", err)
+		return team.ErrTeamInternal
+	}
+	if _, err := s.client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(fromKey),
+	}); err != nil {
+		// Копия уже создана; исходный объект останется лишним, но данные не потеряны
+		log.Warn("failed to delete source team image after copy", "error", err)
+	}
+	log.Info("team image moved in S3 successfully")
+	return nil
+}
+
+// DeleteTeamImagesByPrefix удаляет все объекты бакета с указанным префиксом (например, все файлы команды).
+func (s *TeamS3) DeleteTeamImagesByPrefix(bucketName string, prefix string) error {
+	op := "TeamS3.DeleteTeamImagesByPrefix"
+	log := s.log.With(
+		slog.String("op", op),
+		slog.String("bucket", bucketName),
+		slog.String("prefix", prefix),
+	)
+
+	deleted := 0
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			log.Error("failed to list team objects in S3", "error", err)
+			return team.ErrTeamInternal
+		}
+		if len(page.Contents) == 0 {
+			continue
+		}
+		objects := make([]types.ObjectIdentifier, 0, len(page.Contents))
+		for _, obj := range page.Contents {
+			objects = append(objects, types.ObjectIdentifier{Key: obj.Key})
+		}
+		if _, err := s.client.DeleteObjects(context.TODO(), &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucketName),
+			Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		}); err != nil {
+			log.Error("failed to delete team objects from S3", "error", err)
+			return team.ErrTeamInternal
+		}
+		deleted += len(objects)
+	}
+	log.Info("team objects deleted from S3", slog.Int("count", deleted))
+	return nil
+}
+
 // GetTeamImagePublicURL формирует публичный URL для изображения команды.
 // s3Key - это ключ объекта в бакете.
 // s.s3BaseURL предполагается как "https://endpoint/bucket" или "https://bucket.endpoint"
@@ -31,6 +31,59 @@ type TeamDb interface {
 
 	GetUserLiteByID(userID uint) (*team.UserLiteResponse, error)
 	GetUserByLoginOrEmail(identifier string) (*usermodels.User, error)
+	GetUserByID(userID uint) (*usermodels.User, error)
+
+	// Приглашения в команду
+	CreateTeamInvite(invite *team.TeamInvite) (*team.TeamInvite, error)
+	GetTeamInviteByTokenHash(tokenHash string) (*team.TeamInvite, error)
+	GetActiveTeamInvites(teamID uint) ([]*team.TeamInvite, error)
+	RevokeTeamInvite(teamID uint, inviteID uint) error
+	DeleteTeamInvite(inviteID uint) error
+	RedeemTeamInvite(inviteID uint, membership *team.UserTeamMembership) error
+
+	// Заявки на вступление
+	GetTeamBySlug(slug string) (*team.Team, error)
+	CreateJoinRequest(request *team.TeamJoinRequest) error
+	GetJoinRequest(teamID uint, requestID uint) (*team.TeamJoinRequest, error)
+	GetPendingJoinRequests(teamID uint) ([]*team.TeamJoinRequest, error)
+	ApproveJoinRequest(request *team.TeamJoinRequest, decidedByUserID uint) error
+	RejectJoinRequest(request *team.TeamJoinRequest, decidedByUserID uint) error
+
+	// Статусы задач команды
+	GetTeamTaskStatuses(teamID uint) ([]*team.TeamTaskStatus, error)
+	GetTeamStatusTransitions(teamID uint) ([]*team.TeamStatusTransition, error)
+	ReplaceTeamTaskWorkflow(teamID uint, statuses []*team.TeamTaskStatus, transitions []*team.TeamStatusTransition) error
+
+	// Передача владения: владелец становится администратором, получатель - владельцем
+	SaveOwnershipTransfer(transfer *team.OwnershipTransfer) error
+	GetOwnershipTransfer(teamID uint) (*team.OwnershipTransfer, error)
+	DeleteOwnershipTransfer(teamID uint) error
+	TransferTeamOwnership(teamID, fromUserID, toUserID uint) error // Удаляет и заявку на передачу
+
+	// Переопределения прав ролей
+	GetTeamPermissionOverrides(teamID uint) ([]*team.TeamRolePermission, error)
+	ReplaceTeamPermissionOverrides(teamID uint, overrides []*team.TeamRolePermission) error
+
+	// Область видимости гостей
+	TeamTagExists(teamID, tagID uint) (bool, error)
+
+	// Корзина команд
+	GetDeletedTeamByID(teamID uint) (*team.Team, error)
+	GetDeletedTeamsByOwner(userID uint) ([]*team.Team, error)
+	GetExpiredDeletedTeams(deletedBefore time.Time, limit int) ([]*team.Team, error)
+	RestoreTeam(teamModel *team.Team, tasksDeletedSince time.Time) error
+	HardDeleteTeam(teamID uint) error
+
+	// Проекты команды
+	CreateTeamProject(project *team.TeamProject) (*team.TeamProject, error)
+	GetTeamProject(teamID, projectID uint) (*team.TeamProject, error)
+	GetTeamProjects(teamID uint) ([]*team.TeamProject, error)
+	UpdateTeamProject(project *team.TeamProject) (*team.TeamProject, error)
+	DeleteTeamProject(teamID, projectID uint) error
+	GetProjectsProgress(teamID uint) (map[uint]*team.ProjectProgress, error)
+
+	// Статистика команды для дашборда
+	GetTeamStats(teamID uint, from, to time.Time) (*team.TeamStatsResponse, error)
 }
 
 // TeamCache определяет методы для работы с кэшем для команд.
@@ -47,15 +100,24 @@ type TeamCache interface {
 	SaveUserTeams(userID uint, teams []*team.Team) error
 	DeleteUserTeams(userID uint) error
 
-	SaveInviteToken(token string, teamID uint, roleToAssign team.TeamMemberRole, expiresAt time.Time) error
-	GetInviteTokenData(token string) (teamID uint, roleToAssign team.TeamMemberRole, isValid bool, err error)
-	DeleteInviteToken(token string) error
+	GetTeamAccess(teamID uint) (*team.TeamAccess, error) // nil, если в кэше нет
+	SaveTeamAccess(access *team.TeamAccess) error
+	DeleteTeamAccess(teamID uint) error
+
+	BumpTeamTasksListVersion(teamID uint) error
+
+	GetTeamStatsVersion(teamID uint) (int64, error)
+	GetTeamStats(teamID uint, version int64, from, to string) (*team.TeamStatsResponse, error) // nil, если в кэше нет
+	SaveTeamStats(version int64, stats *team.TeamStatsResponse) error
+	BumpTeamStatsVersion(teamID uint) error
 }
 
 // TeamS3 определяет методы для работы с S3 для изображений команд.
 type TeamS3 interface {
 	UploadTeamImage(bucketName string, s3Key string, imageBytes []byte, contentType string) error
 	DeleteTeamImage(bucketName string, s3Key string) error
+	MoveTeamImage(bucketName string, fromKey string, toKey string) error
+	DeleteTeamImagesByPrefix(bucketName string, prefix string) error
 	GetTeamImagePublicURL(s3Key string) string // Формирует URL на основе базового URL и ключа
 }
 
@@ -102,7 +164,7 @@ func (r *repo) CreateMembership(membership *team.UserTeamMembership) error {
 	// инвалидируем кэш участников команды и кэш списка команд этого пользователя.
 	// defer r.ch.DeleteTeamMembers(membership.TeamID)
 	// defer r.ch.DeleteUserTeams(membership.UserID)
-	return r.db.CreateMembership(membership)
+	return r.evictTeamAccess(membership.TeamID, r.db.CreateMembership(membership))
 }
 
 func (r *repo) GetTeamByID(teamID uint) (*team.Team, error) {
@@ -145,7 +207,8 @@ func (r *repo) UpdateTeam(teamModel *team.Team) (*team.Team, error) {
 	// if err == nil && updatedTeam != nil {
 	// 	go r.ch.SaveTeam(updatedTeam) // Обновить в кэше
 	// }
-	return updatedTeam, err
+	// Архив, удаление и тег для гостей участвуют в проверке прав
+	return updatedTeam, r.evictTeamAccess(teamModel.TeamID, err)
 }
 
 // Membership
@@ -173,7 +236,7 @@ func (r *repo) AddTeamMember(membership *team.UserTeamMembership) (*team.UserTea
 	//defer r.ch.DeleteTeamMembers(membership.TeamID)
 	//defer r.ch.DeleteUserTeams(membership.UserID)
 
-	err := r.db.CreateMembership(membership) // Используем CreateMembership
+	err := r.evictTeamAccess(membership.TeamID, r.db.CreateMembership(membership)) // Используем CreateMembership
 	if err != nil {
 		return nil, err // Он вернет ErrUserAlreadyMember или ErrTeamInternal
 	}
@@ -194,13 +257,14 @@ func (r *repo) AddTeamMember(membership *team.UserTeamMembership) (*team.UserTea
 func (r *repo) UpdateTeamMemberRole(userID, teamID uint, newRole team.TeamMemberRole) (*team.UserTeamMembership, error) {
 	// defer r.ch.DeleteTeamMembers(teamID)
 	// defer r.ch.DeleteUserTeams(userID) // Если роль влияет на то, как команды отображаются для пользователя
-	return r.db.UpdateTeamMemberRole(userID, teamID, newRole)
+	membership, err := r.db.UpdateTeamMemberRole(userID, teamID, newRole)
+	return membership, r.evictTeamAccess(teamID, err)
 }
 
 func (r *repo) RemoveTeamMember(userID, teamID uint) error {
 	// defer r.ch.DeleteTeamMembers(teamID)
 	// defer r.ch.DeleteUserTeams(userID)
-	return r.db.RemoveTeamMember(userID, teamID)
+	return r.evictTeamAccess(teamID, r.db.RemoveTeamMember(userID, teamID))
 }
 
 func (r *repo) IsTeamMember(userID, teamID uint) (bool, error) {
@@ -250,14 +314,47 @@ func (r *repo) DeleteUserTeams(userID uint) error {
 	return r.ch.DeleteUserTeams(userID)
 }
 
-func (r *repo) SaveInviteToken(token string, teamID uint, roleToAssign team.TeamMemberRole, expiresAt time.Time) error {
-	return r.ch.SaveInviteToken(token, teamID, roleToAssign, expiresAt)
+// Приглашения в команду
+func (r *repo) CreateTeamInvite(invite *team.TeamInvite) (*team.TeamInvite, error) {
+	return r.db.CreateTeamInvite(invite)
+}
+func (r *repo) GetTeamInviteByTokenHash(tokenHash string) (*team.TeamInvite, error) {
+	return r.db.GetTeamInviteByTokenHash(tokenHash)
+}
+func (r *repo) GetActiveTeamInvites(teamID uint) ([]*team.TeamInvite, error) {
+	return r.db.GetActiveTeamInvites(teamID)
+}
+func (r *repo) RevokeTeamInvite(teamID uint, inviteID uint) error {
+	return r.db.RevokeTeamInvite(teamID, inviteID)
+}
+func (r *repo) DeleteTeamInvite(inviteID uint) error {
+	return r.db.DeleteTeamInvite(inviteID)
 }
-func (r *repo) GetInviteTokenData(token string) (teamID uint, roleToAssign team.TeamMemberRole, isValid bool, err error) {
-	return r.ch.GetInviteTokenData(token)
+func (r *repo) RedeemTeamInvite(inviteID uint, membership *team.UserTeamMembership) error {
+	return r.evictTeamAccess(membership.TeamID, r.db.RedeemTeamInvite(inviteID, membership))
 }
-func (r *repo) DeleteInviteToken(token string) error {
-	return r.ch.DeleteInviteToken(token)
+func (r *repo) GetUserByID(userID uint) (*usermodels.User, error) {
+	return r.db.GetUserByID(userID)
+}
+
+// Заявки на вступление
+func (r *repo) GetTeamBySlug(slug string) (*team.Team, error) {
+	return r.db.GetTeamBySlug(slug)
+}
+func (r *repo) CreateJoinRequest(request *team.TeamJoinRequest) error {
+	return r.db.CreateJoinRequest(request)
+}
+func (r *repo) GetJoinRequest(teamID uint, requestID uint) (*team.TeamJoinRequest, error) {
+	return r.db.GetJoinRequest(teamID, requestID)
+}
+func (r *repo) GetPendingJoinRequests(teamID uint) ([]*team.TeamJoinRequest, error) {
+	return r.db.GetPendingJoinRequests(teamID)
+}
+func (r *repo) ApproveJoinRequest(request *team.TeamJoinRequest, decidedByUserID uint) error {
+	return r.evictTeamAccess(request.TeamID, r.db.ApproveJoinRequest(request, decidedByUserID))
+}
+func (r *repo) RejectJoinRequest(request *team.TeamJoinRequest, decidedByUserID uint) error {
+	return r.db.RejectJoinRequest(request, decidedByUserID)
 }
 
 func (r *repo) UploadTeamImage(bucketName string, s3Key string, imageBytes []byte, contentType string) error {
@@ -266,6 +363,173 @@ func (r *repo) UploadTeamImage(bucketName string, s3Key string, imageBytes []byt
 func (r *repo) DeleteTeamImage(bucketName string, s3Key string) error {
 	return r.s3.DeleteTeamImage(bucketName, s3Key)
 }
+func (r *repo) MoveTeamImage(bucketName string, fromKey string, toKey string) error {
+	return r.s3.MoveTeamImage(bucketName, fromKey, toKey)
+}
+func (r *repo) DeleteTeamImagesByPrefix(bucketName string, prefix string) error {
+	return r.s3.DeleteTeamImagesByPrefix(bucketName, prefix)
+}
 func (r *repo) GetTeamImagePublicURL(s3Key string) string {
 	return r.s3.GetTeamImagePublicURL(s3Key)
 }
+
+// Статусы задач команды
+func (r *repo) GetTeamTaskStatuses(teamID uint) ([]*team.TeamTaskStatus, error) {
+	return r.db.GetTeamTaskStatuses(teamID)
+}
+func (r *repo) GetTeamStatusTransitions(teamID uint) ([]*team.TeamStatusTransition, error) {
+	return r.db.GetTeamStatusTransitions(teamID)
+}
+func (r *repo) ReplaceTeamTaskWorkflow(teamID uint, statuses []*team.TeamTaskStatus, transitions []*team.TeamStatusTransition) error {
+	return r.db.ReplaceTeamTaskWorkflow(teamID, statuses, transitions)
+}
+func (r *repo) InvalidateTeamTaskLists(teamID uint) error {
+	return r.ch.BumpTeamTasksListVersion(teamID)
+}
+
+func (r *repo) TransferTeamOwnership(teamID, fromUserID, toUserID uint) error {
+	return r.evictTeamAccess(teamID, r.db.TransferTeamOwnership(teamID, fromUserID, toUserID))
+}
+
+func (r *repo) GetTeamPermissionOverrides(teamID uint) ([]*team.TeamRolePermission, error) {
+	return r.db.GetTeamPermissionOverrides(teamID)
+}
+func (r *repo) ReplaceTeamPermissionOverrides(teamID uint, overrides []*team.TeamRolePermission) error {
+	return r.evictTeamAccess(teamID, r.db.ReplaceTeamPermissionOverrides(teamID, overrides))
+}
+
+// GetTeamAccess читает данные для проверки прав из кэша; при промахе собирает их из БД и сохраняет в кэш.
+func (r *repo) GetTeamAccess(teamID uint) (*team.TeamAccess, error) {
+	if access, err := r.ch.GetTeamAccess(teamID); err == nil && access != nil {
+		return access, nil
+	}
+
+	teamModel, err := r.db.GetTeamByID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	memberships, err := r.db.GetTeamMemberships(teamID)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := r.db.GetTeamPermissionOverrides(teamID)
+	if err != nil {
+		return nil, err
+	}
+	access := &team.TeamAccess{
+		TeamID:     teamID,
+		IsArchived: teamModel.IsArchived,
+		GuestTagID: teamModel.GuestTagID,
+		Roles:      make(map[uint]team.TeamMemberRole, len(memberships)),
+		Overrides:  overrides,
+	}
+	for _, m := range memberships {
+		access.Roles[m.UserID] = m.Role
+	}
+	_ = r.ch.SaveTeamAccess(access)
+	return access, nil
+}
+
+func (r *repo) InvalidateTeamAccess(teamID uint) error {
+	return r.ch.DeleteTeamAccess(teamID)
+}
+
+// InvalidateTeamCache сбрасывает закэшированную команду, ее участников и данные для проверки прав.
+// Сбрасываются все три записи, даже если одна не удалилась; возвращается первая ошибка.
+func (r *repo) InvalidateTeamCache(teamID uint) error {
+	var firstErr error
+	for _, err := range []error{r.ch.DeleteTeam(teamID), r.ch.DeleteTeamMembers(teamID), r.ch.DeleteTeamAccess(teamID)} {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// evictTeamAccess сбрасывает кэш прав команды после успешного изменения в БД и возвращает ошибку изменения.
+// Если кэш сбросить не удалось, права могли остаться устаревшими - это логируется как ошибка.
+func (r *repo) evictTeamAccess(teamID uint, err error) error {
+	if err != nil {
+		return err
+	}
+	if errCache := r.ch.DeleteTeamAccess(teamID); errCache != nil {
+		r.log.Error("team access cache may be stale after change", "teamID", teamID, "error", errCache)
+	}
+	return nil
+}
+
+func (r *repo) TeamTagExists(teamID, tagID uint) (bool, error) {
+	return r.db.TeamTagExists(teamID, tagID)
+}
+
+func (r *repo) GetDeletedTeamByID(teamID uint) (*team.Team, error) {
+	return r.db.GetDeletedTeamByID(teamID)
+}
+func (r *repo) GetDeletedTeamsByOwner(userID uint) ([]*team.Team, error) {
+	return r.db.GetDeletedTeamsByOwner(userID)
+}
+func (r *repo) GetExpiredDeletedTeams(deletedBefore time.Time, limit int) ([]*team.Team, error) {
+	return r.db.GetExpiredDeletedTeams(deletedBefore, limit)
+}
+func (r *repo) RestoreTeam(teamModel *team.Team, tasksDeletedSince time.Time) error {
+	return r.evictTeamAccess(teamModel.TeamID, r.db.RestoreTeam(teamModel, tasksDeletedSince))
+}
+func (r *repo) HardDeleteTeam(teamID uint) error {
+	return r.evictTeamAccess(teamID, r.db.HardDeleteTeam(teamID))
+}
+
+func (r *repo) CreateTeamProject(project *team.TeamProject) (*team.TeamProject, error) {
+	return r.db.CreateTeamProject(project)
+}
+
+func (r *repo) GetTeamProject(teamID, projectID uint) (*team.TeamProject, error) {
+	return r.db.GetTeamProject(teamID, projectID)
+}
+
+func (r *repo) GetTeamProjects(teamID uint) ([]*team.TeamProject, error) {
+	return r.db.GetTeamProjects(teamID)
+}
+
+func (r *repo) UpdateTeamProject(project *team.TeamProject) (*team.TeamProject, error) {
+	return r.db.UpdateTeamProject(project)
+}
+
+func (r *repo) DeleteTeamProject(teamID, projectID uint) error {
+	return r.db.DeleteTeamProject(teamID, projectID)
+}
+
+func (r *repo) GetProjectsProgress(teamID uint) (map[uint]*team.ProjectProgress, error) {
+	return r.db.GetProjectsProgress(teamID)
+}
+
+func (r *repo) ComputeTeamStats(teamID uint, from, to time.Time) (*team.TeamStatsResponse, error) {
+	return r.db.GetTeamStats(teamID, from, to)
+}
+
+func (r *repo) GetTeamStatsVersion(teamID uint) (int64, error) {
+	return r.ch.GetTeamStatsVersion(teamID)
+}
+
+func (r *repo) GetCachedTeamStats(teamID uint, version int64, from, to string) (*team.TeamStatsResponse, error) {
+	return r.ch.GetTeamStats(teamID, version, from, to)
+}
+
+func (r *repo) SaveTeamStats(version int64, stats *team.TeamStatsResponse) error {
+	return r.ch.SaveTeamStats(version, stats)
+}
+
+func (r *repo) BumpTeamStatsVersion(teamID uint) error {
+	return r.ch.BumpTeamStatsVersion(teamID)
+}
+
+func (r *repo) SaveOwnershipTransfer(transfer *team.OwnershipTransfer) error {
+	return r.db.SaveOwnershipTransfer(transfer)
+}
+
+func (r *repo) GetOwnershipTransfer(teamID uint) (*team.OwnershipTransfer, error) {
+	return r.db.GetOwnershipTransfer(teamID)
+}
+
+func (r *repo) DeleteOwnershipTransfer(teamID uint) error {
+	return r.db.DeleteOwnershipTransfer(teamID)
+}
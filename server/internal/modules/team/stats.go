@@ -0,0 +1,65 @@
+package team
+
+import "time"
+
+// TeamStatsRequest - период статистики команды (YYYY-MM-DD, обе даты включительно).
+// По умолчанию - последние 30 дней по текущий день.
+type TeamStatsRequest struct {
+	From *string `validate:"omitempty,datetime=2006-01-02"`
+	To   *string `validate:"omitempty,datetime=2006-01-02"`
+}
+
+// TeamTaskTotals - сводка по задачам команды (без удаленных). Open и Overdue - на текущий момент,
+// Created и Completed - за период.
+type TeamTaskTotals struct {
+	Open      int `json:"open" gorm:"column:open"`           // Задачи не в категории done
+	Overdue   int `json:"overdue" gorm:"column:overdue"`     // Дедлайн прошел, а задача не завершена
+	Created   int `json:"created" gorm:"column:created"`     // Созданы за период
+	Completed int `json:"completed" gorm:"column:completed"` // Завершены за период
+}
+
+// TeamStatusCount - число задач команды в статусе
+type TeamStatusCount struct {
+	Status   string             `json:"status" gorm:"column:status"`
+	Category TaskStatusCategory `json:"category" gorm:"column:status_category"`
+	Count    int                `json:"count" gorm:"column:count"`
+}
+
+// TeamPriorityCount - число незавершенных задач команды с приоритетом
+type TeamPriorityCount struct {
+	Priority int `json:"priority" gorm:"column:priority"`
+	Count    int `json:"count" gorm:"column:count"`
+}
+
+// TeamMemberWorkload - нагрузка участника по задачам, где он среди исполнителей
+type TeamMemberWorkload struct {
+	UserID    uint           `json:"user_id" gorm:"column:user_id"`
+	Login     string         `json:"login" gorm:"column:login"`
+	Role      TeamMemberRole `json:"role" gorm:"column:role"`
+	Open      int            `json:"open" gorm:"column:open"`           // Незавершенные задачи
+	Overdue   int            `json:"overdue" gorm:"column:overdue"`     // Из них просроченные
+	Completed int            `json:"completed" gorm:"column:completed"` // Завершены за период
+}
+
+// TeamChatActivity - активность в чате команды за период (без удаленных сообщений)
+type TeamChatActivity struct {
+	Messages      int        `json:"messages" gorm:"column:messages"`
+	ActiveMembers int        `json:"active_members" gorm:"column:active_members"` // Писавшие в чат за период
+	LastMessageAt *time.Time `json:"last_message_at,omitempty" gorm:"column:last_message_at"`
+}
+
+// TeamStatsResponse - статистика для дашборда команды.
+// Хранится в кэше до изменения задач, состава команды или сообщений чата (но не дольше TTL кэша команды).
+type TeamStatsResponse struct {
+	TeamID            uint                  `json:"team_id"`
+	From              string                `json:"from"` // YYYY-MM-DD
+	To                string                `json:"to"`   // YYYY-MM-DD
+	MemberCount       int                   `json:"member_count"`
+	Tasks             TeamTaskTotals        `json:"tasks"`
+	ByStatus          []*TeamStatusCount    `json:"by_status"`
+	ByPriority        []*TeamPriorityCount  `json:"by_priority"`
+	Members           []*TeamMemberWorkload `json:"members"`
+	AvgCycleTimeHours *float64              `json:"avg_cycle_time_hours,omitempty"` // От создания до завершения задач, завершенных за период
+	Chat              TeamChatActivity      `json:"chat"`
+	GeneratedAt       time.Time             `json:"generated_at"`
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"log/slog"
+	"server/internal/modules/team"
+	"time"
+)
+
+// CheckTeamWritable - единая проверка режима архива для всех модулей: задачи, теги и чат архивной команды не меняются.
+func (uc *TeamUseCase) CheckTeamWritable(teamID uint) error {
+	access, err := uc.repo.GetTeamAccess(teamID)
+	if err != nil {
+		return err
+	}
+	if access.IsArchived {
+		return team.ErrTeamArchived
+	}
+	return nil
+}
+
+// ArchiveTeam переводит команду в архив (только чтение). Доступно владельцу и администраторам.
+func (uc *TeamUseCase) ArchiveTeam(teamID uint, userID uint) (*team.TeamResponse, error) {
+	return uc.setTeamArchived(teamID, userID, true)
+}
+
+// UnarchiveTeam возвращает команду из архива. Доступно владельцу и администраторам.
+func (uc *TeamUseCase) UnarchiveTeam(teamID uint, userID uint) (*team.TeamResponse, error) {
+	return uc.setTeamArchived(teamID, userID, false)
+}
+
+func (uc *TeamUseCase) setTeamArchived(teamID uint, userID uint, archived bool) (*team.TeamResponse, error) {
+	op := "TeamUseCase.setTeamArchived"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)),
+		slog.Bool("archived", archived))
+
+	teamModel, err := uc.repo.GetTeamByID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	membership, err := uc.repo.GetMembership(userID, teamID)
+	if err != nil || (membership.Role != team.RoleOwner && membership.Role != team.RoleAdmin) {
+		log.Warn("only the owner or an admin can change team archive state")
+		return nil, team.ErrTeamAccessDenied
+	}
+
+	if teamModel.IsArchived != archived {
+		teamModel.IsArchived = archived
+		teamModel.ArchivedAt = nil
+		if archived {
+			now := time.Now()
+			teamModel.ArchivedAt = &now
+		}
+		if teamModel, err = uc.repo.UpdateTeam(teamModel); err != nil {
+			return nil, err
+		}
+
+		// Признак архива хранится в кэше команды и в списках команд участников
+		if errCache := uc.repo.InvalidateTeamCache(teamID); errCache != nil {
+			log.Warn("failed to invalidate team cache after archive state change", "error", errCache)
+		}
+		memberships, _ := uc.repo.GetTeamMemberships(teamID)
+		for _, m := range memberships {
+			_ = uc.repo.DeleteUserTeams(m.UserID)
+		}
+		log.Info("team archive state changed")
+	}
+
+	memberCount, err := uc.repo.GetTeamMembershipsCount(teamID)
+	if err != nil {
+		log.Warn("failed to get member count for response", "error", err)
+	}
+	return uc.toTeamResponse(teamModel, &membership.Role, memberCount), nil
+}
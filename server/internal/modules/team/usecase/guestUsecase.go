@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"log/slog"
+	"server/internal/modules/team"
+)
+
+// applyGuestSettings применяет к команде настройки гостей из запроса и сообщает, изменилось ли что-то.
+func (uc *TeamUseCase) applyGuestSettings(t *team.Team, req team.UpdateTeamDetailsRequest) (bool, error) {
+	changed := false
+
+	if req.GuestTagID != nil {
+		var newTagID *uint
+		if *req.GuestTagID != 0 {
+			exists, err := uc.repo.TeamTagExists(t.TeamID, *req.GuestTagID)
+			if err != nil {
+				return false, err
+			}
+			if !exists {
+				return false, team.ErrTeamGuestTagInvalid
+			}
+			tagID := *req.GuestTagID
+			newTagID = &tagID
+		}
+		if (t.GuestTagID == nil) != (newTagID == nil) || (newTagID != nil && *t.GuestTagID != *newTagID) {
+			t.GuestTagID = newTagID
+			changed = true
+		}
+	}
+
+	if req.GuestChatEnabled != nil && *req.GuestChatEnabled != t.GuestChatEnabled {
+		t.GuestChatEnabled = *req.GuestChatEnabled
+		changed = true
+	}
+	return changed, nil
+}
+
+// GetGuestTaskTagScope возвращает тег команды, задачи с которым видит гость.
+// nil - ограничения нет: пользователь не гость или команда не задала тег.
+func (uc *TeamUseCase) GetGuestTaskTagScope(userID, teamID uint) (*uint, error) {
+	access, err := uc.teamAccess(teamID)
+	if err != nil || access == nil {
+		return nil, err
+	}
+	if role := access.Role(userID); role == nil || *role != team.RoleGuest {
+		return nil, nil
+	}
+	return access.GuestTagID, nil
+}
+
+// CanUserAccessTeamChat - чат доступен участникам команды; гостям - только если команда это разрешила.
+func (uc *TeamUseCase) CanUserAccessTeamChat(userID, teamID uint) (bool, error) {
+	role, err := uc.GetUserRoleInTeam(userID, teamID)
+	if err != nil || role == nil {
+		return false, err
+	}
+	if *role != team.RoleGuest {
+		return true, nil
+	}
+	teamModel, err := uc.repo.GetTeamByID(teamID)
+	if err != nil {
+		return false, err
+	}
+	if !teamModel.GuestChatEnabled {
+		uc.log.Debug("team chat is disabled for guests", slog.String("op", "TeamUseCase.CanUserAccessTeamChat"),
+			slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+	}
+	return teamModel.GuestChatEnabled, nil
+}
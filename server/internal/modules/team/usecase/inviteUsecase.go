@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"log/slog"
+	"server/internal/modules/team"
+	"time"
+)
+
+// InviteMailer отправляет письма с приглашениями (pkg/lib/emailsender).
+type InviteMailer interface {
+	SendEmail(recipientEmail, subject, htmlBody, textBody string) error
+}
+
+// hashInviteToken - в БД хранится только SHA-256 токена приглашения.
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetTeamInvites возвращает действующие приглашения команды: не отозванные, не истекшие и не исчерпавшие лимит.
+func (uc *TeamUseCase) GetTeamInvites(teamID uint, userID uint) ([]*team.TeamInviteResponse, error) {
+	op := "TeamUseCase.GetTeamInvites"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	if _, err := uc.repo.GetTeamByID(teamID); err != nil {
+		return nil, err
+	}
+	if err := uc.requirePermission(teamID, userID, team.PermInviteCreate); err != nil {
+		log.Warn("user cannot manage team invites")
+		return nil, err
+	}
+
+	invites, err := uc.repo.GetActiveTeamInvites(teamID)
+	if err != nil {
+		return nil, err
+	}
+	creators := make(map[uint]*team.UserLiteResponse)
+	responses := make([]*team.TeamInviteResponse, 0, len(invites))
+	for _, invite := range invites {
+		resp := &team.TeamInviteResponse{
+			InviteID:   invite.InviteID,
+			RoleOnJoin: invite.Role,
+			MaxUses:    invite.MaxUses,
+			UseCount:   invite.UseCount,
+			Email:      invite.TargetEmail,
+			ExpiresAt:  invite.ExpiresAt,
+			CreatedAt:  invite.CreatedAt,
+		}
+		if invite.CreatedByUserID != nil {
+			creator, ok := creators[*invite.CreatedByUserID]
+			if !ok {
+				if creator, err = uc.repo.GetUserLiteByID(*invite.CreatedByUserID); err != nil {
+					log.Warn("failed to get invite creator", "error", err, "creatorID", *invite.CreatedByUserID)
+				}
+				creators[*invite.CreatedByUserID] = creator
+			}
+			resp.CreatedBy = creator
+		}
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// RevokeTeamInvite отзывает приглашение: вступить по нему больше нельзя, запись остается в БД.
+func (uc *TeamUseCase) RevokeTeamInvite(teamID uint, userID uint, inviteID uint) error {
+	op := "TeamUseCase.RevokeTeamInvite"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)),
+		slog.Uint64("inviteID", uint64(inviteID)))
+
+	if _, err := uc.repo.GetTeamByID(teamID); err != nil {
+		return err
+	}
+	if err := uc.requirePermission(teamID, userID, team.PermInviteCreate); err != nil {
+		log.Warn("user cannot manage team invites")
+		return err
+	}
+	if err := uc.repo.RevokeTeamInvite(teamID, inviteID); err != nil {
+		return err
+	}
+	log.Info("team invite revoked")
+	return nil
+}
+
+// sendInviteEmail отправляет ссылку-приглашение на указанный адрес.
+func (uc *TeamUseCase) sendInviteEmail(teamModel *team.Team, recipientEmail string, inviteLink string, expiresAt time.Time) error {
+	if uc.mailer == nil {
+		return fmt.Errorf("invite mailer is not configured")
+	}
+	teamName := html.EscapeString(teamModel.Name)
+	expires := expiresAt.Format("02.01.2006 15:04")
+
+	subject := fmt.Sprintf("Приглашение в команду «%s» в ToDo App", teamModel.Name)
+	htmlBody := `<!DOCTYPE html>
+    <html lang="ru">
+    <head><meta charset="UTF-8"><title>Приглашение в команду - ToDo App</title></head>
+    <body style="font-family: sans-serif; color: #333;">
+        <h2>Вас пригласили в команду «` + teamName + `»</h2>
+        <p>Чтобы присоединиться, откройте ссылку и войдите в аккаунт с этим адресом электронной почты:</p>
+        <p><a href="` + html.EscapeString(inviteLink) + `">Присоединиться к команде</a></p>
+        <p>Приглашение действует до ` + expires + `.</p>
+        <p>Если вы не ждали этого письма, просто проигнорируйте его.</p>
+    </body>
+    </html>`
+	textBody := fmt.Sprintf(
+		"Вас пригласили в команду «%s» в ToDo App.\n\nЧтобы присоединиться, откройте ссылку и войдите в аккаунт с этим адресом электронной почты:\n%s\n\nПриглашение действует до %s.",
+		teamModel.Name, inviteLink, expires,
+	)
+	return uc.mailer.SendEmail(recipientEmail, subject, htmlBody, textBody)
+}
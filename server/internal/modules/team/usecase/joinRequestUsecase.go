@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"errors"
+	"log/slog"
+	"regexp"
+	"server/internal/modules/team"
+	"strings"
+)
+
+var (
+	teamSlugPattern    = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	emailDomainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}$`)
+)
+
+// applyJoinSettings применяет к команде настройки вступления из запроса и сообщает, изменилось ли что-то.
+func (uc *TeamUseCase) applyJoinSettings(t *team.Team, req team.UpdateTeamDetailsRequest) (bool, error) {
+	changed := false
+	t.JoinPolicy = t.EffectiveJoinPolicy() // Модель из старого кэша не должна записать пустую политику
+
+	if req.Slug != nil {
+		var newSlug *string
+		if slug := strings.ToLower(strings.TrimSpace(*req.Slug)); slug != "" {
+			if len(slug) < 3 || len(slug) > 50 || !teamSlugPattern.MatchString(slug) {
+				return false, team.ErrTeamSlugInvalid
+			}
+			newSlug = &slug
+		}
+		if !equalOptionalStrings(t.Slug, newSlug) {
+			if newSlug != nil {
+				existing, err := uc.repo.GetTeamBySlug(*newSlug)
+				if err == nil && existing.TeamID != t.TeamID {
+					return false, team.ErrTeamSlugTaken
+				}
+				if err != nil && !errors.Is(err, team.ErrTeamNotFound) {
+					return false, err
+				}
+			}
+			t.Slug = newSlug
+			changed = true
+		}
+	}
+
+	if req.JoinEmailDomain != nil {
+		var newDomain *string
+		if domain := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(*req.JoinEmailDomain)), "@"); domain != "" {
+			if !emailDomainPattern.MatchString(domain) {
+				return false, team.ErrTeamJoinPolicyInvalid
+			}
+			newDomain = &domain
+		}
+		if !equalOptionalStrings(t.JoinEmailDomain, newDomain) {
+			t.JoinEmailDomain = newDomain
+			changed = true
+		}
+	}
+
+	if req.JoinPolicy != nil && *req.JoinPolicy != t.EffectiveJoinPolicy() {
+		t.JoinPolicy = *req.JoinPolicy
+		changed = true
+	}
+	if req.InviteApprovalRequired != nil && *req.InviteApprovalRequired != t.InviteApprovalRequired {
+		t.InviteApprovalRequired = *req.InviteApprovalRequired
+		changed = true
+	}
+
+	if t.EffectiveJoinPolicy() == team.JoinPolicyOpenWithinDomain && t.JoinEmailDomain == nil {
+		return false, team.ErrTeamJoinPolicyInvalid
+	}
+	return changed, nil
+}
+
+// RequestToJoinTeam обрабатывает желание пользователя вступить в команду без приглашения согласно ее политике:
+// request - создается заявка для администраторов, open_within_domain - пользователь с подтвержденным email
+// в домене команды вступает сразу, invite_only - вступить нельзя.
+func (uc *TeamUseCase) RequestToJoinTeam(userID uint, req team.CreateJoinRequestRequest) (*team.JoinTeamResult, error) {
+	op := "TeamUseCase.RequestToJoinTeam"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	var teamModel *team.Team
+	var err error
+	if req.TeamID != nil {
+		teamModel, err = uc.repo.GetTeamByID(*req.TeamID)
+	} else {
+		teamModel, err = uc.repo.GetTeamBySlug(strings.ToLower(strings.TrimSpace(*req.Slug)))
+	}
+	if err != nil {
+		return nil, err
+	}
+	log = log.With(slog.Uint64("teamID", uint64(teamModel.TeamID)))
+
+	if _, err := uc.repo.GetMembership(userID, teamModel.TeamID); err == nil {
+		return nil, team.ErrUserAlreadyMember
+	} else if !errors.Is(err, team.ErrUserNotMember) {
+		log.Error("failed to check membership", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+
+	switch teamModel.EffectiveJoinPolicy() {
+	case team.JoinPolicyRequest:
+		var message *string
+		if req.Message != nil && strings.TrimSpace(*req.Message) != "" {
+			trimmed := strings.TrimSpace(*req.Message)
+			message = &trimmed
+		}
+		return uc.submitJoinRequest(teamModel, userID, nil, team.RoleMember, message)
+
+	case team.JoinPolicyOpenWithinDomain:
+		user, err := uc.repo.GetUserByID(userID)
+		if err != nil {
+			log.Error("failed to get user for domain check", "error", err)
+			return nil, team.ErrTeamInternal
+		}
+		at := strings.LastIndex(user.Email, "@")
+		if !user.VerifiedEmail || teamModel.JoinEmailDomain == nil || at < 0 ||
+			!strings.EqualFold(user.Email[at+1:], *teamModel.JoinEmailDomain) {
+			log.Warn("user email is not verified or outside of team domain")
+			return nil, team.ErrTeamJoinNotAllowed
+		}
+
+		membership := team.UserTeamMembership{UserID: userID, TeamID: teamModel.TeamID, Role: team.RoleMember}
+		if err := uc.repo.CreateMembership(&membership); err != nil {
+			return nil, err
+		}
+		_ = uc.repo.DeleteTeamMembers(teamModel.TeamID)
+		_ = uc.repo.DeleteUserTeams(userID)
+		_ = uc.repo.DeleteTeam(teamModel.TeamID)
+		uc.InvalidateTeamStats(teamModel.TeamID)
+
+		log.Info("user joined team by email domain")
+		memberCount, _ := uc.repo.GetTeamMembershipsCount(teamModel.TeamID)
+		return &team.JoinTeamResult{Team: uc.toTeamResponse(teamModel, &membership.Role, memberCount)}, nil
+
+	default:
+		log.Warn("team accepts members by invite only")
+		return nil, team.ErrTeamJoinNotAllowed
+	}
+}
+
+// GetTeamJoinRequests возвращает очередь ожидающих заявок команды (от старых к новым).
+func (uc *TeamUseCase) GetTeamJoinRequests(teamID uint, userID uint) ([]*team.TeamJoinRequestResponse, error) {
+	op := "TeamUseCase.GetTeamJoinRequests"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	teamModel, err := uc.repo.GetTeamByID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.requirePermission(teamID, userID, team.PermMemberManage); err != nil {
+		log.Warn("user cannot manage join requests")
+		return nil, err
+	}
+
+	requests, err := uc.repo.GetPendingJoinRequests(teamID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*team.TeamJoinRequestResponse, 0, len(requests))
+	for _, request := range requests {
+		responses = append(responses, uc.toJoinRequestResponse(teamModel, request))
+	}
+	return responses, nil
+}
+
+// ApproveJoinRequest одобряет заявку: пользователь становится участником с ролью из заявки и получает уведомление.
+func (uc *TeamUseCase) ApproveJoinRequest(teamID uint, userID uint, requestID uint) (*team.TeamJoinRequestResponse, error) {
+	op := "TeamUseCase.ApproveJoinRequest"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)),
+		slog.Uint64("requestID", uint64(requestID)))
+
+	teamModel, request, err := uc.getPendingJoinRequest(teamID, userID, requestID)
+	if err != nil {
+		log.Warn("cannot approve join request", "error", err)
+		return nil, err
+	}
+	if err := uc.repo.ApproveJoinRequest(request, userID); err != nil {
+		return nil, err
+	}
+
+	_ = uc.repo.DeleteTeamMembers(teamID)
+	_ = uc.repo.DeleteUserTeams(request.UserID)
+	_ = uc.repo.DeleteTeam(teamID)
+	uc.InvalidateTeamStats(teamID)
+
+	uc.notify([]uint{request.UserID}, teamModel, "Ваша заявка на вступление в команду одобрена", "join_request_approved")
+	log.Info("join request approved", slog.Uint64("requesterID", uint64(request.UserID)))
+	return uc.toJoinRequestResponse(teamModel, request), nil
+}
+
+// RejectJoinRequest отклоняет заявку и уведомляет о решении ее автора.
+func (uc *TeamUseCase) RejectJoinRequest(teamID uint, userID uint, requestID uint) (*team.TeamJoinRequestResponse, error) {
+	op := "TeamUseCase.RejectJoinRequest"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)),
+		slog.Uint64("requestID", uint64(requestID)))
+
+	teamModel, request, err := uc.getPendingJoinRequest(teamID, userID, requestID)
+	if err != nil {
+		log.Warn("cannot reject join request", "error", err)
+		return nil, err
+	}
+	if err := uc.repo.RejectJoinRequest(request, userID); err != nil {
+		return nil, err
+	}
+
+	uc.notify([]uint{request.UserID}, teamModel, "Ваша заявка на вступление в команду отклонена", "join_request_rejected")
+	log.Info("join request rejected", slog.Uint64("requesterID", uint64(request.UserID)))
+	return uc.toJoinRequestResponse(teamModel, request), nil
+}
+
+// getPendingJoinRequest проверяет права на рассмотрение заявки и возвращает ее, если она еще ожидает решения.
+func (uc *TeamUseCase) getPendingJoinRequest(teamID uint, userID uint, requestID uint) (*team.Team, *team.TeamJoinRequest, error) {
+	teamModel, err := uc.repo.GetTeamByID(teamID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := uc.requirePermission(teamID, userID, team.PermMemberManage); err != nil {
+		return nil, nil, err
+	}
+	request, err := uc.repo.GetJoinRequest(teamID, requestID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if request.Status != team.JoinRequestPending {
+		return nil, nil, team.ErrJoinRequestNotFound
+	}
+	return teamModel, request, nil
+}
+
+// submitJoinRequest создает заявку на вступление и уведомляет участников, которые могут ее рассмотреть.
+func (uc *TeamUseCase) submitJoinRequest(teamModel *team.Team, userID uint, inviteID *uint, role team.TeamMemberRole, message *string) (*team.JoinTeamResult, error) {
+	request := &team.TeamJoinRequest{
+		TeamID:   teamModel.TeamID,
+		UserID:   userID,
+		InviteID: inviteID,
+		Role:     role,
+		Message:  message,
+		Status:   team.JoinRequestPending,
+	}
+	if err := uc.repo.CreateJoinRequest(request); err != nil {
+		return nil, err
+	}
+
+	uc.notify(uc.usersWithPermission(teamModel.TeamID, team.PermMemberManage), teamModel, "Новая заявка на вступление в команду", "join_request_created")
+	uc.log.Info("join request submitted", slog.String("op", "TeamUseCase.submitJoinRequest"),
+		slog.Uint64("teamID", uint64(teamModel.TeamID)), slog.Uint64("userID", uint64(userID)),
+		slog.Uint64("requestID", uint64(request.RequestID)), slog.Bool("viaInvite", inviteID != nil))
+	return &team.JoinTeamResult{Request: uc.toJoinRequestResponse(teamModel, request)}, nil
+}
+
+func (uc *TeamUseCase) toJoinRequestResponse(teamModel *team.Team, request *team.TeamJoinRequest) *team.TeamJoinRequestResponse {
+	resp := &team.TeamJoinRequestResponse{
+		RequestID:       request.RequestID,
+		TeamID:          teamModel.TeamID,
+		TeamName:        teamModel.Name,
+		User:            team.UserLiteResponse{UserID: request.UserID},
+		Role:            request.Role,
+		Message:         request.Message,
+		Status:          request.Status,
+		ViaInvite:       request.InviteID != nil,
+		DecidedByUserID: request.DecidedByUserID,
+		DecidedAt:       request.DecidedAt,
+		CreatedAt:       request.CreatedAt,
+	}
+	if user, err := uc.repo.GetUserLiteByID(request.UserID); err == nil {
+		resp.User = *user
+	}
+	return resp
+}
+
+func equalOptionalStrings(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
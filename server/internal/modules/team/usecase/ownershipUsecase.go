@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"errors"
+	"log/slog"
+	"server/internal/modules/team"
+	"time"
+)
+
+// defaultOwnershipTransferTTL - срок, в течение которого получатель может принять передачу владения.
+const defaultOwnershipTransferTTL = 72 * time.Hour
+
+// StartOwnershipTransfer создает заявку на передачу владения командой другому ее участнику.
+// Роли не меняются, пока получатель не примет заявку; новая заявка заменяет прежнюю.
+func (uc *TeamUseCase) StartOwnershipTransfer(teamID uint, userID uint, req team.StartOwnershipTransferRequest) (*team.OwnershipTransferResponse, error) {
+	op := "TeamUseCase.StartOwnershipTransfer"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)),
+		slog.Uint64("toUserID", uint64(req.ToUserID)))
+
+	teamModel, err := uc.repo.GetTeamByID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	membership, err := uc.repo.GetMembership(userID, teamID)
+	if err != nil || membership.Role != team.RoleOwner {
+		log.Warn("only the owner can transfer the team")
+		return nil, team.ErrTeamAccessDenied
+	}
+	if req.ToUserID == userID {
+		return nil, team.ErrCannotPerformActionOnSelf
+	}
+	if err := uc.checkOwnershipRecipient(teamID, req.ToUserID); err != nil {
+		log.Warn("recipient cannot become the owner", "error", err)
+		return nil, err
+	}
+
+	ttl := defaultOwnershipTransferTTL
+	if req.ExpiresInHours != nil {
+		ttl = time.Duration(*req.ExpiresInHours) * time.Hour
+	}
+	now := time.Now()
+	transfer := &team.OwnershipTransfer{
+		TeamID:     teamID,
+		FromUserID: userID,
+		ToUserID:   req.ToUserID,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	if err := uc.repo.SaveOwnershipTransfer(transfer); err != nil {
+		return nil, err
+	}
+
+	uc.notify([]uint{req.ToUserID}, teamModel, "Вам предлагают стать владельцем команды", "ownership_transfer_requested")
+	log.Info("ownership transfer requested", "expiresAt", transfer.ExpiresAt)
+	return uc.toOwnershipTransferResponse(transfer), nil
+}
+
+// GetOwnershipTransfer возвращает ожидающую передачу владения. Ее видят только владелец и получатель.
+func (uc *TeamUseCase) GetOwnershipTransfer(teamID uint, userID uint) (*team.OwnershipTransferResponse, error) {
+	if _, err := uc.repo.GetTeamByID(teamID); err != nil {
+		return nil, err
+	}
+	transfer, err := uc.getOwnershipTransferFor(teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.toOwnershipTransferResponse(transfer), nil
+}
+
+// CancelOwnershipTransfer отменяет передачу владения: владелец отзывает заявку, получатель отказывается от нее.
+// Вторая сторона получает уведомление.
+func (uc *TeamUseCase) CancelOwnershipTransfer(teamID uint, userID uint) error {
+	op := "TeamUseCase.CancelOwnershipTransfer"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	teamModel, err := uc.repo.GetTeamByID(teamID)
+	if err != nil {
+		return err
+	}
+	transfer, err := uc.getOwnershipTransferFor(teamID, userID)
+	if err != nil {
+		return err
+	}
+	if err := uc.repo.DeleteOwnershipTransfer(teamID); err != nil {
+		return err
+	}
+
+	if userID == transfer.ToUserID {
+		uc.notify([]uint{transfer.FromUserID}, teamModel, "Передача владения командой отклонена", "ownership_transfer_declined")
+	} else {
+		uc.notify([]uint{transfer.ToUserID}, teamModel, "Передача владения командой отменена", "ownership_transfer_canceled")
+	}
+	log.Info("ownership transfer canceled")
+	return nil
+}
+
+// AcceptOwnershipTransfer завершает передачу владения: получатель становится владельцем, прежний владелец - администратором.
+// Заявка отклоняется, если за время ожидания сменился владелец, получатель покинул команду или стал гостем.
+func (uc *TeamUseCase) AcceptOwnershipTransfer(teamID uint, userID uint) (*team.TeamResponse, error) {
+	op := "TeamUseCase.AcceptOwnershipTransfer"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	teamModel, err := uc.repo.GetTeamByID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	transfer, err := uc.getOwnershipTransferFor(teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.ToUserID != userID {
+		log.Warn("only the recipient can accept the ownership transfer")
+		return nil, team.ErrTeamAccessDenied
+	}
+	// Роль получателя могла измениться, пока заявка ждала ответа; в транзакции она проверяется еще раз
+	if err := uc.checkOwnershipRecipient(teamID, userID); err != nil && !errors.Is(err, team.ErrUserNotMember) {
+		if errors.Is(err, team.ErrOwnershipTransferToGuest) {
+			log.Warn("recipient became a guest, discarding ownership transfer")
+			_ = uc.repo.DeleteOwnershipTransfer(teamID)
+		}
+		return nil, err
+	}
+
+	if err := uc.repo.TransferTeamOwnership(teamID, transfer.FromUserID, transfer.ToUserID); err != nil {
+		if errors.Is(err, team.ErrOwnershipTransferToGuest) {
+			log.Warn("recipient became a guest, discarding ownership transfer")
+			_ = uc.repo.DeleteOwnershipTransfer(teamID)
+			return nil, err
+		}
+		if errors.Is(err, team.ErrTeamAccessDenied) || errors.Is(err, team.ErrUserNotMember) {
+			log.Warn("ownership transfer is outdated, discarding", "error", err)
+			_ = uc.repo.DeleteOwnershipTransfer(teamID)
+			return nil, team.ErrOwnershipTransferNotFound
+		}
+		return nil, err
+	}
+
+	// Роли кэшируются в списке участников команды и в списках команд обоих пользователей
+	_ = uc.repo.DeleteTeamMembers(teamID)
+	_ = uc.repo.DeleteTeam(teamID)
+	_ = uc.repo.DeleteUserTeams(transfer.FromUserID)
+	_ = uc.repo.DeleteUserTeams(transfer.ToUserID)
+	uc.InvalidateTeamStats(teamID) // Роли участников входят в статистику
+
+	uc.notify([]uint{transfer.FromUserID}, teamModel, "Владение командой передано, теперь вы администратор", "ownership_transferred")
+	uc.notify([]uint{transfer.ToUserID}, teamModel, "Теперь вы владелец команды", "ownership_transferred")
+
+	log.Info("team ownership transferred", slog.Uint64("fromUserID", uint64(transfer.FromUserID)))
+	ownerRole := team.RoleOwner
+	memberCount, _ := uc.repo.GetTeamMembershipsCount(teamID)
+	return uc.toTeamResponse(teamModel, &ownerRole, memberCount), nil
+}
+
+// getOwnershipTransferFor возвращает заявку, если пользователь - ее отправитель или получатель.
+func (uc *TeamUseCase) getOwnershipTransferFor(teamID uint, userID uint) (*team.OwnershipTransfer, error) {
+	transfer, err := uc.repo.GetOwnershipTransfer(teamID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer == nil {
+		return nil, team.ErrOwnershipTransferNotFound
+	}
+	if userID != transfer.FromUserID && userID != transfer.ToUserID {
+		return nil, team.ErrTeamAccessDenied
+	}
+	return transfer, nil
+}
+
+func (uc *TeamUseCase) toOwnershipTransferResponse(transfer *team.OwnershipTransfer) *team.OwnershipTransferResponse {
+	resp := &team.OwnershipTransferResponse{
+		TeamID:    transfer.TeamID,
+		FromUser:  team.UserLiteResponse{UserID: transfer.FromUserID},
+		ToUser:    team.UserLiteResponse{UserID: transfer.ToUserID},
+		CreatedAt: transfer.CreatedAt,
+		ExpiresAt: transfer.ExpiresAt,
+	}
+	if from, err := uc.repo.GetUserLiteByID(transfer.FromUserID); err == nil {
+		resp.FromUser = *from
+	}
+	if to, err := uc.repo.GetUserLiteByID(transfer.ToUserID); err == nil {
+		resp.ToUser = *to
+	}
+	return resp
+}
+
+// checkOwnershipRecipient проверяет, что пользователь может стать владельцем: он участник команды и не гость.
+func (uc *TeamUseCase) checkOwnershipRecipient(teamID, userID uint) error {
+	membership, err := uc.repo.GetMembership(userID, teamID)
+	if err != nil {
+		if errors.Is(err, team.ErrUserNotMember) {
+			return team.ErrUserNotMember
+		}
+		uc.log.Error("failed to get recipient membership", "op", "TeamUseCase.checkOwnershipRecipient", "error", err)
+		return team.ErrTeamInternal
+	}
+	if membership.Role == team.RoleGuest {
+		return team.ErrOwnershipTransferToGuest
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"errors"
+	"log/slog"
+	"server/internal/modules/team"
+)
+
+// teamAccess возвращает данные для проверки прав в команде (из кэша, при промахе - из БД).
+// Для удаленной команды возвращает nil без ошибки: в ней ни у кого нет прав.
+func (uc *TeamUseCase) teamAccess(teamID uint) (*team.TeamAccess, error) {
+	access, err := uc.repo.GetTeamAccess(teamID)
+	if errors.Is(err, team.ErrTeamNotFound) {
+		return nil, nil
+	}
+	return access, err
+}
+
+// HasTeamPermission - единая точка проверки прав в команде для всех модулей.
+// Не участник команды не имеет прав; владельцу разрешено все.
+// В архивной команде права на изменение содержимого не действуют: участнику возвращается ErrTeamArchived.
+func (uc *TeamUseCase) HasTeamPermission(userID, teamID uint, permission team.Permission) (bool, error) {
+	access, err := uc.teamAccess(teamID)
+	if err != nil || access == nil || access.Role(userID) == nil {
+		return false, err
+	}
+	if permission.ModifiesContent() && access.IsArchived {
+		return false, team.ErrTeamArchived
+	}
+	return access.Allows(userID, permission), nil
+}
+
+// requirePermission возвращает ErrTeamAccessDenied, если у пользователя нет права в команде.
+func (uc *TeamUseCase) requirePermission(teamID uint, userID uint, permission team.Permission) error {
+	allowed, err := uc.HasTeamPermission(userID, teamID, permission)
+	if errors.Is(err, team.ErrTeamArchived) {
+		return err
+	}
+	if err != nil {
+		uc.log.Error("failed to check team permission", slog.String("op", "TeamUseCase.requirePermission"),
+			slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)), "permission", permission, "error", err)
+		return team.ErrTeamInternal
+	}
+	if !allowed {
+		return team.ErrTeamAccessDenied
+	}
+	return nil
+}
+
+// usersWithPermission возвращает участников команды, у которых есть право (например, получателей уведомлений).
+func (uc *TeamUseCase) usersWithPermission(teamID uint, permission team.Permission) []uint {
+	access, err := uc.teamAccess(teamID)
+	if err != nil || access == nil {
+		return nil
+	}
+	var ids []uint
+	for userID := range access.Roles {
+		if access.Allows(userID, permission) {
+			ids = append(ids, userID)
+		}
+	}
+	return ids
+}
+
+// GetTeamPermissions возвращает матрицу прав команды. Доступна всем участникам, чтобы клиент мог скрывать недоступные действия.
+func (uc *TeamUseCase) GetTeamPermissions(teamID uint, userID uint) (*team.TeamPermissionsResponse, error) {
+	if _, err := uc.repo.GetTeamByID(teamID); err != nil {
+		return nil, err
+	}
+	role, err := uc.GetUserRoleInTeam(userID, teamID)
+	if err != nil {
+		return nil, team.ErrTeamInternal
+	}
+	if role == nil {
+		return nil, team.ErrTeamAccessDenied
+	}
+	overrides, err := uc.repo.GetTeamPermissionOverrides(teamID)
+	if err != nil {
+		return nil, err
+	}
+	return toTeamPermissionsResponse(overrides, *role), nil
+}
+
+// UpdateTeamPermissions заменяет переопределения прав команды. Доступно только владельцу.
+// Переопределения, совпадающие с правами по умолчанию, не сохраняются.
+func (uc *TeamUseCase) UpdateTeamPermissions(teamID uint, userID uint, req team.UpdateTeamPermissionsRequest) (*team.TeamPermissionsResponse, error) {
+	op := "TeamUseCase.UpdateTeamPermissions"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	if _, err := uc.repo.GetTeamByID(teamID); err != nil {
+		return nil, err
+	}
+	membership, err := uc.repo.GetMembership(userID, teamID)
+	if err != nil || membership.Role != team.RoleOwner {
+		log.Warn("only the owner can change team permissions")
+		return nil, team.ErrTeamAccessDenied
+	}
+
+	type rolePermission struct {
+		role       team.TeamMemberRole
+		permission team.Permission
+	}
+	seen := make(map[rolePermission]bool, len(req.Overrides))
+	overrides := make([]*team.TeamRolePermission, 0, len(req.Overrides))
+	for _, o := range req.Overrides {
+		key := rolePermission{o.Role, o.Permission}
+		if !o.Permission.IsValid() || o.Role == team.RoleOwner || seen[key] {
+			log.Warn("invalid permission override", "role", o.Role, "permission", o.Permission)
+			return nil, team.ErrTeamPermissionInvalid
+		}
+		seen[key] = true
+		if o.Allowed == team.IsDefaultPermission(o.Role, o.Permission) {
+			continue
+		}
+		overrides = append(overrides, &team.TeamRolePermission{
+			TeamID:     teamID,
+			Role:       o.Role,
+			Permission: o.Permission,
+			Allowed:    o.Allowed,
+		})
+	}
+
+	if err := uc.repo.ReplaceTeamPermissionOverrides(teamID, overrides); err != nil {
+		return nil, err
+	}
+	log.Info("team permissions updated", slog.Int("overrides", len(overrides)))
+	return toTeamPermissionsResponse(overrides, team.RoleOwner), nil
+}
+
+func toTeamPermissionsResponse(overrides []*team.TeamRolePermission, currentRole team.TeamMemberRole) *team.TeamPermissionsResponse {
+	matrix := team.NewPermissionMatrix(overrides)
+	resp := &team.TeamPermissionsResponse{
+		Permissions:            team.AllPermissions,
+		Roles:                  make(map[team.TeamMemberRole][]team.Permission, len(team.ConfigurableRoles)+2),
+		Defaults:               make(map[team.TeamMemberRole][]team.Permission, len(team.ConfigurableRoles)),
+		Overrides:              make([]team.PermissionOverride, 0, len(overrides)),
+		CurrentUserPermissions: matrix.RolePermissions(currentRole),
+	}
+	resp.Roles[team.RoleOwner] = matrix.RolePermissions(team.RoleOwner)
+	resp.Roles[team.RoleGuest] = matrix.RolePermissions(team.RoleGuest)
+	for _, role := range team.ConfigurableRoles {
+		resp.Roles[role] = matrix.RolePermissions(role)
+		resp.Defaults[role] = team.DefaultRolePermissions[role]
+	}
+	for _, o := range overrides {
+		resp.Overrides = append(resp.Overrides, team.PermissionOverride{Role: o.Role, Permission: o.Permission, Allowed: o.Allowed})
+	}
+	return resp
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"server/internal/modules/team"
+	"testing"
+)
+
+// accessRepo отдает заранее заданные данные для проверки прав; остальные методы репозитория не вызываются.
+type accessRepo struct {
+	team.Repo
+	access *team.TeamAccess
+	err    error
+}
+
+func (r *accessRepo) GetTeamAccess(teamID uint) (*team.TeamAccess, error) {
+	return r.access, r.err
+}
+
+func TestHasTeamPermission(t *testing.T) {
+	const (
+		teamID   = 7
+		ownerID  = 1
+		adminID  = 2
+		editorID = 3
+		memberID = 4
+		guestID  = 5
+		outsider = 6
+	)
+	roles := map[uint]team.TeamMemberRole{
+		ownerID:  team.RoleOwner,
+		adminID:  team.RoleAdmin,
+		editorID: team.RoleEditor,
+		memberID: team.RoleMember,
+		guestID:  team.RoleGuest,
+	}
+	internalErr := errors.New("cache is down")
+
+	tests := []struct {
+		name       string
+		access     *team.TeamAccess
+		repoErr    error
+		userID     uint
+		permission team.Permission
+		want       bool
+		wantErr    error
+	}{
+		{name: "owner has every permission", access: &team.TeamAccess{Roles: roles}, userID: ownerID, permission: team.PermWorkflowManage, want: true},
+		{name: "admin has every permission by default", access: &team.TeamAccess{Roles: roles}, userID: adminID, permission: team.PermMemberManage, want: true},
+		{name: "editor edits any task", access: &team.TeamAccess{Roles: roles}, userID: editorID, permission: team.PermTaskEditAny, want: true},
+		{name: "editor cannot manage members", access: &team.TeamAccess{Roles: roles}, userID: editorID, permission: team.PermMemberManage, want: false},
+		{name: "member creates tasks", access: &team.TeamAccess{Roles: roles}, userID: memberID, permission: team.PermTaskCreate, want: true},
+		{name: "member cannot edit any task", access: &team.TeamAccess{Roles: roles}, userID: memberID, permission: team.PermTaskEditAny, want: false},
+		{name: "guest has no permissions", access: &team.TeamAccess{Roles: roles}, userID: guestID, permission: team.PermTaskCreate, want: false},
+		{name: "non-member has no permissions", access: &team.TeamAccess{Roles: roles}, userID: outsider, permission: team.PermTaskCreate, want: false},
+		{
+			name: "override grants permission to member",
+			access: &team.TeamAccess{Roles: roles, Overrides: []*team.TeamRolePermission{
+				{TeamID: teamID, Role: team.RoleMember, Permission: team.PermTaskEditAny, Allowed: true},
+			}},
+			userID: memberID, permission: team.PermTaskEditAny, want: true,
+		},
+		{
+			name: "override revokes default permission of editor",
+			access: &team.TeamAccess{Roles: roles, Overrides: []*team.TeamRolePermission{
+				{TeamID: teamID, Role: team.RoleEditor, Permission: team.PermTaskDeleteAny, Allowed: false},
+			}},
+			userID: editorID, permission: team.PermTaskDeleteAny, want: false,
+		},
+		{
+			name: "override cannot restrict owner",
+			access: &team.TeamAccess{Roles: roles, Overrides: []*team.TeamRolePermission{
+				{TeamID: teamID, Role: team.RoleOwner, Permission: team.PermTaskCreate, Allowed: false},
+			}},
+			userID: ownerID, permission: team.PermTaskCreate, want: true,
+		},
+		{
+			name: "override cannot grant guest",
+			access: &team.TeamAccess{Roles: roles, Overrides: []*team.TeamRolePermission{
+				{TeamID: teamID, Role: team.RoleGuest, Permission: team.PermTaskCreate, Allowed: true},
+			}},
+			userID: guestID, permission: team.PermTaskCreate, want: false,
+		},
+		{name: "archived team rejects content changes", access: &team.TeamAccess{Roles: roles, IsArchived: true}, userID: ownerID, permission: team.PermTaskCreate, wantErr: team.ErrTeamArchived},
+		{name: "archived team keeps activity view", access: &team.TeamAccess{Roles: roles, IsArchived: true}, userID: adminID, permission: team.PermActivityView, want: true},
+		{name: "archived team keeps member management", access: &team.TeamAccess{Roles: roles, IsArchived: true}, userID: adminID, permission: team.PermMemberManage, want: true},
+		{name: "archived team does not reveal itself to non-members", access: &team.TeamAccess{Roles: roles, IsArchived: true}, userID: outsider, permission: team.PermTaskCreate, want: false},
+		{name: "deleted team grants nothing", repoErr: team.ErrTeamNotFound, userID: ownerID, permission: team.PermTaskCreate, want: false},
+		{name: "repository error is returned", repoErr: internalErr, userID: ownerID, permission: team.PermTaskCreate, wantErr: internalErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.access != nil {
+				tt.access.TeamID = teamID
+			}
+			uc := &TeamUseCase{
+				repo: &accessRepo{access: tt.access, err: tt.repoErr},
+				log:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+			}
+			got, err := uc.HasTeamPermission(tt.userID, teamID, tt.permission)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("HasTeamPermission() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("HasTeamPermission() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
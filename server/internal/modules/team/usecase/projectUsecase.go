@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"errors"
+	"log/slog"
+	"server/internal/modules/team"
+	"strings"
+	"time"
+)
+
+// parseProjectDate разбирает дату проекта (YYYY-MM-DD); пустая строка очищает дату.
+func parseProjectDate(value string) (*time.Time, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, team.ErrProjectInvalid
+	}
+	return &date, nil
+}
+
+// requireTeamMember проверяет, что пользователь состоит в неудаленной команде (гости тоже видят проекты).
+func (uc *TeamUseCase) requireTeamMember(teamID uint, userID uint) error {
+	if _, err := uc.repo.GetTeamByID(teamID); err != nil {
+		return err
+	}
+	isMember, err := uc.repo.IsTeamMember(userID, teamID)
+	if err != nil {
+		return team.ErrTeamInternal
+	}
+	if !isMember {
+		return team.ErrTeamAccessDenied
+	}
+	return nil
+}
+
+// validateProjectLead проверяет, что руководитель проекта - участник команды, но не гость.
+func (uc *TeamUseCase) validateProjectLead(teamID uint, leadUserID uint) error {
+	role, err := uc.GetUserRoleInTeam(leadUserID, teamID)
+	if err != nil {
+		return team.ErrTeamInternal
+	}
+	if role == nil || *role == team.RoleGuest {
+		return team.ErrProjectInvalid
+	}
+	return nil
+}
+
+// isActiveProjectLead сообщает, является ли пользователь руководителем проекта и участником команды (не гостем).
+func (uc *TeamUseCase) isActiveProjectLead(project *team.TeamProject, userID uint) bool {
+	if project.LeadUserID == nil || *project.LeadUserID != userID {
+		return false
+	}
+	return uc.validateProjectLead(project.TeamID, userID) == nil
+}
+
+// toProjectResponse собирает DTO проекта с руководителем и прогрессом.
+func (uc *TeamUseCase) toProjectResponse(project *team.TeamProject, progress map[uint]*team.ProjectProgress) *team.ProjectResponse {
+	var lead *team.UserLiteResponse
+	if project.LeadUserID != nil {
+		var err error
+		if lead, err = uc.repo.GetUserLiteByID(*project.LeadUserID); err != nil {
+			uc.log.Warn("failed to get project lead", slog.String("op", "TeamUseCase.toProjectResponse"),
+				slog.Uint64("projectID", uint64(project.ProjectID)), "error", err)
+		}
+	}
+	return team.ToProjectResponse(project, lead, progress[project.ProjectID])
+}
+
+// GetTeamProject возвращает проект команды (для проверки привязки задач к проекту в модуле задач).
+func (uc *TeamUseCase) GetTeamProject(teamID, projectID uint) (*team.TeamProject, error) {
+	return uc.repo.GetTeamProject(teamID, projectID)
+}
+
+func (uc *TeamUseCase) GetTeamProjects(teamID uint, userID uint) ([]*team.ProjectResponse, error) {
+	op := "TeamUseCase.GetTeamProjects"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	if err := uc.requireTeamMember(teamID, userID); err != nil {
+		log.Warn("user cannot view team projects", "error", err)
+		return nil, err
+	}
+
+	projects, err := uc.repo.GetTeamProjects(teamID)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := uc.repo.GetProjectsProgress(teamID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]*team.ProjectResponse, 0, len(projects))
+	for _, project := range projects {
+		responses = append(responses, uc.toProjectResponse(project, progress))
+	}
+	return responses, nil
+}
+
+func (uc *TeamUseCase) GetTeamProjectDetails(teamID uint, userID uint, projectID uint) (*team.ProjectResponse, error) {
+	op := "TeamUseCase.GetTeamProjectDetails"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)),
+		slog.Uint64("projectID", uint64(projectID)))
+
+	if err := uc.requireTeamMember(teamID, userID); err != nil {
+		log.Warn("user cannot view team project", "error", err)
+		return nil, err
+	}
+
+	project, err := uc.repo.GetTeamProject(teamID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := uc.repo.GetProjectsProgress(teamID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.toProjectResponse(project, progress), nil
+}
+
+func (uc *TeamUseCase) CreateTeamProject(teamID uint, userID uint, req team.CreateProjectRequest) (*team.ProjectResponse, error) {
+	op := "TeamUseCase.CreateTeamProject"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	if _, err := uc.repo.GetTeamByID(teamID); err != nil {
+		return nil, err
+	}
+	if err := uc.requirePermission(teamID, userID, team.PermProjectManage); err != nil {
+		log.Warn("user cannot manage team projects")
+		return nil, err
+	}
+
+	project := &team.TeamProject{
+		TeamID:          teamID,
+		Name:            strings.TrimSpace(req.Name),
+		Description:     req.Description,
+		Color:           req.Color,
+		Status:          team.ProjectStatusPlanned,
+		CreatedByUserID: &userID,
+	}
+	if project.Name == "" {
+		return nil, team.ErrProjectInvalid
+	}
+	if req.Status != nil {
+		project.Status = *req.Status
+	}
+	var err error
+	if req.StartDate != nil {
+		if project.StartDate, err = parseProjectDate(*req.StartDate); err != nil {
+			return nil, err
+		}
+	}
+	if req.TargetDate != nil {
+		if project.TargetDate, err = parseProjectDate(*req.TargetDate); err != nil {
+			return nil, err
+		}
+	}
+	if project.StartDate != nil && project.TargetDate != nil && project.StartDate.After(*project.TargetDate) {
+		log.Warn("project start date is after target date")
+		return nil, team.ErrProjectInvalid
+	}
+	if req.LeadUserID != nil && *req.LeadUserID != 0 {
+		if err := uc.validateProjectLead(teamID, *req.LeadUserID); err != nil {
+			log.Warn("invalid project lead", "leadUserID", *req.LeadUserID, "error", err)
+			return nil, err
+		}
+		leadID := *req.LeadUserID
+		project.LeadUserID = &leadID
+	}
+
+	created, err := uc.repo.CreateTeamProject(project)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("team project created", slog.Uint64("projectID", uint64(created.ProjectID)))
+	return uc.toProjectResponse(created, nil), nil
+}
+
+// UpdateTeamProject меняет проект. Руководитель проекта может менять его без права project.manage,
+// но назначить другого руководителя может только пользователь с этим правом.
+func (uc *TeamUseCase) UpdateTeamProject(teamID uint, userID uint, projectID uint, req team.UpdateProjectRequest) (*team.ProjectResponse, error) {
+	op := "TeamUseCase.UpdateTeamProject"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)),
+		slog.Uint64("projectID", uint64(projectID)))
+
+	if _, err := uc.repo.GetTeamByID(teamID); err != nil {
+		return nil, err
+	}
+	project, err := uc.repo.GetTeamProject(teamID, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	canManage := true
+	if err := uc.requirePermission(teamID, userID, team.PermProjectManage); err != nil {
+		if !errors.Is(err, team.ErrTeamAccessDenied) || errors.Is(err, team.ErrTeamArchived) || !uc.isActiveProjectLead(project, userID) {
+			log.Warn("user cannot edit team project", "error", err)
+			return nil, err
+		}
+		canManage = false
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, team.ErrProjectInvalid
+		}
+		project.Name = name
+	}
+	if req.Description != nil {
+		project.Description = req.Description
+	}
+	if req.Color != nil {
+		project.Color = req.Color
+	}
+	if req.Status != nil {
+		project.Status = *req.Status
+	}
+	if req.StartDate != nil {
+		if project.StartDate, err = parseProjectDate(*req.StartDate); err != nil {
+			return nil, err
+		}
+	}
+	if req.TargetDate != nil {
+		if project.TargetDate, err = parseProjectDate(*req.TargetDate); err != nil {
+			return nil, err
+		}
+	}
+	if project.StartDate != nil && project.TargetDate != nil && project.StartDate.After(*project.TargetDate) {
+		log.Warn("project start date is after target date")
+		return nil, team.ErrProjectInvalid
+	}
+	if req.LeadUserID != nil {
+		var newLeadID *uint
+		if *req.LeadUserID != 0 {
+			if err := uc.validateProjectLead(teamID, *req.LeadUserID); err != nil {
+				log.Warn("invalid project lead", "leadUserID", *req.LeadUserID, "error", err)
+				return nil, err
+			}
+			leadID := *req.LeadUserID
+			newLeadID = &leadID
+		}
+		leadChanged := (project.LeadUserID == nil) != (newLeadID == nil) ||
+			(newLeadID != nil && *project.LeadUserID != *newLeadID)
+		if leadChanged && !canManage {
+			log.Warn("project lead cannot reassign the project")
+			return nil, team.ErrTeamAccessDenied
+		}
+		project.LeadUserID = newLeadID
+	}
+
+	updated, err := uc.repo.UpdateTeamProject(project)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := uc.repo.GetProjectsProgress(teamID)
+	if err != nil {
+		log.Warn("failed to get project progress", "error", err)
+	}
+	log.Info("team project updated")
+	return uc.toProjectResponse(updated, progress), nil
+}
+
+// DeleteTeamProject удаляет проект; его задачи остаются в команде без проекта.
+func (uc *TeamUseCase) DeleteTeamProject(teamID uint, userID uint, projectID uint) error {
+	op := "TeamUseCase.DeleteTeamProject"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)),
+		slog.Uint64("projectID", uint64(projectID)))
+
+	if _, err := uc.repo.GetTeamByID(teamID); err != nil {
+		return err
+	}
+	if err := uc.requirePermission(teamID, userID, team.PermProjectManage); err != nil {
+		log.Warn("user cannot manage team projects")
+		return err
+	}
+	if err := uc.repo.DeleteTeamProject(teamID, projectID); err != nil {
+		return err
+	}
+	log.Info("team project deleted")
+	return nil
+}
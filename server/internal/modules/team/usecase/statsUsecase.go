@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"log/slog"
+	"server/internal/modules/team"
+	"time"
+)
+
+const (
+	defaultTeamStatsDays = 30  // Период статистики по умолчанию - последние 30 дней
+	maxTeamStatsDays     = 366 // Самый длинный допустимый период
+)
+
+// teamStatsPeriod разбирает период статистики. Возвращает даты для ответа и границы [from, to) в UTC.
+func teamStatsPeriod(req team.TeamStatsRequest, now time.Time) (fromDate, toDate string, from, to time.Time, err error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	lastDay := today
+	if req.To != nil {
+		if lastDay, err = time.Parse("2006-01-02", *req.To); err != nil {
+			return "", "", time.Time{}, time.Time{}, team.ErrTeamStatsPeriodInvalid
+		}
+	}
+	firstDay := lastDay.AddDate(0, 0, -(defaultTeamStatsDays - 1))
+	if req.From != nil {
+		if firstDay, err = time.Parse("2006-01-02", *req.From); err != nil {
+			return "", "", time.Time{}, time.Time{}, team.ErrTeamStatsPeriodInvalid
+		}
+	}
+	if lastDay.Before(firstDay) || lastDay.Sub(firstDay) >= maxTeamStatsDays*24*time.Hour {
+		return "", "", time.Time{}, time.Time{}, team.ErrTeamStatsPeriodInvalid
+	}
+	return firstDay.Format("2006-01-02"), lastDay.Format("2006-01-02"), firstDay, lastDay.AddDate(0, 0, 1), nil
+}
+
+func (uc *TeamUseCase) GetTeamStats(teamID uint, userID uint, req team.TeamStatsRequest) (*team.TeamStatsResponse, error) {
+	op := "TeamUseCase.GetTeamStats"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	if _, err := uc.repo.GetTeamByID(teamID); err != nil {
+		return nil, err
+	}
+	if err := uc.requirePermission(teamID, userID, team.PermActivityView); err != nil {
+		log.Warn("user cannot view team stats", "error", err)
+		return nil, err
+	}
+	fromDate, toDate, from, to, err := teamStatsPeriod(req, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	// Версия читается до расчета: если данные изменятся во время расчета, результат сохранится под устаревшей версией
+	version, err := uc.repo.GetTeamStatsVersion(teamID)
+	if err == nil {
+		if cached, errCache := uc.repo.GetCachedTeamStats(teamID, version, fromDate, toDate); errCache == nil && cached != nil {
+			log.Debug("team stats retrieved from cache")
+			return cached, nil
+		}
+	}
+
+	stats, errStats := uc.repo.ComputeTeamStats(teamID, from, to)
+	if errStats != nil {
+		return nil, errStats
+	}
+	stats.From = fromDate
+	stats.To = toDate
+	stats.GeneratedAt = time.Now()
+	uc.sortStatsByWorkflow(stats)
+
+	if err == nil {
+		_ = uc.repo.SaveTeamStats(version, stats)
+	}
+	log.Info("team stats computed", slog.String("from", fromDate), slog.String("to", toDate))
+	return stats, nil
+}
+
+// sortStatsByWorkflow упорядочивает статусы по набору статусов команды; статусы вне набора идут в конце.
+func (uc *TeamUseCase) sortStatsByWorkflow(stats *team.TeamStatsResponse) {
+	workflow, err := uc.GetTaskWorkflow(stats.TeamID)
+	if err != nil {
+		return
+	}
+	ordered := make([]*team.TeamStatusCount, 0, len(stats.ByStatus))
+	byKey := make(map[string]*team.TeamStatusCount, len(stats.ByStatus))
+	for _, s := range stats.ByStatus {
+		byKey[s.Status] = s
+	}
+	for _, status := range workflow.Statuses {
+		if s, ok := byKey[status.Key]; ok {
+			ordered = append(ordered, s)
+			delete(byKey, status.Key)
+		}
+	}
+	for _, s := range stats.ByStatus {
+		if _, ok := byKey[s.Status]; ok {
+			ordered = append(ordered, s)
+		}
+	}
+	stats.ByStatus = ordered
+}
+
+// InvalidateTeamStats делает устаревшей закэшированную статистику команды.
+func (uc *TeamUseCase) InvalidateTeamStats(teamID uint) {
+	if err := uc.repo.BumpTeamStatsVersion(teamID); err != nil {
+		uc.log.Warn("failed to invalidate team stats cache", "error", err, "teamID", teamID)
+	}
+}
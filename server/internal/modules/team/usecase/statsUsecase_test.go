@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"errors"
+	"server/internal/modules/team"
+	"testing"
+	"time"
+)
+
+func TestTeamStatsPeriod(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+	date := func(s string) *string { return &s }
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+
+	tests := []struct {
+		name         string
+		req          team.TeamStatsRequest
+		wantFromDate string
+		wantToDate   string
+		wantFrom     time.Time
+		wantTo       time.Time
+		wantErr      error
+	}{
+		{
+			name:         "default is the last 30 days including today",
+			wantFromDate: "2026-02-09", wantToDate: "2026-03-10",
+			wantFrom: day("2026-02-09"), wantTo: day("2026-03-11"),
+		},
+		{
+			name:         "explicit period ends on the next day exclusively",
+			req:          team.TeamStatsRequest{From: date("2026-01-01"), To: date("2026-01-31")},
+			wantFromDate: "2026-01-01", wantToDate: "2026-01-31",
+			wantFrom: day("2026-01-01"), wantTo: day("2026-02-01"),
+		},
+		{
+			name:         "only to counts 30 days back",
+			req:          team.TeamStatsRequest{To: date("2026-01-30")},
+			wantFromDate: "2026-01-01", wantToDate: "2026-01-30",
+			wantFrom: day("2026-01-01"), wantTo: day("2026-01-31"),
+		},
+		{
+			name:         "only from ends today",
+			req:          team.TeamStatsRequest{From: date("2026-03-01")},
+			wantFromDate: "2026-03-01", wantToDate: "2026-03-10",
+			wantFrom: day("2026-03-01"), wantTo: day("2026-03-11"),
+		},
+		{
+			name:         "single day",
+			req:          team.TeamStatsRequest{From: date("2026-03-05"), To: date("2026-03-05")},
+			wantFromDate: "2026-03-05", wantToDate: "2026-03-05",
+			wantFrom: day("2026-03-05"), wantTo: day("2026-03-06"),
+		},
+		{
+			name:         "longest allowed period",
+			req:          team.TeamStatsRequest{From: date("2025-01-01"), To: date("2026-01-01")},
+			wantFromDate: "2025-01-01", wantToDate: "2026-01-01",
+			wantFrom: day("2025-01-01"), wantTo: day("2026-01-02"),
+		},
+		{name: "period longer than a year", req: team.TeamStatsRequest{From: date("2024-12-31"), To: date("2026-01-01")}, wantErr: team.ErrTeamStatsPeriodInvalid},
+		{name: "from after to", req: team.TeamStatsRequest{From: date("2026-03-02"), To: date("2026-03-01")}, wantErr: team.ErrTeamStatsPeriodInvalid},
+		{name: "invalid from", req: team.TeamStatsRequest{From: date("01.03.2026")}, wantErr: team.ErrTeamStatsPeriodInvalid},
+		{name: "invalid to", req: team.TeamStatsRequest{To: date("2026-02-30")}, wantErr: team.ErrTeamStatsPeriodInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fromDate, toDate, from, to, err := teamStatsPeriod(tt.req, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("teamStatsPeriod() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if fromDate != tt.wantFromDate || toDate != tt.wantToDate {
+				t.Errorf("teamStatsPeriod() dates = %s..%s, want %s..%s", fromDate, toDate, tt.wantFromDate, tt.wantToDate)
+			}
+			if !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+				t.Errorf("teamStatsPeriod() bounds = [%v, %v), want [%v, %v)", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
@@ -23,25 +23,31 @@ import (
 )
 
 type TeamUseCase struct {
-	repo    team.Repo
-	log     *slog.Logger
-	s3Cfg   config.S3Config // Храним всю S3Config для доступа к разным бакетам и настройкам
-	ttlCfg  config.CacheConfig
-	httpCfg config.HttpServerConfig
+	repo     team.Repo
+	notifier Notifier // nil - Push-уведомления отключены
+	mailer   InviteMailer
+	log      *slog.Logger
+	s3Cfg    config.S3Config // Храним всю S3Config для доступа к разным бакетам и настройкам
+	ttlCfg   config.CacheConfig
+	httpCfg  config.HttpServerConfig
 	// maxTeamImageSizeBytes убран, будем брать из s3Cfg
 }
 
 func NewTeamUseCase(
 	repo team.Repo,
+	notifier Notifier,
+	mailer InviteMailer,
 	log *slog.Logger,
 	appCfg config.Config, // Передаем всю конфигурацию
 ) team.UseCase {
 	return &TeamUseCase{
-		repo:    repo,
-		log:     log,
-		s3Cfg:   appCfg.S3Config, // Сохраняем S3Config
-		ttlCfg:  appCfg.CacheConfig,
-		httpCfg: appCfg.HttpServerConfig,
+		repo:     repo,
+		notifier: notifier,
+		mailer:   mailer,
+		log:      log,
+		s3Cfg:    appCfg.S3Config, // Сохраняем S3Config
+		ttlCfg:   appCfg.CacheConfig,
+		httpCfg:  appCfg.HttpServerConfig,
 	}
 }
 
@@ -74,6 +80,20 @@ func (uc *TeamUseCase) toTeamResponse(t *team.Team, role *team.TeamMemberRole, m
 		IsDeleted:       t.IsDeleted,
 		CurrentUserRole: role,
 		MemberCount:     memberCount,
+
+		JoinPolicy:             t.EffectiveJoinPolicy(),
+		Slug:                   t.Slug,
+		JoinEmailDomain:        t.JoinEmailDomain,
+		InviteApprovalRequired: t.InviteApprovalRequired,
+
+		GuestTagID:       t.GuestTagID,
+		GuestChatEnabled: t.GuestChatEnabled,
+
+		IsArchived: t.IsArchived,
+		ArchivedAt: t.ArchivedAt,
+
+		DeletedAt: t.DeletedAt,
+		PurgeAt:   teamPurgeAt(t),
 	}
 }
 
@@ -229,6 +249,9 @@ func (uc *TeamUseCase) GetMyTeams(userID uint, params team.GetMyTeamsRequest) ([
 
 	responses := make([]*team.TeamResponse, 0, len(dbTeams))
 	for _, t := range dbTeams {
+		if t.IsArchived && !params.IncludeArchived {
+			continue
+		}
 		var rolePtr *team.TeamMemberRole
 		membership, errRole := uc.repo.GetMembership(userID, t.TeamID)
 		if errRole == nil {
@@ -262,10 +285,21 @@ func (uc *TeamUseCase) UpdateTeamDetails(teamID uint, userID uint, req team.Upda
 		return nil, team.ErrTeamAccessDenied
 	}
 
-	// Разрешаем редактирование владельцу или администратору
-	if membership.Role != team.RoleOwner && membership.Role != team.RoleAdmin {
-		log.Warn("user not owner or admin for team update", "role", membership.Role)
-		return nil, team.ErrTeamAccessDenied
+	if err := uc.requirePermission(teamID, userID, team.PermTeamEdit); err != nil {
+		log.Warn("user cannot edit team", "role", membership.Role)
+		return nil, err
+	}
+
+	// Настройки вступления и гостей проверяем до загрузки изображения, чтобы не оставлять файлы в S3 при ошибке
+	joinSettingsChanged, err := uc.applyJoinSettings(existingTeam, req)
+	if err != nil {
+		log.Warn("invalid team join settings", "error", err)
+		return nil, err
+	}
+	guestSettingsChanged, err := uc.applyGuestSettings(existingTeam, req)
+	if err != nil {
+		log.Warn("invalid team guest settings", "error", err)
+		return nil, err
 	}
 
 	var newS3Key *string
@@ -332,7 +366,7 @@ func (uc *TeamUseCase) UpdateTeamDetails(teamID uint, userID uint, req team.Upda
 		madeChangesToImage = true
 	}
 
-	changedInDB := false
+	changedInDB := joinSettingsChanged || guestSettingsChanged
 	if req.Name != nil && *req.Name != "" && *req.Name != existingTeam.Name {
 		existingTeam.Name = *req.Name
 		changedInDB = true
@@ -417,21 +451,18 @@ func (uc *TeamUseCase) DeleteTeam(teamID uint, userID uint) error {
 	teamToDelete.IsDeleted = true
 	teamToDelete.DeletedAt = &now
 
-	oldS3Key := teamToDelete.ImageURLS3Key // Сохраняем ключ перед обнулением
-	teamToDelete.ImageURLS3Key = nil       // Обнуляем ключ S3 при удалении команды
+	// Изображение переносится в корзину, чтобы его можно было вернуть при восстановлении команды
+	oldS3Key := teamToDelete.ImageURLS3Key
+	if trashKey, moved := uc.moveTeamImageToTrash(oldS3Key, log); moved {
+		teamToDelete.ImageURLS3Key = &trashKey
+	}
 
 	if _, errDB := uc.repo.UpdateTeam(teamToDelete); errDB != nil {
 		log.Error("failed to logically delete team in DB", "error", errDB)
-		return team.ErrTeamInternal
-	}
-
-	// Удаляем изображение из S3, если оно было
-	if oldS3Key != nil && *oldS3Key != "" {
-		log.Info("deleting S3 team image during team delete", "s3_key", *oldS3Key)
-		if errS3 := uc.repo.DeleteTeamImage(uc.s3Cfg.BucketTeamImages, *oldS3Key); errS3 != nil {
-			log.Error("failed to delete team image from S3 during team delete, but proceeding", "s3_key", *oldS3Key, "error", errS3)
-			// Не фатально, команда все равно удалена логически
+		if teamToDelete.ImageURLS3Key != oldS3Key {
+			_ = uc.repo.MoveTeamImage(uc.s3Cfg.BucketTeamImages, *teamToDelete.ImageURLS3Key, *oldS3Key)
 		}
+		return team.ErrTeamInternal
 	}
 
 	if err := uc.repo.LogicallyDeleteTasksByTeamID(teamID, userID); err != nil {
@@ -462,6 +493,7 @@ func (uc *TeamUseCase) AddTeamMember(teamID uint, currentUserID uint, req team.A
 	// ... (существующая логика до return) ...
 	// Перед return:
 	_ = uc.repo.DeleteTeam(teamID) // Инвалидация кэша команды для обновления MemberCount при следующем GetTeamByID
+	uc.InvalidateTeamStats(teamID)
 
 	createdMembership, err := uc.repo.GetMembership(req.UserID, teamID)
 	if err != nil {
@@ -488,6 +520,7 @@ func (uc *TeamUseCase) UpdateTeamMemberRole(teamID uint, currentUserID uint, tar
 	// ... (существующая логика до return) ...
 	// После uc.repo.UpdateTeamMemberRole и uc.repo.DeleteTeamMembers(teamID):
 	_ = uc.repo.DeleteTeam(teamID) // Инвалидация кэша команды для обновления MemberCount при следующем GetTeamByID
+	uc.InvalidateTeamStats(teamID)
 
 	updatedMembership, err := uc.repo.GetMembership(targetUserID, teamID) // Получаем обновленное
 	if err != nil {
@@ -514,6 +547,7 @@ func (uc *TeamUseCase) RemoveTeamMember(teamID uint, currentUserID uint, targetU
 	// ... (существующая логика до return nil) ...
 	// После успешного uc.repo.RemoveTeamMember и других инвалидаций:
 	_ = uc.repo.DeleteTeam(teamID) // Инвалидация кэша команды для обновления MemberCount
+	uc.InvalidateTeamStats(teamID)
 	return nil
 }
 
@@ -522,49 +556,57 @@ func (uc *TeamUseCase) LeaveTeam(teamID uint, userID uint) error {
 	// ... (существующая логика до return nil) ...
 	// После успешного uc.repo.RemoveTeamMember и других инвалидаций:
 	_ = uc.repo.DeleteTeam(teamID) // Инвалидация кэша команды для обновления MemberCount
+	uc.InvalidateTeamStats(teamID)
 	return nil
 }
 
 // TeamService методы
+// IsUserMember и GetUserRoleInTeam читают состав из кэша прав команды: их вызывают для каждой задачи в списках.
 func (uc *TeamUseCase) IsUserMember(userID, teamID uint) (bool, error) {
-	return uc.repo.IsTeamMember(userID, teamID)
+	role, err := uc.GetUserRoleInTeam(userID, teamID)
+	return role != nil, err
 }
 func (uc *TeamUseCase) GetUserRoleInTeam(userID, teamID uint) (*team.TeamMemberRole, error) {
-	m, err := uc.repo.GetMembership(userID, teamID)
-	if err != nil {
-		if errors.Is(err, team.ErrUserNotMember) {
-			return nil, nil
-		} // Не ошибка, просто не участник
+	access, err := uc.teamAccess(teamID)
+	if err != nil || access == nil {
 		return nil, err
 	}
-	return &m.Role, nil
+	return access.Role(userID), nil // nil - не ошибка, просто не участник
 }
 func (uc *TeamUseCase) CanUserCreateTeamTask(userID, teamID uint) (bool, error) {
-	return uc.IsUserMember(userID, teamID)
+	return uc.HasTeamPermission(userID, teamID, team.PermTaskCreate)
 }
 func (uc *TeamUseCase) CanUserEditTeamTaskDetails(userID, teamID uint) (bool, error) {
-	role, err := uc.GetUserRoleInTeam(userID, teamID)
-	if err != nil || role == nil {
-		return false, err
-	}
-	return *role == team.RoleOwner || *role == team.RoleAdmin || *role == team.RoleEditor, nil
+	return uc.HasTeamPermission(userID, teamID, team.PermTaskEditAny)
 }
-func (uc *TeamUseCase) CanUserChangeTeamTaskStatus(userID, teamID uint, taskAssignedToUserID *uint) (bool, error) {
-	role, err := uc.GetUserRoleInTeam(userID, teamID)
-	if err != nil || role == nil {
-		return false, err
+
+// CanUserChangeTeamTaskStatus: с правом task.status_any - статус любой задачи,
+// с правом task.status_assigned - только задачи, где пользователь среди исполнителей.
+func (uc *TeamUseCase) CanUserChangeTeamTaskStatus(userID, teamID uint, taskAssigneeIDs []uint) (bool, error) {
+	if allowed, err := uc.HasTeamPermission(userID, teamID, team.PermTaskStatusAny); err != nil || allowed {
+		return allowed, err
+	}
+	isAssignee := false
+	for _, assigneeID := range taskAssigneeIDs {
+		if assigneeID == userID {
+			isAssignee = true
+			break
+		}
 	}
-	if *role == team.RoleOwner || *role == team.RoleAdmin || *role == team.RoleEditor {
-		return true, nil
+	if !isAssignee {
+		return false, nil
 	}
-	return *role == team.RoleMember && taskAssignedToUserID != nil && *taskAssignedToUserID == userID, nil
+	return uc.HasTeamPermission(userID, teamID, team.PermTaskStatusAssigned)
 }
 func (uc *TeamUseCase) CanUserDeleteTeamTask(userID, teamID uint, taskCreatorID uint) (bool, error) {
-	return uc.CanUserEditTeamTaskDetails(userID, teamID)
+	return uc.HasTeamPermission(userID, teamID, team.PermTaskDeleteAny)
 }
 func (uc *TeamUseCase) IsUserTeamMemberWithUserID(teamID uint, targetUserID uint) (bool, error) {
 	return uc.IsUserMember(targetUserID, teamID)
 }
+func (uc *TeamUseCase) CanUserViewTeamActivity(userID, teamID uint) (bool, error) {
+	return uc.HasTeamPermission(userID, teamID, team.PermActivityView)
+}
 
 func generateSecureRandomToken(length int) (string, error) {
 	bytes := make([]byte, length)
@@ -578,17 +620,13 @@ func (uc *TeamUseCase) GenerateInviteToken(teamID uint, userID uint, req team.Ge
 	op := "TeamUseCase.GenerateInviteToken"
 	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
 
-	_, err := uc.repo.GetTeamByID(teamID) // Проверяем, что команда существует и не удалена
+	teamModel, err := uc.repo.GetTeamByID(teamID) // Проверяем, что команда существует и не удалена
 	if err != nil {
 		return nil, err
 	}
 
-	membership, err := uc.repo.GetMembership(userID, teamID)
-	if err != nil {
-		return nil, team.ErrTeamAccessDenied
-	}
-	if membership.Role != team.RoleOwner && membership.Role != team.RoleAdmin {
-		return nil, team.ErrTeamAccessDenied
+	if err := uc.requirePermission(teamID, userID, team.PermInviteCreate); err != nil {
+		return nil, err
 	}
 
 	hoursToExpire := uint(24 * 7)
@@ -599,7 +637,7 @@ func (uc *TeamUseCase) GenerateInviteToken(teamID uint, userID uint, req team.Ge
 
 	roleOnJoin := team.RoleMember
 	if req.RoleToAssign != nil && req.RoleToAssign.IsValid() {
-		if *req.RoleToAssign == team.RoleEditor || *req.RoleToAssign == team.RoleMember {
+		if *req.RoleToAssign == team.RoleEditor || *req.RoleToAssign == team.RoleMember || *req.RoleToAssign == team.RoleGuest {
 			roleOnJoin = *req.RoleToAssign
 		} else {
 			return nil, team.ErrRoleChangeNotAllowed
@@ -611,7 +649,21 @@ func (uc *TeamUseCase) GenerateInviteToken(teamID uint, userID uint, req team.Ge
 		return nil, team.ErrTeamInternal
 	}
 
-	if err := uc.repo.SaveInviteToken(inviteToken, teamID, roleOnJoin, expiresAt); err != nil {
+	var targetEmail *string
+	if req.Email != nil {
+		normalized := strings.ToLower(strings.TrimSpace(*req.Email))
+		targetEmail = &normalized
+	}
+	invite, err := uc.repo.CreateTeamInvite(&team.TeamInvite{
+		TeamID:          teamID,
+		TokenHash:       hashInviteToken(inviteToken),
+		CreatedByUserID: &userID,
+		Role:            roleOnJoin,
+		MaxUses:         req.MaxUses,
+		TargetEmail:     targetEmail,
+		ExpiresAt:       expiresAt,
+	})
+	if err != nil {
 		return nil, team.ErrTeamInternal
 	}
 
@@ -627,27 +679,49 @@ func (uc *TeamUseCase) GenerateInviteToken(teamID uint, userID uint, req team.Ge
 	joinPath := "/join-team"
 	fullInviteLink := fmt.Sprintf("%s%s/%s", frontendBase, joinPath, inviteToken)
 
-	log.Info("invite token generated", "teamID", teamID, "roleOnJoin", roleOnJoin, "expiresAt", expiresAt, "link", fullInviteLink)
-	return &team.TeamInviteTokenResponse{
+	response := &team.TeamInviteTokenResponse{
+		InviteID:    invite.InviteID,
 		InviteToken: inviteToken,
 		InviteLink:  fullInviteLink,
 		ExpiresAt:   expiresAt,
 		RoleOnJoin:  roleOnJoin,
-	}, nil
+		MaxUses:     invite.MaxUses,
+		Email:       invite.TargetEmail,
+	}
+	if targetEmail != nil {
+		if err := uc.sendInviteEmail(teamModel, *targetEmail, fullInviteLink, expiresAt); err != nil {
+			log.Error("failed to send invite email, discarding invite", "error", err)
+			_ = uc.repo.DeleteTeamInvite(invite.InviteID)
+			return nil, team.ErrTeamInviteFailed
+		}
+		response.EmailSent = true
+	}
+
+	log.Info("invite token generated", "inviteID", invite.InviteID, "roleOnJoin", roleOnJoin, "expiresAt", expiresAt,
+		"maxUses", req.MaxUses, "emailInvite", targetEmail != nil)
+	return response, nil
 }
 
-func (uc *TeamUseCase) JoinTeamByToken(tokenValue string, userID uint) (*team.TeamResponse, error) {
-	teamID, roleToAssign, isValid, err := uc.repo.GetInviteTokenData(tokenValue)
+// JoinTeamByToken добавляет пользователя в команду по приглашению. Повторный вход участника не расходует приглашение.
+// Приглашение на email принимается только от пользователя с этим подтвержденным адресом.
+// Если команда требует одобрения вступлений по приглашению, вместо членства создается заявка
+// (кроме приглашений на email: адресата уже выбрал администратор).
+func (uc *TeamUseCase) JoinTeamByToken(tokenValue string, userID uint) (*team.JoinTeamResult, error) {
+	op := "TeamUseCase.JoinTeamByToken"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("userID", uint64(userID)))
+
+	invite, err := uc.repo.GetTeamInviteByTokenHash(hashInviteToken(tokenValue))
 	if err != nil {
-		return nil, team.ErrTeamInternal
+		return nil, err
 	}
-	if !isValid {
+	if !invite.IsActive(time.Now()) {
+		log.Info("invite is revoked, expired or used up", slog.Uint64("inviteID", uint64(invite.InviteID)))
 		return nil, team.ErrTeamInviteTokenInvalid
 	}
+	teamID := invite.TeamID
 
 	teamModel, err := uc.repo.GetTeamByID(teamID)
 	if err != nil {
-		_ = uc.repo.DeleteInviteToken(tokenValue)
 		return nil, team.ErrTeamInviteTokenInvalid
 	}
 
@@ -655,27 +729,41 @@ func (uc *TeamUseCase) JoinTeamByToken(tokenValue string, userID uint) (*team.Te
 	if isAlreadyMember {
 		role, _ := uc.repo.GetMembership(userID, teamID)
 		memberCount, _ := uc.repo.GetTeamMembershipsCount(teamID)
-		return uc.toTeamResponse(teamModel, &role.Role, memberCount), nil
+		return &team.JoinTeamResult{Team: uc.toTeamResponse(teamModel, &role.Role, memberCount)}, nil
+	}
+
+	if invite.TargetEmail != nil {
+		user, err := uc.repo.GetUserByID(userID)
+		if err != nil {
+			log.Error("failed to get user for email invite", "error", err)
+			return nil, team.ErrTeamInternal
+		}
+		if !user.VerifiedEmail || !strings.EqualFold(user.Email, *invite.TargetEmail) {
+			log.Warn("email invite redeemed by another account", slog.Uint64("inviteID", uint64(invite.InviteID)))
+			return nil, team.ErrTeamInviteEmailMismatch
+		}
+	} else if teamModel.InviteApprovalRequired {
+		log.Info("team requires approval for invite joins", slog.Uint64("teamID", uint64(teamID)))
+		return uc.submitJoinRequest(teamModel, userID, &invite.InviteID, invite.Role, nil)
 	}
 
 	membership := team.UserTeamMembership{
 		UserID: userID,
 		TeamID: teamID,
-		Role:   roleToAssign,
+		Role:   invite.Role,
 	}
-	if err := uc.repo.CreateMembership(&membership); err != nil { // CreateMembership должен обработать уже существующее (хотя мы проверили)
+	if err := uc.repo.RedeemTeamInvite(invite.InviteID, &membership); err != nil {
 		return nil, err
 	}
 
-	// Удаляем токен, если он одноразовый (сейчас он не помечен как одноразовый, но можно добавить логику)
-	// _ = uc.repo.DeleteInviteToken(tokenValue)
-
 	_ = uc.repo.DeleteTeamMembers(teamID) // Обновляем кэш участников
 	_ = uc.repo.DeleteUserTeams(userID)   // Обновляем кэш списка команд пользователя
 	_ = uc.repo.DeleteTeam(teamID)        // Обновляем кэш самой команды (например, для member_count)
+	uc.InvalidateTeamStats(teamID)        // Нагрузка участников в статистике
 
+	log.Info("user joined team by invite", slog.Uint64("teamID", uint64(teamID)), slog.Uint64("inviteID", uint64(invite.InviteID)))
 	memberCountAfterJoin, _ := uc.repo.GetTeamMembershipsCount(teamID)
-	return uc.toTeamResponse(teamModel, &roleToAssign, memberCountAfterJoin), nil
+	return &team.JoinTeamResult{Team: uc.toTeamResponse(teamModel, &membership.Role, memberCountAfterJoin)}, nil
 }
 
 // hashTokenForLog - вспомогательная функция для логирования части хеша токена
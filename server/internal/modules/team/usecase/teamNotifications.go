@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"server/internal/modules/team"
+	gouser "server/internal/modules/user"
+	"server/pkg/lib/pushsender"
+	"strconv"
+	"time"
+)
+
+const notificationSendTimeout = 10 * time.Second
+
+// DeviceTokenProvider - токены устройств пользователей (модуль profile).
+type DeviceTokenProvider interface {
+	GetUserDeviceTokens(userID uint) ([]gouser.UserDeviceToken, error)
+}
+
+// Notifier доставляет уведомление о событиях команды ее участникам.
+type Notifier interface {
+	Notify(userIDs []uint, msg pushsender.PushMessage)
+}
+
+type pushNotifier struct {
+	sender  pushsender.Sender
+	devices DeviceTokenProvider
+	log     *slog.Logger
+}
+
+// NewPushNotifier создает Notifier, отправляющий Push-уведомления на все устройства получателей.
+// Уведомления команды касаются прав пользователя, поэтому настройки уведомлений о задачах не учитываются.
+func NewPushNotifier(sender pushsender.Sender, devices DeviceTokenProvider, log *slog.Logger) Notifier {
+	return &pushNotifier{sender: sender, devices: devices, log: log}
+}
+
+func (n *pushNotifier) Notify(userIDs []uint, msg pushsender.PushMessage) {
+	op := "pushNotifier.Notify"
+	log := n.log.With(slog.String("op", op), slog.Int("recipients", len(userIDs)))
+
+	var tokens []string
+	for _, userID := range userIDs {
+		devices, err := n.devices.GetUserDeviceTokens(userID)
+		if err != nil {
+			log.Warn("failed to get device tokens, skipping user", "userID", userID, "error", err)
+			continue
+		}
+		for _, device := range devices {
+			tokens = append(tokens, device.DeviceToken)
+		}
+	}
+	if len(tokens) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notificationSendTimeout)
+	defer cancel()
+	msg.Tokens = tokens
+	result, err := n.sender.Send(ctx, msg)
+	if err != nil {
+		log.Error("failed to send push notification", "error", err)
+		return
+	}
+	log.Debug("push notification sent", slog.Int("success", result.SuccessCount), slog.Int("failure", result.FailureCount))
+}
+
+// notify отправляет уведомление о команде в фоне. Без настроенного Notifier ничего не делает.
+func (uc *TeamUseCase) notify(userIDs []uint, teamModel *team.Team, body string, event string) {
+	if uc.notifier == nil || len(userIDs) == 0 {
+		return
+	}
+	go uc.notifier.Notify(userIDs, pushsender.PushMessage{
+		Title: teamModel.Name,
+		Body:  body,
+		Data: map[string]string{
+			"type":    "team",
+			"team_id": strconv.FormatUint(uint64(teamModel.TeamID), 10),
+			"event":   event,
+		},
+	})
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"server/internal/modules/team"
+	"strings"
+	"time"
+)
+
+const (
+	teamTrashRetention   = 30 * 24 * time.Hour // Сколько команда хранится в корзине до окончательного удаления
+	teamTrashImagePrefix = "trash/"            // Префикс S3, под который переносится изображение удаленной команды
+	teamPurgeBatchSize   = 100
+)
+
+// teamPurgeAt возвращает момент окончательного удаления команды из корзины.
+func teamPurgeAt(t *team.Team) *time.Time {
+	if !t.IsDeleted || t.DeletedAt == nil {
+		return nil
+	}
+	purgeAt := t.DeletedAt.Add(teamTrashRetention)
+	return &purgeAt
+}
+
+// moveTeamImageToTrash переносит изображение команды под префикс корзины.
+// Если перенос не удался, изображение остается на месте и будет удалено вместе с командой по истечении срока.
+func (uc *TeamUseCase) moveTeamImageToTrash(key *string, log *slog.Logger) (string, bool) {
+	if key == nil || *key == "" || strings.HasPrefix(*key, teamTrashImagePrefix) {
+		return "", false
+	}
+	trashKey := teamTrashImagePrefix + *key
+	if err := uc.repo.MoveTeamImage(uc.s3Cfg.BucketTeamImages, *key, trashKey); err != nil {
+		log.Error("failed to move team image to trash, keeping original key", "s3_key", *key, "error", err)
+		return "", false
+	}
+	return trashKey, true
+}
+
+// RestoreTeam возвращает команду из корзины вместе с ее задачами и изображением. Доступно только владельцу.
+func (uc *TeamUseCase) RestoreTeam(teamID, userID uint) (*team.TeamResponse, error) {
+	op := "TeamUseCase.RestoreTeam"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	teamModel, err := uc.repo.GetDeletedTeamByID(teamID)
+	if err != nil {
+		return nil, err
+	}
+	membership, err := uc.repo.GetMembership(userID, teamID)
+	if err != nil {
+		if errors.Is(err, team.ErrUserNotMember) {
+			return nil, team.ErrTeamAccessDenied
+		}
+		return nil, err
+	}
+	if membership.Role != team.RoleOwner {
+		return nil, team.ErrTeamAccessDenied
+	}
+	purgeAt := teamPurgeAt(teamModel)
+	if purgeAt == nil || time.Now().After(*purgeAt) {
+		log.Warn("team trash period expired, cannot restore")
+		return nil, team.ErrTeamNotFound
+	}
+	deletedAt := *teamModel.DeletedAt
+
+	// Возвращаем изображение из корзины; при ошибке остается ключ корзины - по нему изображение тоже доступно
+	var trashKey *string
+	if teamModel.ImageURLS3Key != nil && strings.HasPrefix(*teamModel.ImageURLS3Key, teamTrashImagePrefix) {
+		trashKey = teamModel.ImageURLS3Key
+		originalKey := strings.TrimPrefix(*trashKey, teamTrashImagePrefix)
+		if errS3 := uc.repo.MoveTeamImage(uc.s3Cfg.BucketTeamImages, *trashKey, originalKey); errS3 != nil {
+			log.Error("failed to move team image out of trash, keeping trash key", "s3_key", *trashKey, "error", errS3)
+			trashKey = nil
+		} else {
+			teamModel.ImageURLS3Key = &originalKey
+		}
+	}
+
+	if err := uc.repo.RestoreTeam(teamModel, deletedAt); err != nil {
+		if trashKey != nil {
+			_ = uc.repo.MoveTeamImage(uc.s3Cfg.BucketTeamImages, *teamModel.ImageURLS3Key, *trashKey)
+		}
+		return nil, err
+	}
+
+	// Инвалидация кэшей
+	_ = uc.repo.DeleteTeam(teamID)
+	_ = uc.repo.DeleteTeamMembers(teamID)
+	uc.InvalidateTeamStats(teamID) // Вместе с командой восстановлены ее задачи
+	memberships, _ := uc.repo.GetTeamMemberships(teamID)
+	for _, m := range memberships {
+		_ = uc.repo.DeleteUserTeams(m.UserID)
+	}
+
+	log.Info("team restored from trash")
+	return uc.toTeamResponse(teamModel, &membership.Role, len(memberships)), nil
+}
+
+// GetDeletedTeams возвращает команды пользователя, находящиеся в корзине (только те, где он владелец).
+func (uc *TeamUseCase) GetDeletedTeams(userID uint) ([]*team.TeamResponse, error) {
+	teams, err := uc.repo.GetDeletedTeamsByOwner(userID)
+	if err != nil {
+		return nil, err
+	}
+	ownerRole := team.RoleOwner
+	responses := make([]*team.TeamResponse, 0, len(teams))
+	for _, t := range teams {
+		responses = append(responses, uc.toTeamResponse(t, &ownerRole, 0))
+	}
+	return responses, nil
+}
+
+// PurgeExpiredTeams окончательно удаляет команды, срок хранения которых в корзине истек,
+// вместе с задачами, участниками, тегами, чатом и объектами в S3. Запускается по расписанию.
+func (uc *TeamUseCase) PurgeExpiredTeams() {
+	op := "TeamUseCase.PurgeExpiredTeams"
+	log := uc.log.With(slog.String("op", op))
+
+	deletedBefore := time.Now().Add(-teamTrashRetention)
+	purged, failed := 0, 0
+	for {
+		teams, err := uc.repo.GetExpiredDeletedTeams(deletedBefore, teamPurgeBatchSize)
+		if err != nil {
+			log.Error("failed to get expired deleted teams", "error", err)
+			break
+		}
+		if len(teams) == 0 {
+			break
+		}
+
+		progress := false
+		for _, t := range teams {
+			if err := uc.repo.HardDeleteTeam(t.TeamID); err != nil {
+				if errors.Is(err, team.ErrTeamNotFound) {
+					progress = true // Команду успели восстановить или удалить параллельно
+					continue
+				}
+				failed++
+				continue
+			}
+			progress = true
+			purged++
+
+			prefixes := []string{fmt.Sprintf("team_%d/", t.TeamID), fmt.Sprintf("%steam_%d/", teamTrashImagePrefix, t.TeamID)}
+			for _, prefix := range prefixes {
+				if errS3 := uc.repo.DeleteTeamImagesByPrefix(uc.s3Cfg.BucketTeamImages, prefix); errS3 != nil {
+					log.Error("failed to delete team images from S3 during purge", "teamID", t.TeamID, "prefix", prefix, "error", errS3)
+				}
+			}
+			_ = uc.repo.DeleteTeam(t.TeamID)
+			_ = uc.repo.DeleteTeamMembers(t.TeamID)
+		}
+		// Если вся пачка не удалилась, следующая выборка вернет те же команды
+		if !progress || len(teams) < teamPurgeBatchSize {
+			break
+		}
+	}
+
+	if purged > 0 || failed > 0 {
+		log.Info("expired teams purged from trash", slog.Int("purged", purged), slog.Int("failed", failed))
+	}
+}
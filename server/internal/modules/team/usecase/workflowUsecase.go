@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"errors"
+	"log/slog"
+	"regexp"
+	"server/internal/modules/team"
+)
+
+var statusKeyRegexp = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// GetTaskWorkflow возвращает набор статусов команды. Если команда не задала свой набор, используется набор по умолчанию.
+func (uc *TeamUseCase) GetTaskWorkflow(teamID uint) (*team.TaskWorkflow, error) {
+	statuses, err := uc.repo.GetTeamTaskStatuses(teamID)
+	if err != nil {
+		return nil, err
+	}
+	transitions, err := uc.repo.GetTeamStatusTransitions(teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow := team.DefaultTaskWorkflow()
+	if len(statuses) > 0 {
+		workflow.Statuses = statuses
+		workflow.IsDefault = false
+	}
+	workflow.Transitions = transitions
+	return workflow, nil
+}
+
+func (uc *TeamUseCase) GetTeamTaskWorkflow(teamID uint, userID uint) (*team.TaskWorkflowResponse, error) {
+	op := "TeamUseCase.GetTeamTaskWorkflow"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	if _, err := uc.repo.GetTeamByID(teamID); err != nil {
+		return nil, err
+	}
+	isMember, err := uc.repo.IsTeamMember(userID, teamID)
+	if err != nil {
+		return nil, team.ErrTeamInternal
+	}
+	if !isMember {
+		log.Warn("user is not a member of the team")
+		return nil, team.ErrTeamAccessDenied
+	}
+
+	workflow, err := uc.GetTaskWorkflow(teamID)
+	if err != nil {
+		log.Error("failed to get team task workflow", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	return team.ToTaskWorkflowResponse(workflow), nil
+}
+
+func (uc *TeamUseCase) UpdateTeamTaskWorkflow(teamID uint, userID uint, req team.UpdateTaskWorkflowRequest) (*team.TaskWorkflowResponse, error) {
+	op := "TeamUseCase.UpdateTeamTaskWorkflow"
+	log := uc.log.With(slog.String("op", op), slog.Uint64("teamID", uint64(teamID)), slog.Uint64("userID", uint64(userID)))
+
+	if _, err := uc.repo.GetTeamByID(teamID); err != nil {
+		return nil, err
+	}
+	if err := uc.requirePermission(teamID, userID, team.PermWorkflowManage); err != nil {
+		log.Warn("user cannot manage team workflow")
+		return nil, err
+	}
+
+	statuses := make([]*team.TeamTaskStatus, 0, len(req.Statuses))
+	hasCategory := map[team.TaskStatusCategory]bool{}
+	for i, in := range req.Statuses {
+		if !statusKeyRegexp.MatchString(in.Key) {
+			log.Warn("invalid status key", "key", in.Key)
+			return nil, team.ErrTeamWorkflowInvalid
+		}
+		for _, s := range statuses {
+			if s.Key == in.Key {
+				log.Warn("duplicate status key", "key", in.Key)
+				return nil, team.ErrTeamWorkflowInvalid
+			}
+		}
+		hasCategory[in.Category] = true
+		statuses = append(statuses, &team.TeamTaskStatus{
+			TeamID:   teamID,
+			Key:      in.Key,
+			Name:     in.Name,
+			Category: in.Category,
+			Position: i,
+			Color:    in.Color,
+		})
+	}
+	if len(statuses) > 0 && (!hasCategory[team.StatusCategoryOpen] || !hasCategory[team.StatusCategoryDone]) {
+		log.Warn("workflow must contain at least one open and one done status")
+		return nil, team.ErrTeamWorkflowInvalid
+	}
+
+	effective := &team.TaskWorkflow{Statuses: statuses}
+	if len(statuses) == 0 {
+		effective = team.DefaultTaskWorkflow()
+	}
+
+	transitions := make([]*team.TeamStatusTransition, 0, len(req.Transitions))
+	for _, in := range req.Transitions {
+		if effective.Status(in.From) == nil || effective.Status(in.To) == nil {
+			log.Warn("transition references unknown status", "from", in.From, "to", in.To)
+			return nil, team.ErrTeamWorkflowInvalid
+		}
+		transitions = append(transitions, &team.TeamStatusTransition{
+			TeamID:        teamID,
+			FromStatusKey: in.From,
+			ToStatusKey:   in.To,
+			Role:          in.Role,
+		})
+	}
+
+	// Используемые задачами статусы проверяются в той же транзакции, что и замена набора
+	if err := uc.repo.ReplaceTeamTaskWorkflow(teamID, statuses, transitions); err != nil {
+		if errors.Is(err, team.ErrTeamStatusInUse) {
+			return nil, err
+		}
+		log.Error("failed to replace team task workflow", "error", err)
+		return nil, team.ErrTeamInternal
+	}
+	// Замена набора пересчитывает категорию статуса у задач: списки с фильтром по категории и счетчики устарели
+	if err := uc.repo.InvalidateTeamTaskLists(teamID); err != nil {
+		log.Warn("failed to invalidate team task lists cache", "error", err)
+	}
+	uc.InvalidateTeamStats(teamID)
+
+	log.Info("team task workflow updated", slog.Int("statuses", len(statuses)), slog.Int("transitions", len(transitions)))
+	effective.Transitions = transitions
+	return team.ToTaskWorkflowResponse(effective), nil
+}
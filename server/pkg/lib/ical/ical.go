@@ -0,0 +1,158 @@
+// Package ical формирует и разбирает календари iCalendar (RFC 5545) с записями VTODO и VEVENT.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Component - тип записи календаря
+type Component string
+
+const (
+	ComponentTodo  Component = "VTODO"
+	ComponentEvent Component = "VEVENT"
+)
+
+// Значения STATUS для VTODO
+const (
+	StatusNeedsAction = "NEEDS-ACTION"
+	StatusInProcess   = "IN-PROCESS"
+	StatusCompleted   = "COMPLETED"
+	StatusCancelled   = "CANCELLED"
+)
+
+// eventDuration - длительность VEVENT: у задачи есть только срок, поэтому событие занимает условные 30 минут до него
+const eventDuration = 30 * time.Minute
+
+const dateTimeLayout = "20060102T150405Z"
+
+// Item - запись календаря. Для VTODO Due выводится как DUE, для VEVENT - как окончание события.
+type Item struct {
+	UID          string
+	Summary      string
+	Description  string
+	URL          string
+	Status       string // Значение STATUS для VTODO; у VEVENT всегда CONFIRMED
+	Priority     int    // 1 (высший) - 9 (низший), 0 - не задан
+	Due          *time.Time
+	Completed    *time.Time
+	Categories   []string
+	Created      time.Time
+	LastModified time.Time
+}
+
+// Writer пишет календарь в поток: Begin, затем записи, затем End.
+type Writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Begin открывает VCALENDAR. name показывается клиентами как название календаря (пустое имя не выводится).
+func (cw *Writer) Begin(prodID, name string) {
+	cw.line("BEGIN:VCALENDAR")
+	cw.line("VERSION:2.0")
+	cw.line("PRODID:" + prodID)
+	cw.line("CALSCALE:GREGORIAN")
+	if name != "" {
+		cw.line("X-WR-CALNAME:" + EscapeText(name))
+	}
+}
+
+// WriteItem пишет одну запись указанного типа.
+func (cw *Writer) WriteItem(component Component, item *Item) {
+	cw.line("BEGIN:" + string(component))
+	cw.line("UID:" + item.UID)
+	cw.line("DTSTAMP:" + formatTime(item.LastModified))
+	cw.line("CREATED:" + formatTime(item.Created))
+	cw.line("LAST-MODIFIED:" + formatTime(item.LastModified))
+	cw.line("SUMMARY:" + EscapeText(item.Summary))
+	if item.Description != "" {
+		cw.line("DESCRIPTION:" + EscapeText(item.Description))
+	}
+	if item.URL != "" {
+		cw.line("URL:" + item.URL)
+	}
+	if item.Priority > 0 {
+		cw.line("PRIORITY:" + strconv.Itoa(item.Priority))
+	}
+	if len(item.Categories) > 0 {
+		categories := make([]string, len(item.Categories))
+		for i, c := range item.Categories {
+			categories[i] = EscapeText(c)
+		}
+		cw.line("CATEGORIES:" + strings.Join(categories, ","))
+	}
+
+	switch component {
+	case ComponentTodo:
+		if item.Due != nil {
+			cw.line("DUE:" + formatTime(*item.Due))
+		}
+		if item.Status != "" {
+			cw.line("STATUS:" + item.Status)
+		}
+		if item.Completed != nil {
+			cw.line("COMPLETED:" + formatTime(*item.Completed))
+			cw.line("PERCENT-COMPLETE:100")
+		}
+	case ComponentEvent:
+		if item.Due != nil {
+			cw.line("DTSTART:" + formatTime(item.Due.Add(-eventDuration)))
+			cw.line("DTEND:" + formatTime(*item.Due))
+		}
+		cw.line("STATUS:CONFIRMED")
+		cw.line("TRANSP:TRANSPARENT")
+	}
+	cw.line("END:" + string(component))
+}
+
+// End закрывает VCALENDAR и сбрасывает буфер. Возвращает первую ошибку записи.
+func (cw *Writer) End() error {
+	cw.line("END:VCALENDAR")
+	if cw.err != nil {
+		return cw.err
+	}
+	return cw.w.Flush()
+}
+
+// line пишет строку содержимого, перенося ее по 75 октетов (RFC 5545, 3.1).
+func (cw *Writer) line(s string) {
+	if cw.err != nil {
+		return
+	}
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		limit = 74 // Строка продолжения начинается с пробела
+		// Не разрываем многобайтовый символ UTF-8
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		if _, cw.err = cw.w.WriteString(s[:cut] + "\r\n "); cw.err != nil {
+			return
+		}
+		s = s[cut:]
+	}
+	_, cw.err = cw.w.WriteString(s + "\r\n")
+}
+
+// EscapeText экранирует значение типа TEXT.
+func EscapeText(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, ";", `\;`)
+	s = strings.ReplaceAll(s, ",", `\,`)
+	s = strings.ReplaceAll(s, "\r\n", `\n`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
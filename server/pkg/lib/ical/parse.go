@@ -0,0 +1,200 @@
+package ical
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoTodo      = errors.New("calendar has no VTODO component")
+	ErrInvalidData = errors.New("invalid iCalendar data")
+)
+
+// property - строка содержимого после разворота переносов: имя, параметры и значение
+type property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// ParseTodo разбирает календарь и возвращает его первую запись VTODO.
+// Поля, которых нет в записи, остаются нулевыми (Due и Completed - nil, Priority - 0).
+func ParseTodo(data []byte) (*Item, error) {
+	props, err := parseLines(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var item *Item
+	depth := 0 // Вложенность внутри VTODO (например, VALARM)
+	for _, p := range props {
+		switch {
+		case p.Name == "BEGIN" && strings.EqualFold(p.Value, string(ComponentTodo)) && item == nil:
+			item = &Item{}
+			depth = 1
+			continue
+		case depth == 0:
+			continue
+		case p.Name == "BEGIN":
+			depth++
+			continue
+		case p.Name == "END":
+			depth--
+			if depth == 0 {
+				return item, nil
+			}
+			continue
+		case depth > 1:
+			continue
+		}
+
+		switch p.Name {
+		case "UID":
+			item.UID = p.Value
+		case "SUMMARY":
+			item.Summary = unescapeText(p.Value)
+		case "DESCRIPTION":
+			item.Description = unescapeText(p.Value)
+		case "URL":
+			item.URL = p.Value
+		case "STATUS":
+			item.Status = strings.ToUpper(p.Value)
+		case "PRIORITY":
+			if v, err := strconv.Atoi(p.Value); err == nil && v >= 0 && v <= 9 {
+				item.Priority = v
+			}
+		case "DUE":
+			t, err := parseTime(p)
+			if err != nil {
+				return nil, err
+			}
+			item.Due = &t
+		case "COMPLETED":
+			t, err := parseTime(p)
+			if err != nil {
+				return nil, err
+			}
+			item.Completed = &t
+		case "CATEGORIES":
+			for _, c := range splitText(p.Value) {
+				if c = strings.TrimSpace(unescapeText(c)); c != "" {
+					item.Categories = append(item.Categories, c)
+				}
+			}
+		}
+	}
+	if item != nil {
+		return nil, ErrInvalidData // VTODO не закрыт
+	}
+	return nil, ErrNoTodo
+}
+
+// parseLines разворачивает перенесенные строки (RFC 5545, 3.1) и разбирает их на свойства.
+func parseLines(data string) ([]property, error) {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	var unfolded []string
+	for _, line := range strings.Split(data, "\n") {
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') && len(unfolded) > 0 {
+			unfolded[len(unfolded)-1] += line[1:]
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			unfolded = append(unfolded, line)
+		}
+	}
+
+	props := make([]property, 0, len(unfolded))
+	for _, line := range unfolded {
+		colon := valueSeparator(line)
+		if colon < 0 {
+			return nil, ErrInvalidData
+		}
+		parts := strings.Split(line[:colon], ";")
+		p := property{Name: strings.ToUpper(parts[0]), Value: line[colon+1:]}
+		for _, param := range parts[1:] {
+			if k, v, ok := strings.Cut(param, "="); ok {
+				if p.Params == nil {
+					p.Params = make(map[string]string)
+				}
+				p.Params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+			}
+		}
+		props = append(props, p)
+	}
+	return props, nil
+}
+
+// valueSeparator находит двоеточие, отделяющее значение, пропуская двоеточия в кавычках параметров.
+func valueSeparator(line string) int {
+	quoted := false
+	for i, r := range line {
+		switch r {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseTime разбирает DATE-TIME (UTC, с TZID или плавающее время) и DATE.
+// Плавающее время и даты без зоны считаются временем UTC.
+func parseTime(p property) (time.Time, error) {
+	loc := time.UTC
+	if tzid := p.Params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	value := strings.TrimSpace(p.Value)
+	switch {
+	case p.Params["VALUE"] == "DATE" || len(value) == 8:
+		return time.ParseInLocation("20060102", value, loc)
+	case strings.HasSuffix(value, "Z"):
+		return time.Parse(dateTimeLayout, value)
+	default:
+		return time.ParseInLocation("20060102T150405", value, loc)
+	}
+}
+
+// splitText делит список значений TEXT по неэкранированным запятым.
+func splitText(value string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+func unescapeText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n', 'N':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -15,6 +15,7 @@ type Response struct {
 	Status string      `json:"status" example:"success/error"` // "success" or "error"
 	Error  string      `json:"error,omitempty" example:"Error message if status is 'error'"`
 	Data   interface{} `json:"data,omitempty"` // Payload for success responses
+	Meta   interface{} `json:"meta,omitempty"` // Pagination and other list metadata
 }
 
 // SuccessResponse используется для Swagger, чтобы показать структуру успешного ответа с data
@@ -63,6 +64,14 @@ func SendSuccess(w http.ResponseWriter, r *http.Request, statusCode int, data in
 	render.JSON(w, r, Success(data))
 }
 
+// SendSuccessWithMeta отправляет успешный ответ с метаданными списка (курсор следующей страницы, общее количество).
+func SendSuccessWithMeta(w http.ResponseWriter, r *http.Request, statusCode int, data interface{}, meta interface{}) {
+	res := Success(data)
+	res.Meta = meta
+	render.Status(r, statusCode)
+	render.JSON(w, r, res)
+}
+
 func SendOK(w http.ResponseWriter, r *http.Request, statusCode int) {
 	render.Status(r, statusCode)
 	render.JSON(w, r, OK())
@@ -0,0 +1,95 @@
+package realip
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// New возвращает middleware, которое подставляет в RemoteAddr адрес клиента из X-Forwarded-For или X-Real-IP.
+// Заголовкам верят, только если запрос пришел от доверенного прокси (адрес или подсеть из trustedProxies);
+// без доверенных прокси заголовки игнорируются, иначе клиент мог бы подставить любой адрес.
+func New(trustedProxies []string, log *slog.Logger) func(next http.Handler) http.Handler {
+	log = log.With(slog.String("component", "middlewareRealIP"))
+
+	var trusted []netip.Prefix
+	for _, entry := range trustedProxies {
+		entry = strings.TrimSpace(entry)
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			addr, errAddr := netip.ParseAddr(entry)
+			if errAddr != nil {
+				log.Error("invalid trusted proxy, skipping", slog.String("proxy", entry), "error", err)
+				continue
+			}
+			prefix = netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen())
+		}
+		trusted = append(trusted, prefix.Masked())
+	}
+	isTrusted := func(addr netip.Addr) bool {
+		addr = addr.Unmap()
+		for _, prefix := range trusted {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	log.Info("real ip middleware enabled", slog.Int("trusted_proxies", len(trusted)))
+
+	return func(next http.Handler) http.Handler {
+		if len(trusted) == 0 {
+			return next
+		}
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			if remote, ok := parseAddr(r.RemoteAddr); ok && isTrusted(remote) {
+				if client, ok := clientAddr(r, isTrusted); ok {
+					r.RemoteAddr = client.String()
+				}
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(fn)
+	}
+}
+
+// clientAddr ищет адрес клиента: в X-Forwarded-For это первый справа адрес, не принадлежащий доверенным прокси
+// (левые значения мог прислать сам клиент), иначе - X-Real-IP.
+func clientAddr(r *http.Request, isTrusted func(netip.Addr) bool) (netip.Addr, bool) {
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		var leftmost netip.Addr
+		for i := len(hops) - 1; i >= 0; i-- {
+			addr, ok := parseAddr(strings.TrimSpace(hops[i]))
+			if !ok {
+				break
+			}
+			if !isTrusted(addr) {
+				return addr, true
+			}
+			leftmost = addr
+		}
+		if leftmost.IsValid() {
+			return leftmost, true // Все адреса цепочки - доверенные прокси
+		}
+	}
+	if xrip := r.Header.Get("X-Real-IP"); xrip != "" {
+		return parseAddr(strings.TrimSpace(xrip))
+	}
+	return netip.Addr{}, false
+}
+
+// parseAddr разбирает адрес с портом или без него.
+func parseAddr(value string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}